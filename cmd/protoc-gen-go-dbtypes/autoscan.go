@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// validateAutoScan reports options under which Scan cannot tell the format
+// from the stored bytes.
+func validateAutoScan(config *GeneratorConfig) error {
+	if !config.AutoScan {
+		return nil
+	}
+	if config.EncryptHooks {
+		// The bytes are ciphertext until the codec decrypts them
+		return fmt.Errorf("auto-scan=true cannot be combined with encrypt-hooks")
+	}
+	return nil
+}
+
+// generateAutoScan emits the DetectFormat hook Scan uses to pick the codec of
+// each stored value, for tables holding rows of more than one format.
+func generateAutoScan(g *protogen.GeneratedFile) {
+	g.P("// DetectFormat returns the codec Scan decodes data with, given the codec of")
+	g.P("// the wrapper, so that a table can hold rows of more than one format while")
+	g.P("// they are migrated. Value still writes the wrapper's format. The default")
+	g.P("// decodes data starting with '{' as protojson and other data as the wrapper's")
+	g.P("// format, or as the protobuf wire format for JSON wrappers. Replace it in an")
+	g.P("// init function to recognize other formats; nil turns detection off.")
+	g.P("var DetectFormat func(data []byte, codec Codec) Codec = dbtypesDetectFormat")
+	g.P()
+	g.P("// dbtypesDetectFormat is the default DetectFormat. A binary message can only")
+	g.P("// start with '{' if its first field is a group numbered 15, which proto3")
+	g.P("// doesn't have.")
+	g.P("func dbtypesDetectFormat(data []byte, codec Codec) Codec {")
+	g.P("	if len(data) > 0 && data[0] == '{' {")
+	g.P("		return dbtypesJSONCodec")
+	g.P("	}")
+	g.P("	if codec == dbtypesJSONCodec {")
+	g.P("		return dbtypesBinaryCodec")
+	g.P("	}")
+	g.P("	return codec")
+	g.P("}")
+	g.P()
+}
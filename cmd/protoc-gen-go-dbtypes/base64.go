@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+const base64Package = protogen.GoImportPath("encoding/base64")
+
+// base64Text reports whether Value stores messages in format as base64 text.
+// Only bytes are encoded: the text formats are already text, unless
+// encryption turns them into ciphertext.
+func (c *GeneratorConfig) base64Text(format Format) bool {
+	return c.Base64Text && (!format.isText() || c.EncryptHooks)
+}
+
+// validateBase64Text reports options whose generated code relies on Value
+// returning bytes for binary messages, which base64-text turns into strings.
+func validateBase64Text(config *GeneratorConfig) error {
+	if !config.Base64Text {
+		return nil
+	}
+	var conflict string
+	switch {
+	case config.Generic:
+		conflict = "generic"
+	case config.PostgresArray:
+		conflict = "postgres-array"
+	case config.Driver == DriverPgx:
+		conflict = "driver=pgx"
+	case config.EmitBSON:
+		conflict = "emit-bson"
+	case config.ORMs[ORMGorm]:
+		conflict = "orm=gorm"
+	default:
+		return nil
+	}
+	return fmt.Errorf("base64-text=true cannot be combined with %s", conflict)
+}
+
+// generateBase64Helpers emits the conversions between stored bytes and the
+// base64 text written to the column.
+func generateBase64Helpers(g *protogen.GeneratedFile) {
+	g.P("// dbtypesValueBase64 encodes stored bytes as standard base64, for binary")
+	g.P("// messages kept in text columns.")
+	g.P("func dbtypesValueBase64(v ", driverPackage.Ident("Value"), ", err error) (", driverPackage.Ident("Value"), ", error) {")
+	g.P("	if data, ok := v.([]byte); ok && err == nil {")
+	g.P("		return ", base64Package.Ident("StdEncoding"), ".EncodeToString(data), nil")
+	g.P("	}")
+	g.P("	return v, err")
+	g.P("}")
+	g.P()
+	g.P("// scanBase64 is scan for base64 text columns. A string source is decoded")
+	g.P("// from standard base64 first; byte sources are taken as the raw encoding.")
+	g.P("func (p *ProtoValue[T]) scanBase64(ctx ", contextPackage.Ident("Context"), ", src any, codec Codec) error {")
+	g.P("	if s, ok := dbtypesScanSource(src).(string); ok {")
+	g.P("		data, err := ", base64Package.Ident("StdEncoding"), ".DecodeString(s)")
+	g.P("		if err != nil {")
+	g.P("			return p.wrapError(", fmtPackage.Ident("Errorf"), `("decode base64 scan source: %w", err))`)
+	g.P("		}")
+	g.P("		src = data")
+	g.P("	}")
+	g.P("	return p.scan(ctx, src, codec)")
+	g.P("}")
+	g.P()
+}
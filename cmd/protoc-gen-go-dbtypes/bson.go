@@ -0,0 +1,100 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+const bsonPackage = protogen.GoImportPath("go.mongodb.org/mongo-driver/v2/bson")
+
+// bsonBuildTag guards the BSON methods so that only builds which opt in
+// depend on the MongoDB driver.
+const bsonBuildTag = "dbtypes_bson"
+
+// generateBSONFile emits the bson.Marshaler and bson.Unmarshaler methods for
+// messages into a separate, build-tagged file. The first file of each
+// package also declares the helpers that bridge protojson and BSON.
+func generateBSONFile(gen *protogen.Plugin, file *protogen.File, messages []*protogen.Message, config *GeneratorConfig, firstInPackage bool) {
+	filename := config.filename(file, "bson")
+	g := gen.NewGeneratedFile(filename, config.importPath(file))
+
+	g.P("// Code generated by protoc-gen-go-dbtypes. DO NOT EDIT.")
+	g.P("// source: ", file.Desc.Path())
+	g.P()
+	g.P("//go:build ", bsonBuildTag)
+	g.P()
+	g.P("package ", config.packageName(file))
+	g.P()
+
+	if firstInPackage {
+		generateBSONHelpers(g, config)
+	}
+
+	for _, m := range messages {
+		typeName := m.GoIdent.GoName
+		wrapperName := config.wrapperName(m)
+
+		g.P("// MarshalBSON implements bson.Marshaler. The document has the fields of the")
+		g.P("// protojson encoding; a nil message is an empty document.")
+		g.P("func (x ", wrapperName, ") MarshalBSON() ([]byte, error) {")
+		g.P("	var msg *", m.GoIdent)
+		g.P("	if x.ProtoValue != nil {")
+		g.P("		msg = x.ProtoValue.Message")
+		g.P("	}")
+		g.P("	return dbtypesMarshalBSON(msg)")
+		g.P("}")
+		g.P()
+
+		g.P("// UnmarshalBSON implements bson.Unmarshaler. Fields that ", typeName, " doesn't")
+		g.P("// declare, such as _id, are ignored.")
+		g.P("func (x *", wrapperName, ") UnmarshalBSON(data []byte) error {")
+		g.P("	msg := &", m.GoIdent, "{}")
+		g.P("	if err := dbtypesUnmarshalBSON(data, msg); err != nil {")
+		g.P("		return err")
+		g.P("	}")
+		g.P("	x.ProtoValue = &ProtoValue[*", m.GoIdent, "]{Message: msg}")
+		g.P("	return nil")
+		g.P("}")
+		g.P()
+	}
+}
+
+func generateBSONHelpers(g *protogen.GeneratedFile, config *GeneratorConfig) {
+	g.P("// dbtypesMarshalBSON converts the protojson encoding of msg to a BSON")
+	g.P("// document. JSON numbers become BSON int32, int64 or double; protojson")
+	g.P("// already encodes 64-bit integers as strings.")
+	g.P("func dbtypesMarshalBSON(msg ", protoPackage.Ident("Message"), ") ([]byte, error) {")
+	if config.AllowPartial {
+		g.P("	data, err := ", protojsonPackage.Ident("MarshalOptions"), "{AllowPartial: true}.Marshal(msg)")
+	} else {
+		g.P("	data, err := ", protojsonPackage.Ident("Marshal"), "(msg)")
+	}
+	g.P("	if err != nil {")
+	g.P("		return nil, dbtypesBSONError(msg, err)")
+	g.P("	}")
+	g.P("	var doc ", bsonPackage.Ident("D"))
+	g.P("	if err := ", bsonPackage.Ident("UnmarshalExtJSON"), "(data, false, &doc); err != nil {")
+	g.P("		return nil, dbtypesBSONError(msg, err)")
+	g.P("	}")
+	g.P("	return ", bsonPackage.Ident("Marshal"), "(doc)")
+	g.P("}")
+	g.P()
+
+	g.P("// dbtypesUnmarshalBSON decodes a BSON document into msg through its relaxed")
+	g.P("// Extended JSON form.")
+	g.P("func dbtypesUnmarshalBSON(data []byte, msg ", protoPackage.Ident("Message"), ") error {")
+	g.P("	js, err := ", bsonPackage.Ident("MarshalExtJSON"), "(", bsonPackage.Ident("Raw"), "(data), false, false)")
+	g.P("	if err != nil {")
+	g.P("		return dbtypesBSONError(msg, err)")
+	g.P("	}")
+	g.P("	if err := (", protojsonPackage.Ident("UnmarshalOptions"), "{", config.withPartial("DiscardUnknown: true"), "}).Unmarshal(js, msg); err != nil {")
+	g.P("		return dbtypesBSONError(msg, err)")
+	g.P("	}")
+	g.P("	return nil")
+	g.P("}")
+	g.P()
+
+	g.P("func dbtypesBSONError(msg ", protoPackage.Ident("Message"), ", err error) error {")
+	g.P("	return ", fmtPackage.Ident("Errorf"), `("dbtypes: %s: bson: %w", msg.ProtoReflect().Descriptor().Name(), err)`)
+	g.P("}")
+	g.P()
+}
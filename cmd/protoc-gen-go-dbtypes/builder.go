@@ -0,0 +1,112 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// builderName returns the name of the builder generated for m.
+func (c *GeneratorConfig) builderName(m *protogen.Message) string {
+	return c.wrapperName(m) + "Builder"
+}
+
+// generateBuilder emits a builder with a chained SetXxx method for each
+// top-level field of m and a Build method returning the wrapper. Setters of
+// fields with explicit presence take the value and store its address, and
+// setters of oneof members select that member.
+func generateBuilder(g *protogen.GeneratedFile, m *protogen.Message, config *GeneratorConfig) {
+	typeName := m.GoIdent.GoName
+	wrapperName := config.wrapperName(m)
+	builderName := config.builderName(m)
+
+	g.P("// ", builderName, " builds a ", wrapperName, " one field at a time, e.g. for")
+	g.P("// test fixtures.")
+	g.P("type ", builderName, " struct {")
+	g.P("	msg *", m.GoIdent)
+	g.P("}")
+	g.P()
+	g.P("// New", builderName, " returns a builder of an empty ", typeName, ".")
+	g.P("func New", builderName, "() *", builderName, " {")
+	g.P("	return &", builderName, "{msg: &", m.GoIdent, "{}}")
+	g.P("}")
+	g.P()
+
+	for _, field := range m.Fields {
+		g.P("// Set", field.GoName, " sets the ", field.Desc.Name(), " field.")
+		g.P("func (b *", builderName, ") Set", field.GoName, "(v ", fieldGoType(g, field), ") *", builderName, " {")
+		switch {
+		case field.Oneof != nil && !field.Oneof.Desc.IsSynthetic():
+			g.P("	b.msg.", field.Oneof.GoName, " = &", field.GoIdent, "{", field.GoName, ": v}")
+		case fieldPointer(field):
+			g.P("	b.msg.", field.GoName, " = &v")
+		default:
+			g.P("	b.msg.", field.GoName, " = v")
+		}
+		g.P("	return b")
+		g.P("}")
+		g.P()
+	}
+
+	g.P("// Build returns a wrapper around the message built so far. Later setters")
+	g.P("// modify the same message.")
+	g.P("func (b *", builderName, ") Build() *", wrapperName, " {")
+	g.P("	return New", wrapperName, "(b.msg)")
+	g.P("}")
+	g.P()
+}
+
+// fieldPointer reports whether protoc-gen-go declares field as a pointer to
+// its value type, as it does for scalars with explicit presence.
+func fieldPointer(field *protogen.Field) bool {
+	if !field.Desc.HasPresence() || field.Desc.IsList() || field.Desc.IsMap() {
+		return false
+	}
+	if field.Oneof != nil && !field.Oneof.Desc.IsSynthetic() {
+		return false
+	}
+	switch field.Desc.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind, protoreflect.BytesKind:
+		return false
+	}
+	return true
+}
+
+// fieldGoType returns the Go type of the values of field as declared by
+// protoc-gen-go, without the pointer of fields with explicit presence.
+func fieldGoType(g *protogen.GeneratedFile, field *protogen.Field) string {
+	if field.Desc.IsMap() {
+		key, value := field.Message.Fields[0], field.Message.Fields[1]
+		return "map[" + singularGoType(g, key) + "]" + singularGoType(g, value)
+	}
+	if field.Desc.IsList() {
+		return "[]" + singularGoType(g, field)
+	}
+	return singularGoType(g, field)
+}
+
+// singularGoType returns the Go type of a single value of field.
+func singularGoType(g *protogen.GeneratedFile, field *protogen.Field) string {
+	switch field.Desc.Kind() {
+	case protoreflect.BoolKind:
+		return "bool"
+	case protoreflect.EnumKind:
+		return g.QualifiedGoIdent(field.Enum.GoIdent)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return "int32"
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return "uint32"
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return "int64"
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return "uint64"
+	case protoreflect.FloatKind:
+		return "float32"
+	case protoreflect.DoubleKind:
+		return "float64"
+	case protoreflect.StringKind:
+		return "string"
+	case protoreflect.BytesKind:
+		return "[]byte"
+	}
+	return "*" + g.QualifiedGoIdent(field.Message.GoIdent)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+const (
+	binaryPackage = protogen.GoImportPath("encoding/binary")
+	crc32Package  = protogen.GoImportPath("hash/crc32")
+	sha256Package = protogen.GoImportPath("crypto/sha256")
+)
+
+// ChecksumAlgorithm is the algorithm of the checksums emit-checksum=true
+// generates.
+type ChecksumAlgorithm string
+
+const (
+	// ChecksumSHA256 checksums are the 32-byte SHA-256 digest.
+	ChecksumSHA256 ChecksumAlgorithm = "sha256"
+	// ChecksumCRC32 checksums are the IEEE CRC-32, as 4 big-endian bytes.
+	ChecksumCRC32 ChecksumAlgorithm = "crc32"
+)
+
+func parseChecksumAlgorithm(s string) (ChecksumAlgorithm, error) {
+	switch a := ChecksumAlgorithm(s); a {
+	case ChecksumSHA256, ChecksumCRC32:
+		return a, nil
+	}
+	return "", fmt.Errorf("unknown checksum algorithm %q (want crc32 or sha256)", s)
+}
+
+// validateChecksum reports options under which Checksum cannot reproduce the
+// bytes Value stored.
+func validateChecksum(config *GeneratorConfig) error {
+	if !config.EmitChecksum {
+		return nil
+	}
+	if config.EncryptHooks {
+		// Every encryption uses a fresh nonce, so the ciphertext never repeats
+		return fmt.Errorf("emit-checksum=true cannot be combined with encrypt-hooks")
+	}
+	return nil
+}
+
+// generateChecksumHelpers emits the hook and helpers Checksum and Scan share,
+// once per package.
+func generateChecksumHelpers(g *protogen.GeneratedFile, config *GeneratorConfig) {
+	g.P("// ExpectedChecksum, when non-nil, returns the checksum Scan compares that of")
+	g.P("// the stored bytes with before decoding them, as Checksum computed it when")
+	g.P("// the row was written. It is given the context passed to ScanContext, which")
+	g.P("// can carry the value of a companion checksum column, and the full name of")
+	g.P("// the message scanned. A nil result skips the check.")
+	g.P("var ExpectedChecksum func(ctx ", contextPackage.Ident("Context"), ", msg ", protoreflectPackage.Ident("FullName"), ") []byte")
+	g.P()
+	g.P("// ErrChecksumMismatch is wrapped by the error Scan returns for stored bytes")
+	g.P("// whose checksum differs from the one ExpectedChecksum returns.")
+	g.P("var ErrChecksumMismatch = ", errorsPackage.Ident("New"), `("checksum mismatch")`)
+	g.P()
+	switch config.ChecksumAlgorithm {
+	case ChecksumCRC32:
+		g.P("// dbtypesChecksum returns the IEEE CRC-32 of data as 4 big-endian bytes.")
+		g.P("func dbtypesChecksum(data []byte) []byte {")
+		g.P("	return ", binaryPackage.Ident("BigEndian"), ".AppendUint32(nil, ", crc32Package.Ident("ChecksumIEEE"), "(data))")
+		g.P("}")
+	default:
+		g.P("// dbtypesChecksum returns the SHA-256 digest of data.")
+		g.P("func dbtypesChecksum(data []byte) []byte {")
+		g.P("	sum := ", sha256Package.Ident("Sum256"), "(data)")
+		g.P("	return sum[:]")
+		g.P("}")
+	}
+	g.P()
+	g.P("// verifyChecksum returns an error wrapping ErrChecksumMismatch if")
+	g.P("// ExpectedChecksum returns a checksum for the message that data doesn't have.")
+	g.P("func (p *ProtoValue[T]) verifyChecksum(ctx ", contextPackage.Ident("Context"), ", data []byte) error {")
+	g.P("	if ExpectedChecksum == nil {")
+	g.P("		return nil")
+	g.P("	}")
+	g.P("	want := ExpectedChecksum(ctx, p.Message.ProtoReflect().Descriptor().FullName())")
+	g.P("	if want == nil || ", bytesPackage.Ident("Equal"), "(dbtypesChecksum(data), want) {")
+	g.P("		return nil")
+	g.P("	}")
+	g.P("	return p.wrapError(ErrChecksumMismatch)")
+	g.P("}")
+	g.P()
+}
+
+// generateChecksum emits the Checksum method of a wrapper whose messages
+// codec encodes.
+func generateChecksum(g *protogen.GeneratedFile, config *GeneratorConfig, recv, codec string) {
+	g.P("// Checksum returns the ", config.ChecksumAlgorithm, " checksum of the encoded message")
+	g.P("// Value stores, before any base64 text encoding, to be stored in a companion")
+	g.P("// column and checked by Scan through ExpectedChecksum. It returns nil if")
+	g.P("// there is no message or it doesn't encode.")
+	g.P("func (x ", recv, ") Checksum() []byte {")
+	g.P("	if x.ProtoValue == nil || x.ProtoValue.Message == nil {")
+	g.P("		return nil")
+	g.P("	}")
+	g.P("	data, err := ", codec, ".Encode(x.ProtoValue.Message)")
+	g.P("	if err != nil {")
+	g.P("		return nil")
+	g.P("	}")
+	g.P("	return dbtypesChecksum(data)")
+	g.P("}")
+	g.P()
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// codecName returns the name the codec of format is generated under.
+func (f Format) codecName() string {
+	switch f {
+	case FormatJSON:
+		return "JSON"
+	case FormatText:
+		return "Text"
+	case FormatCBOR:
+		return "CBOR"
+	case FormatMsgpack:
+		return "Msgpack"
+	}
+	return "Binary"
+}
+
+// codecVar returns the generated variable holding the codec of format.
+func (c *GeneratorConfig) codecVar(format Format) string {
+	return "dbtypes" + format.codecName() + "Codec"
+}
+
+// codecFormats returns the formats a package's messages can be stored in:
+// binary, json and text, plus cbor and msgpack where the default format or a
+// (dbtypes.format) option selects them.
+func (c *GeneratorConfig) codecFormats() []Format {
+	formats := []Format{FormatBinary, FormatJSON, FormatText}
+	for _, format := range []Format{FormatCBOR, FormatMsgpack} {
+		if c.Format == format || c.MessageFormats[format] {
+			formats = append(formats, format)
+		}
+	}
+	return formats
+}
+
+// generateCodec emits the Codec interface and the codec of each format,
+// which hold everything Value and Scan do to the stored bytes apart from
+// reading the driver's source.
+func generateCodec(g *protogen.GeneratedFile, config *GeneratorConfig) {
+	g.P("// Codec converts messages to and from the bytes Value stores and Scan reads,")
+	g.P("// for code that handles the stored encoding outside database/sql, such as")
+	g.P("// a message queue carrying the same rows. The XxxCodec functions return the")
+	g.P("// codec of each message type.")
+	g.P("type Codec interface {")
+	g.P("	Encode(msg ", protoPackage.Ident("Message"), ") ([]byte, error)")
+	g.P("	Decode(data []byte, msg ", protoPackage.Ident("Message"), ") error")
+	g.P("}")
+	g.P()
+	g.P("// dbtypesCodec is the Codec of one format.")
+	g.P("type dbtypesCodec struct {")
+	g.P("	marshal   func(", protoPackage.Ident("Message"), ") ([]byte, error)")
+	g.P("	unmarshal func([]byte, ", protoPackage.Ident("Message"), ") error")
+	g.P("}")
+	g.P()
+	g.P("// Encode implements Codec.")
+	g.P("func (c *dbtypesCodec) Encode(msg ", protoPackage.Ident("Message"), ") ([]byte, error) {")
+	if config.SortRepeated {
+		g.P("	data, err := c.marshal(dbtypesSortRepeated(msg))")
+	} else {
+		g.P("	data, err := c.marshal(msg)")
+	}
+	g.P("	if err == nil {")
+	g.P("		err = dbtypesCheckSize(len(data))")
+	g.P("	}")
+	g.P("	if err != nil {")
+	g.P("		return nil, dbtypesWrapError(msg, err)")
+	g.P("	}")
+	if config.EncryptHooks {
+		g.P("	if EncryptCipher != nil {")
+		g.P("		if data, err = EncryptCipher(data); err != nil {")
+		g.P("			return nil, dbtypesWrapError(msg, ", fmtPackage.Ident("Errorf"), `("encrypt: %w", err))`)
+		g.P("		}")
+		g.P("	}")
+	}
+	g.P("	return data, nil")
+	g.P("}")
+	g.P()
+	g.P("// Decode implements Codec.")
+	g.P("func (c *dbtypesCodec) Decode(data []byte, msg ", protoPackage.Ident("Message"), ") error {")
+	if config.EncryptHooks {
+		g.P("	if DecryptCipher != nil {")
+		g.P("		var err error")
+		g.P("		if data, err = DecryptCipher(data); err != nil {")
+		g.P("			return dbtypesWrapError(msg, ", fmtPackage.Ident("Errorf"), `("decrypt: %w", err))`)
+		g.P("		}")
+		g.P("	}")
+		g.P()
+	}
+	g.P("	if err := c.unmarshal(data, msg); err != nil {")
+	g.P("		return &DecodeError{Message: msg.ProtoReflect().Descriptor().FullName(), Err: err}")
+	g.P("	}")
+	g.P("	return nil")
+	g.P("}")
+	g.P()
+	g.P("// The codecs of the formats messages can be stored in.")
+	g.P("var (")
+	for _, format := range config.codecFormats() {
+		marshal, unmarshal := config.marshalFunc(format), config.unmarshalFunc(format)
+		// The hooks may be replaced after the codecs are initialized
+		if marshal == "Marshal" {
+			marshal = "func(m " + g.QualifiedGoIdent(protoPackage.Ident("Message")) + ") ([]byte, error) { return Marshal(m) }"
+		}
+		if unmarshal == "Unmarshal" {
+			unmarshal = "func(b []byte, m " + g.QualifiedGoIdent(protoPackage.Ident("Message")) + ") error { return Unmarshal(b, m) }"
+		}
+		g.P("	", config.codecVar(format), " Codec = &dbtypesCodec{marshal: ", marshal, ", unmarshal: ", unmarshal, "}")
+	}
+	g.P(")")
+	g.P()
+}
+
+// generateCodecAccessor emits the function returning the codec of m, which
+// its wrapper's Value and Scan go through.
+func generateCodecAccessor(g *protogen.GeneratedFile, m *protogen.Message, config *GeneratorConfig, format Format) {
+	switch {
+	case config.Generic:
+		g.P("// ", m.GoIdent.GoName, "Codec returns the codec of ", config.wrapperName(m), ".")
+		if len(redactedFields(m)) > 0 {
+			// The runtime codecs read the field options themselves
+			g.P("// It encodes the message without the fields marked (dbtypes.redact) or")
+			g.P("// (dbtypes.skip), and clears those marked (dbtypes.skip) when decoding.")
+		}
+		g.P("func ", m.GoIdent.GoName, "Codec() Codec { return ", dbtypesPackage.Ident(format.codecName()+"Codec"), "() }")
+	case len(redactedFields(m)) > 0:
+		g.P("// ", m.GoIdent.GoName, "Codec returns the codec of ", config.wrapperName(m), ", which")
+		g.P("// encodes the message without the fields marked (dbtypes.redact) or")
+		g.P("// (dbtypes.skip).")
+		g.P("func ", m.GoIdent.GoName, "Codec() Codec { return dbtypes", m.GoIdent.GoName, "Codec }")
+		g.P()
+		g.P("var dbtypes", m.GoIdent.GoName, "Codec Codec = ", redactCodec(m, config.codecVar(format)))
+		g.P()
+		generateRedact(g, m)
+		return
+	default:
+		g.P("// ", m.GoIdent.GoName, "Codec returns the codec of ", config.wrapperName(m), ".")
+		g.P("func ", m.GoIdent.GoName, "Codec() Codec { return ", config.codecVar(format), " }")
+	}
+	g.P()
+}
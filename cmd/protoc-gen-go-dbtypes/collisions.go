@@ -0,0 +1,225 @@
+package main
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// checkCollisions reports Go identifiers that the generated code would
+// declare twice, so that generation fails with the offending message named
+// instead of producing code that doesn't compile.
+func checkCollisions(gen *protogen.Plugin, config *GeneratorConfig) error {
+	// Identifiers already declared by protoc-gen-go, per Go package
+	declared := make(map[protogen.GoImportPath]map[string]string)
+	for _, f := range gen.Files {
+		names := declared[f.GoImportPath]
+		if names == nil {
+			names = make(map[string]string)
+			declared[f.GoImportPath] = names
+		}
+		addDeclaredNames(names, f)
+	}
+
+	// Identifiers the wrappers declare, per output package
+	generated := make(map[protogen.GoImportPath]map[string]string)
+	for _, f := range gen.Files {
+		if !f.Generate {
+			continue
+		}
+		messages := wrappedMessages(f, config)
+		enums := wrappedEnums(f, config)
+		if len(messages) == 0 && len(enums) == 0 {
+			continue
+		}
+		pkg := config.importPath(f)
+		taken := generated[pkg]
+		if taken == nil {
+			// Wrappers generated alongside the messages share their package
+			taken = declared[f.GoImportPath]
+			if config.OutPackage != "" {
+				taken = make(map[string]string)
+			}
+			if other, ok := taken["ProtoValue"]; ok && !config.Generic {
+				return fmt.Errorf("%s: generated type ProtoValue collides with %s", f.Desc.Path(), other)
+			}
+			if other, ok := taken["Codec"]; ok {
+				return fmt.Errorf("%s: generated type Codec collides with %s", f.Desc.Path(), other)
+			}
+			if other, ok := taken["ColumnInfo"]; ok && config.EmitColumns {
+				return fmt.Errorf("%s: generated type ColumnInfo collides with %s", f.Desc.Path(), other)
+			}
+			if other, ok := taken["FieldChange"]; ok && config.EmitDiff {
+				return fmt.Errorf("%s: generated type FieldChange collides with %s", f.Desc.Path(), other)
+			}
+			if other, ok := taken["DBTX"]; ok && config.EmitRepo {
+				return fmt.Errorf("%s: generated type DBTX collides with %s", f.Desc.Path(), other)
+			}
+			if other, ok := taken["Register"]; ok && config.Driver == DriverPgx {
+				return fmt.Errorf("%s: generated function Register collides with %s", f.Desc.Path(), other)
+			}
+			for _, name := range config.functionalOptionNames() {
+				if other, ok := taken[name]; ok {
+					return fmt.Errorf("%s: generated identifier %s collides with %s", f.Desc.Path(), name, other)
+				}
+			}
+			for _, hook := range config.hookNames() {
+				if other, ok := taken[hook]; ok {
+					return fmt.Errorf("%s: generated variable %s collides with %s", f.Desc.Path(), hook, other)
+				}
+			}
+			generated[pkg] = taken
+		}
+		if other, ok := taken["DBTypeRegistry"]; ok && len(messages) > 0 {
+			return fmt.Errorf("%s: generated variable DBTypeRegistry collides with %s", f.Desc.Path(), other)
+		}
+		for _, m := range messages {
+			if err := checkMessageCollisions(m, config, taken); err != nil {
+				return err
+			}
+		}
+		for _, e := range enums {
+			if err := checkEnumCollisions(e, config, taken); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// hookNames returns the exported package-level variables the options
+// generate, which a message of the same name would clash with.
+func (c *GeneratorConfig) hookNames() []string {
+	var names []string
+	if c.MarshalHooks {
+		names = append(names, "Marshal", "Unmarshal")
+	}
+	if c.MetricsHooks {
+		names = append(names, "OnValue", "OnValueError", "OnScan", "OnScanError")
+	}
+	if c.OTel {
+		names = append(names, "Tracer")
+	}
+	if c.AutoScan {
+		names = append(names, "DetectFormat")
+	}
+	if c.EmitChecksum {
+		names = append(names, "ExpectedChecksum", "ErrChecksumMismatch")
+	}
+	return names
+}
+
+// addDeclaredNames records the package-level identifiers protoc-gen-go
+// declares for f.
+func addDeclaredNames(names map[string]string, f *protogen.File) {
+	var addEnums func(enums []*protogen.Enum)
+	addEnums = func(enums []*protogen.Enum) {
+		for _, e := range enums {
+			names[e.GoIdent.GoName] = "enum " + string(e.Desc.FullName())
+			for _, v := range e.Values {
+				names[v.GoIdent.GoName] = "enum value " + string(v.Desc.FullName())
+			}
+		}
+	}
+	var addMessages func(messages []*protogen.Message)
+	addMessages = func(messages []*protogen.Message) {
+		for _, m := range messages {
+			names[m.GoIdent.GoName] = "message " + string(m.Desc.FullName())
+			for _, field := range m.Fields {
+				// proto2 defaults are package-level constants
+				if field.Desc.HasDefault() {
+					names["Default_"+m.GoIdent.GoName+"_"+field.GoName] = "default of field " + string(field.Desc.FullName())
+				}
+			}
+			for _, x := range m.Extensions {
+				names["E_"+x.GoIdent.GoName] = "extension " + string(x.Desc.FullName())
+			}
+			for _, o := range m.Oneofs {
+				for _, field := range o.Fields {
+					names[field.GoIdent.GoName] = "oneof wrapper for field " + string(field.Desc.FullName())
+				}
+			}
+			addEnums(m.Enums)
+			addMessages(m.Messages)
+		}
+	}
+	addEnums(f.Enums)
+	addMessages(f.Messages)
+	// protoc-gen-go declares extensions with an E_ prefix
+	for _, x := range f.Extensions {
+		names["E_"+x.GoIdent.GoName] = "extension " + string(x.Desc.FullName())
+	}
+}
+
+// checkMessageCollisions checks the identifiers generated for m against the
+// names already taken in its output package, then records them there.
+func checkMessageCollisions(m *protogen.Message, config *GeneratorConfig, taken map[string]string) error {
+	wrapperName := config.wrapperName(m)
+	idents := []string{wrapperName, "New" + wrapperName, "Null" + wrapperName, "NewNull" + wrapperName, "Encode" + m.GoIdent.GoName + "Batch", config.valuesName(m), m.GoIdent.GoName + "Codec"}
+	if config.ORMs[ORMEnt] {
+		idents = append(idents, wrapperName+"Scanner")
+	}
+	if config.PostgresArray {
+		idents = append(idents, wrapperName+"Array")
+	}
+	if config.Builders {
+		idents = append(idents, config.builderName(m), "New"+config.builderName(m))
+	}
+	if config.EmitMigrators {
+		idents = append(idents, "Convert"+m.GoIdent.GoName)
+	}
+	if config.EmitColumns {
+		idents = append(idents, columnName(m))
+	}
+	if config.EmitDiff {
+		idents = append(idents, diffName(m))
+	}
+	if config.EmitRepo {
+		idents = append(idents, storeName(m))
+	}
+	if config.EmitJSONPaths {
+		paths, _ := jsonPathConstants(m, config)
+		idents = append(idents, paths...)
+	}
+	for _, ident := range idents {
+		if other, ok := taken[ident]; ok {
+			return fmt.Errorf("%s: generated identifier %s collides with %s", m.Desc.FullName(), ident, other)
+		}
+	}
+	for _, ident := range idents {
+		taken[ident] = "the code generated for message " + string(m.Desc.FullName())
+	}
+
+	// DatabaseValue is a method on the message itself, so it must not clash
+	// with the message's own fields and oneofs.
+	if config.OutPackage != "" {
+		return nil
+	}
+	for _, field := range m.Fields {
+		if field.GoName == "DatabaseValue" {
+			return fmt.Errorf("%s: generated method DatabaseValue collides with field %s", m.Desc.FullName(), field.Desc.Name())
+		}
+	}
+	for _, o := range m.Oneofs {
+		if o.GoName == "DatabaseValue" {
+			return fmt.Errorf("%s: generated method DatabaseValue collides with oneof %s", m.Desc.FullName(), o.Desc.Name())
+		}
+	}
+	return nil
+}
+
+// checkEnumCollisions checks the identifiers generated for e against the
+// names already taken in its output package, then records them there.
+func checkEnumCollisions(e *protogen.Enum, config *GeneratorConfig, taken map[string]string) error {
+	wrapperName := config.enumWrapperName(e)
+	idents := []string{wrapperName, "New" + wrapperName}
+	for _, ident := range idents {
+		if other, ok := taken[ident]; ok {
+			return fmt.Errorf("%s: generated identifier %s collides with %s", e.Desc.FullName(), ident, other)
+		}
+	}
+	for _, ident := range idents {
+		taken[ident] = "the code generated for enum " + string(e.Desc.FullName())
+	}
+	return nil
+}
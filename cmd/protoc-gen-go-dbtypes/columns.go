@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strconv"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// columnName returns the name of the ColumnInfo variable emit-columns
+// declares for m.
+func columnName(m *protogen.Message) string {
+	return m.GoIdent.GoName + "Column"
+}
+
+// generateColumnInfo emits the ColumnInfo type, once per package.
+func generateColumnInfo(g *protogen.GeneratedFile) {
+	g.P("// ColumnInfo describes how a wrapper stores its message, for query builders")
+	g.P("// and other tooling that needs it without reflection. The XxxColumn")
+	g.P("// variables hold the ColumnInfo of each message type.")
+	g.P("type ColumnInfo struct {")
+	g.P("	// Message is the full name of the stored message.")
+	g.P("	Message string")
+	g.P("	// GoType is the Go type of the message as %T prints it.")
+	g.P("	GoType string")
+	g.P("	// Format is the storage format: binary, json, text, cbor or msgpack.")
+	g.P("	Format string")
+	g.P("	// Compressed reports whether Value compresses the encoded message.")
+	g.P("	Compressed bool")
+	g.P("}")
+	g.P()
+}
+
+// generateColumn emits the ColumnInfo variable of m, a message of file
+// stored in format.
+func generateColumn(g *protogen.GeneratedFile, file *protogen.File, m *protogen.Message, config *GeneratorConfig, format Format) {
+	compressed := format == FormatBinary && config.Compression != CompressionNone
+	g.P("// ", columnName(m), " describes the column ", config.wrapperName(m), " stores.")
+	g.P("var ", columnName(m), " = ColumnInfo{")
+	g.P("	Message:    ", strconv.Quote(string(m.Desc.FullName())), ",")
+	g.P("	GoType:     ", strconv.Quote("*"+string(file.GoPackageName)+"."+m.GoIdent.GoName), ",")
+	g.P("	Format:     ", strconv.Quote(string(format)), ",")
+	g.P("	Compressed: ", compressed, ",")
+	g.P("}")
+	g.P()
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// generateDDLFiles writes a SQL comment block per output package that lists
+// the column type suggested for each wrapped message, for use as migration
+// documentation.
+func generateDDLFiles(gen *protogen.Plugin, config *GeneratorConfig) {
+	for _, pkg := range outputPackages(gen, config) {
+		generateDDLFile(gen, pkg, config)
+	}
+}
+
+func generateDDLFile(gen *protogen.Plugin, pkg *outputPackage, config *GeneratorConfig) {
+	g := gen.NewGeneratedFile(config.packageFilename(pkg, ".sql"), "")
+
+	g.P("-- Code generated by protoc-gen-go-dbtypes. DO NOT EDIT.")
+	g.P("-- package: ", string(pkg.path))
+	g.P("-- dialect: ", config.Dialect)
+	g.P("--")
+	g.P("-- Suggested column types for the wrapped messages of this package. Each")
+	g.P("-- column stores what the wrapper's Value returns.")
+	g.P("--")
+	if config.Dialect == DialectSQLite {
+		// A declared type such as json would get NUMERIC affinity
+		g.P("-- SQLite derives the affinity of a column from its declared type: declare")
+		g.P("-- them exactly as listed, so that blob columns get BLOB affinity and text")
+		g.P("-- columns TEXT affinity and values are kept as Value returns them.")
+		g.P("--")
+	}
+
+	rows := make([][3]string, 0, len(pkg.messages))
+	nameWidth, typeWidth := 0, 0
+	for _, m := range pkg.messages {
+		format := messageFormat(m, config)
+		column := format
+		if config.EncryptHooks {
+			// Ciphertext is binary whatever the format
+			column = FormatBinary
+		}
+		if config.base64Text(format) {
+			column = FormatText
+		}
+		row := [3]string{string(m.Desc.FullName()), column.columnType(config.Dialect), config.wrapperName(m) + ", " + ddlEncoding(format, config)}
+		nameWidth = max(nameWidth, len(row[0]))
+		typeWidth = max(typeWidth, len(row[1]))
+		rows = append(rows, row)
+	}
+	for _, row := range rows {
+		g.P(fmt.Sprintf("--   %-*s  %-*s  %s", nameWidth, row[0], typeWidth, row[1], row[2]))
+	}
+}
+
+// ddlEncoding describes how a message stored in format is encoded.
+func ddlEncoding(format Format, config *GeneratorConfig) string {
+	encoding := string(format)
+	if format == FormatBinary && config.Compression != CompressionNone {
+		encoding += ", " + string(config.Compression)
+	}
+	if config.EncryptHooks {
+		encoding += ", encrypted"
+	}
+	if config.base64Text(format) {
+		encoding += ", base64"
+	}
+	return encoding
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// diffName returns the name of the function emit-diff declares for m.
+func diffName(m *protogen.Message) string {
+	return "Diff" + m.GoIdent.GoName
+}
+
+// generateFieldChange emits the FieldChange type and the reflection helpers
+// the DiffXxx functions share, once per package.
+func generateFieldChange(g *protogen.GeneratedFile) {
+	g.P("// FieldChange describes a top-level field whose value differs between two")
+	g.P("// messages, as the DiffXxx functions report it, for example to log what an")
+	g.P("// update changed.")
+	g.P("type FieldChange struct {")
+	g.P("	// Field is the name of the field in the .proto file.")
+	g.P("	Field string")
+	g.P("	// Old and New are the values of the field before and after the change.")
+	g.P("	// They are nil where the message is nil or the field, having presence,")
+	g.P("	// is unset. Otherwise they are what protoreflect.Value.Interface returns,")
+	g.P("	// except that messages are proto.Message, lists []any and maps map[any]any.")
+	g.P("	Old, New any")
+	g.P("}")
+	g.P()
+	g.P("// dbtypesDiff returns the fields whose values differ between old and new, two")
+	g.P("// messages of the same type, in the order they are declared. A nil message")
+	g.P("// has no fields set.")
+	g.P("func dbtypesDiff(old, new ", protoreflectPackage.Ident("Message"), ") []FieldChange {")
+	g.P("	var changes []FieldChange")
+	g.P("	fields := old.Descriptor().Fields()")
+	g.P("	for i := 0; i < fields.Len(); i++ {")
+	g.P("		fd := fields.Get(i)")
+	g.P("		if old.Has(fd) == new.Has(fd) && old.Get(fd).Equal(new.Get(fd)) {")
+	g.P("			continue")
+	g.P("		}")
+	g.P("		changes = append(changes, FieldChange{")
+	g.P("			Field: string(fd.Name()),")
+	g.P("			Old:   dbtypesFieldValue(old, fd),")
+	g.P("			New:   dbtypesFieldValue(new, fd),")
+	g.P("		})")
+	g.P("	}")
+	g.P("	return changes")
+	g.P("}")
+	g.P()
+	g.P("// dbtypesFieldValue returns the value of fd in m as FieldChange reports it.")
+	g.P("func dbtypesFieldValue(m ", protoreflectPackage.Ident("Message"), ", fd ", protoreflectPackage.Ident("FieldDescriptor"), ") any {")
+	g.P("	if !m.IsValid() || (fd.HasPresence() && !m.Has(fd)) {")
+	g.P("		return nil")
+	g.P("	}")
+	g.P("	v := m.Get(fd)")
+	g.P("	switch {")
+	g.P("	case fd.IsList():")
+	g.P("		list := v.List()")
+	g.P("		out := make([]any, list.Len())")
+	g.P("		for i := range out {")
+	g.P("			out[i] = dbtypesInterface(fd, list.Get(i))")
+	g.P("		}")
+	g.P("		return out")
+	g.P("	case fd.IsMap():")
+	g.P("		out := make(map[any]any, v.Map().Len())")
+	g.P("		v.Map().Range(func(k ", protoreflectPackage.Ident("MapKey"), ", mv ", protoreflectPackage.Ident("Value"), ") bool {")
+	g.P("			out[k.Interface()] = dbtypesInterface(fd.MapValue(), mv)")
+	g.P("			return true")
+	g.P("		})")
+	g.P("		return out")
+	g.P("	}")
+	g.P("	return dbtypesInterface(fd, v)")
+	g.P("}")
+	g.P()
+	g.P("// dbtypesInterface returns a single value of fd, with messages as")
+	g.P("// proto.Message.")
+	g.P("func dbtypesInterface(fd ", protoreflectPackage.Ident("FieldDescriptor"), ", v ", protoreflectPackage.Ident("Value"), ") any {")
+	g.P("	if fd.Message() != nil {")
+	g.P("		return v.Message().Interface()")
+	g.P("	}")
+	g.P("	return v.Interface()")
+	g.P("}")
+	g.P()
+}
+
+// generateDiff emits the DiffXxx function of m.
+func generateDiff(g *protogen.GeneratedFile, m *protogen.Message) {
+	g.P("// ", diffName(m), " returns the top-level fields of ", m.Desc.FullName(), " whose values")
+	g.P("// differ between old and new, in the order they are declared. Either may be")
+	g.P("// nil, which is compared as a message with no fields set.")
+	g.P("func ", diffName(m), "(old, new *", m.GoIdent, ") []FieldChange {")
+	g.P("	return dbtypesDiff(old.ProtoReflect(), new.ProtoReflect())")
+	g.P("}")
+	g.P()
+}
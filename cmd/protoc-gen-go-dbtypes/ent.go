@@ -0,0 +1,84 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+const entFieldPackage = protogen.GoImportPath("entgo.io/ent/schema/field")
+
+// entBuildTag guards the ent integration so that only builds which opt in
+// depend on entgo.io/ent.
+const entBuildTag = "dbtypes_ent"
+
+// generateEntFile emits the ent value scanners for messages into a separate,
+// build-tagged file.
+func generateEntFile(gen *protogen.Plugin, file *protogen.File, messages []*protogen.Message, config *GeneratorConfig) {
+	filename := config.filename(file, "ent")
+	g := gen.NewGeneratedFile(filename, config.importPath(file))
+
+	g.P("// Code generated by protoc-gen-go-dbtypes. DO NOT EDIT.")
+	g.P("// source: ", file.Desc.Path())
+	g.P()
+	g.P("//go:build ", entBuildTag)
+	g.P()
+	g.P("package ", config.packageName(file))
+	g.P()
+
+	for _, m := range messages {
+		generateEntValueScanner(g, m, config)
+	}
+}
+
+func generateEntValueScanner(g *protogen.GeneratedFile, m *protogen.Message, config *GeneratorConfig) {
+	typeName := m.GoIdent.GoName
+	wrapperName := config.wrapperName(m)
+	scannerName := wrapperName + "Scanner"
+
+	// Type definition
+	g.P("// ", scannerName, " stores *", typeName, " in ent fields through ", wrapperName, ".")
+	g.P("// It implements field.TypeValueScanner[*", typeName, "]:")
+	g.P("//")
+	g.P("//	field.Bytes(\"name\").GoType(&", typeName, "{}).ValueScanner(", scannerName, "{})")
+	g.P("type ", scannerName, " struct{}")
+	g.P()
+
+	// Value method
+	g.P("// Value implements field.TypeValueScanner.")
+	g.P("func (", scannerName, ") Value(msg *", m.GoIdent, ") (", driverPackage.Ident("Value"), ", error) {")
+	g.P("	if msg == nil {")
+	g.P("		return nil, nil")
+	g.P("	}")
+	g.P("	return New", wrapperName, "(msg).Value()")
+	g.P("}")
+	g.P()
+
+	// ScanValue method
+	g.P("// ScanValue implements field.TypeValueScanner.")
+	g.P("func (", scannerName, ") ScanValue() ", entFieldPackage.Ident("ValueScanner"), " {")
+	g.P("	return &", sqlPackage.Ident("NullString"), "{}")
+	g.P("}")
+	g.P()
+
+	// FromValue method
+	g.P("// FromValue implements field.TypeValueScanner. It returns nil for NULL columns.")
+	g.P("func (", scannerName, ") FromValue(v ", driverPackage.Ident("Value"), ") (*", m.GoIdent, ", error) {")
+	g.P("	s, ok := v.(*", sqlPackage.Ident("NullString"), ")")
+	g.P("	if !ok {")
+	g.P("		return nil, ", fmtPackage.Ident("Errorf"), `("dbtypes: unexpected input for FromValue: %T", v)`)
+	g.P("	}")
+	g.P("	if !s.Valid {")
+	g.P("		return nil, nil")
+	g.P("	}")
+	g.P("	x := &", wrapperName, "{}")
+	// Base64 text is only decoded from strings
+	if config.base64Text(messageFormat(m, config)) {
+		g.P("	if err := x.Scan(s.String); err != nil {")
+	} else {
+		g.P("	if err := x.Scan([]byte(s.String)); err != nil {")
+	}
+	g.P("		return nil, err")
+	g.P("	}")
+	g.P("	return x.Unwrap(), nil")
+	g.P("}")
+	g.P()
+}
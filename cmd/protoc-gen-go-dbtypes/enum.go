@@ -0,0 +1,110 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// generateEnumWrapper emits a wrapper storing e as its number in an integer
+// column. Numbers without a named value are kept, as proto3 open enums keep
+// them, so that rows written by a newer schema survive a round-trip. Closed
+// enums, such as those of proto2 files, reject them as the binary decoder
+// does.
+func generateEnumWrapper(g *protogen.GeneratedFile, e *protogen.Enum, config *GeneratorConfig) {
+	typeName := e.GoIdent.GoName
+	wrapperName := config.enumWrapperName(e)
+
+	// checkClosed emits the check that n names a value of a closed enum
+	checkClosed := func(indent string) {
+		if !e.Desc.IsClosed() {
+			return
+		}
+		g.P(indent, "if _, ok := ", e.GoIdent.GoImportPath.Ident(typeName+"_name"), "[int32(n)]; !ok {")
+		g.P(indent, "	return ", fmtPackage.Ident("Errorf"), `("dbtypes: `, typeName, `: %d is not a value of the closed enum", n)`)
+		g.P(indent, "}")
+	}
+
+	// Type definition
+	generateWrapperDoc(g, wrapperName, e.Desc, e.Comments.Leading, "its number")
+	g.P("type ", wrapperName, " struct {")
+	g.P("	Enum ", e.GoIdent)
+	g.P("}")
+	g.P()
+
+	// Constructor
+	g.P("// New", wrapperName, " creates a new ", wrapperName, " wrapper.")
+	g.P("func New", wrapperName, "(e ", e.GoIdent, ") *", wrapperName, " {")
+	g.P("	return &", wrapperName, "{Enum: e}")
+	g.P("}")
+	g.P()
+
+	// Scan method
+	g.P("// Scan implements sql.Scanner. It accepts the enum number as an integer or")
+	g.P("// in decimal text form; NULL scans as the zero value.")
+	g.P("func (x *", wrapperName, ") Scan(src any) error {")
+	g.P("	var n int64")
+	g.P("	switch v := src.(type) {")
+	g.P("	case nil:")
+	g.P("	case int64:")
+	g.P("		n = v")
+	g.P("	case int32:")
+	g.P("		n = int64(v)")
+	g.P("	case []byte:")
+	g.P("		return x.parse(string(v))")
+	g.P("	case string:")
+	g.P("		return x.parse(v)")
+	g.P("	default:")
+	g.P("		return ", fmtPackage.Ident("Errorf"), `("dbtypes: `, typeName, `: %w: %T", ErrInvalidScanType, src)`)
+	g.P("	}")
+	g.P("	if n < ", mathPackage.Ident("MinInt32"), " || n > ", mathPackage.Ident("MaxInt32"), " {")
+	g.P("		return ", fmtPackage.Ident("Errorf"), `("dbtypes: `, typeName, `: enum number %d out of range", n)`)
+	g.P("	}")
+	checkClosed("	")
+	g.P("	x.Enum = ", e.GoIdent, "(n)")
+	g.P("	return nil")
+	g.P("}")
+	g.P()
+	g.P("// parse sets the enum from the decimal text form of its number.")
+	g.P("func (x *", wrapperName, ") parse(s string) error {")
+	g.P("	n, err := ", strconvPackage.Ident("ParseInt"), "(s, 10, 32)")
+	g.P("	if err != nil {")
+	g.P("		return ", fmtPackage.Ident("Errorf"), `("dbtypes: `, typeName, `: %w", err)`)
+	g.P("	}")
+	checkClosed("	")
+	g.P("	x.Enum = ", e.GoIdent, "(n)")
+	g.P("	return nil")
+	g.P("}")
+	g.P()
+
+	// Value method
+	g.P("// Value implements driver.Valuer. It returns the enum number as an int64,")
+	g.P("// the integer type drivers accept.")
+	g.P("func (x ", wrapperName, ") Value() (", driverPackage.Ident("Value"), ", error) {")
+	g.P("	return int64(x.Enum), nil")
+	g.P("}")
+	g.P()
+	generateSQLAssertions(g, wrapperName)
+
+	// Unwrap helper
+	g.P("// Unwrap returns the underlying enum value.")
+	g.P("func (x ", wrapperName, ") Unwrap() ", e.GoIdent, " {")
+	g.P("	return x.Enum")
+	g.P("}")
+	g.P()
+
+	// String method for logs and test failures
+	g.P("// String returns the name of the enum value, or its number if it has none.")
+	g.P("func (x ", wrapperName, ") String() string {")
+	g.P("	return x.Enum.String()")
+	g.P("}")
+	g.P()
+
+	// DatabaseValue method on the enum, which can only be declared in the
+	// enum's own package
+	if config.OutPackage == "" {
+		g.P("// DatabaseValue returns a database-compatible wrapper for this enum value.")
+		g.P("func (x ", typeName, ") DatabaseValue() *", wrapperName, " {")
+		g.P("	return New", wrapperName, "(x)")
+		g.P("}")
+		g.P()
+	}
+}
@@ -1,56 +1,867 @@
 package main
 
 import (
+	"fmt"
+	"go/token"
+	"path"
+	"regexp"
+	"strings"
+
 	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	dbtypespb "github.com/cadenya/protoc-gen-go-dbtypes/gen/go/dbtypes"
+)
+
+const (
+	sqlPackage           = protogen.GoImportPath("database/sql")
+	driverPackage        = protogen.GoImportPath("database/sql/driver")
+	protoPackage         = protogen.GoImportPath("google.golang.org/protobuf/proto")
+	protojsonPackage     = protogen.GoImportPath("google.golang.org/protobuf/encoding/protojson")
+	prototextPackage     = protogen.GoImportPath("google.golang.org/protobuf/encoding/prototext")
+	fmtPackage           = protogen.GoImportPath("fmt")
+	bytesPackage         = protogen.GoImportPath("bytes")
+	gzipPackage          = protogen.GoImportPath("compress/gzip")
+	ioPackage            = protogen.GoImportPath("io")
+	encodingPackage      = protogen.GoImportPath("encoding")
+	gobPackage           = protogen.GoImportPath("encoding/gob")
+	jsonPackage          = protogen.GoImportPath("encoding/json")
+	syncPackage          = protogen.GoImportPath("sync")
+	contextPackage       = protogen.GoImportPath("context")
+	timePackage          = protogen.GoImportPath("time")
+	errorsPackage        = protogen.GoImportPath("errors")
+	reflectPackage       = protogen.GoImportPath("reflect")
+	slicesPackage        = protogen.GoImportPath("slices")
+	strconvPackage       = protogen.GoImportPath("strconv")
+	mathPackage          = protogen.GoImportPath("math")
+	protoregistryPackage = protogen.GoImportPath("google.golang.org/protobuf/reflect/protoregistry")
+	protoreflectPackage  = protogen.GoImportPath("google.golang.org/protobuf/reflect/protoreflect")
+	utf8Package          = protogen.GoImportPath("unicode/utf8")
+)
+
+// Format is the serialization used for values stored in the database. Every
+// format must keep the selected case of a oneof, even one set to its zero
+// value; the fixture suite checks this for each format.
+type Format string
+
+const (
+	// FormatBinary stores messages in the protobuf wire format.
+	FormatBinary Format = "binary"
+	// FormatJSON stores messages as protojson.
+	FormatJSON Format = "json"
+	// FormatText stores messages as prototext.
+	FormatText Format = "text"
+	// FormatCBOR stores messages as CBOR maps keyed by field name, encoded by
+	// the dbtypes runtime package.
+	FormatCBOR Format = "cbor"
+	// FormatMsgpack stores messages as MessagePack maps keyed by field name,
+	// encoded by the dbtypes runtime package.
+	FormatMsgpack Format = "msgpack"
+)
+
+func parseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case "":
+		return FormatBinary, nil
+	case FormatBinary, FormatJSON, FormatText, FormatCBOR, FormatMsgpack:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown format %q (want binary, json, text, cbor or msgpack)", s)
+}
+
+// marshalIdent returns the function that encodes a message in the format.
+func (f Format) marshalIdent() protogen.GoIdent {
+	switch f {
+	case FormatJSON:
+		return protojsonPackage.Ident("Marshal")
+	case FormatText:
+		return prototextPackage.Ident("Marshal")
+	case FormatCBOR:
+		return dbtypesPackage.Ident("MarshalCBOR")
+	case FormatMsgpack:
+		return dbtypesPackage.Ident("MarshalMsgpack")
+	}
+	return protoPackage.Ident("Marshal")
+}
+
+// unmarshalIdent returns the function that decodes a message in the format.
+func (f Format) unmarshalIdent() protogen.GoIdent {
+	switch f {
+	case FormatJSON:
+		return protojsonPackage.Ident("Unmarshal")
+	case FormatText:
+		return prototextPackage.Ident("Unmarshal")
+	case FormatCBOR:
+		return dbtypesPackage.Ident("UnmarshalCBOR")
+	case FormatMsgpack:
+		return dbtypesPackage.Ident("UnmarshalMsgpack")
+	}
+	return protoPackage.Ident("Unmarshal")
+}
+
+// valueString reports whether Value returns a string rather than []byte for
+// messages stored in format.
+func (c *GeneratorConfig) valueString(format Format) bool {
+	return c.ValueAsString && format.isText()
+}
+
+// storageDescription describes how Value stores messages in format, for the
+// doc comments of wrappers.
+func (c *GeneratorConfig) storageDescription(format Format) string {
+	switch format {
+	case FormatJSON:
+		return "protojson"
+	case FormatText:
+		return "prototext"
+	case FormatCBOR:
+		return "a CBOR map"
+	case FormatMsgpack:
+		return "a MessagePack map"
+	}
+	switch c.Compression {
+	case CompressionGzip:
+		return "gzip-compressed protobuf binary"
+	case CompressionZstd:
+		return "zstd-compressed protobuf binary"
+	}
+	return "protobuf binary"
+}
+
+// generateWrapperDoc emits the doc comment of a wrapper of the type desc,
+// followed by the leading comment of the type in its proto file, if any.
+func generateWrapperDoc(g *protogen.GeneratedFile, wrapperName string, desc protoreflect.Descriptor, leading protogen.Comments, storage string) {
+	g.P("// ", wrapperName, " is a database-serializable wrapper around ", desc.FullName(), ".")
+	g.P("// Value stores it as ", storage, ".")
+	if comment := strings.TrimSuffix(leading.String(), "\n"); comment != "" {
+		g.P("//")
+		g.P(comment)
+	}
+}
+
+// isText reports whether the format is stored as text rather than bytes.
+func (f Format) isText() bool {
+	return f == FormatJSON || f == FormatText
+}
+
+// columnType returns the column type that suits values stored in the format
+// on dialect.
+func (f Format) columnType(dialect Dialect) string {
+	switch f {
+	case FormatJSON:
+		switch dialect {
+		case DialectPostgres:
+			return "jsonb"
+		case DialectMySQL:
+			return "json"
+		case DialectSQLServer:
+			return "nvarchar(max)"
+		}
+		return "text"
+	case FormatText:
+		switch dialect {
+		case DialectMySQL:
+			return "longtext"
+		case DialectSQLServer:
+			return "nvarchar(max)"
+		}
+		return "text"
+	}
+	switch dialect {
+	case DialectPostgres:
+		return "bytea"
+	case DialectSQLServer:
+		return "varbinary(max)"
+	}
+	return "blob"
+}
+
+// Dialect is the SQL database column types are suggested for. The names
+// match those of the GORM dialectors.
+type Dialect string
+
+const (
+	// DialectPostgres suggests PostgreSQL types such as bytea and jsonb.
+	DialectPostgres Dialect = "postgres"
+	// DialectMySQL suggests MySQL types such as json and longtext.
+	DialectMySQL Dialect = "mysql"
+	// DialectSQLite suggests the generic blob and text types.
+	DialectSQLite Dialect = "sqlite"
+	// DialectSQLServer suggests SQL Server types such as varbinary(max).
+	DialectSQLServer Dialect = "sqlserver"
+)
+
+func parseDialect(s string) (Dialect, error) {
+	switch d := Dialect(s); d {
+	case DialectPostgres, DialectMySQL, DialectSQLite, DialectSQLServer:
+		return d, nil
+	}
+	return "", fmt.Errorf("unknown dialect %q (want postgres, mysql, sqlite or sqlserver)", s)
+}
+
+// Compression is the algorithm applied to messages stored in the binary
+// format. JSON-format messages are never compressed so that they stay valid
+// in JSON columns.
+type Compression string
+
+const (
+	// CompressionNone stores serialized messages as-is.
+	CompressionNone Compression = ""
+	// CompressionGzip stores serialized messages gzip-compressed.
+	CompressionGzip Compression = "gzip"
+	// CompressionZstd stores serialized messages zstd-compressed, optionally
+	// with a dictionary.
+	CompressionZstd Compression = "zstd"
+)
+
+func parseCompression(s string) (Compression, error) {
+	switch c := Compression(s); c {
+	case CompressionNone, CompressionGzip, CompressionZstd:
+		return c, nil
+	}
+	return "", fmt.Errorf("unknown compression %q (want gzip or zstd)", s)
+}
+
+// Nested selects whether messages declared inside other messages are
+// wrapped.
+type Nested string
+
+const (
+	// NestedSkip wraps top-level messages only.
+	NestedSkip Nested = "skip"
+	// NestedInclude also wraps nested messages such as Container_Item.
+	NestedInclude Nested = "include"
+)
+
+func parseNested(s string) (Nested, error) {
+	switch n := Nested(s); n {
+	case NestedSkip, NestedInclude:
+		return n, nil
+	}
+	return "", fmt.Errorf("unknown nested %q (want skip or include)", s)
+}
+
+// NilMessage selects what Value stores for a wrapper holding a nil message.
+// A wrapper without a ProtoValue is always NULL.
+type NilMessage string
+
+const (
+	// NilMessageEmpty stores a nil message as an empty one.
+	NilMessageEmpty NilMessage = "empty"
+	// NilMessageNull stores a nil message as NULL, like a nil wrapper.
+	NilMessageNull NilMessage = "null"
+)
+
+func parseNilMessage(s string) (NilMessage, error) {
+	switch n := NilMessage(s); n {
+	case NilMessageEmpty, NilMessageNull:
+		return n, nil
+	}
+	return "", fmt.Errorf("unknown nil-message %q (want empty or null)", s)
+}
+
+// ORM is an ORM integration generated alongside the wrappers.
+type ORM string
+
+const (
+	// ORMNone generates no ORM integration.
+	ORMNone ORM = ""
+	// ORMGorm generates GORM column type and valuer methods.
+	ORMGorm ORM = "gorm"
+	// ORMEnt generates ent field.TypeValueScanner implementations.
+	ORMEnt ORM = "ent"
 )
 
+func parseORM(s string) (ORM, error) {
+	switch o := ORM(s); o {
+	case ORMNone, ORMGorm, ORMEnt:
+		return o, nil
+	}
+	return "", fmt.Errorf("unknown orm %q (want gorm or ent)", s)
+}
+
+// parseORMs parses the values of a repeated orm option into a set.
+func parseORMs(values []string) (map[ORM]bool, error) {
+	orms := make(map[ORM]bool)
+	for _, s := range values {
+		o, err := parseORM(strings.TrimSpace(s))
+		if err != nil {
+			return nil, err
+		}
+		if o != ORMNone {
+			orms[o] = true
+		}
+	}
+	return orms, nil
+}
+
+// Driver is a database driver whose native type system the wrapped messages
+// are registered with.
+type Driver string
+
 const (
-	driverPackage = protogen.GoImportPath("database/sql/driver")
-	protoPackage  = protogen.GoImportPath("google.golang.org/protobuf/proto")
-	fmtPackage    = protogen.GoImportPath("fmt")
+	// DriverNone relies on database/sql interfaces only.
+	DriverNone Driver = ""
+	// DriverPgx generates a Register function adding pgtype codecs for the
+	// messages to a pgx type map.
+	DriverPgx Driver = "pgx"
 )
 
+func parseDriver(s string) (Driver, error) {
+	switch d := Driver(s); d {
+	case DriverNone, DriverPgx:
+		return d, nil
+	}
+	return "", fmt.Errorf("unknown driver %q (want pgx)", s)
+}
+
+// parseFileSuffix checks that s names Go source files.
+func parseFileSuffix(s string) (string, error) {
+	if !strings.HasSuffix(s, ".go") || strings.ContainsAny(s, "/\\") {
+		return "", fmt.Errorf("invalid filename-suffix %q (want a suffix ending in .go)", s)
+	}
+	return s, nil
+}
+
+// parseOutPackage checks that s can name both a directory and a Go package.
+func parseOutPackage(s string) (string, error) {
+	if s != "" && (!token.IsIdentifier(s) || s == "_") {
+		return "", fmt.Errorf("invalid out-package %q (want a Go package name)", s)
+	}
+	return s, nil
+}
+
+// parseTypeSuffix checks that s can be appended to a message name to form
+// the wrapper's Go identifier.
+func parseTypeSuffix(s string) (string, error) {
+	if s == "" || !token.IsIdentifier("_"+s) {
+		return "", fmt.Errorf("invalid type-suffix %q (want a non-empty Go identifier suffix)", s)
+	}
+	return s, nil
+}
+
+// messageFormat returns the format for m, honoring the (dbtypes.format)
+// message option and falling back to the configured default.
+func messageFormat(m *protogen.Message, config *GeneratorConfig) Format {
+	opts, ok := m.Desc.Options().(*descriptorpb.MessageOptions)
+	if !ok || opts == nil {
+		return config.Format
+	}
+	switch proto.GetExtension(opts, dbtypespb.E_Format).(dbtypespb.Format) {
+	case dbtypespb.Format_BINARY:
+		return FormatBinary
+	case dbtypespb.Format_JSON:
+		return FormatJSON
+	case dbtypespb.Format_TEXT:
+		return FormatText
+	case dbtypespb.Format_CBOR:
+		return FormatCBOR
+	case dbtypespb.Format_MSGPACK:
+		return FormatMsgpack
+	}
+	return config.Format
+}
+
+// collectMessageFormats records in config.MessageFormats the formats that
+// (dbtypes.format) options select besides the default, so that their codecs
+// are generated.
+func collectMessageFormats(gen *protogen.Plugin, config *GeneratorConfig) error {
+	config.MessageFormats = make(map[Format]bool)
+	for _, f := range gen.Files {
+		if !f.Generate {
+			continue
+		}
+		for _, m := range wrappedMessages(f, config) {
+			format := messageFormat(m, config)
+			if format == config.Format {
+				continue
+			}
+			if config.Generic && (format == FormatCBOR || format == FormatMsgpack) {
+				// The runtime package has no generic type for them
+				return fmt.Errorf("%s: generic=true cannot store %s as %s; drop its (dbtypes.format) option", f.Desc.Path(), m.Desc.FullName(), format)
+			}
+			config.MessageFormats[format] = true
+		}
+	}
+	return nil
+}
+
 // GeneratorConfig holds configuration options for the generator.
 type GeneratorConfig struct {
-	ExcludedTypes map[string]bool
-	OnlyPackage   string
+	ExcludedTypes       map[string]bool
+	IncludeRegex        *regexp.Regexp
+	RequireOptIn        bool
+	Nested              Nested
+	TypeSuffix          string
+	FileSuffix          string
+	OutPackage          string
+	OnlyPackages        map[string]bool
+	Format              Format
+	MessageFormats      map[Format]bool // set by collectMessageFormats
+	Compression         Compression
+	ZstdDict            []byte
+	EncryptHooks        bool
+	MarshalHooks        bool
+	MetricsHooks        bool
+	OTel                bool
+	EmptyAsNull         bool
+	NilMessage          NilMessage
+	Deterministic       bool
+	SortRepeated        bool
+	EmitChecksum        bool
+	ChecksumAlgorithm   ChecksumAlgorithm
+	MaxScanSize         int
+	DiscardUnknown      bool
+	AllowPartial        bool
+	PreserveUnknown     bool
+	ValueAsString       bool
+	Base64Text          bool
+	JSONEmitUnpopulated bool
+	JSONUseProtoNames   bool
+	Generic             bool
+	Validate            bool
+	PostgresArray       bool
+	Driver              Driver
+	EmitDDL             bool
+	Dialect             Dialect
+	EmitSqlc            bool
+	EmitJSONSchema      bool
+	EmitBSON            bool
+	EmitJSONPaths       bool
+	EmitMigrators       bool
+	Builders            bool
+	ValueReceiver       bool
+	AutoScan            bool
+	EmitColumns         bool
+	EmitDiff            bool
+	GlobalRegistry      bool
+	VTProto             bool
+	ScanNullAsEmpty     bool
+	EmitRepo            bool
+	FunctionalOptions   bool
+	MigrateFrom         Format
+	MigrateTo           Format
+	Enums               bool
+	ORMs                map[ORM]bool
 }
 
-func generateFile(gen *protogen.Plugin, file *protogen.File, config *GeneratorConfig, generatedPackages map[protogen.GoImportPath]bool) error {
-	// Skip if package filter is set and doesn't match
-	if config.OnlyPackage != "" && string(file.Desc.Package()) != config.OnlyPackage {
+// excluded reports whether the exclude option names the type with Go name
+// goName and descriptor desc, by its Go name ("Container_Item"), its full
+// proto name ("test.v1.Container.Item") or its name within the proto package
+// ("Container.Item").
+func (c *GeneratorConfig) excluded(goName string, desc protoreflect.Descriptor) bool {
+	fullName := string(desc.FullName())
+	relative := strings.TrimPrefix(fullName, string(desc.ParentFile().Package())+".")
+	return c.ExcludedTypes[goName] || c.ExcludedTypes[fullName] || c.ExcludedTypes[relative]
+}
+
+// packageSelected reports whether the proto package of file passes the
+// package filter, which selects every package when empty.
+func (c *GeneratorConfig) packageSelected(file *protogen.File) bool {
+	return len(c.OnlyPackages) == 0 || c.OnlyPackages[string(file.Desc.Package())]
+}
+
+// wrapperName returns the name of the wrapper type generated for m.
+func (c *GeneratorConfig) wrapperName(m *protogen.Message) string {
+	return m.GoIdent.GoName + c.TypeSuffix
+}
+
+// enumWrapperName returns the name of the wrapper type generated for e.
+func (c *GeneratorConfig) enumWrapperName(e *protogen.Enum) string {
+	return e.GoIdent.GoName + c.TypeSuffix
+}
+
+// filename returns the output path for the file generated from file. A
+// non-empty variant names an integration file: it is inserted before the
+// first dot of the suffix, so "_dbtypes.pb.go" becomes "_dbtypes_gorm.pb.go"
+// and ".dbv.go" becomes "_gorm.dbv.go".
+func (c *GeneratorConfig) filename(file *protogen.File, variant string) string {
+	prefix := file.GeneratedFilenamePrefix
+	if c.OutPackage != "" {
+		prefix = path.Join(path.Dir(prefix), c.OutPackage, path.Base(prefix))
+	}
+	if variant == "" {
+		return prefix + c.FileSuffix
+	}
+	stem, ext := c.FileSuffix, ""
+	if i := strings.Index(stem, "."); i >= 0 {
+		stem, ext = stem[:i], stem[i:]
+	}
+	return prefix + stem + "_" + variant + ext
+}
+
+// packageFilename returns the output path of a file generated once for pkg.
+// It is named after the Go package and the stem of the suffix, so
+// "_dbtypes.pb.go" and ext ".sql" give "examplev1_dbtypes.sql".
+func (c *GeneratorConfig) packageFilename(pkg *outputPackage, ext string) string {
+	stem := c.FileSuffix
+	if i := strings.Index(stem, "."); i >= 0 {
+		stem = stem[:i]
+	}
+	return path.Join(pkg.dir, string(pkg.name)+stem+ext)
+}
+
+// importPath returns the import path of the package the wrappers for file
+// are generated into.
+func (c *GeneratorConfig) importPath(file *protogen.File) protogen.GoImportPath {
+	if c.OutPackage == "" {
+		return file.GoImportPath
+	}
+	return protogen.GoImportPath(path.Join(string(file.GoImportPath), c.OutPackage))
+}
+
+// packageName returns the name of the package the wrappers for file are
+// generated into.
+func (c *GeneratorConfig) packageName(file *protogen.File) protogen.GoPackageName {
+	if c.OutPackage == "" {
+		return file.GoPackageName
+	}
+	return protogen.GoPackageName(c.OutPackage)
+}
+
+// marshalFunc returns the function Value uses to encode a message stored in
+// format.
+func (c *GeneratorConfig) marshalFunc(format Format) any {
+	if format == FormatBinary {
+		switch c.Compression {
+		case CompressionGzip:
+			return "dbtypesMarshalGzip"
+		case CompressionZstd:
+			return "dbtypesMarshalZstd"
+		}
+	}
+	if c.hooked(format) {
+		return "Marshal"
+	}
+	return c.formatMarshalFunc(format)
+}
+
+// formatMarshalFunc returns the encoder of format itself, which the Marshal
+// hook defaults to.
+func (c *GeneratorConfig) formatMarshalFunc(format Format) any {
+	switch format {
+	case FormatJSON:
+		return "dbtypesMarshalJSON"
+	case FormatBinary:
+		return c.binaryMarshalFunc()
+	}
+	if !c.AllowPartial {
+		return format.marshalIdent()
+	}
+	switch format {
+	case FormatText:
+		return "dbtypesMarshalText"
+	case FormatCBOR:
+		return "dbtypesMarshalCBOR"
+	}
+	return "dbtypesMarshalMsgpack"
+}
+
+// withPartial adds AllowPartial to the fields of a generated marshal or
+// unmarshal options literal when allow-partial is set.
+func (c *GeneratorConfig) withPartial(opts string) string {
+	switch {
+	case !c.AllowPartial:
+		return opts
+	case opts == "":
+		return "AllowPartial: true"
+	}
+	return opts + ", AllowPartial: true"
+}
+
+// hooked reports whether messages stored in format go through the Marshal
+// and Unmarshal hooks, which replace the default format only.
+func (c *GeneratorConfig) hooked(format Format) bool {
+	return c.MarshalHooks && format == c.Format
+}
+
+// plainWireFormat reports whether Value stores messages in format as nothing
+// but their wire format, so that batches can be marshaled directly.
+func (c *GeneratorConfig) plainWireFormat(format Format) bool {
+	return format == FormatBinary && c.Compression == CompressionNone && !c.EncryptHooks && !c.EmptyAsNull && !c.Validate && !c.Base64Text && !c.SortRepeated && !c.hooked(format)
+}
+
+// binaryMarshalFunc returns the function that produces the uncompressed wire
+// format. protojson already orders map keys, so only the wire format needs
+// the deterministic variant.
+func (c *GeneratorConfig) binaryMarshalFunc() any {
+	if c.VTProto {
+		return "dbtypesMarshalVT"
+	}
+	if c.Deterministic {
+		return "dbtypesMarshalDeterministic"
+	}
+	if c.AllowPartial {
+		return "dbtypesMarshalBinary"
+	}
+	return FormatBinary.marshalIdent()
+}
+
+// unmarshalFunc returns the function Scan uses to decode a message stored in
+// format.
+func (c *GeneratorConfig) unmarshalFunc(format Format) any {
+	if format == FormatBinary {
+		switch c.Compression {
+		case CompressionGzip:
+			return "dbtypesUnmarshalGzip"
+		case CompressionZstd:
+			return "dbtypesUnmarshalZstd"
+		}
+	}
+	return c.plainUnmarshalFunc(format)
+}
+
+// plainUnmarshalFunc returns the function that decodes format as written by
+// its marshaler, before any compression.
+func (c *GeneratorConfig) plainUnmarshalFunc(format Format) any {
+	if c.hooked(format) {
+		return "Unmarshal"
+	}
+	return c.formatUnmarshalFunc(format)
+}
+
+// formatUnmarshalFunc returns the decoder of format itself, which the
+// Unmarshal hook defaults to. With discard-unknown or allow-partial it is one
+// of the generated dbtypesUnmarshal variables.
+func (c *GeneratorConfig) formatUnmarshalFunc(format Format) any {
+	if format == FormatJSON {
+		return "dbtypesUnmarshalJSON"
+	}
+	if format == FormatBinary && c.VTProto {
+		return "dbtypesUnmarshalVT"
+	}
+	if !c.DiscardUnknown && !c.AllowPartial {
+		return format.unmarshalIdent()
+	}
+	switch format {
+	case FormatText:
+		return "dbtypesUnmarshalText"
+	case FormatCBOR:
+		return "dbtypesUnmarshalCBOR"
+	case FormatMsgpack:
+		return "dbtypesUnmarshalMsgpack"
+	}
+	return "dbtypesUnmarshalBinary"
+}
+
+// packageState records what has been generated into an output package.
+type packageState struct {
+	// declared is set once the package-level declarations such as
+	// ProtoValue have been generated.
+	declared bool
+	// wrapped is set once a file with message wrappers, and so the
+	// per-package declarations of the integration files, has been generated.
+	wrapped bool
+	// redacting is set once dbtypesRedactCodec has been generated.
+	redacting bool
+}
+
+func generateFile(gen *protogen.Plugin, file *protogen.File, config *GeneratorConfig, generatedPackages map[protogen.GoImportPath]*packageState) error {
+	messages := wrappedMessages(file, config)
+	enums := wrappedEnums(file, config)
+	if len(messages) == 0 && len(enums) == 0 {
 		return nil
 	}
 
-	// Filter messages that should have wrappers generated
-	var messages []*protogen.Message
-	for _, m := range file.Messages {
-		if shouldGenerateWrapper(m, config) {
-			messages = append(messages, m)
+	filename := config.filename(file, "")
+	g := gen.NewGeneratedFile(filename, config.importPath(file))
+
+	generateHeader(g, file, config)
+
+	// Package-level declarations are only generated into the first file of
+	// each package
+	state := generatedPackages[config.importPath(file)]
+	if state == nil {
+		state = &packageState{}
+		generatedPackages[config.importPath(file)] = state
+	}
+	firstInPackage := !state.declared
+	firstWrapped := !state.wrapped && len(messages) > 0
+	state.declared = true
+	state.wrapped = state.wrapped || len(messages) > 0
+
+	if config.Generic {
+		if firstInPackage {
+			generateGenericPackage(g)
+		}
+		for _, m := range messages {
+			generateGenericWrapper(g, m, config, messageFormat(m, config))
+		}
+	} else {
+		if firstInPackage {
+			generateProtoValueType(g, config)
+		}
+		for _, m := range messages {
+			if !state.redacting && len(redactedFields(m)) > 0 {
+				generateRedactCodec(g)
+				state.redacting = true
+			}
+		}
+
+		// Generate wrapper for each message
+		for _, m := range messages {
+			generateMessageWrapper(g, m, config, messageFormat(m, config))
+		}
+	}
+	if config.EmitColumns {
+		if firstWrapped {
+			generateColumnInfo(g)
+		}
+		for _, m := range messages {
+			generateColumn(g, file, m, config, messageFormat(m, config))
+		}
+	}
+	if config.EmitDiff {
+		if firstWrapped {
+			generateFieldChange(g)
+		}
+		for _, m := range messages {
+			generateDiff(g, m)
+		}
+	}
+	if config.EmitRepo {
+		if firstWrapped {
+			generateDBTX(g)
+		}
+		for _, m := range messages {
+			generateStore(g, m, config)
+		}
+	}
+	if config.EmitJSONPaths {
+		for _, m := range messages {
+			generateJSONPaths(g, m, config)
+		}
+	}
+	if config.EmitMigrators {
+		for _, m := range messages {
+			generateMigrator(g, m, config)
+		}
+	}
+	if config.Builders {
+		for _, m := range messages {
+			generateBuilder(g, m, config)
 		}
 	}
+	for _, e := range enums {
+		generateEnumWrapper(g, e, config)
+	}
 
 	if len(messages) == 0 {
 		return nil
 	}
+	if firstWrapped {
+		generateRegistry(g, packageMessages(gen, config, config.importPath(file)), config)
+	}
+	if config.ORMs[ORMGorm] {
+		generateGormFile(gen, file, messages, config)
+	}
+	if config.ORMs[ORMEnt] {
+		generateEntFile(gen, file, messages, config)
+	}
+	if config.Validate {
+		generateValidateFile(gen, file, messages, config)
+	}
+	if config.OTel && firstWrapped {
+		generateOTelFile(gen, file, config)
+	}
+	if config.PostgresArray {
+		generatePQFile(gen, file, messages, config)
+	}
+	if config.Driver == DriverPgx {
+		generatePgxFile(gen, file, messages, config, firstWrapped)
+	}
+	if config.EmitBSON {
+		generateBSONFile(gen, file, messages, config, firstWrapped)
+	}
 
-	filename := file.GeneratedFilenamePrefix + "_dbtypes.pb.go"
-	g := gen.NewGeneratedFile(filename, file.GoImportPath)
+	return nil
+}
 
-	generateHeader(g, file)
+// outputPackage collects the wrapped messages of one output package, for the
+// files that are generated per package rather than per proto file.
+type outputPackage struct {
+	dir      string
+	name     protogen.GoPackageName
+	path     protogen.GoImportPath
+	messages []*protogen.Message
+}
 
-	// Only generate ProtoValue once per package
-	if !generatedPackages[file.GoImportPath] {
-		generateProtoValueType(g)
-		generatedPackages[file.GoImportPath] = true
+// outputPackages groups the wrapped messages of the files being generated by
+// output package, in file order.
+func outputPackages(gen *protogen.Plugin, config *GeneratorConfig) []*outputPackage {
+	var packages []*outputPackage
+	byPath := make(map[protogen.GoImportPath]*outputPackage)
+	for _, f := range gen.Files {
+		if !f.Generate {
+			continue
+		}
+		messages := wrappedMessages(f, config)
+		if len(messages) == 0 {
+			continue
+		}
+		pkg := byPath[config.importPath(f)]
+		if pkg == nil {
+			pkg = &outputPackage{
+				dir:  path.Dir(config.filename(f, "")),
+				name: config.packageName(f),
+				path: config.importPath(f),
+			}
+			byPath[pkg.path] = pkg
+			packages = append(packages, pkg)
+		}
+		pkg.messages = append(pkg.messages, messages...)
 	}
+	return packages
+}
 
-	// Generate wrapper for each message
-	for _, m := range messages {
-		generateMessageWrapper(g, m)
+// wrappedMessages returns the messages of file that wrappers are generated
+// for.
+func wrappedMessages(file *protogen.File, config *GeneratorConfig) []*protogen.Message {
+	// Skip if package filter is set and doesn't match
+	if !config.packageSelected(file) {
+		return nil
 	}
 
-	return nil
+	// Filter messages that should have wrappers generated
+	var messages []*protogen.Message
+	var walk func(ms []*protogen.Message)
+	walk = func(ms []*protogen.Message) {
+		for _, m := range ms {
+			if shouldGenerateWrapper(m, config) {
+				messages = append(messages, m)
+			}
+			if config.Nested == NestedInclude {
+				walk(m.Messages)
+			}
+		}
+	}
+	walk(file.Messages)
+	return messages
+}
+
+// wrappedEnums returns the top-level enums of file that wrappers are
+// generated for. They are selected like messages, except that
+// require-opt-in does not apply.
+func wrappedEnums(file *protogen.File, config *GeneratorConfig) []*protogen.Enum {
+	if !config.Enums {
+		return nil
+	}
+	if !config.packageSelected(file) {
+		return nil
+	}
+
+	var enums []*protogen.Enum
+	for _, e := range file.Enums {
+		if config.IncludeRegex != nil && !config.IncludeRegex.MatchString(string(e.Desc.FullName())) {
+			continue
+		}
+		if config.excluded(e.GoIdent.GoName, e.Desc) {
+			continue
+		}
+		enums = append(enums, e)
+	}
+	return enums
 }
 
 func shouldGenerateWrapper(m *protogen.Message, config *GeneratorConfig) bool {
@@ -59,13 +870,18 @@ func shouldGenerateWrapper(m *protogen.Message, config *GeneratorConfig) bool {
 		return false
 	}
 
+	// Skip types that don't match the include filter
+	if config.IncludeRegex != nil && !config.IncludeRegex.MatchString(string(m.Desc.FullName())) {
+		return false
+	}
+
 	// Skip excluded types
-	if config.ExcludedTypes[m.GoIdent.GoName] {
+	if config.excluded(m.GoIdent.GoName, m.Desc) {
 		return false
 	}
 
-	// Also check the full proto name (e.g., "test.v1.ToolSetSpec")
-	if config.ExcludedTypes[string(m.Desc.FullName())] {
+	// Skip messages without (dbtypes.generate) when opt-in is required
+	if config.RequireOptIn && !optedIn(m) {
 		return false
 	}
 
@@ -73,102 +889,983 @@ func shouldGenerateWrapper(m *protogen.Message, config *GeneratorConfig) bool {
 	return true
 }
 
-func generateHeader(g *protogen.GeneratedFile, file *protogen.File) {
+// optedIn reports whether m sets the (dbtypes.generate) message option.
+func optedIn(m *protogen.Message) bool {
+	opts, ok := m.Desc.Options().(*descriptorpb.MessageOptions)
+	if !ok || opts == nil {
+		return false
+	}
+	return proto.GetExtension(opts, dbtypespb.E_Generate).(bool)
+}
+
+func generateHeader(g *protogen.GeneratedFile, file *protogen.File, config *GeneratorConfig) {
 	g.P("// Code generated by protoc-gen-go-dbtypes. DO NOT EDIT.")
 	g.P("// source: ", file.Desc.Path())
 	g.P()
-	g.P("package ", file.GoPackageName)
+	g.P("package ", config.packageName(file))
 	g.P()
 }
 
-func generateProtoValueType(g *protogen.GeneratedFile) {
+func generateProtoValueType(g *protogen.GeneratedFile, config *GeneratorConfig) {
 	g.P("// ProtoValue wraps a protobuf message for database scanning/valuing.")
 	g.P("type ProtoValue[T ", protoPackage.Ident("Message"), "] struct {")
 	g.P("	Message T")
+	if config.FunctionalOptions {
+		g.P("	// codec is the codec WithFormat or WithCodec selected, if any.")
+		g.P("	codec Codec")
+	}
 	g.P("}")
 	g.P()
 
 	// Scan method
 	g.P("// Scan implements sql.Scanner.")
 	g.P("func (p *ProtoValue[T]) Scan(src any) error {")
+	if config.base64Text(config.Format) {
+		g.P("	return p.scanBase64(", contextPackage.Ident("Background"), "(), src, ", config.codecVar(config.Format), ")")
+	} else if config.Format == FormatJSON {
+		g.P("	return p.scanJSON(", contextPackage.Ident("Background"), "(), src, ", config.codecVar(config.Format), ")")
+	} else {
+		g.P("	return p.scan(", contextPackage.Ident("Background"), "(), src, ", config.codecVar(config.Format), ")")
+	}
+	g.P("}")
+	g.P()
+
+	// Value method
+	g.P("// Value implements driver.Valuer.")
+	g.P("func (p *ProtoValue[T]) Value() (", driverPackage.Ident("Value"), ", error) {")
+	if config.base64Text(config.Format) {
+		g.P("	return dbtypesValueBase64(p.value(", contextPackage.Ident("Background"), "(), ", config.codecVar(config.Format), "))")
+	} else if config.valueString(config.Format) {
+		g.P("	return dbtypesValueString(p.value(", contextPackage.Ident("Background"), "(), ", config.codecVar(config.Format), "))")
+	} else {
+		g.P("	return p.value(", contextPackage.Ident("Background"), "(), ", config.codecVar(config.Format), ")")
+	}
+	g.P("}")
+	g.P()
+
+	// scan helper shared by the message wrappers
+	g.P("// scan decodes src into the message using codec.")
+	g.P("func (p *ProtoValue[T]) scan(ctx ", contextPackage.Ident("Context"), ", src any, codec Codec) (err error) {")
+	g.P("	if err := ctx.Err(); err != nil {")
+	g.P("		return err")
+	g.P("	}")
+	g.P("	if ObserveSerialization != nil {")
+	g.P(`		defer dbtypesObserve(ctx, "scan", p.Message, `, timePackage.Ident("Now"), "(), &err)")
+	g.P("	}")
+	g.P("	src = dbtypesScanSource(src)")
 	g.P("	if src == nil {")
+	if config.ScanNullAsEmpty {
+		g.P("		// NULL leaves a new empty message, so that Unwrap is always usable and")
+		g.P("		// a wrapper scanned into before doesn't change that of the previous row.")
+		g.P("		p.Message = p.Message.ProtoReflect().Type().New().Interface().(T)")
+	} else {
+		g.P("		// NULL leaves no message, so that a wrapper scanned into before")
+		g.P("		// doesn't keep that of the previous row.")
+		g.P("		var zero T")
+		g.P("		p.Message = zero")
+	}
 	g.P("		return nil")
 	g.P("	}")
 	g.P()
+	if config.MaxScanSize > 0 {
+		g.P("	if r, ok := src.(", ioPackage.Ident("Reader"), "); ok && MaxScanSize > 0 {")
+		g.P("		// Read one byte past the limit to tell a source at the limit from a longer one.")
+		g.P("		src = ", ioPackage.Ident("LimitReader"), "(r, int64(MaxScanSize)+1)")
+		g.P("	}")
+		g.P()
+	}
 	g.P("	var data []byte")
+	if config.MetricsHooks {
+		g.P("	if OnScan != nil || OnScanError != nil {")
+		g.P("		defer func() { dbtypesReportScan(p.Message, len(data), err) }()")
+		g.P("	}")
+	}
+	if config.OTel {
+		g.P("	if dbtypesTrace != nil {")
+		g.P(`		defer func() { dbtypesTrace(ctx, "scan", p.Message, len(data), err) }()`)
+		g.P("	}")
+	}
 	g.P("	switch v := src.(type) {")
 	g.P("	case []byte:")
-	g.P("		data = v")
+	g.P("		// Drivers may reuse the buffer once Scan returns, so copy it.")
+	g.P("		data = append([]byte(nil), v...)")
+	g.P("	case ", sqlPackage.Ident("RawBytes"), ":")
+	g.P("		// RawBytes is only valid until the next call to Next, Scan or Close.")
+	g.P("		data = append([]byte(nil), v...)")
 	g.P("	case string:")
 	g.P("		data = []byte(v)")
+	g.P("	case ", ioPackage.Ident("Reader"), ":")
+	g.P("		if data, err = ", ioPackage.Ident("ReadAll"), "(v); err != nil {")
+	g.P("			return p.wrapError(", fmtPackage.Ident("Errorf"), `("read scan source: %w", err))`)
+	g.P("		}")
 	g.P("	default:")
-	g.P("		return ", fmtPackage.Ident("Errorf"), `("dbtypes: unsupported scan type: %T", src)`)
+	g.P("		return &ScanTypeError{Message: p.Message.ProtoReflect().Descriptor().FullName(), SourceType: ", reflectPackage.Ident("TypeOf"), "(src)}")
 	g.P("	}")
 	g.P()
-	g.P("	return ", protoPackage.Ident("Unmarshal"), "(data, p.Message)")
+	if config.MaxScanSize > 0 {
+		g.P("	if MaxScanSize > 0 && len(data) > MaxScanSize {")
+		g.P("		return p.wrapError(", fmtPackage.Ident("Errorf"), `("%w: more than %d bytes", ErrScanTooLarge, MaxScanSize))`)
+		g.P("	}")
+	}
+	if config.EmitChecksum {
+		g.P("	if err := p.verifyChecksum(ctx, data); err != nil {")
+		g.P("		return err")
+		g.P("	}")
+	}
+	if config.AutoScan {
+		g.P("	if DetectFormat != nil {")
+		g.P("		codec = DetectFormat(data, codec)")
+		g.P("	}")
+	}
+	g.P("	// Decode into the existing message, so that scanning row after row into")
+	g.P("	// one wrapper allocates no new message.")
+	g.P("	", protoPackage.Ident("Reset"), "(p.Message)")
+	g.P("	return codec.Decode(data, p.Message)")
 	g.P("}")
 	g.P()
 
-	// Value method
-	g.P("// Value implements driver.Valuer.")
-	g.P("func (p *ProtoValue[T]) Value() (", driverPackage.Ident("Value"), ", error) {")
-	g.P("	if any(p.Message) == nil {")
-	g.P("		return nil, nil")
+	// JSON columns come back from some drivers and ORMs already decoded
+	g.P("// scanJSON is scan for protojson. It also accepts the json.RawMessage and")
+	g.P("// map[string]any values some drivers and ORMs return for JSON columns.")
+	g.P("func (p *ProtoValue[T]) scanJSON(ctx ", contextPackage.Ident("Context"), ", src any, codec Codec) error {")
+	g.P("	switch v := dbtypesScanSource(src).(type) {")
+	g.P("	case ", jsonPackage.Ident("RawMessage"), ":")
+	g.P("		src = []byte(v)")
+	g.P("	case map[string]any:")
+	g.P("		data, err := ", jsonPackage.Ident("Marshal"), "(v)")
+	g.P("		if err != nil {")
+	g.P("			return p.wrapError(", fmtPackage.Ident("Errorf"), `("marshal scan source: %w", err))`)
+	g.P("		}")
+	g.P("		src = data")
 	g.P("	}")
-	g.P("	return ", protoPackage.Ident("Marshal"), "(p.Message)")
+	g.P("	return p.scan(ctx, src, codec)")
 	g.P("}")
 	g.P()
-}
-
-func generateMessageWrapper(g *protogen.GeneratedFile, m *protogen.Message) {
-	typeName := m.GoIdent.GoName
-	wrapperName := typeName + "Value"
 
-	// Type definition
-	g.P("// ", wrapperName, " wraps *", typeName, " for database operations.")
-	g.P("type ", wrapperName, " struct {")
-	g.P("	*ProtoValue[*", typeName, "]")
-	g.P("}")
-	g.P()
+	// value helper shared by the message wrappers
+	g.P("// value encodes the message using codec.")
+	result := "_"
+	if config.MetricsHooks || config.OTel {
+		result = "v"
+	}
+	g.P("func (p *ProtoValue[T]) value(ctx ", contextPackage.Ident("Context"), ", codec Codec) (", result, " ", driverPackage.Ident("Value"), ", err error) {")
+	g.P("	if err := ctx.Err(); err != nil {")
+	g.P("		return nil, err")
+	g.P("	}")
+	g.P("	if ObserveSerialization != nil {")
+	g.P(`		defer dbtypesObserve(ctx, "value", p.Message, `, timePackage.Ident("Now"), "(), &err)")
+	g.P("	}")
+	if config.NilMessage == NilMessageNull {
+		g.P("	if any(p.Message) == nil || !p.Message.ProtoReflect().IsValid() {")
+	} else {
+		g.P("	if any(p.Message) == nil {")
+	}
+	g.P("		return nil, nil")
+	g.P("	}")
+	if config.EmptyAsNull {
+		g.P("	if ", protoPackage.Ident("Size"), "(p.Message) == 0 {")
+		g.P("		return nil, nil")
+		g.P("	}")
+	}
+	if config.MetricsHooks {
+		g.P("	if OnValue != nil || OnValueError != nil {")
+		g.P("		defer func() { dbtypesReportValue(p.Message, v, err) }()")
+		g.P("	}")
+	}
+	if config.OTel {
+		g.P("	if dbtypesTrace != nil {")
+		g.P("		defer func() {")
+		g.P("			data, _ := v.([]byte)")
+		g.P(`			dbtypesTrace(ctx, "value", p.Message, len(data), err)`)
+		g.P("		}()")
+		g.P("	}")
+	}
+	if config.Validate {
+		g.P("	if dbtypesValidate != nil {")
+		g.P("		if err := dbtypesValidate(p.Message); err != nil {")
+		g.P("			return nil, p.wrapError(", fmtPackage.Ident("Errorf"), `("validate: %w", err))`)
+		g.P("		}")
+		g.P("	}")
+	}
+	g.P("	return codec.Encode(p.Message)")
+	g.P("}")
+	g.P()
 
-	// Constructor
-	g.P("// New", wrapperName, " creates a new ", wrapperName, " wrapper.")
-	g.P("func New", wrapperName, "(msg *", typeName, ") *", wrapperName, " {")
-	g.P("	if msg == nil {")
-	g.P("		msg = &", typeName, "{}")
+	// wrapError helper naming the message in every serialization error
+	g.P("// wrapError prefixes err with the name of the wrapped message type.")
+	g.P("func (p *ProtoValue[T]) wrapError(err error) error {")
+	g.P("	return dbtypesWrapError(p.Message, err)")
+	g.P("}")
+	g.P()
+	g.P("// dbtypesWrapError prefixes err with the name of the type of msg.")
+	g.P("func dbtypesWrapError(msg ", protoPackage.Ident("Message"), ", err error) error {")
+	g.P("	return ", fmtPackage.Ident("Errorf"), `("dbtypes: %s: %w", msg.ProtoReflect().Descriptor().Name(), err)`)
+	g.P("}")
+	g.P()
+	generateCodec(g, config)
+	if config.FunctionalOptions {
+		generateFunctionalOptions(g, config)
+	}
+	if config.AutoScan {
+		generateAutoScan(g)
+	}
+
+	g.P("// ErrInvalidScanType is wrapped by the error Scan returns for a source of an")
+	g.P("// unsupported type.")
+	g.P("var ErrInvalidScanType = ", errorsPackage.Ident("New"), `("unsupported scan type")`)
+	g.P()
+	generateSizeCheck(g)
+	generateScanErrors(g)
+	generateScanSource(g)
+	if config.MaxScanSize > 0 {
+		g.P("// MaxScanSize is the length in bytes of the longest source Scan decodes;")
+		g.P("// longer ones fail with ErrScanTooLarge before they are unmarshaled. With")
+		g.P("// compress=gzip or zstd it also limits the inflated length. Zero or less")
+		g.P("// means no limit.")
+		g.P("var MaxScanSize = ", config.MaxScanSize)
+		g.P()
+		g.P("// ErrScanTooLarge is wrapped by the error Scan returns for a source longer")
+		g.P("// than MaxScanSize.")
+		g.P("var ErrScanTooLarge = ", errorsPackage.Ident("New"), `("scan source exceeds MaxScanSize")`)
+		g.P()
+	}
+
+	if config.ValueAsString {
+		g.P("// dbtypesValueString converts encoded text to a string, which drivers bind")
+		g.P("// as text rather than as a blob.")
+		g.P("func dbtypesValueString(v ", driverPackage.Ident("Value"), ", err error) (", driverPackage.Ident("Value"), ", error) {")
+		g.P("	if data, ok := v.([]byte); ok {")
+		g.P("		return string(data), err")
+		g.P("	}")
+		g.P("	return v, err")
+		g.P("}")
+		g.P()
+	}
+	if config.Base64Text {
+		generateBase64Helpers(g)
+	}
+
+	g.P("// ObserveSerialization, when non-nil, is called after every Value and Scan")
+	g.P("// with the operation (\"value\" or \"scan\"), the message, how long it took")
+	g.P("// and the resulting error, for example to record tracing spans. The context")
+	g.P("// is the one given to ValueContext or ScanContext.")
+	g.P("var ObserveSerialization func(ctx ", contextPackage.Ident("Context"), ", op string, msg ", protoPackage.Ident("Message"), ", elapsed ", timePackage.Ident("Duration"), ", err error)")
+	g.P()
+	g.P("// dbtypesObserve reports an operation started at start to")
+	g.P("// ObserveSerialization, if set. Value and Scan defer it with their result.")
+	g.P("func dbtypesObserve(ctx ", contextPackage.Ident("Context"), ", op string, msg ", protoPackage.Ident("Message"), ", start ", timePackage.Ident("Time"), ", err *error) {")
+	g.P("	ObserveSerialization(ctx, op, msg, ", timePackage.Ident("Since"), "(start), *err)")
+	g.P("}")
+	g.P()
+	if config.MetricsHooks {
+		generateMetricsHooks(g)
+	}
+	if config.OTel {
+		g.P("// dbtypesTrace, when non-nil, is called after every Value and Scan with the")
+		g.P("// number of bytes stored or read. Building with the ", otelBuildTag, " tag sets it")
+		g.P("// to record OpenTelemetry span events.")
+		g.P("var dbtypesTrace func(ctx ", contextPackage.Ident("Context"), ", op string, msg ", protoPackage.Ident("Message"), ", size int, err error)")
+		g.P()
+	}
+
+	generateAnyResolver(g, config)
+
+	if config.Validate {
+		g.P("// dbtypesValidate, when non-nil, is called by Value before a message is")
+		g.P("// written. Building with the ", validateBuildTag, " tag sets it to protovalidate.")
+		g.P("var dbtypesValidate func(", protoPackage.Ident("Message"), ") error")
+		g.P()
+	}
+	if config.Deterministic {
+		g.P("// dbtypesMarshalDeterministic marshals m with map entries in a stable order.")
+		g.P("var dbtypesMarshalDeterministic = ", protoPackage.Ident("MarshalOptions"), "{", config.withPartial("Deterministic: true"), "}.Marshal")
+		g.P()
+	}
+	if config.SortRepeated {
+		generateSortRepeated(g)
+	}
+	if config.AllowPartial {
+		generateAllowPartial(g, config)
+	}
+	if config.DiscardUnknown || config.AllowPartial {
+		generateUnmarshalOptions(g, config)
+	}
+	if config.VTProto {
+		generateVTProto(g, config)
+	}
+	switch config.Compression {
+	case CompressionGzip:
+		generateGzipHelpers(g, config)
+	case CompressionZstd:
+		generateZstdHelpers(g, config)
+	}
+	if config.EncryptHooks {
+		generateEncryptHooks(g)
+	}
+	if config.MarshalHooks {
+		generateMarshalHooks(g, config)
+	}
+	generateBatchHelpers(g, config)
+	generateScanFieldsHelpers(g)
+	generateMaskHelpers(g)
+	if config.EmitChecksum {
+		generateChecksumHelpers(g, config)
+	}
+}
+
+// valuesName returns the name of the function converting a slice of m to
+// driver values, the plural of its wrapper's.
+func (c *GeneratorConfig) valuesName(m *protogen.Message) string {
+	return c.wrapperName(m) + "s"
+}
+
+// generateValuesDoc emits the doc comment of the XxxValues function of m.
+func generateValuesDoc(g *protogen.GeneratedFile, m *protogen.Message, config *GeneratorConfig) {
+	wrapperName := config.wrapperName(m)
+	g.P("// ", config.valuesName(m), " returns what ", wrapperName, ".Value returns for each message")
+	g.P("// of msgs, for passing many messages as arguments to ExecContext and the")
+	g.P("// like. Nil messages give nil entries, which are stored as NULL.")
+}
+
+// generateBatchHelpers emits the functions the EncodeXxxBatch and XxxValues
+// helpers share.
+func generateBatchHelpers(g *protogen.GeneratedFile, config *GeneratorConfig) {
+	g.P("// dbtypesEncodeBatch encodes each message of msgs with value, leaving nil")
+	g.P("// entries for nil messages.")
+	g.P("func dbtypesEncodeBatch[T ", protoPackage.Ident("Message"), "](msgs []T, value func(T) (", driverPackage.Ident("Value"), ", error)) ([][]byte, error) {")
+	g.P("	out := make([][]byte, len(msgs))")
+	g.P("	for i, msg := range msgs {")
+	g.P("		if !msg.ProtoReflect().IsValid() {")
+	g.P("			continue")
+	g.P("		}")
+	g.P("		v, err := value(msg)")
+	g.P("		if err != nil {")
+	g.P("			return nil, ", fmtPackage.Ident("Errorf"), `("batch element %d: %w", i, err)`)
+	g.P("		}")
+	if config.ValueAsString || config.Base64Text {
+		g.P("		switch v := v.(type) {")
+		g.P("		case []byte:")
+		g.P("			out[i] = v")
+		g.P("		case string:")
+		g.P("			out[i] = []byte(v)")
+		g.P("		}")
+	} else {
+		g.P("		out[i], _ = v.([]byte)")
+	}
 	g.P("	}")
-	g.P("	return &", wrapperName, "{")
-	g.P("		ProtoValue: &ProtoValue[*", typeName, "]{Message: msg},")
+	g.P("	return out, nil")
+	g.P("}")
+	g.P()
+	g.P("// dbtypesValues calls value on each message of msgs, leaving nil entries for")
+	g.P("// nil messages.")
+	g.P("func dbtypesValues[T ", protoPackage.Ident("Message"), "](msgs []T, value func(T) (", driverPackage.Ident("Value"), ", error)) ([]", driverPackage.Ident("Value"), ", error) {")
+	g.P("	out := make([]", driverPackage.Ident("Value"), ", len(msgs))")
+	g.P("	for i, msg := range msgs {")
+	g.P("		if !msg.ProtoReflect().IsValid() {")
+	g.P("			continue")
+	g.P("		}")
+	g.P("		v, err := value(msg)")
+	g.P("		if err != nil {")
+	g.P("			return nil, ", fmtPackage.Ident("Errorf"), `("element %d: %w", i, err)`)
+	g.P("		}")
+	g.P("		out[i] = v")
 	g.P("	}")
+	g.P("	return out, nil")
 	g.P("}")
 	g.P()
 
-	// Scan method
+	if !config.plainWireFormat(FormatBinary) {
+		return
+	}
+	g.P("// dbtypesMarshalBatch is dbtypesEncodeBatch for messages stored in the plain")
+	g.P("// wire format. It sizes the messages first and marshals them all into one")
+	g.P("// buffer, which the entries are slices of. While ObserveSerialization is set")
+	g.P("// the messages are encoded with value instead, so that each is observed.")
+	g.P("func dbtypesMarshalBatch[T ", protoPackage.Ident("Message"), "](msgs []T, value func(T) (", driverPackage.Ident("Value"), ", error)) ([][]byte, error) {")
+	hooks := "ObserveSerialization != nil"
+	if config.MetricsHooks {
+		hooks += " || OnValue != nil || OnValueError != nil"
+	}
+	if config.OTel {
+		hooks += " || dbtypesTrace != nil"
+	}
+	g.P("	if ", hooks, " {")
+	g.P("		return dbtypesEncodeBatch(msgs, value)")
+	g.P("	}")
+	g.P("	size := 0")
+	g.P("	for i, msg := range msgs {")
+	g.P("		n := ", protoPackage.Ident("Size"), "(msg)")
+	g.P("		if err := dbtypesCheckSize(n); err != nil {")
+	g.P("			p := &ProtoValue[T]{Message: msg}")
+	g.P("			return nil, ", fmtPackage.Ident("Errorf"), `("batch element %d: %w", i, p.wrapError(err))`)
+	g.P("		}")
+	g.P("		size += n")
+	g.P("	}")
+	g.P("	// Size has cached the sizes of the messages")
+	if config.Deterministic {
+		g.P("	opts := ", protoPackage.Ident("MarshalOptions"), "{", config.withPartial("Deterministic: true, UseCachedSize: true"), "}")
+	} else {
+		g.P("	opts := ", protoPackage.Ident("MarshalOptions"), "{", config.withPartial("UseCachedSize: true"), "}")
+	}
+	g.P("	buf := make([]byte, 0, size)")
+	g.P("	out := make([][]byte, len(msgs))")
+	g.P("	for i, msg := range msgs {")
+	g.P("		if !msg.ProtoReflect().IsValid() {")
+	g.P("			continue")
+	g.P("		}")
+	g.P("		start := len(buf)")
+	g.P("		var err error")
+	g.P("		if buf, err = opts.MarshalAppend(buf, msg); err != nil {")
+	g.P("			p := &ProtoValue[T]{Message: msg}")
+	g.P("			return nil, ", fmtPackage.Ident("Errorf"), `("batch element %d: %w", i, p.wrapError(err))`)
+	g.P("		}")
+	g.P("		out[i] = buf[start:len(buf):len(buf)]")
+	g.P("	}")
+	g.P("	return out, nil")
+	g.P("}")
+	g.P()
+}
+
+// generateScanSource emits the helper that unwraps the pointer sources some
+// drivers pass to Scan, for example for OUT parameters.
+func generateScanSource(g *protogen.GeneratedFile) {
+	g.P("// dbtypesScanSource returns the value a *[]byte or *string source points to,")
+	g.P("// or nil, meaning NULL, for a nil pointer. Other sources are returned as is.")
+	g.P("func dbtypesScanSource(src any) any {")
+	g.P("	switch v := src.(type) {")
+	g.P("	case *[]byte:")
+	g.P("		if v == nil {")
+	g.P("			return nil")
+	g.P("		}")
+	g.P("		return *v")
+	g.P("	case *string:")
+	g.P("		if v == nil {")
+	g.P("			return nil")
+	g.P("		}")
+	g.P("		return *v")
+	g.P("	}")
+	g.P("	return src")
+	g.P("}")
+	g.P()
+}
+
+// generateSizeCheck emits the check that Value doesn't store a message too
+// long for the protobuf wire format.
+func generateSizeCheck(g *protogen.GeneratedFile) {
+	g.P("// ErrMessageTooLarge is wrapped by the error Value returns for a message")
+	g.P("// whose encoding is longer than the 2 GiB the protobuf wire format allows.")
+	g.P("var ErrMessageTooLarge = ", errorsPackage.Ident("New"), `("message too large")`)
+	g.P()
+	g.P("// dbtypesMaxMessageSize is the length in bytes of the longest encoding Value")
+	g.P("// stores. Protobuf parsers reject longer messages, so such a row could not")
+	g.P("// be read back by every client.")
+	g.P("var dbtypesMaxMessageSize = ", mathPackage.Ident("MaxInt32"))
+	g.P()
+	g.P("// dbtypesCheckSize returns an error wrapping ErrMessageTooLarge if an")
+	g.P("// encoding of n bytes is longer than dbtypesMaxMessageSize.")
+	g.P("func dbtypesCheckSize(n int) error {")
+	g.P("	if n <= dbtypesMaxMessageSize {")
+	g.P("		return nil")
+	g.P("	}")
+	g.P("	return ", fmtPackage.Ident("Errorf"), `("%w: %d bytes exceed the 2 GiB limit of the protobuf wire format; split the message or store large fields outside it", ErrMessageTooLarge, n)`)
+	g.P("}")
+	g.P()
+}
+
+// generateScanErrors emits the error types Scan returns, so that callers can
+// tell a column of the wrong type from bytes that don't decode.
+func generateScanErrors(g *protogen.GeneratedFile) {
+	g.P("// ScanTypeError is returned by Scan for a source of an unsupported type,")
+	g.P("// usually a sign that the wrong column was scanned. It wraps")
+	g.P("// ErrInvalidScanType.")
+	g.P("type ScanTypeError struct {")
+	g.P("	// Message is the full name of the message being scanned into.")
+	g.P("	Message ", protoreflectPackage.Ident("FullName"))
+	g.P("	// SourceType is the dynamic type of the source.")
+	g.P("	SourceType ", reflectPackage.Ident("Type"))
+	g.P("}")
+	g.P()
+	g.P("// Error implements error.")
+	g.P("func (e *ScanTypeError) Error() string {")
+	g.P("	return ", fmtPackage.Ident("Sprintf"), `("dbtypes: %s: %v: %v", e.Message.Name(), ErrInvalidScanType, e.SourceType)`)
+	g.P("}")
+	g.P()
+	g.P("// Unwrap returns ErrInvalidScanType, for errors.Is.")
+	g.P("func (e *ScanTypeError) Unwrap() error { return ErrInvalidScanType }")
+	g.P()
+	g.P("// DecodeError is returned by Scan and UnmarshalBinary when the stored bytes do")
+	g.P("// not decode into the message, for example because they are corrupt or in")
+	g.P("// another format.")
+	g.P("type DecodeError struct {")
+	g.P("	// Message is the full name of the message being decoded.")
+	g.P("	Message ", protoreflectPackage.Ident("FullName"))
+	g.P("	// Err is the error from the decoder.")
+	g.P("	Err error")
+	g.P("}")
+	g.P()
+	g.P("// Error implements error.")
+	g.P("func (e *DecodeError) Error() string {")
+	g.P("	return ", fmtPackage.Ident("Sprintf"), `("dbtypes: %s: %v", e.Message.Name(), e.Err)`)
+	g.P("}")
+	g.P()
+	g.P("// Unwrap returns the error from the decoder.")
+	g.P("func (e *DecodeError) Unwrap() error { return e.Err }")
+	g.P()
+}
+
+// generateAnyResolver emits the resolver protojson uses for the types embedded
+// in google.protobuf.Any fields, with the marshal functions that apply it.
+func generateAnyResolver(g *protogen.GeneratedFile, config *GeneratorConfig) {
+	g.P("// AnyResolver resolves the message types embedded in google.protobuf.Any")
+	g.P("// fields when messages are encoded as protojson: in JSON-format values and")
+	g.P("// in MarshalJSON and UnmarshalJSON. It defaults to protoregistry.GlobalTypes.")
+	g.P("var AnyResolver ", protoregistryPackage.Ident("MessageTypeResolver"), " = ", protoregistryPackage.Ident("GlobalTypes"))
+	g.P()
+	g.P("// dbtypesResolver is the resolver protojson takes, which also looks up")
+	g.P("// extensions.")
+	g.P("type dbtypesResolver interface {")
+	g.P("	", protoregistryPackage.Ident("MessageTypeResolver"))
+	g.P("	", protoregistryPackage.Ident("ExtensionTypeResolver"))
+	g.P("}")
+	g.P()
+	g.P("// dbtypesJSONResolver returns AnyResolver as a dbtypesResolver, looking")
+	g.P("// extensions up in protoregistry.GlobalTypes unless AnyResolver can.")
+	g.P("func dbtypesJSONResolver() dbtypesResolver {")
+	g.P("	switch r := AnyResolver.(type) {")
+	g.P("	case nil:")
+	g.P("		return ", protoregistryPackage.Ident("GlobalTypes"))
+	g.P("	case dbtypesResolver:")
+	g.P("		return r")
+	g.P("	default:")
+	g.P("		return struct {")
+	g.P("			", protoregistryPackage.Ident("MessageTypeResolver"))
+	g.P("			", protoregistryPackage.Ident("ExtensionTypeResolver"))
+	g.P("		}{r, ", protoregistryPackage.Ident("GlobalTypes"), "}")
+	g.P("	}")
+	g.P("}")
+	g.P()
+	opts := "Resolver: dbtypesJSONResolver()"
+	if config.JSONEmitUnpopulated {
+		opts += ", EmitUnpopulated: true"
+	}
+	if config.JSONUseProtoNames {
+		opts += ", UseProtoNames: true"
+	}
+	opts = config.withPartial(opts)
+	g.P("// dbtypesMarshalJSON marshals m as protojson, resolving Any fields with")
+	switch {
+	case config.JSONEmitUnpopulated && config.JSONUseProtoNames:
+		g.P("// AnyResolver. Fields are named as in the .proto file and written even")
+		g.P("// when unpopulated.")
+	case config.JSONEmitUnpopulated:
+		g.P("// AnyResolver. Unpopulated fields are written with their zero values.")
+	case config.JSONUseProtoNames:
+		g.P("// AnyResolver. Fields are named as in the .proto file.")
+	default:
+		g.P("// AnyResolver.")
+	}
+	g.P("func dbtypesMarshalJSON(m ", protoPackage.Ident("Message"), ") ([]byte, error) {")
+	g.P("	data, err := ", protojsonPackage.Ident("MarshalOptions"), "{", opts, "}.Marshal(m)")
+	g.P("	if err != nil {")
+	g.P("		if path := dbtypesInvalidUTF8(m.ProtoReflect()); path != \"\" {")
+	g.P("			err = ", fmtPackage.Ident("Errorf"), `("field %s: %w", path, err)`)
+	g.P("		}")
+	g.P("	}")
+	g.P("	return data, err")
+	g.P("}")
+	g.P()
+	g.P("// dbtypesUnmarshalJSON unmarshals protojson into m, resolving Any fields")
+	g.P("// with AnyResolver.")
+	g.P("func dbtypesUnmarshalJSON(data []byte, m ", protoPackage.Ident("Message"), ") error {")
+	unmarshalOpts := "Resolver: dbtypesJSONResolver()"
+	if config.DiscardUnknown {
+		unmarshalOpts += ", DiscardUnknown: true"
+	}
+	unmarshalOpts = config.withPartial(unmarshalOpts)
+	g.P("	return ", protojsonPackage.Ident("UnmarshalOptions"), "{", unmarshalOpts, "}.Unmarshal(data, m)")
+	g.P("}")
+	g.P()
+	generateInvalidUTF8(g)
+}
+
+// generateInvalidUTF8 emits the helpers that find the string protojson
+// refused to write, since its error doesn't say which field holds it.
+func generateInvalidUTF8(g *protogen.GeneratedFile) {
+	g.P("// dbtypesInvalidUTF8 returns the path of a string in m that is not valid")
+	g.P(`// UTF-8, such as "items[2].key", or "" if there is none.`)
+	g.P("func dbtypesInvalidUTF8(m ", protoreflectPackage.Ident("Message"), ") string {")
+	g.P("	var path string")
+	g.P("	m.Range(func(fd ", protoreflectPackage.Ident("FieldDescriptor"), ", v ", protoreflectPackage.Ident("Value"), ") bool {")
+	g.P("		switch {")
+	g.P("		case fd.IsList():")
+	g.P("			list := v.List()")
+	g.P(`			for i := 0; i < list.Len() && path == ""; i++ {`)
+	g.P(`				path = dbtypesInvalidUTF8In(fd, list.Get(i), `, fmtPackage.Ident("Sprintf"), `("%s[%d]", fd.Name(), i))`)
+	g.P("			}")
+	g.P("		case fd.IsMap():")
+	g.P("			v.Map().Range(func(k ", protoreflectPackage.Ident("MapKey"), ", mv ", protoreflectPackage.Ident("Value"), ") bool {")
+	g.P(`				elem := `, fmtPackage.Ident("Sprintf"), `("%s[%v]", fd.Name(), k.Interface())`)
+	g.P("				if fd.MapKey().Kind() == ", protoreflectPackage.Ident("StringKind"), " {")
+	g.P(`					elem = `, fmtPackage.Ident("Sprintf"), `("%s[%q]", fd.Name(), k.String())`)
+	g.P("					if !", utf8Package.Ident("ValidString"), "(k.String()) {")
+	g.P("						path = elem")
+	g.P("						return false")
+	g.P("					}")
+	g.P("				}")
+	g.P("				path = dbtypesInvalidUTF8In(fd.MapValue(), mv, elem)")
+	g.P(`				return path == ""`)
+	g.P("			})")
+	g.P("		default:")
+	g.P("			path = dbtypesInvalidUTF8In(fd, v, string(fd.Name()))")
+	g.P("		}")
+	g.P(`		return path == ""`)
+	g.P("	})")
+	g.P("	return path")
+	g.P("}")
+	g.P()
+	g.P("// dbtypesInvalidUTF8In returns path if v, a single value of fd, is a string")
+	g.P("// that is not valid UTF-8, or the path within it of such a string if v is a")
+	g.P(`// message, or "".`)
+	g.P("func dbtypesInvalidUTF8In(fd ", protoreflectPackage.Ident("FieldDescriptor"), ", v ", protoreflectPackage.Ident("Value"), ", path string) string {")
+	g.P("	switch fd.Kind() {")
+	g.P("	case ", protoreflectPackage.Ident("StringKind"), ":")
+	g.P("		if !", utf8Package.Ident("ValidString"), "(v.String()) {")
+	g.P("			return path")
+	g.P("		}")
+	g.P("	case ", protoreflectPackage.Ident("MessageKind"), ", ", protoreflectPackage.Ident("GroupKind"), ":")
+	g.P(`		if sub := dbtypesInvalidUTF8(v.Message()); sub != "" {`)
+	g.P(`			return path + "." + sub`)
+	g.P("		}")
+	g.P("	}")
+	g.P(`	return ""`)
+	g.P("}")
+	g.P()
+}
+
+// generateUnmarshalOptions declares the unmarshal functions returned by
+// plainUnmarshalFunc. Binary and text are always declared, since UnmarshalText
+// and the (dbtypes.format) option can select them whatever the default.
+func generateUnmarshalOptions(g *protogen.GeneratedFile, config *GeneratorConfig) {
+	opts := ""
+	switch {
+	case config.DiscardUnknown && config.AllowPartial:
+		opts = "DiscardUnknown: true"
+		g.P("// The dbtypesUnmarshal functions drop fields the messages don't declare,")
+		g.P("// such as those written by a newer version of the schema, and accept")
+		g.P("// messages with missing required fields.")
+	case config.DiscardUnknown:
+		opts = "DiscardUnknown: true"
+		g.P("// The dbtypesUnmarshal functions drop fields the messages don't declare,")
+		g.P("// such as those written by a newer version of the schema.")
+	default:
+		g.P("// The dbtypesUnmarshal functions accept messages with missing required")
+		g.P("// fields.")
+	}
+	opts = config.withPartial(opts)
+	g.P("var (")
+	g.P("	dbtypesUnmarshalBinary = ", protoPackage.Ident("UnmarshalOptions"), "{", opts, "}.Unmarshal")
+	g.P("	dbtypesUnmarshalText = ", prototextPackage.Ident("UnmarshalOptions"), "{", opts, "}.Unmarshal")
+	for _, format := range config.codecFormats() {
+		switch format {
+		case FormatCBOR:
+			g.P("	dbtypesUnmarshalCBOR = ", dbtypesPackage.Ident("UnmarshalOptions"), "{", opts, "}.UnmarshalCBOR")
+		case FormatMsgpack:
+			g.P("	dbtypesUnmarshalMsgpack = ", dbtypesPackage.Ident("UnmarshalOptions"), "{", opts, "}.UnmarshalMsgpack")
+		}
+	}
+	g.P(")")
+	g.P()
+}
+
+// generateAllowPartial declares the marshal functions returned by
+// formatMarshalFunc with allow-partial. Binary and text are declared like
+// their unmarshal functions; with deterministic=true the binary one is
+// dbtypesMarshalDeterministic.
+func generateAllowPartial(g *protogen.GeneratedFile, config *GeneratorConfig) {
+	g.P("// The dbtypesMarshal functions encode messages with missing required")
+	g.P("// fields.")
+	g.P("var (")
+	if !config.Deterministic {
+		g.P("	dbtypesMarshalBinary = ", protoPackage.Ident("MarshalOptions"), "{AllowPartial: true}.Marshal")
+	}
+	g.P("	dbtypesMarshalText = ", prototextPackage.Ident("MarshalOptions"), "{AllowPartial: true}.Marshal")
+	for _, format := range config.codecFormats() {
+		switch format {
+		case FormatCBOR:
+			g.P("	dbtypesMarshalCBOR = ", dbtypesPackage.Ident("MarshalOptions"), "{AllowPartial: true}.MarshalCBOR")
+		case FormatMsgpack:
+			g.P("	dbtypesMarshalMsgpack = ", dbtypesPackage.Ident("MarshalOptions"), "{AllowPartial: true}.MarshalMsgpack")
+		}
+	}
+	g.P(")")
+	g.P()
+}
+
+// generateMetricsHooks emits the hooks value and scan report to, and the
+// helpers that call them.
+func generateMetricsHooks(g *protogen.GeneratedFile) {
+	g.P("// The metrics hooks, when non-nil, are called by Value and Scan with the full")
+	g.P("// name of the message, for example to count serialized bytes and errors per")
+	g.P("// message type. OnValue and OnScan receive the number of bytes stored or")
+	g.P("// read; NULL values are not reported. Set them before first use.")
+	g.P("var (")
+	g.P("	OnValue      func(typeName string, size int)")
+	g.P("	OnValueError func(typeName string, err error)")
+	g.P("	OnScan       func(typeName string, size int)")
+	g.P("	OnScanError  func(typeName string, err error)")
+	g.P(")")
+	g.P()
+	g.P("func dbtypesReportValue(msg ", protoPackage.Ident("Message"), ", v ", driverPackage.Ident("Value"), ", err error) {")
+	g.P("	name := string(msg.ProtoReflect().Descriptor().FullName())")
+	g.P("	switch {")
+	g.P("	case err != nil && OnValueError != nil:")
+	g.P("		OnValueError(name, err)")
+	g.P("	case err == nil && OnValue != nil:")
+	g.P("		data, _ := v.([]byte)")
+	g.P("		OnValue(name, len(data))")
+	g.P("	}")
+	g.P("}")
+	g.P()
+	g.P("func dbtypesReportScan(msg ", protoPackage.Ident("Message"), ", size int, err error) {")
+	g.P("	name := string(msg.ProtoReflect().Descriptor().FullName())")
+	g.P("	switch {")
+	g.P("	case err != nil && OnScanError != nil:")
+	g.P("		OnScanError(name, err)")
+	g.P("	case err == nil && OnScan != nil:")
+	g.P("		OnScan(name, size)")
+	g.P("	}")
+	g.P("}")
+	g.P()
+}
+
+func generateMarshalHooks(g *protogen.GeneratedFile, config *GeneratorConfig) {
+	g.P("// Marshal encodes the messages stored in the default ", config.Format, " format, for")
+	g.P("// Value and MarshalBinary. Replace it before first use, such as in an init")
+	g.P("// function, to store a custom encoding; compression and encryption still")
+	g.P("// apply to its output. Messages with a (dbtypes.format) option keep their")
+	g.P("// own format.")
+	g.P("var Marshal func(", protoPackage.Ident("Message"), ") ([]byte, error) = ", config.formatMarshalFunc(config.Format))
+	g.P()
+	g.P("// Unmarshal decodes what Marshal encodes, for Scan and UnmarshalBinary.")
+	g.P("var Unmarshal func([]byte, ", protoPackage.Ident("Message"), ") error = ", config.formatUnmarshalFunc(config.Format))
+	g.P()
+}
+
+func generateEncryptHooks(g *protogen.GeneratedFile) {
+	g.P("// EncryptCipher, when non-nil, is applied by Value to the serialized (and")
+	g.P("// compressed) message bytes before they are handed to the driver. It")
+	g.P("// receives and returns raw bytes.")
+	g.P("var EncryptCipher func([]byte) ([]byte, error)")
+	g.P()
+	g.P("// DecryptCipher, when non-nil, is applied by Scan to the raw column bytes")
+	g.P("// before they are decompressed and unmarshaled. It must invert EncryptCipher.")
+	g.P("var DecryptCipher func([]byte) ([]byte, error)")
+	g.P()
+}
+
+func generateGzipHelpers(g *protogen.GeneratedFile, config *GeneratorConfig) {
+	g.P("// GzipLevel is the compression level used by Value. It may be set to any")
+	g.P("// level accepted by gzip.NewWriterLevel.")
+	g.P("var GzipLevel = ", gzipPackage.Ident("DefaultCompression"))
+	g.P()
+	g.P("// dbtypesGzipMagic is the gzip header. A valid protobuf payload never starts")
+	g.P("// with it, so Scan uses it to tell compressed rows from uncompressed ones.")
+	g.P("var dbtypesGzipMagic = []byte{0x1f, 0x8b}")
+	g.P()
+	// Compression state is pooled: a gzip.Writer alone allocates close to a
+	// megabyte, which dominates the cost of Value on write-heavy paths.
+	marshalOptions := "{" + config.withPartial("") + "}"
+	if config.Deterministic {
+		marshalOptions = "{" + config.withPartial("Deterministic: true") + "}"
+	}
+	g.P("// dbtypesGzipState holds the buffers reused to compress a value.")
+	g.P("type dbtypesGzipState struct {")
+	g.P("	raw   []byte")
+	g.P("	buf   ", bytesPackage.Ident("Buffer"))
+	g.P("	w     *", gzipPackage.Ident("Writer"))
+	g.P("	level int")
+	g.P("}")
+	g.P()
+	g.P("// dbtypesGzipMaxPooled caps the size of buffers returned to the pool so that")
+	g.P("// one huge message doesn't pin its buffers for the life of the process.")
+	g.P("const dbtypesGzipMaxPooled = 1 << 20")
+	g.P()
+	g.P("var dbtypesGzipPool = ", syncPackage.Ident("Pool"), "{New: func() any { return new(dbtypesGzipState) }}")
+	g.P()
+	g.P("// dbtypesMarshalGzip marshals m and gzip-compresses the result.")
+	g.P("func dbtypesMarshalGzip(m ", protoPackage.Ident("Message"), ") ([]byte, error) {")
+	g.P("	s := dbtypesGzipPool.Get().(*dbtypesGzipState)")
+	g.P("	defer func() {")
+	g.P("		if cap(s.raw) <= dbtypesGzipMaxPooled && s.buf.Cap() <= dbtypesGzipMaxPooled {")
+	g.P("			dbtypesGzipPool.Put(s)")
+	g.P("		}")
+	g.P("	}()")
+	g.P()
+	if config.hooked(FormatBinary) {
+		g.P("	raw, err := Marshal(m)")
+	} else {
+		g.P("	raw, err := ", protoPackage.Ident("MarshalOptions"), marshalOptions, ".MarshalAppend(s.raw[:0], m)")
+	}
+	g.P("	if err != nil {")
+	g.P("		return nil, err")
+	g.P("	}")
+	if !config.hooked(FormatBinary) {
+		g.P("	s.raw = raw")
+	}
+	g.P("	s.buf.Reset()")
+	g.P("	if s.w == nil || s.level != GzipLevel {")
+	g.P("		w, err := ", gzipPackage.Ident("NewWriterLevel"), "(&s.buf, GzipLevel)")
+	g.P("		if err != nil {")
+	g.P("			return nil, err")
+	g.P("		}")
+	g.P("		s.w, s.level = w, GzipLevel")
+	g.P("	} else {")
+	g.P("		s.w.Reset(&s.buf)")
+	g.P("	}")
+	g.P("	if _, err := s.w.Write(raw); err != nil {")
+	g.P("		return nil, err")
+	g.P("	}")
+	g.P("	if err := s.w.Close(); err != nil {")
+	g.P("		return nil, err")
+	g.P("	}")
+	g.P("	// The driver retains the result, so copy it out of the pooled buffer.")
+	g.P("	return append([]byte(nil), s.buf.Bytes()...), nil")
+	g.P("}")
+	g.P()
+	g.P("// dbtypesUnmarshalGzip unmarshals data into m, inflating it first if it is")
+	g.P("// gzip-compressed.")
+	g.P("func dbtypesUnmarshalGzip(data []byte, m ", protoPackage.Ident("Message"), ") error {")
+	g.P("	if ", bytesPackage.Ident("HasPrefix"), "(data, dbtypesGzipMagic) {")
+	g.P("		r, err := ", gzipPackage.Ident("NewReader"), "(", bytesPackage.Ident("NewReader"), "(data))")
+	g.P("		if err != nil {")
+	g.P("			return ", fmtPackage.Ident("Errorf"), `("gzip: %w", err)`)
+	g.P("		}")
+	g.P("		defer r.Close()")
+	if config.MaxScanSize > 0 {
+		g.P("		var inflated ", ioPackage.Ident("Reader"), " = r")
+		g.P("		if MaxScanSize > 0 {")
+		g.P("			inflated = ", ioPackage.Ident("LimitReader"), "(r, int64(MaxScanSize)+1)")
+		g.P("		}")
+		g.P("		if data, err = ", ioPackage.Ident("ReadAll"), "(inflated); err != nil {")
+		g.P("			return ", fmtPackage.Ident("Errorf"), `("gzip: %w", err)`)
+		g.P("		}")
+		g.P("		if MaxScanSize > 0 && len(data) > MaxScanSize {")
+		g.P("			return ", fmtPackage.Ident("Errorf"), `("gzip: %w: inflates to more than %d bytes", ErrScanTooLarge, MaxScanSize)`)
+		g.P("		}")
+	} else {
+		g.P("		if data, err = ", ioPackage.Ident("ReadAll"), "(r); err != nil {")
+		g.P("			return ", fmtPackage.Ident("Errorf"), `("gzip: %w", err)`)
+		g.P("		}")
+	}
+	g.P("	}")
+	g.P("	return ", config.plainUnmarshalFunc(FormatBinary), "(data, m)")
+	g.P("}")
+	g.P()
+}
+
+func generateMessageWrapper(g *protogen.GeneratedFile, m *protogen.Message, config *GeneratorConfig, format Format) {
+	typeName := m.GoIdent.GoName
+	wrapperName := config.wrapperName(m)
+	codec := typeName + "Codec()"
+	if config.FunctionalOptions {
+		// WithFormat or WithCodec may have replaced the codec of the message type
+		codec = "x.ProtoValue.codecOr(" + codec + ")"
+	}
+
+	// Read-only methods take a copy of the wrapper with value-receiver=true,
+	// which is cheap since the wrapper is a single pointer.
+	recv := "*" + wrapperName
+	if config.ValueReceiver {
+		recv = wrapperName
+	}
+
+	// Type definition
+	generateWrapperDoc(g, wrapperName, m.Desc, m.Comments.Leading, config.storageDescription(format))
+	g.P("type ", wrapperName, " struct {")
+	g.P("	*ProtoValue[*", m.GoIdent, "]")
+	g.P("}")
+	g.P()
+
+	// Constructor
+	if config.FunctionalOptions {
+		generateOptionsConstructor(g, m, wrapperName)
+	} else {
+		g.P("// New", wrapperName, " creates a new ", wrapperName, " wrapper. A nil msg")
+		g.P("// gives an empty wrapper, which is stored as NULL like a nil one.")
+		g.P("func New", wrapperName, "(msg *", m.GoIdent, ") *", wrapperName, " {")
+		g.P("	if msg == nil {")
+		g.P("		return &", wrapperName, "{}")
+		g.P("	}")
+		g.P("	return &", wrapperName, "{")
+		g.P("		ProtoValue: &ProtoValue[*", m.GoIdent, "]{Message: msg},")
+		g.P("	}")
+		g.P("}")
+		g.P()
+	}
+	generateCodecAccessor(g, m, config, format)
+
+	// Scan methods
 	g.P("// Scan implements sql.Scanner.")
 	g.P("func (x *", wrapperName, ") Scan(src any) error {")
+	g.P("	return x.ScanContext(", contextPackage.Ident("Background"), "(), src)")
+	g.P("}")
+	g.P()
+	g.P("// ScanContext is like Scan but returns ctx.Err() without decoding if ctx is")
+	g.P("// done.")
+	g.P("func (x *", wrapperName, ") ScanContext(ctx ", contextPackage.Ident("Context"), ", src any) error {")
 	g.P("	if x.ProtoValue == nil {")
-	g.P("		x.ProtoValue = &ProtoValue[*", typeName, "]{Message: &", typeName, "{}}")
+	g.P("		x.ProtoValue = &ProtoValue[*", m.GoIdent, "]{Message: &", m.GoIdent, "{}}")
 	g.P("	}")
 	g.P("	if x.ProtoValue.Message == nil {")
-	g.P("		x.ProtoValue.Message = &", typeName, "{}")
+	g.P("		x.ProtoValue.Message = &", m.GoIdent, "{}")
 	g.P("	}")
-	g.P("	return x.ProtoValue.Scan(src)")
+	if config.base64Text(format) {
+		g.P("	return x.ProtoValue.scanBase64(ctx, src, ", codec, ")")
+	} else if format == FormatJSON {
+		g.P("	return x.ProtoValue.scanJSON(ctx, src, ", codec, ")")
+	} else {
+		g.P("	return x.ProtoValue.scan(ctx, src, ", codec, ")")
+	}
 	g.P("}")
 	g.P()
+	generateScanFields(g, m, config, format, codec)
 
-	// Value method
+	// Value methods
 	g.P("// Value implements driver.Valuer.")
-	g.P("func (x *", wrapperName, ") Value() (", driverPackage.Ident("Value"), ", error) {")
+	g.P("func (x ", recv, ") Value() (", driverPackage.Ident("Value"), ", error) {")
+	g.P("	return x.ValueContext(", contextPackage.Ident("Background"), "())")
+	g.P("}")
+	g.P()
+	g.P("// ValueContext is like Value but returns ctx.Err() without encoding if ctx")
+	g.P("// is done.")
+	g.P("func (x ", recv, ") ValueContext(ctx ", contextPackage.Ident("Context"), ") (", driverPackage.Ident("Value"), ", error) {")
 	g.P("	if x.ProtoValue == nil {")
 	g.P("		return nil, nil")
 	g.P("	}")
-	g.P("	return x.ProtoValue.Value()")
+	if config.FunctionalOptions {
+		g.P("	if x.ProtoValue.Message == nil && x.ProtoValue.codec != nil {")
+		g.P("		// An empty wrapper New", wrapperName, " gave a codec, stored as NULL like a nil one")
+		g.P("		return nil, nil")
+		g.P("	}")
+	}
+	if config.base64Text(format) {
+		g.P("	return dbtypesValueBase64(x.ProtoValue.value(ctx, ", codec, "))")
+	} else if config.valueString(format) {
+		g.P("	return dbtypesValueString(x.ProtoValue.value(ctx, ", codec, "))")
+	} else {
+		g.P("	return x.ProtoValue.value(ctx, ", codec, ")")
+	}
 	g.P("}")
 	g.P()
+	generateValueMasked(g, m, config, recv)
+	if config.EmitChecksum {
+		generateChecksum(g, config, recv, codec)
+	}
+	generateSQLAssertions(g, wrapperName)
 
 	// Unwrap helper
 	g.P("// Unwrap returns the underlying protobuf message.")
-	g.P("func (x *", wrapperName, ") Unwrap() *", typeName, " {")
+	g.P("func (x ", recv, ") Unwrap() *", m.GoIdent, " {")
 	g.P("	if x.ProtoValue == nil || x.ProtoValue.Message == nil {")
 	g.P("		return nil")
 	g.P("	}")
@@ -176,10 +1873,388 @@ func generateMessageWrapper(g *protogen.GeneratedFile, m *protogen.Message) {
 	g.P("}")
 	g.P()
 
-	// DatabaseValue method on the proto message
-	g.P("// DatabaseValue returns a database-compatible wrapper for this message.")
-	g.P("func (x *", typeName, ") DatabaseValue() *", wrapperName, " {")
-	g.P("	return New", wrapperName, "(x)")
+	// ProtoMessage helper for code handling wrappers of any type
+	g.P("// ProtoMessage returns the underlying message as a proto.Message, for code")
+	g.P("// that handles wrappers of different types alike. It returns a nil interface,")
+	g.P("// not a typed nil, for a nil wrapper or one holding no message.")
+	g.P("func (x ", recv, ") ProtoMessage() ", protoPackage.Ident("Message"), " {")
+	if config.ValueReceiver {
+		g.P("	if x.ProtoValue == nil || x.ProtoValue.Message == nil {")
+	} else {
+		g.P("	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {")
+	}
+	g.P("		return nil")
+	g.P("	}")
+	g.P("	return x.ProtoValue.Message")
+	g.P("}")
+	g.P()
+
+	// GetOrInit helper for safe field access
+	g.P("// GetOrInit returns the underlying protobuf message, first storing an empty")
+	g.P("// one in the wrapper if there is none, so that it never returns nil.")
+	g.P("func (x *", wrapperName, ") GetOrInit() *", m.GoIdent, " {")
+	g.P("	if x.ProtoValue == nil {")
+	g.P("		x.ProtoValue = &ProtoValue[*", m.GoIdent, "]{}")
+	g.P("	}")
+	g.P("	if x.ProtoValue.Message == nil {")
+	g.P("		x.ProtoValue.Message = &", m.GoIdent, "{}")
+	g.P("	}")
+	g.P("	return x.ProtoValue.Message")
+	g.P("}")
+	g.P()
+
+	// Merge helper for read-modify-write upserts
+	g.P("// Merge merges src into the message as proto.Merge does, first storing an")
+	g.P("// empty message in the wrapper if there is none: populated scalar fields of")
+	g.P("// src overwrite those of the message, repeated fields are appended to and map")
+	g.P("// entries added. A nil src leaves the wrapper unchanged.")
+	g.P("func (x *", wrapperName, ") Merge(src *", m.GoIdent, ") {")
+	g.P("	if src == nil {")
+	g.P("		return")
+	g.P("	}")
+	g.P("	", protoPackage.Ident("Merge"), "(x.GetOrInit(), src)")
+	g.P("}")
+	g.P()
+
+	// Reset helper for pooled wrappers
+	g.P("// Reset drops the message, leaving the wrapper like its zero value, so that")
+	g.P("// it can be reused. The message itself is not modified, since callers may")
+	g.P("// still hold it; use proto.Reset to clear a message in place.")
+	g.P("func (x *", wrapperName, ") Reset() {")
+	g.P("	x.ProtoValue = nil")
+	g.P("}")
+	g.P()
+
+	// Size helper for checking the footprint without marshaling
+	g.P("// Size returns the length of the message in the protobuf wire format, or 0")
+	if format == FormatBinary {
+		g.P("// if there is none. Value stores that many bytes unless it compresses,")
+		g.P("// encrypts or stores the message as NULL.")
+	} else {
+		g.P("// if there is none. Value stores the message in another format, so Size")
+		g.P("// does not measure what it stores.")
+	}
+	g.P("func (x ", recv, ") Size() int {")
+	g.P("	return ", protoPackage.Ident("Size"), "(x.Unwrap())")
+	g.P("}")
+	g.P()
+
+	// Clone method
+	g.P("// Clone returns a wrapper around a deep copy of the message, or nil for a")
+	g.P("// nil wrapper.")
+	g.P("func (x *", wrapperName, ") Clone() *", wrapperName, " {")
+	g.P("	if x == nil || x.ProtoValue == nil {")
+	g.P("		return nil")
+	g.P("	}")
+	g.P("	msg, _ := ", protoPackage.Ident("Clone"), "(x.ProtoValue.Message).(*", m.GoIdent, ")")
+	if config.FunctionalOptions {
+		g.P("	return &", wrapperName, "{ProtoValue: &ProtoValue[*", m.GoIdent, "]{Message: msg, codec: x.ProtoValue.codec}}")
+	} else {
+		g.P("	return &", wrapperName, "{ProtoValue: &ProtoValue[*", m.GoIdent, "]{Message: msg}}")
+	}
+	g.P("}")
+	g.P()
+
+	// Equal method
+	g.P("// Equal reports whether x and other wrap equal messages. Nil wrappers are")
+	g.P("// equal to each other but not to a wrapper holding a message.")
+	g.P("func (x ", recv, ") Equal(other *", wrapperName, ") bool {")
+	if config.ValueReceiver {
+		g.P("	var b *", m.GoIdent)
+		g.P("	if other != nil {")
+		g.P("		b = other.Unwrap()")
+		g.P("	}")
+		g.P("	return ", protoPackage.Ident("Equal"), "(x.Unwrap(), b)")
+	} else {
+		g.P("	var a, b *", m.GoIdent)
+		g.P("	if x != nil {")
+		g.P("		a = x.Unwrap()")
+		g.P("	}")
+		g.P("	if other != nil {")
+		g.P("		b = other.Unwrap()")
+		g.P("	}")
+		g.P("	return ", protoPackage.Ident("Equal"), "(a, b)")
+	}
+	g.P("}")
+	g.P()
+
+	// String method for logs and test failures
+	if config.ValueReceiver {
+		g.P("// String returns the message in prototext form, or \"<nil>\" if there is none.")
+		g.P("func (x ", recv, ") String() string {")
+		g.P("	if x.ProtoValue == nil || x.ProtoValue.Message == nil {")
+	} else {
+		g.P("// String returns the message in prototext form, or \"<nil>\" if there is none")
+		g.P("// or the wrapper is nil.")
+		g.P("func (x ", recv, ") String() string {")
+		g.P("	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {")
+	}
+	g.P(`		return "<nil>"`)
+	g.P("	}")
+	g.P("	return x.ProtoValue.Message.String()")
+	g.P("}")
+	g.P()
+
+	// Binary methods, sharing the encoding of Value/Scan
+	g.P("// MarshalBinary implements encoding.BinaryMarshaler with the same encoding as")
+	g.P("// Value. A nil message encodes as an empty one.")
+	g.P("func (x *", wrapperName, ") MarshalBinary() ([]byte, error) {")
+	g.P("	msg := x.Unwrap()")
+	g.P("	if msg == nil {")
+	g.P("		msg = &", m.GoIdent, "{}")
+	g.P("	}")
+	g.P("	return ", codec, ".Encode(msg)")
+	g.P("}")
+	g.P()
+	g.P("// UnmarshalBinary implements encoding.BinaryUnmarshaler with the same decoding")
+	g.P("// as Scan.")
+	g.P("func (x *", wrapperName, ") UnmarshalBinary(data []byte) error {")
+	if config.FunctionalOptions {
+		g.P("	p := &ProtoValue[*", m.GoIdent, "]{Message: &", m.GoIdent, "{}}")
+		g.P("	if x.ProtoValue != nil {")
+		g.P("		p.codec = x.ProtoValue.codec")
+		g.P("	}")
+		g.P("	x.ProtoValue = p")
+	} else {
+		g.P("	x.ProtoValue = &ProtoValue[*", m.GoIdent, "]{Message: &", m.GoIdent, "{}}")
+	}
+	g.P("	return ", codec, ".Decode(data, x.ProtoValue.Message)")
+	g.P("}")
+	g.P()
+	g.P("// WriteTo implements io.WriterTo, writing the encoding of MarshalBinary to w.")
+	g.P("func (x *", wrapperName, ") WriteTo(w ", ioPackage.Ident("Writer"), ") (int64, error) {")
+	g.P("	data, err := x.MarshalBinary()")
+	g.P("	if err != nil {")
+	g.P("		return 0, err")
+	g.P("	}")
+	g.P("	n, err := w.Write(data)")
+	g.P("	return int64(n), err")
+	g.P("}")
+	g.P()
+	if config.MaxScanSize > 0 {
+		g.P("// ReadFrom implements io.ReaderFrom, reading r to EOF and decoding the bytes")
+		g.P("// like UnmarshalBinary. Like Scan, it reads at most MaxScanSize bytes.")
+	} else {
+		g.P("// ReadFrom implements io.ReaderFrom, reading r to EOF and decoding the bytes")
+		g.P("// like UnmarshalBinary.")
+	}
+	g.P("func (x *", wrapperName, ") ReadFrom(r ", ioPackage.Ident("Reader"), ") (int64, error) {")
+	if config.MaxScanSize > 0 {
+		g.P("	if MaxScanSize > 0 {")
+		g.P("		r = ", ioPackage.Ident("LimitReader"), "(r, int64(MaxScanSize)+1)")
+		g.P("	}")
+	}
+	g.P("	data, err := ", ioPackage.Ident("ReadAll"), "(r)")
+	g.P("	if err != nil {")
+	g.P("		return int64(len(data)), err")
+	g.P("	}")
+	if config.MaxScanSize > 0 {
+		g.P("	if MaxScanSize > 0 && len(data) > MaxScanSize {")
+		g.P("		return int64(len(data)), dbtypesWrapError((*", m.GoIdent, ")(nil), ", fmtPackage.Ident("Errorf"), `("%w: more than %d bytes", ErrScanTooLarge, MaxScanSize))`)
+		g.P("	}")
+	}
+	g.P("	return int64(len(data)), x.UnmarshalBinary(data)")
+	g.P("}")
+	g.P()
+	g.P("var (")
+	g.P("	_ ", encodingPackage.Ident("BinaryMarshaler"), "   = (*", wrapperName, ")(nil)")
+	g.P("	_ ", encodingPackage.Ident("BinaryUnmarshaler"), " = (*", wrapperName, ")(nil)")
+	g.P("	_ ", ioPackage.Ident("WriterTo"), "          = (*", wrapperName, ")(nil)")
+	g.P("	_ ", ioPackage.Ident("ReaderFrom"), "        = (*", wrapperName, ")(nil)")
+	g.P(")")
+	g.P()
+
+	// gob methods, sharing the binary encoding
+	g.P("// GobEncode implements gob.GobEncoder with the same encoding as MarshalBinary.")
+	g.P("// A nil wrapper or message encodes as no bytes.")
+	g.P("func (x ", wrapperName, ") GobEncode() ([]byte, error) {")
+	g.P("	if x.Unwrap() == nil {")
+	g.P("		return []byte{}, nil")
+	g.P("	}")
+	g.P("	return x.MarshalBinary()")
+	g.P("}")
+	g.P()
+	g.P("// GobDecode implements gob.GobDecoder with the same decoding as")
+	g.P("// UnmarshalBinary. No bytes decode as a nil message.")
+	g.P("func (x *", wrapperName, ") GobDecode(data []byte) error {")
+	g.P("	if len(data) == 0 {")
+	g.P("		x.ProtoValue = nil")
+	g.P("		return nil")
+	g.P("	}")
+	g.P("	return x.UnmarshalBinary(data)")
+	g.P("}")
+	g.P()
+	g.P("var (")
+	g.P("	_ ", gobPackage.Ident("GobEncoder"), " = (*", wrapperName, ")(nil)")
+	g.P("	_ ", gobPackage.Ident("GobDecoder"), " = (*", wrapperName, ")(nil)")
+	g.P(")")
+	g.P()
+
+	// JSON methods, independent of the storage format
+	g.P("// MarshalJSON implements json.Marshaler using protojson. A nil message")
+	g.P("// encodes as null.")
+	g.P("func (x ", wrapperName, ") MarshalJSON() ([]byte, error) {")
+	g.P("	if x.ProtoValue == nil || x.ProtoValue.Message == nil {")
+	g.P(`		return []byte("null"), nil`)
+	g.P("	}")
+	g.P("	return dbtypesMarshalJSON(x.ProtoValue.Message)")
+	g.P("}")
+	g.P()
+	g.P("// UnmarshalJSON implements json.Unmarshaler using protojson.")
+	g.P("func (x *", wrapperName, ") UnmarshalJSON(data []byte) error {")
+	g.P(`	if string(data) == "null" {`)
+	g.P("		x.ProtoValue = nil")
+	g.P("		return nil")
+	g.P("	}")
+	g.P("	msg := &", m.GoIdent, "{}")
+	g.P("	if err := dbtypesUnmarshalJSON(data, msg); err != nil {")
+	g.P("		return err")
+	g.P("	}")
+	g.P("	x.ProtoValue = &ProtoValue[*", m.GoIdent, "]{Message: msg}")
+	g.P("	return nil")
+	g.P("}")
+	g.P()
+
+	// Text methods, independent of the storage format
+	g.P("// MarshalText implements encoding.TextMarshaler using prototext. A nil")
+	g.P("// wrapper or message encodes as empty text.")
+	g.P("func (x *", wrapperName, ") MarshalText() ([]byte, error) {")
+	g.P("	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {")
+	g.P("		return []byte{}, nil")
+	g.P("	}")
+	g.P("	// Appending keeps the result non-nil for an empty message")
+	g.P("	return ", prototextPackage.Ident("MarshalOptions"), "{", config.withPartial(""), "}.MarshalAppend([]byte{}, x.ProtoValue.Message)")
+	g.P("}")
+	g.P()
+	g.P("// UnmarshalText implements encoding.TextUnmarshaler using prototext. Empty")
+	g.P("// text decodes as an empty message.")
+	g.P("func (x *", wrapperName, ") UnmarshalText(data []byte) error {")
+	g.P("	msg := &", m.GoIdent, "{}")
+	g.P("	if err := ", config.formatUnmarshalFunc(FormatText), "(data, msg); err != nil {")
+	g.P("		return err")
+	g.P("	}")
+	g.P("	x.ProtoValue = &ProtoValue[*", m.GoIdent, "]{Message: msg}")
+	g.P("	return nil")
+	g.P("}")
+	g.P()
+	g.P("var (")
+	g.P("	_ ", encodingPackage.Ident("TextMarshaler"), "   = (*", wrapperName, ")(nil)")
+	g.P("	_ ", encodingPackage.Ident("TextUnmarshaler"), " = (*", wrapperName, ")(nil)")
+	g.P(")")
+	g.P()
+
+	// Batch encoder for bulk inserts
+	batch := "dbtypesEncodeBatch"
+	if config.plainWireFormat(format) && len(redactedFields(m)) == 0 {
+		batch = "dbtypesMarshalBatch"
+	}
+	g.P("// Encode", typeName, "Batch encodes msgs as ", wrapperName, ".Value would, for")
+	g.P("// bulk inserts such as COPY. Nil messages give nil entries.")
+	g.P("func Encode", typeName, "Batch(msgs []*", m.GoIdent, ") ([][]byte, error) {")
+	g.P("	return ", batch, "(msgs, func(msg *", m.GoIdent, ") (", driverPackage.Ident("Value"), ", error) {")
+	g.P("		return New", wrapperName, "(msg).Value()")
+	g.P("	})")
 	g.P("}")
 	g.P()
+
+	// Driver values for passing many messages as query arguments
+	generateValuesDoc(g, m, config)
+	g.P("func ", config.valuesName(m), "(msgs []*", m.GoIdent, ") ([]", driverPackage.Ident("Value"), ", error) {")
+	g.P("	return dbtypesValues(msgs, func(msg *", m.GoIdent, ") (", driverPackage.Ident("Value"), ", error) {")
+	g.P("		return New", wrapperName, "(msg).Value()")
+	g.P("	})")
+	g.P("}")
+	g.P()
+
+	// DatabaseValue method on the proto message, which can only be declared
+	// in the message's own package
+	if config.OutPackage == "" {
+		g.P("// DatabaseValue returns a database-compatible wrapper for this message.")
+		g.P("func (x *", typeName, ") DatabaseValue() *", wrapperName, " {")
+		g.P("	return New", wrapperName, "(x)")
+		g.P("}")
+		g.P()
+	}
+
+	generateNullWrapper(g, m, config)
+}
+
+// generateNullWrapper emits a nullable variant of the wrapper modeled on
+// sql.NullString. The wrapped value field is named after its type, since a
+// field called Value would collide with the Value method.
+func generateNullWrapper(g *protogen.GeneratedFile, m *protogen.Message, config *GeneratorConfig) {
+	typeName := m.GoIdent.GoName
+	wrapperName := config.wrapperName(m)
+	nullName := "Null" + wrapperName
+
+	// Type definition
+	g.P("// ", nullName, " represents a *", typeName, " that may be NULL.")
+	g.P("type ", nullName, " struct {")
+	g.P("	", wrapperName, " ", wrapperName)
+	g.P("	Valid bool // Valid is true if ", wrapperName, " is not NULL")
+	g.P("}")
+	g.P()
+
+	// Constructor
+	g.P("// New", nullName, " creates a new ", nullName, " that is valid if msg is non-nil.")
+	g.P("func New", nullName, "(msg *", m.GoIdent, ") ", nullName, " {")
+	g.P("	if msg == nil {")
+	g.P("		return ", nullName, "{}")
+	g.P("	}")
+	g.P("	return ", nullName, "{", wrapperName, ": *New", wrapperName, "(msg), Valid: true}")
+	g.P("}")
+	g.P()
+
+	// Scan method
+	g.P("// Scan implements sql.Scanner.")
+	g.P("func (n *", nullName, ") Scan(src any) error {")
+	g.P("	src = dbtypesScanSource(src)")
+	g.P("	if src == nil {")
+	if config.ScanNullAsEmpty {
+		g.P("		// An empty message rather than nil, so that Unwrap is always usable")
+		g.P("		n.", wrapperName, ", n.Valid = *New", wrapperName, "(&", m.GoIdent, "{}), false")
+	} else {
+		g.P("		n.", wrapperName, ", n.Valid = ", wrapperName, "{}, false")
+	}
+	g.P("		return nil")
+	g.P("	}")
+	g.P("	err := n.", wrapperName, ".Scan(src)")
+	g.P("	n.Valid = err == nil")
+	g.P("	return err")
+	g.P("}")
+	g.P()
+
+	// Value method
+	g.P("// Value implements driver.Valuer.")
+	g.P("func (n ", nullName, ") Value() (", driverPackage.Ident("Value"), ", error) {")
+	g.P("	if !n.Valid {")
+	g.P("		return nil, nil")
+	g.P("	}")
+	if config.FunctionalOptions {
+		// Value of the wrapper itself keeps the format WithFormat selected
+		g.P("	if n.", wrapperName, ".Unwrap() != nil {")
+		g.P("		return n.", wrapperName, ".Value()")
+		g.P("	}")
+		g.P("	return New", wrapperName, "(&", m.GoIdent, "{}).Value()")
+	} else {
+		g.P("	msg := n.", wrapperName, ".Unwrap()")
+		g.P("	if msg == nil {")
+		g.P("		msg = &", m.GoIdent, "{}")
+		g.P("	}")
+		g.P("	return New", wrapperName, "(msg).Value()")
+	}
+	g.P("}")
+	g.P()
+	generateSQLAssertions(g, nullName)
+}
+
+// generateSQLAssertions declares that typeName implements driver.Valuer and
+// sql.Scanner, so that a change to either method fails to compile in the
+// generated package rather than at a distant call site.
+func generateSQLAssertions(g *protogen.GeneratedFile, typeName string) {
+	g.P("var (")
+	g.P("	_ ", driverPackage.Ident("Valuer"), " = (*", typeName, ")(nil)")
+	g.P("	_ ", sqlPackage.Ident("Scanner"), "  = (*", typeName, ")(nil)")
+	g.P(")")
+	g.P()
 }
@@ -0,0 +1,3339 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	gengo "google.golang.org/protobuf/cmd/protoc-gen-go/internal_gengo"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/pluginpb"
+	"gopkg.in/yaml.v3"
+
+	dbtypespb "github.com/cadenya/protoc-gen-go-dbtypes/gen/go/dbtypes"
+	testv1 "github.com/cadenya/protoc-gen-go-dbtypes/gen/go/test/v1"
+)
+
+// testFiles are the proto files of the test fixture package.
+var testFiles = []protoreflect.FileDescriptor{
+	testv1.File_test_v1_enum_proto,
+	testv1.File_test_v1_format_proto,
+	testv1.File_test_v1_legacy_proto,
+	testv1.File_test_v1_oneof_proto,
+	testv1.File_test_v1_optin_proto,
+	testv1.File_test_v1_other_proto,
+	testv1.File_test_v1_presence_proto,
+	testv1.File_test_v1_redact_proto,
+	testv1.File_test_v1_skip_proto,
+	testv1.File_test_v1_test_proto,
+}
+
+// generate runs the plugin over the test fixtures with the given parameter
+// string and returns the generated files keyed by name.
+func generate(t *testing.T, param string) (map[string]string, error) {
+	t.Helper()
+	return generateFiles(t, param, testFiles...)
+}
+
+// generateFiles is like generate but runs the plugin over the given files.
+func generateFiles(t *testing.T, param string, fds ...protoreflect.FileDescriptor) (map[string]string, error) {
+	t.Helper()
+
+	var flags flag.FlagSet
+	params := bindFlags(&flags)
+	gen, err := protogen.Options{ParamFunc: flags.Set}.New(codeGeneratorRequest(param, fds...))
+	if err != nil {
+		t.Fatalf("protogen.Options.New error: %v", err)
+	}
+	if err := run(gen, params); err != nil {
+		return nil, err
+	}
+	return responseFiles(t, gen), nil
+}
+
+// generateGo returns the files protoc-gen-go generates for the given files,
+// for fixtures that only the scratch modules can compile.
+func generateGo(t *testing.T, fds ...protoreflect.FileDescriptor) map[string]string {
+	t.Helper()
+
+	gen, err := protogen.Options{}.New(codeGeneratorRequest("paths=source_relative", fds...))
+	if err != nil {
+		t.Fatalf("protogen.Options.New error: %v", err)
+	}
+	for _, f := range gen.Files {
+		if f.Generate {
+			gengo.GenerateFile(gen, f)
+		}
+	}
+	return responseFiles(t, gen)
+}
+
+// codeGeneratorRequest returns the request protoc sends a plugin to generate
+// fds, listing the files they import first.
+func codeGeneratorRequest(param string, fds ...protoreflect.FileDescriptor) *pluginpb.CodeGeneratorRequest {
+	req := &pluginpb.CodeGeneratorRequest{
+		Parameter: proto.String(param),
+	}
+	seen := make(map[string]bool)
+	var addFile func(fd protoreflect.FileDescriptor)
+	addFile = func(fd protoreflect.FileDescriptor) {
+		if seen[fd.Path()] {
+			return
+		}
+		seen[fd.Path()] = true
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			addFile(imports.Get(i).FileDescriptor)
+		}
+		req.ProtoFile = append(req.ProtoFile, protodesc.ToFileDescriptorProto(fd))
+	}
+	for _, fd := range fds {
+		req.FileToGenerate = append(req.FileToGenerate, fd.Path())
+		addFile(fd)
+	}
+	return req
+}
+
+// responseFiles returns the files gen generated, keyed by name.
+func responseFiles(t *testing.T, gen *protogen.Plugin) map[string]string {
+	t.Helper()
+
+	resp := gen.Response()
+	if resp.Error != nil {
+		t.Fatalf("generation error: %s", resp.GetError())
+	}
+	files := make(map[string]string)
+	for _, f := range resp.File {
+		files[f.GetName()] = f.GetContent()
+	}
+	return files
+}
+
+// mustGenerate is like generate but fails the test on error.
+func mustGenerate(t *testing.T, param string) map[string]string {
+	t.Helper()
+	files, err := generate(t, param)
+	if err != nil {
+		t.Fatalf("run() error: %v", err)
+	}
+	return files
+}
+
+// runGeneratedTests generates the fixtures with the given parameter string
+// into a scratch module next to the fixture .pb.go files and runs the
+// fixture test suite, plus any extra test files from testdata, against the
+// result.
+func runGeneratedTests(t *testing.T, param string, extraTests ...string) {
+	t.Helper()
+	runScratchModule(t, scratchModule{param: param, tests: extraTests})
+}
+
+// scratchModule describes a throwaway module that generated code is tested in.
+type scratchModule struct {
+	param    string   // plugin parameter string
+	tests    []string // extra test files copied from testdata
+	requires []string // additional module@version requirements
+	tags     string   // build tags passed to go test
+
+	// protoFiles are extra fixtures generated with protoc-gen-go, along with
+	// their wrappers, for rules the fixture package cannot depend on.
+	protoFiles []protoreflect.FileDescriptor
+
+	// skipFixtureTests leaves out the fixture test suite, for options that
+	// deliberately change the default behavior it asserts.
+	skipFixtureTests bool
+}
+
+func runScratchModule(t *testing.T, mod scratchModule) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping go test of generated code in short mode")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not available")
+	}
+
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	fixtures, err := filepath.Glob(filepath.Join(root, "gen", "go", "test", "v1", "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range fixtures {
+		if strings.Contains(filepath.Base(path), "_dbtypes") && !strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		if mod.skipFixtureTests && strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		copyFile(t, path, filepath.Join(dir, filepath.Base(path)))
+	}
+	for _, name := range mod.tests {
+		copyFile(t, filepath.Join("testdata", name), filepath.Join(dir, name))
+	}
+	copyFile(t, filepath.Join(root, "go.sum"), filepath.Join(dir, "go.sum"))
+	goMod := "module dbtypestest\n\ngo 1.21\n\n" +
+		"require (\n\tgithub.com/cadenya/protoc-gen-go-dbtypes v0.0.0\n\tgoogle.golang.org/protobuf v1.36.11\n)\n\n" +
+		"replace github.com/cadenya/protoc-gen-go-dbtypes => " + root + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	files, err := generateFiles(t, mod.param, append(slices.Clip(testFiles), mod.protoFiles...)...)
+	if err != nil {
+		t.Fatalf("run() error: %v", err)
+	}
+	if len(mod.protoFiles) > 0 {
+		maps.Copy(files, generateGo(t, mod.protoFiles...))
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, filepath.Base(name)), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	goCmd := func(args ...string) *exec.Cmd {
+		cmd := exec.Command(goTool, args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+		return cmd
+	}
+	for _, req := range mod.requires {
+		// Third-party integrations can only be checked when the module is
+		// reachable through the configured module proxy.
+		if out, err := goCmd("get", req).CombinedOutput(); err != nil {
+			t.Skipf("cannot fetch %s: %v\n%s", req, err, out)
+		}
+	}
+
+	args := []string{"test"}
+	if mod.tags != "" {
+		args = append(args, "-tags", mod.tags)
+	}
+	if out, err := goCmd(append(args, "./...")...).CombinedOutput(); err != nil {
+		t.Fatalf("go test of generated code failed: %v\n%s", err, out)
+	}
+}
+
+// funcSource returns the source of the generated function or method whose
+// declaration starts with decl, e.g. "func (x *ToolSetSpecValue) ValueContext(".
+func funcSource(t *testing.T, content, decl string) string {
+	t.Helper()
+	start := strings.Index(content, decl)
+	if start < 0 {
+		t.Fatalf("generated code has no %q", decl)
+	}
+	end := strings.Index(content[start:], "\n}\n")
+	if end < 0 {
+		t.Fatalf("unterminated %q", decl)
+	}
+	return content[start : start+end+3]
+}
+
+// messageCodec returns the marshal and unmarshal functions of the codec the
+// wrapper of the message typeName goes through.
+func messageCodec(t *testing.T, files map[string]string, typeName string) (marshal, unmarshal string) {
+	t.Helper()
+	accessor := regexp.MustCompile(`func ` + typeName + `Codec\(\) Codec \{ return (\w+) \}`)
+	var codec string
+	for _, content := range files {
+		if m := accessor.FindStringSubmatch(content); m != nil {
+			codec = m[1]
+		}
+	}
+	if codec == "" {
+		t.Fatalf("generated code has no %sCodec", typeName)
+	}
+	decl := regexp.MustCompile(`(?m)^\t` + codec + ` +Codec = &dbtypesCodec\{marshal: (.*), unmarshal: (.*)\}$`)
+	for _, content := range files {
+		if m := decl.FindStringSubmatch(content); m != nil {
+			return m[1], m[2]
+		}
+	}
+	t.Fatalf("generated code does not declare %s", codec)
+	return "", ""
+}
+
+func copyFile(t *testing.T, src, dst string) {
+	t.Helper()
+	data, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dst, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGenerate_DefaultFormat(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative")
+
+	content := files["test/v1/test_dbtypes.pb.go"]
+	marshal, unmarshal := messageCodec(t, files, "ToolSetSpec")
+	if marshal != "proto.Marshal" {
+		t.Errorf("binary Value() should use proto.Marshal, not %s", marshal)
+	}
+	if unmarshal != "proto.Unmarshal" {
+		t.Errorf("binary Scan() should use proto.Unmarshal, not %s", unmarshal)
+	}
+	if strings.Contains(funcSource(t, content, "func (x *ToolSetSpecValue) ValueContext(ctx context.Context) (driver.Value, error)"), "protojson") {
+		t.Error("binary Value() should not use protojson")
+	}
+}
+
+func TestGenerate_WrapperDoc(t *testing.T) {
+	// The compiled-in descriptors carry no comments, so give ToolSetSpec the
+	// leading comment protoc would report for test.proto.
+	fdp := protodesc.ToFileDescriptorProto(testv1.File_test_v1_test_proto)
+	fdp.SourceCodeInfo = &descriptorpb.SourceCodeInfo{
+		Location: []*descriptorpb.SourceCodeInfo_Location{{
+			Path:            []int32{4, 0}, // message_type[0]
+			Span:            []int32{7, 0, 11, 1},
+			LeadingComments: proto.String(" ToolSetSpec represents a set of tools configuration.\n"),
+		}},
+	}
+	fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile error: %v", err)
+	}
+
+	for param, want := range map[string]string{
+		"paths=source_relative": "// ToolSetSpecValue is a database-serializable wrapper around test.v1.ToolSetSpec.\n" +
+			"// Value stores it as protobuf binary.\n" +
+			"//\n" +
+			"// ToolSetSpec represents a set of tools configuration.\n" +
+			"type ToolSetSpecValue struct {",
+		"paths=source_relative,format=json,generic=true": "// ToolSetSpecValue is a database-serializable wrapper around test.v1.ToolSetSpec.\n" +
+			"// Value stores it as protojson.\n" +
+			"//\n" +
+			"// ToolSetSpec represents a set of tools configuration.\n" +
+			"type ToolSetSpecValue = dbtypes.JSONValue[*ToolSetSpec]",
+		"paths=source_relative,compress=gzip": "// Value stores it as gzip-compressed protobuf binary.\n",
+	} {
+		files, err := generateFiles(t, param, fd)
+		if err != nil {
+			t.Fatalf("%s: generate error: %v", param, err)
+		}
+		if content := files["test/v1/test_dbtypes.pb.go"]; !strings.Contains(content, want) {
+			t.Errorf("%s: generated code should contain %q", param, want)
+		}
+	}
+
+	// Without a comment in the proto file, the doc comment ends at the format
+	files := mustGenerate(t, "paths=source_relative")
+	want := "// Value stores it as protobuf binary.\ntype ToolSetSpecValue struct {"
+	if !strings.Contains(files["test/v1/test_dbtypes.pb.go"], want) {
+		t.Errorf("generated code should contain %q", want)
+	}
+}
+
+func TestGenerate_MessageFilters(t *testing.T) {
+	all := []string{"JSONDocument", "BinaryDocument", "TextDocument", "AnotherMessage", "SecondMessage", "ToolSetSpec", "UserPreferences", "Container"}
+	tests := []struct {
+		param string
+		want  []string
+	}{
+		{"", all},
+		{"include-regex=Document$", []string{"JSONDocument", "BinaryDocument", "TextDocument"}},
+		{"include-regex=^test\\.v1\\.(ToolSetSpec|Container)$", []string{"ToolSetSpec", "Container"}},
+		{"exclude=ToolSetSpec", []string{"JSONDocument", "BinaryDocument", "TextDocument", "AnotherMessage", "SecondMessage", "UserPreferences", "Container"}},
+		{"include-regex=Document$,exclude=test.v1.BinaryDocument", []string{"JSONDocument", "TextDocument"}},
+	}
+	for _, tt := range tests {
+		var generated strings.Builder
+		for _, content := range mustGenerate(t, "paths=source_relative,"+tt.param) {
+			generated.WriteString(content)
+		}
+		want := make(map[string]bool)
+		for _, name := range tt.want {
+			want[name] = true
+		}
+		for _, name := range all {
+			got := strings.Contains(generated.String(), "type "+name+"Value struct")
+			if got != want[name] {
+				t.Errorf("%q: %sValue generated = %v, want %v", tt.param, name, got, want[name])
+			}
+		}
+	}
+}
+
+func TestGenerate_ExcludeNames(t *testing.T) {
+	// test.collide.ToolSetSpec shares its simple name with the fixture
+	fds := append([]protoreflect.FileDescriptor{collisionFile(t, map[string][]string{"ToolSetSpec": nil})}, testFiles...)
+	tests := []struct {
+		param                  string
+		fixture, other, nested bool
+	}{
+		{"", true, true, true},
+		{"exclude=ToolSetSpec", false, false, true},
+		{"exclude=test.v1.ToolSetSpec", false, true, true},
+		{"exclude=test.collide.ToolSetSpec", true, false, true},
+		{"exclude=test.v1.ToolSetSpec,exclude=test.collide.ToolSetSpec", false, false, true},
+		{"exclude=Container_Item", true, true, false},
+		{"exclude=Container.Item", true, true, false},
+		{"exclude=test.v1.Container.Item", true, true, false},
+		// Only the names above identify a nested message
+		{"exclude=Item", true, true, true},
+		{"exclude=v1.Container.Item", true, true, true},
+	}
+	for _, tt := range tests {
+		files, err := generateFiles(t, "paths=source_relative,nested=include,"+tt.param, fds...)
+		if err != nil {
+			t.Fatalf("%q: run() error: %v", tt.param, err)
+		}
+		fixture := files["test/v1/test_dbtypes.pb.go"]
+		for _, check := range []struct {
+			what    string
+			content string
+			decl    string
+			want    bool
+		}{
+			{"test.v1.ToolSetSpec", fixture, "type ToolSetSpecValue struct", tt.fixture},
+			{"test.collide.ToolSetSpec", files["collide/v1/collide_dbtypes.pb.go"], "type ToolSetSpecValue struct", tt.other},
+			{"test.v1.Container.Item", fixture, "type Container_ItemValue struct", tt.nested},
+		} {
+			if got := strings.Contains(check.content, check.decl); got != check.want {
+				t.Errorf("%q: %s wrapped = %v, want %v", tt.param, check.what, got, check.want)
+			}
+		}
+	}
+}
+
+func TestGenerate_PackageFilter(t *testing.T) {
+	// The fixtures are in test.v1, the extra file in test.collide
+	fds := append([]protoreflect.FileDescriptor{collisionFile(t, map[string][]string{"Record": nil})}, testFiles...)
+	tests := []struct {
+		param           string
+		fixtures, other bool
+	}{
+		{"", true, true},
+		{"package=test.v1", true, false},
+		{"package=test.collide", false, true},
+		{"package=test.v1,package=test.collide", true, true},
+		{"package=test.v1,package=example.v1", true, false},
+		{"package=example.v1", false, false},
+	}
+	for _, tt := range tests {
+		files, err := generateFiles(t, "paths=source_relative,"+tt.param, fds...)
+		if err != nil {
+			t.Fatalf("%q: run() error: %v", tt.param, err)
+		}
+		if _, got := files["test/v1/test_dbtypes.pb.go"]; got != tt.fixtures {
+			t.Errorf("%q: test.v1 generated = %v, want %v", tt.param, got, tt.fixtures)
+		}
+		if _, got := files["collide/v1/collide_dbtypes.pb.go"]; got != tt.other {
+			t.Errorf("%q: test.collide generated = %v, want %v", tt.param, got, tt.other)
+		}
+	}
+}
+
+func TestGenerate_SkipsMapEntries(t *testing.T) {
+	// UserPreferences.settings and the other map fields have synthetic entry
+	// messages, which no one wraps, even when a filter names them.
+	for _, param := range []string{"paths=source_relative", "paths=source_relative,include-regex=Entry$"} {
+		for name, content := range mustGenerate(t, param) {
+			if strings.Contains(content, "EntryValue") {
+				t.Errorf("%q: %s declares a wrapper for a map entry", param, name)
+			}
+		}
+	}
+	content := mustGenerate(t, "paths=source_relative")["test/v1/test_dbtypes.pb.go"]
+	if !strings.Contains(content, "type UserPreferencesValue struct") {
+		t.Error("UserPreferences has a map field and should still be wrapped")
+	}
+}
+
+func TestGenerate_Nested(t *testing.T) {
+	for _, param := range []string{"", ",nested=skip"} {
+		content := mustGenerate(t, "paths=source_relative"+param)["test/v1/test_dbtypes.pb.go"]
+		if strings.Contains(content, "Container_ItemValue") {
+			t.Errorf("%q: nested message Container.Item should not be wrapped", param)
+		}
+		if !strings.Contains(content, "type ContainerValue struct") {
+			t.Errorf("%q: top-level message Container should still be wrapped", param)
+		}
+	}
+
+	content := mustGenerate(t, "paths=source_relative,nested=include")["test/v1/test_dbtypes.pb.go"]
+	for _, want := range []string{"type ContainerValue struct", "type Container_ItemValue struct", "type NullContainer_ItemValue struct"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("nested=include should generate %q", want)
+		}
+	}
+	if strings.Contains(content, "EntryValue") {
+		t.Error("nested=include should still skip map entries")
+	}
+
+	// The filters apply to nested messages like any other.
+	content = mustGenerate(t, "paths=source_relative,nested=include,include-regex=^test\\.v1\\.Container\\.")["test/v1/test_dbtypes.pb.go"]
+	if !strings.Contains(content, "type Container_ItemValue struct") || strings.Contains(content, "type ContainerValue struct") {
+		t.Error("include-regex should select the nested message alone")
+	}
+	content = mustGenerate(t, "paths=source_relative,nested=include,exclude=Container_Item")["test/v1/test_dbtypes.pb.go"]
+	if strings.Contains(content, "Container_ItemValue") {
+		t.Error("exclude should apply to nested messages")
+	}
+}
+
+func TestGenerate_UnknownNested(t *testing.T) {
+	if _, err := generate(t, "nested=all"); err == nil {
+		t.Error("nested=all should fail generation")
+	}
+}
+
+func TestGeneratedCode_Nested(t *testing.T) {
+	runGeneratedTests(t, "paths=source_relative,nested=include", "nested_test.go")
+}
+
+func TestGenerate_RequireOptIn(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative,require-opt-in=true")
+
+	content := files["test/v1/optin_dbtypes.pb.go"]
+	if !strings.Contains(content, "type OptInRecordValue struct") {
+		t.Error("OptInRecord sets (dbtypes.generate) and should be generated")
+	}
+	if strings.Contains(content, "PlainRecordValue") {
+		t.Error("PlainRecord lacks (dbtypes.generate) and should be skipped")
+	}
+	if _, ok := files["test/v1/test_dbtypes.pb.go"]; ok {
+		t.Error("test.proto has no opted-in messages and should produce no file")
+	}
+	if !strings.Contains(content, "type ProtoValue[T proto.Message] struct") {
+		t.Error("the only generated file should declare ProtoValue")
+	}
+
+	// The option narrows the other filters rather than overriding them.
+	files = mustGenerate(t, "paths=source_relative,require-opt-in=true,exclude=OptInRecord")
+	if _, ok := files["test/v1/optin_dbtypes.pb.go"]; ok {
+		t.Error("exclude should still apply to opted-in messages")
+	}
+
+	if !strings.Contains(mustGenerate(t, "paths=source_relative")["test/v1/optin_dbtypes.pb.go"], "type PlainRecordValue struct") {
+		t.Error("without require-opt-in every message should be generated")
+	}
+}
+
+func TestGenerate_TypeSuffix(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative,type-suffix=DB,orm=ent")
+
+	content := files["test/v1/test_dbtypes.pb.go"]
+	for _, decl := range []string{
+		"type ToolSetSpecDB struct",
+		"func NewToolSetSpecDB(msg *ToolSetSpec) *ToolSetSpecDB",
+		"func (x *ToolSetSpecDB) Value() (driver.Value, error)",
+		"func (x *ToolSetSpec) DatabaseValue() *ToolSetSpecDB",
+		"type NullToolSetSpecDB struct",
+	} {
+		if !strings.Contains(content, decl) {
+			t.Errorf("type-suffix=DB: generated code has no %q", decl)
+		}
+	}
+	if strings.Contains(content, "ToolSetSpecValue") {
+		t.Error("type-suffix=DB: generated code still uses the Value suffix")
+	}
+	if !strings.Contains(files["test/v1/test_dbtypes_ent.pb.go"], "type ToolSetSpecDBScanner struct") {
+		t.Error("type-suffix=DB: ent scanner should be named after the wrapper")
+	}
+
+	for _, suffix := range []string{"", "-DB", "D B"} {
+		if _, err := generate(t, "type-suffix="+suffix); err == nil {
+			t.Errorf("type-suffix=%q should fail generation", suffix)
+		}
+	}
+}
+
+func TestGeneratedCode_TypeSuffix(t *testing.T) {
+	for _, param := range []string{"type-suffix=DB", "type-suffix=DB,generic=true"} {
+		t.Run(param, func(t *testing.T) {
+			runScratchModule(t, scratchModule{
+				param:            "paths=source_relative," + param,
+				tests:            []string{"type_suffix_test.go"},
+				skipFixtureTests: true,
+			})
+		})
+	}
+}
+
+func TestGenerate_FilenameSuffix(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative,filename-suffix=.dbv.go,orm=gorm,orm=ent,validate=true")
+
+	for _, name := range []string{
+		"test/v1/test.dbv.go",
+		"test/v1/test_gorm.dbv.go",
+		"test/v1/test_ent.dbv.go",
+		"test/v1/test_validate.dbv.go",
+	} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("filename-suffix=.dbv.go should generate %s", name)
+		}
+	}
+	for name := range files {
+		if strings.Contains(name, "_dbtypes") {
+			t.Errorf("filename-suffix=.dbv.go generated %s with the default suffix", name)
+		}
+	}
+	if !strings.Contains(files["test/v1/test.dbv.go"], "type ToolSetSpecValue struct") {
+		t.Error("filename-suffix should not change type names")
+	}
+
+	for _, suffix := range []string{"", ".dbv", "x/.go"} {
+		if _, err := generate(t, "filename-suffix="+suffix); err == nil {
+			t.Errorf("filename-suffix=%q should fail generation", suffix)
+		}
+	}
+}
+
+func TestGeneratedCode_FilenameSuffix(t *testing.T) {
+	runGeneratedTests(t, "paths=source_relative,filename-suffix=.dbv.go")
+}
+
+func TestGenerate_OutPackage(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative,out-package=dbtypes,orm=ent")
+
+	if _, ok := files["test/v1/test_dbtypes.pb.go"]; ok {
+		t.Error("out-package should move the wrappers out of the proto package directory")
+	}
+	for _, name := range []string{"test/v1/dbtypes/test_dbtypes.pb.go", "test/v1/dbtypes/test_dbtypes_ent.pb.go"} {
+		content, ok := files[name]
+		if !ok {
+			t.Errorf("out-package=dbtypes should generate %s", name)
+			continue
+		}
+		if !strings.Contains(content, "\npackage dbtypes\n") {
+			t.Errorf("%s should declare package dbtypes", name)
+		}
+		if !strings.Contains(content, `v1 "github.com/cadenya-agents/protoc-gen-go-dbtypes/gen/go/test/v1"`) {
+			t.Errorf("%s should import the proto package", name)
+		}
+	}
+
+	content := files["test/v1/dbtypes/test_dbtypes.pb.go"]
+	if !strings.Contains(content, "*ProtoValue[*v1.ToolSetSpec]") {
+		t.Error("wrappers should refer to the message type through the proto package")
+	}
+	if strings.Contains(content, "DatabaseValue") {
+		t.Error("DatabaseValue cannot be declared outside the message's package")
+	}
+
+	// ProtoValue is emitted once for the output package, not once per file.
+	var declared int
+	for name, content := range files {
+		if strings.HasPrefix(name, "test/v1/dbtypes/") {
+			declared += strings.Count(content, "type ProtoValue[T proto.Message] struct")
+		}
+	}
+	if declared != 1 {
+		t.Errorf("ProtoValue declared %d times in the output package, want 1", declared)
+	}
+
+	for _, name := range []string{"db/types", "1db", "_"} {
+		if _, err := generate(t, "out-package="+name); err == nil {
+			t.Errorf("out-package=%q should fail generation", name)
+		}
+	}
+}
+
+// collisionFile builds a proto file in package test.collide declaring
+// messages with the given fields.
+func collisionFile(t *testing.T, messages map[string][]string) protoreflect.FileDescriptor {
+	t.Helper()
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("collide/v1/collide.proto"),
+		Package: proto.String("test.collide"),
+		Syntax:  proto.String("proto3"),
+		Options: &descriptorpb.FileOptions{
+			GoPackage: proto.String("example.com/collide/v1;collidev1"),
+		},
+	}
+	for name, fields := range messages {
+		msg := &descriptorpb.DescriptorProto{Name: proto.String(name)}
+		for i, field := range fields {
+			msg.Field = append(msg.Field, &descriptorpb.FieldDescriptorProto{
+				Name:     proto.String(field),
+				JsonName: proto.String(field),
+				Number:   proto.Int32(int32(i + 1)),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			})
+		}
+		fdp.MessageType = append(fdp.MessageType, msg)
+	}
+	fd, err := protodesc.NewFile(fdp, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fd
+}
+
+func TestGenerate_Collisions(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages map[string][]string
+		param    string
+		wantErr  string // substring of the error, or "" for success
+	}{
+		{
+			name:     "field named like DatabaseValue",
+			messages: map[string][]string{"Record": {"database_value"}},
+			wantErr:  "test.collide.Record: generated method DatabaseValue collides with field database_value",
+		},
+		{
+			name:     "DatabaseValue not generated into another package",
+			messages: map[string][]string{"Record": {"database_value"}},
+			param:    "out-package=dbtypes",
+		},
+		{
+			// The generated Value method is declared on RecordValue, not on
+			// Record, so a field called value is fine.
+			name:     "field named value",
+			messages: map[string][]string{"Record": {"value", "scan", "unwrap"}},
+		},
+		{
+			name:     "message named like a wrapper",
+			messages: map[string][]string{"Spec": nil, "SpecValue": nil},
+			wantErr:  "test.collide.Spec: generated identifier SpecValue collides with message test.collide.SpecValue",
+		},
+		{
+			name:     "message named like a wrapper with another suffix",
+			messages: map[string][]string{"Spec": nil, "SpecValue": nil},
+			param:    "type-suffix=DB",
+		},
+		{
+			name:     "message named like a null wrapper",
+			messages: map[string][]string{"Spec": nil, "NullSpecValue": nil},
+			param:    "exclude=NullSpecValue",
+			wantErr:  "generated identifier NullSpecValue collides with message test.collide.NullSpecValue",
+		},
+		{
+			name:     "message named ProtoValue",
+			messages: map[string][]string{"ProtoValue": nil},
+			wantErr:  "generated type ProtoValue collides with message test.collide.ProtoValue",
+		},
+		{
+			name:     "message named ProtoValue with generic runtime types",
+			messages: map[string][]string{"ProtoValue": nil},
+			param:    "generic=true",
+		},
+		{
+			name:     "message named like a marshal hook",
+			messages: map[string][]string{"Unmarshal": nil},
+			param:    "marshal-hooks=true",
+			wantErr:  "generated variable Unmarshal collides with message test.collide.Unmarshal",
+		},
+		{
+			name:     "message named DetectFormat",
+			messages: map[string][]string{"DetectFormat": nil},
+			param:    "auto-scan=true",
+			wantErr:  "generated variable DetectFormat collides with message test.collide.DetectFormat",
+		},
+		{
+			name:     "message named ExpectedChecksum",
+			messages: map[string][]string{"ExpectedChecksum": nil},
+			param:    "emit-checksum=true",
+			wantErr:  "generated variable ExpectedChecksum collides with message test.collide.ExpectedChecksum",
+		},
+		{
+			name:     "message named ColumnInfo",
+			messages: map[string][]string{"Spec": nil, "ColumnInfo": nil},
+			param:    "emit-columns=true,exclude=ColumnInfo",
+			wantErr:  "generated type ColumnInfo collides with message test.collide.ColumnInfo",
+		},
+		{
+			name:     "message named like the column of another",
+			messages: map[string][]string{"Spec": nil, "SpecColumn": nil},
+			param:    "emit-columns=true,exclude=SpecColumn",
+			wantErr:  "generated identifier SpecColumn collides with message test.collide.SpecColumn",
+		},
+		{
+			name:     "message named DBTX",
+			messages: map[string][]string{"Spec": nil, "DBTX": nil},
+			param:    "emit-repo=true,exclude=DBTX",
+			wantErr:  "generated type DBTX collides with message test.collide.DBTX",
+		},
+		{
+			name:     "message named like the store of another",
+			messages: map[string][]string{"Spec": nil, "SpecStore": nil},
+			param:    "emit-repo=true,exclude=SpecStore",
+			wantErr:  "generated identifier SpecStore collides with message test.collide.SpecStore",
+		},
+		{
+			name:     "message named Option",
+			messages: map[string][]string{"Spec": nil, "Option": nil},
+			param:    "functional-options=true,exclude=Option",
+			wantErr:  "generated identifier Option collides with message test.collide.Option",
+		},
+		{
+			name:     "message named like a marshal hook without hooks",
+			messages: map[string][]string{"Unmarshal": nil},
+		},
+		{
+			name:     "message named DBTypeRegistry",
+			messages: map[string][]string{"Spec": nil, "DBTypeRegistry": nil},
+			param:    "exclude=DBTypeRegistry",
+			wantErr:  "generated variable DBTypeRegistry collides with message test.collide.DBTypeRegistry",
+		},
+		{
+			name:     "message named like a codec accessor",
+			messages: map[string][]string{"Spec": nil, "SpecCodec": nil},
+			param:    "exclude=SpecCodec",
+			wantErr:  "test.collide.Spec: generated identifier SpecCodec collides with message test.collide.SpecCodec",
+		},
+		{
+			name:     "message named Codec",
+			messages: map[string][]string{"Spec": nil, "Codec": nil},
+			param:    "exclude=Codec",
+			wantErr:  "generated type Codec collides with message test.collide.Codec",
+		},
+		{
+			name:     "message named like a metrics hook",
+			messages: map[string][]string{"OnScanError": nil},
+			param:    "metrics-hooks=true",
+			wantErr:  "generated variable OnScanError collides with message test.collide.OnScanError",
+		},
+		{
+			name:     "message named FieldChange",
+			messages: map[string][]string{"Spec": nil, "FieldChange": nil},
+			param:    "emit-diff=true,exclude=FieldChange",
+			wantErr:  "generated type FieldChange collides with message test.collide.FieldChange",
+		},
+		{
+			name:     "message named like a diff function",
+			messages: map[string][]string{"Spec": nil, "DiffSpec": nil},
+			param:    "emit-diff=true,exclude=DiffSpec",
+			wantErr:  "generated identifier DiffSpec collides with message test.collide.DiffSpec",
+		},
+		{
+			name:     "message named Tracer",
+			messages: map[string][]string{"Tracer": nil},
+			param:    "otel=true",
+			wantErr:  "generated variable Tracer collides with message test.collide.Tracer",
+		},
+		{
+			name:     "message named like a JSON path constant",
+			messages: map[string][]string{"Spec": {"name"}, "SpecPathName": nil},
+			param:    "emit-json-paths=true,exclude=SpecPathName",
+			wantErr:  "test.collide.Spec: generated identifier SpecPathName collides with message test.collide.SpecPathName",
+		},
+		{
+			name:     "message named like a migrator",
+			messages: map[string][]string{"Spec": nil, "ConvertSpec": nil},
+			param:    "emit-migrators=true,exclude=ConvertSpec",
+			wantErr:  "test.collide.Spec: generated identifier ConvertSpec collides with message test.collide.ConvertSpec",
+		},
+		{
+			name:     "message named like a builder",
+			messages: map[string][]string{"Spec": nil, "SpecValueBuilder": nil},
+			param:    "builders=true,exclude=SpecValueBuilder",
+			wantErr:  "test.collide.Spec: generated identifier SpecValueBuilder collides with message test.collide.SpecValueBuilder",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := generateFiles(t, tt.param, collisionFile(t, tt.messages))
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("run() error = %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("run() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerate_CollisionsProto2(t *testing.T) {
+	// protoc-gen-go declares the extension as E_SpecValue and the default as
+	// Default_Spec_Name, so neither takes the wrapper's names.
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("collide/v1/legacy.proto"),
+		Package: proto.String("test.collide"),
+		Syntax:  proto.String("proto2"),
+		Options: &descriptorpb.FileOptions{
+			GoPackage: proto.String("example.com/collide/v1;collidev1"),
+		},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Spec"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:         proto.String("name"),
+				JsonName:     proto.String("name"),
+				Number:       proto.Int32(1),
+				Label:        descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:         descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				DefaultValue: proto.String("unnamed"),
+			}},
+			ExtensionRange: []*descriptorpb.DescriptorProto_ExtensionRange{{Start: proto.Int32(100), End: proto.Int32(200)}},
+		}},
+		Extension: []*descriptorpb.FieldDescriptorProto{{
+			Name:     proto.String("spec_value"),
+			JsonName: proto.String("specValue"),
+			Number:   proto.Int32(100),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			Extendee: proto.String(".test.collide.Spec"),
+		}},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := generateFiles(t, "paths=source_relative", fd)
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if !strings.Contains(files["collide/v1/legacy_dbtypes.pb.go"], "type SpecValue struct") {
+		t.Error("SpecValue should be generated for a proto2 message")
+	}
+}
+
+func TestGenerate_InvalidIncludeRegex(t *testing.T) {
+	_, err := generate(t, "include-regex=(Spec")
+	if err == nil || !strings.Contains(err.Error(), "include-regex") {
+		t.Errorf("run() error = %v, want an include-regex error", err)
+	}
+}
+
+func TestGenerate_SQLAssertions(t *testing.T) {
+	wrapperType := regexp.MustCompile(`(?m)^type (\w+Value(?:Array)?) (?:struct|=|\[\])`)
+	for _, param := range []string{"", ",generic=true", ",enums=true", ",postgres-array=true"} {
+		for name, content := range mustGenerate(t, "paths=source_relative"+param) {
+			types := wrapperType.FindAllStringSubmatch(content, -1)
+			if len(types) == 0 && strings.HasSuffix(name, "_dbtypes.pb.go") {
+				t.Errorf("%q: %s declares no wrappers", param, name)
+			}
+			for _, match := range types {
+				for _, want := range []string{
+					"_ driver.Valuer = (*" + match[1] + ")(nil)",
+					"_ sql.Scanner   = (*" + match[1] + ")(nil)",
+				} {
+					if !strings.Contains(content, want) {
+						t.Errorf("%q: %s lacks the assertion %q", param, name, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestGenerate_Context(t *testing.T) {
+	content := mustGenerate(t, "paths=source_relative")["test/v1/test_dbtypes.pb.go"]
+
+	value := funcSource(t, content, "func (x *ToolSetSpecValue) Value() (driver.Value, error)")
+	if !strings.Contains(value, "x.ValueContext(context.Background())") {
+		t.Errorf("Value() should delegate to ValueContext:\n%s", value)
+	}
+	scan := funcSource(t, content, "func (x *ToolSetSpecValue) Scan(src any) error")
+	if !strings.Contains(scan, "x.ScanContext(context.Background(), src)") {
+		t.Errorf("Scan() should delegate to ScanContext:\n%s", scan)
+	}
+}
+
+func TestGeneratedCode_ObserveSerialization(t *testing.T) {
+	runGeneratedTests(t, "paths=source_relative", "observe_test.go")
+}
+
+func TestGeneratedCode_Size(t *testing.T) {
+	runGeneratedTests(t, "paths=source_relative", "size_test.go")
+}
+
+func TestGenerate_MarshalJSON(t *testing.T) {
+	// MarshalJSON uses protojson, with AnyResolver, regardless of the storage
+	// format.
+	for _, format := range []string{"binary", "json"} {
+		content := mustGenerate(t, "paths=source_relative,format="+format)["test/v1/test_dbtypes.pb.go"]
+		marshal := funcSource(t, content, "func (x ToolSetSpecValue) MarshalJSON() ([]byte, error)")
+		if !strings.Contains(marshal, "dbtypesMarshalJSON(x.ProtoValue.Message)") {
+			t.Errorf("format=%s: MarshalJSON should use dbtypesMarshalJSON:\n%s", format, marshal)
+		}
+		unmarshal := funcSource(t, content, "func (x *ToolSetSpecValue) UnmarshalJSON(data []byte) error")
+		if !strings.Contains(unmarshal, "dbtypesUnmarshalJSON(data, msg)") {
+			t.Errorf("format=%s: UnmarshalJSON should use dbtypesUnmarshalJSON:\n%s", format, unmarshal)
+		}
+	}
+}
+
+func TestGenerate_MarshalText(t *testing.T) {
+	// MarshalText uses prototext regardless of the storage format.
+	for _, format := range []string{"binary", "json"} {
+		content := mustGenerate(t, "paths=source_relative,format="+format)["test/v1/test_dbtypes.pb.go"]
+		marshal := funcSource(t, content, "func (x *ToolSetSpecValue) MarshalText() ([]byte, error)")
+		if !strings.Contains(marshal, "prototext.MarshalOptions{}.MarshalAppend([]byte{}, x.ProtoValue.Message)") {
+			t.Errorf("format=%s: MarshalText should use prototext:\n%s", format, marshal)
+		}
+		unmarshal := funcSource(t, content, "func (x *ToolSetSpecValue) UnmarshalText(data []byte) error")
+		if !strings.Contains(unmarshal, "prototext.Unmarshal(data, msg)") {
+			t.Errorf("format=%s: UnmarshalText should use prototext.Unmarshal:\n%s", format, unmarshal)
+		}
+		if !strings.Contains(content, "_ encoding.TextMarshaler   = (*ToolSetSpecValue)(nil)") {
+			t.Errorf("format=%s: missing encoding.TextMarshaler assertion", format)
+		}
+	}
+}
+
+func TestGenerate_EncodeBatch(t *testing.T) {
+	content := mustGenerate(t, "paths=source_relative")["test/v1/format_dbtypes.pb.go"]
+	if !strings.Contains(funcSource(t, content, "func EncodeBinaryDocumentBatch("), "dbtypesMarshalBatch(msgs,") {
+		t.Error("binary messages should be marshaled into a shared buffer")
+	}
+	if !strings.Contains(funcSource(t, content, "func EncodeJSONDocumentBatch("), "dbtypesEncodeBatch(msgs,") {
+		t.Error("json messages should be encoded one by one through Value")
+	}
+	if !strings.Contains(funcSource(t, content, "func dbtypesMarshalBatch["), "proto.MarshalOptions{UseCachedSize: true}") {
+		t.Error("dbtypesMarshalBatch should reuse the sizes computed for the buffer")
+	}
+
+	content = mustGenerate(t, "paths=source_relative,deterministic=true")["test/v1/format_dbtypes.pb.go"]
+	if !strings.Contains(funcSource(t, content, "func dbtypesMarshalBatch["), "proto.MarshalOptions{Deterministic: true, UseCachedSize: true}") {
+		t.Error("deterministic=true should apply to batches")
+	}
+
+	// Options that change what Value stores take the Value path.
+	for _, param := range []string{"compress=gzip", "encrypt-hooks=true", "marshal-hooks=true", "empty-as-null=true", "validate=true", "base64-text=true"} {
+		content := mustGenerate(t, "paths=source_relative,"+param)["test/v1/format_dbtypes.pb.go"]
+		if strings.Contains(content, "dbtypesMarshalBatch") {
+			t.Errorf("%s: batches should not bypass Value", param)
+		}
+	}
+
+	content = mustGenerate(t, "paths=source_relative,generic=true,format=json")["test/v1/test_dbtypes.pb.go"]
+	if !strings.Contains(funcSource(t, content, "func EncodeToolSetSpecBatch("), "dbtypes.EncodeJSONBatch(msgs)") {
+		t.Error("generic wrappers should use the runtime batch function of their format")
+	}
+	if !strings.Contains(funcSource(t, content, "func ToolSetSpecValues("), "dbtypes.JSONValues(msgs)") {
+		t.Error("generic wrappers should use the runtime Values function of their format")
+	}
+}
+
+func TestGenerate_FormatJSON(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative,format=json")
+
+	content := files["test/v1/test_dbtypes.pb.go"]
+	if marshal, unmarshal := messageCodec(t, files, "ToolSetSpec"); marshal != "dbtypesMarshalJSON" || unmarshal != "dbtypesUnmarshalJSON" {
+		t.Errorf("json codec uses %s and %s, want dbtypesMarshalJSON and dbtypesUnmarshalJSON", marshal, unmarshal)
+	}
+	if !strings.Contains(content, "x.ProtoValue.scanJSON(ctx, src, ToolSetSpecCodec())") {
+		t.Error("json Scan() should use scanJSON")
+	}
+
+	// scanJSON accepts decoded JSON before handing the bytes to protojson
+	content = files["test/v1/format_dbtypes.pb.go"]
+	scan := funcSource(t, content, "func (p *ProtoValue[T]) scanJSON(ctx context.Context, src any, codec Codec) error")
+	for _, want := range []string{"case json.RawMessage:", "case map[string]any:", "p.scan(ctx, src, codec)"} {
+		if !strings.Contains(scan, want) {
+			t.Errorf("scanJSON() should contain %q:\n%s", want, scan)
+		}
+	}
+	binary := mustGenerate(t, "paths=source_relative")["test/v1/format_dbtypes.pb.go"]
+	if !strings.Contains(funcSource(t, binary, "func (x *BinaryDocumentValue) ScanContext(ctx context.Context, src any) error"), "x.ProtoValue.scan(ctx, src, BinaryDocumentCodec())") {
+		t.Error("binary Scan() should not accept decoded JSON")
+	}
+
+	// Any fields are resolved with the overridable AnyResolver
+	content = files["test/v1/format_dbtypes.pb.go"]
+	if !strings.Contains(content, "var AnyResolver protoregistry.MessageTypeResolver = protoregistry.GlobalTypes") {
+		t.Error("the package should declare AnyResolver defaulting to protoregistry.GlobalTypes")
+	}
+	if !strings.Contains(funcSource(t, content, "func dbtypesUnmarshalJSON("), "protojson.UnmarshalOptions{Resolver: dbtypesJSONResolver()}") {
+		t.Error("dbtypesUnmarshalJSON should resolve Any fields with AnyResolver")
+	}
+}
+
+func TestGeneratedCode_AnyResolver(t *testing.T) {
+	runGeneratedTests(t, "paths=source_relative", "any_resolver_test.go")
+}
+
+func TestGenerate_FormatCBOR(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative,format=cbor")
+
+	marshal, unmarshal := messageCodec(t, files, "ToolSetSpec")
+	if marshal != "dbtypes.MarshalCBOR" {
+		t.Errorf("cbor Value() should use dbtypes.MarshalCBOR, not %s", marshal)
+	}
+	if unmarshal != "dbtypes.UnmarshalCBOR" {
+		t.Errorf("cbor Scan() should use dbtypes.UnmarshalCBOR, not %s", unmarshal)
+	}
+	if !strings.Contains(files["test/v1/format_dbtypes.pb.go"], `"github.com/cadenya/protoc-gen-go-dbtypes/dbtypes"`) {
+		t.Error("cbor output should import the dbtypes runtime package")
+	}
+}
+
+func TestGenerate_FormatMsgpack(t *testing.T) {
+	marshal, unmarshal := messageCodec(t, mustGenerate(t, "paths=source_relative,format=msgpack"), "ToolSetSpec")
+	if marshal != "dbtypes.MarshalMsgpack" {
+		t.Errorf("msgpack Value() should use dbtypes.MarshalMsgpack, not %s", marshal)
+	}
+	if unmarshal != "dbtypes.UnmarshalMsgpack" {
+		t.Errorf("msgpack Scan() should use dbtypes.UnmarshalMsgpack, not %s", unmarshal)
+	}
+}
+
+func TestGenerate_FormatOption(t *testing.T) {
+	for _, param := range []string{"format=binary", "format=json", "format=text", "format=cbor", "format=msgpack"} {
+		t.Run(param, func(t *testing.T) {
+			files := mustGenerate(t, "paths=source_relative,"+param)
+
+			// JSONDocument sets (dbtypes.format) = JSON and ignores the flag.
+			if marshal, _ := messageCodec(t, files, "JSONDocument"); marshal != "dbtypesMarshalJSON" {
+				t.Errorf("JSONDocumentValue.ValueContext() should use dbtypesMarshalJSON, not %s", marshal)
+			}
+
+			// TextDocument sets (dbtypes.format) = TEXT and ignores the flag.
+			if marshal, _ := messageCodec(t, files, "TextDocument"); marshal != "prototext.Marshal" {
+				t.Errorf("TextDocumentValue.ValueContext() should use prototext.Marshal, not %s", marshal)
+			}
+
+			// BinaryDocument has no option and follows the flag.
+			want := "proto.Marshal"
+			switch param {
+			case "format=json":
+				want = "dbtypesMarshalJSON"
+			case "format=text":
+				want = "prototext.Marshal"
+			case "format=cbor":
+				want = "dbtypes.MarshalCBOR"
+			case "format=msgpack":
+				want = "dbtypes.MarshalMsgpack"
+			}
+			if marshal, _ := messageCodec(t, files, "BinaryDocument"); marshal != want {
+				t.Errorf("BinaryDocumentValue.ValueContext() should use %s, not %s", want, marshal)
+			}
+		})
+	}
+}
+
+func TestGenerate_UnknownFormat(t *testing.T) {
+	if _, err := generate(t, "format=xml"); err == nil {
+		t.Error("format=xml should fail generation")
+	}
+}
+
+func TestGeneratedCode_Formats(t *testing.T) {
+	for _, format := range []Format{FormatBinary, FormatJSON, FormatText, FormatCBOR, FormatMsgpack} {
+		t.Run(string(format), func(t *testing.T) {
+			runGeneratedTests(t, "paths=source_relative,format="+string(format))
+		})
+	}
+}
+
+// formatOverrideFixture builds test/v1/format_override.proto, declaring for
+// each name a message whose (dbtypes.format) option selects the given format.
+func formatOverrideFixture(t *testing.T, formats map[string]dbtypespb.Format) protoreflect.FileDescriptor {
+	t.Helper()
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("test/v1/format_override.proto"),
+		Package:    proto.String("test.v1"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"dbtypes/options.proto"},
+		Options: &descriptorpb.FileOptions{
+			GoPackage: proto.String("github.com/cadenya-agents/protoc-gen-go-dbtypes/gen/go/test/v1;testv1"),
+		},
+	}
+	for _, name := range slices.Sorted(maps.Keys(formats)) {
+		options := &descriptorpb.MessageOptions{}
+		proto.SetExtension(options, dbtypespb.E_Format, formats[name])
+		fdp.MessageType = append(fdp.MessageType, &descriptorpb.DescriptorProto{
+			Name: proto.String(name),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("name"),
+				JsonName: proto.String("name"),
+				Number:   proto.Int32(1),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			}, {
+				Name:     proto.String("count"),
+				JsonName: proto.String("count"),
+				Number:   proto.Int32(2),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum(),
+			}},
+			Options: options,
+		})
+	}
+	fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fd
+}
+
+func TestGenerate_CBORFormatOption(t *testing.T) {
+	fixture := formatOverrideFixture(t, map[string]dbtypespb.Format{"CBORDocument": dbtypespb.Format_CBOR})
+	for _, param := range []string{"paths=source_relative", "paths=source_relative,format=json"} {
+		files, err := generateFiles(t, param, append(slices.Clip(testFiles), fixture)...)
+		if err != nil {
+			t.Fatalf("%s: run() error: %v", param, err)
+		}
+		marshal, unmarshal := messageCodec(t, files, "CBORDocument")
+		if marshal != "dbtypes.MarshalCBOR" || unmarshal != "dbtypes.UnmarshalCBOR" {
+			t.Errorf("%s: CBORDocument codec = %s, %s; want dbtypes.MarshalCBOR, dbtypes.UnmarshalCBOR", param, marshal, unmarshal)
+		}
+	}
+
+	// The options declaring the cbor functions follow the message too
+	files, err := generateFiles(t, "paths=source_relative,allow-partial=true", append(slices.Clip(testFiles), fixture)...)
+	if err != nil {
+		t.Fatalf("run() error: %v", err)
+	}
+	if marshal, _ := messageCodec(t, files, "CBORDocument"); marshal != "dbtypesMarshalCBOR" {
+		t.Errorf("allow-partial=true: CBORDocument should use dbtypesMarshalCBOR, not %s", marshal)
+	}
+	if !regexp.MustCompile(`dbtypesMarshalCBOR += dbtypes\.MarshalOptions\{AllowPartial: true\}\.MarshalCBOR`).MatchString(files["test/v1/format_dbtypes.pb.go"]) {
+		t.Error("allow-partial=true should declare dbtypesMarshalCBOR for a message selecting cbor")
+	}
+
+	// Without such a message the cbor codec is not generated
+	if content := mustGenerate(t, "paths=source_relative")["test/v1/format_dbtypes.pb.go"]; strings.Contains(content, "dbtypesCBORCodec") {
+		t.Error("dbtypesCBORCodec generated with no message stored as cbor")
+	}
+
+	_, err = generateFiles(t, "paths=source_relative,generic=true", append(slices.Clip(testFiles), fixture)...)
+	if err == nil || !strings.Contains(err.Error(), "generic=true cannot store test.v1.CBORDocument as cbor") {
+		t.Errorf("generic=true with a message selecting cbor error = %v, want a rejection", err)
+	}
+}
+
+func TestGenerate_MsgpackFormatOption(t *testing.T) {
+	fixture := formatOverrideFixture(t, map[string]dbtypespb.Format{"MsgpackDocument": dbtypespb.Format_MSGPACK})
+	for _, param := range []string{"paths=source_relative", "paths=source_relative,format=cbor"} {
+		files, err := generateFiles(t, param, append(slices.Clip(testFiles), fixture)...)
+		if err != nil {
+			t.Fatalf("%s: run() error: %v", param, err)
+		}
+		marshal, unmarshal := messageCodec(t, files, "MsgpackDocument")
+		if marshal != "dbtypes.MarshalMsgpack" || unmarshal != "dbtypes.UnmarshalMsgpack" {
+			t.Errorf("%s: MsgpackDocument codec = %s, %s; want dbtypes.MarshalMsgpack, dbtypes.UnmarshalMsgpack", param, marshal, unmarshal)
+		}
+	}
+
+	files, err := generateFiles(t, "paths=source_relative,discard-unknown=true", append(slices.Clip(testFiles), fixture)...)
+	if err != nil {
+		t.Fatalf("run() error: %v", err)
+	}
+	if _, unmarshal := messageCodec(t, files, "MsgpackDocument"); unmarshal != "dbtypesUnmarshalMsgpack" {
+		t.Errorf("discard-unknown=true: MsgpackDocument should use dbtypesUnmarshalMsgpack, not %s", unmarshal)
+	}
+	if !regexp.MustCompile(`dbtypesUnmarshalMsgpack += dbtypes\.UnmarshalOptions\{DiscardUnknown: true\}\.UnmarshalMsgpack`).MatchString(files["test/v1/format_dbtypes.pb.go"]) {
+		t.Error("discard-unknown=true should declare dbtypesUnmarshalMsgpack for a message selecting msgpack")
+	}
+
+	_, err = generateFiles(t, "paths=source_relative,generic=true", append(slices.Clip(testFiles), fixture)...)
+	if err == nil || !strings.Contains(err.Error(), "generic=true cannot store test.v1.MsgpackDocument as msgpack") {
+		t.Errorf("generic=true with a message selecting msgpack error = %v, want a rejection", err)
+	}
+}
+
+func TestGeneratedCode_FormatOverride(t *testing.T) {
+	runScratchModule(t, scratchModule{
+		param: "paths=source_relative",
+		tests: []string{"format_override_test.go"},
+		protoFiles: []protoreflect.FileDescriptor{formatOverrideFixture(t, map[string]dbtypespb.Format{
+			"CBORDocument":    dbtypespb.Format_CBOR,
+			"MsgpackDocument": dbtypespb.Format_MSGPACK,
+		})},
+	})
+}
+
+func TestGenerate_CompressGzip(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative,compress=gzip")
+
+	if !strings.Contains(files["test/v1/format_dbtypes.pb.go"], "var GzipLevel = gzip.DefaultCompression") {
+		t.Error("compress=gzip should expose GzipLevel")
+	}
+	marshal, unmarshal := messageCodec(t, files, "BinaryDocument")
+	if marshal != "dbtypesMarshalGzip" {
+		t.Error("binary Value() should compress the marshaled bytes")
+	}
+	if unmarshal != "dbtypesUnmarshalGzip" {
+		t.Error("binary Scan() should inflate compressed bytes")
+	}
+
+	// JSON-format messages stay uncompressed.
+	if marshal, _ := messageCodec(t, files, "JSONDocument"); marshal != "dbtypesMarshalJSON" {
+		t.Error("json Value() should not compress")
+	}
+
+	// Without the flag no compression code is emitted.
+	content := mustGenerate(t, "paths=source_relative")["test/v1/format_dbtypes.pb.go"]
+	if strings.Contains(content, "gzip") {
+		t.Error("gzip code generated without compress=gzip")
+	}
+}
+
+func TestGenerate_UnknownCompression(t *testing.T) {
+	if _, err := generate(t, "compress=lz4"); err == nil {
+		t.Error("compress=lz4 should fail generation")
+	}
+}
+
+func TestGeneratedCode_CompressGzip(t *testing.T) {
+	runGeneratedTests(t, "paths=source_relative,compress=gzip", "gzip_test.go")
+}
+
+// zstdModule is the klauspost/compress version the zstd tests build against.
+const zstdModule = "github.com/klauspost/compress@v1.20.1"
+
+func TestGenerate_CompressZstd(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative,compress=zstd")
+	content := files["test/v1/format_dbtypes.pb.go"]
+	if !strings.Contains(content, "var ZstdLevel = zstd.SpeedDefault") {
+		t.Error("compress=zstd should expose ZstdLevel")
+	}
+	marshal, unmarshal := messageCodec(t, files, "BinaryDocument")
+	if marshal != "dbtypesMarshalZstd" {
+		t.Error("binary Value() should compress the marshaled bytes")
+	}
+	if unmarshal != "dbtypesUnmarshalZstd" {
+		t.Error("binary Scan() should decompress compressed bytes")
+	}
+	if strings.Contains(content, "dbtypesZstdDict") {
+		t.Error("dictionary embedded without zstd-dict")
+	}
+
+	content = mustGenerate(t, "paths=source_relative,compress=zstd,zstd-dict=testdata/zstd.dict")["test/v1/format_dbtypes.pb.go"]
+	for _, want := range []string{
+		`const dbtypesZstdDict = "7\xa40\xec`,
+		"zstd.WithEncoderDict([]byte(dbtypesZstdDict))",
+		"zstd.WithDecoderDicts([]byte(dbtypesZstdDict))",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("zstd-dict should generate %q", want)
+		}
+	}
+
+	for _, param := range []string{
+		"zstd-dict=testdata/zstd.dict",
+		"compress=gzip,zstd-dict=testdata/zstd.dict",
+		"compress=zstd,zstd-dict=testdata/missing.dict",
+		"compress=zstd,zstd-dict=testdata/gzip_test.go",
+	} {
+		if _, err := generate(t, param); err == nil {
+			t.Errorf("%s should fail generation", param)
+		}
+	}
+}
+
+func TestGeneratedCode_CompressZstd(t *testing.T) {
+	runScratchModule(t, scratchModule{
+		param:    "paths=source_relative,compress=zstd",
+		tests:    []string{"zstd_test.go"},
+		requires: []string{zstdModule},
+	})
+}
+
+func TestGeneratedCode_CompressZstdDict(t *testing.T) {
+	runScratchModule(t, scratchModule{
+		param:    "paths=source_relative,compress=zstd,zstd-dict=testdata/zstd.dict",
+		tests:    []string{"zstd_test.go", "zstd_dict_test.go"},
+		requires: []string{zstdModule},
+	})
+}
+
+func TestGenerate_EncryptHooks(t *testing.T) {
+	content := mustGenerate(t, "paths=source_relative,encrypt-hooks=true")["test/v1/format_dbtypes.pb.go"]
+	for _, want := range []string{
+		"var EncryptCipher func([]byte) ([]byte, error)",
+		"var DecryptCipher func([]byte) ([]byte, error)",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("generated code missing %q", want)
+		}
+	}
+
+	content = mustGenerate(t, "paths=source_relative")["test/v1/format_dbtypes.pb.go"]
+	if strings.Contains(content, "Cipher") {
+		t.Error("cipher hooks generated without encrypt-hooks")
+	}
+}
+
+func TestGeneratedCode_EncryptHooks(t *testing.T) {
+	runGeneratedTests(t, "paths=source_relative,encrypt-hooks=true", "encrypt_test.go")
+	runGeneratedTests(t, "paths=source_relative,encrypt-hooks=true,compress=gzip", "encrypt_test.go", "gzip_test.go")
+}
+
+func TestGenerate_EmptyAsNull(t *testing.T) {
+	content := mustGenerate(t, "paths=source_relative,empty-as-null=true")["test/v1/format_dbtypes.pb.go"]
+	value := funcSource(t, content, "func (p *ProtoValue[T]) value(ctx context.Context, codec Codec) (_ driver.Value, err error)")
+	if !strings.Contains(value, "proto.Size(p.Message) == 0") {
+		t.Errorf("value() should store empty messages as NULL:\n%s", value)
+	}
+
+	content = mustGenerate(t, "paths=source_relative")["test/v1/format_dbtypes.pb.go"]
+	value = funcSource(t, content, "func (p *ProtoValue[T]) value(ctx context.Context, codec Codec) (_ driver.Value, err error)")
+	if strings.Contains(value, "proto.Size") {
+		t.Error("empty messages stored as NULL without empty-as-null")
+	}
+}
+
+func TestGeneratedCode_EmptyAsNull(t *testing.T) {
+	runScratchModule(t, scratchModule{
+		param:            "paths=source_relative,empty-as-null=true",
+		tests:            []string{"empty_as_null_test.go"},
+		skipFixtureTests: true,
+	})
+}
+
+func TestGenerate_NilMessage(t *testing.T) {
+	content := mustGenerate(t, "paths=source_relative,nil-message=null")["test/v1/format_dbtypes.pb.go"]
+	value := funcSource(t, content, "func (p *ProtoValue[T]) value(ctx context.Context, codec Codec) (_ driver.Value, err error)")
+	if !strings.Contains(value, "!p.Message.ProtoReflect().IsValid()") {
+		t.Errorf("value() should store nil messages as NULL:\n%s", value)
+	}
+
+	for _, param := range []string{"paths=source_relative", "paths=source_relative,nil-message=empty"} {
+		content = mustGenerate(t, param)["test/v1/format_dbtypes.pb.go"]
+		value = funcSource(t, content, "func (p *ProtoValue[T]) value(ctx context.Context, codec Codec) (_ driver.Value, err error)")
+		if strings.Contains(value, "IsValid") {
+			t.Errorf("%s: nil messages stored as NULL without nil-message=null", param)
+		}
+	}
+
+	if _, err := generate(t, "nil-message=zero"); err == nil {
+		t.Error("nil-message=zero should be rejected")
+	}
+}
+
+func TestGeneratedCode_NilMessage(t *testing.T) {
+	runScratchModule(t, scratchModule{
+		param: "paths=source_relative,nil-message=null",
+		tests: []string{"nil_message_null_test.go"},
+	})
+	runScratchModule(t, scratchModule{
+		param: "paths=source_relative",
+		tests: []string{"nil_message_empty_test.go"},
+	})
+}
+
+func TestGenerate_MaxScanSize(t *testing.T) {
+	content := mustGenerate(t, "paths=source_relative,max-scan-size=4096")["test/v1/format_dbtypes.pb.go"]
+	if !strings.Contains(content, "var MaxScanSize = 4096") {
+		t.Error("MaxScanSize should default to the max-scan-size option")
+	}
+	scan := funcSource(t, content, "func (p *ProtoValue[T]) scan(ctx context.Context, src any, codec Codec) (err error)")
+	if !strings.Contains(scan, "len(data) > MaxScanSize") || !strings.Contains(scan, "io.LimitReader(r, int64(MaxScanSize)+1)") {
+		t.Errorf("scan() should enforce MaxScanSize before decoding:\n%s", scan)
+	}
+
+	// Without the option Scan has no limit
+	content = mustGenerate(t, "paths=source_relative")["test/v1/format_dbtypes.pb.go"]
+	if strings.Contains(content, "MaxScanSize") {
+		t.Error("MaxScanSize generated without max-scan-size")
+	}
+
+	if _, err := generate(t, "max-scan-size=-1"); err == nil {
+		t.Error("max-scan-size=-1 should be rejected")
+	}
+}
+
+func TestGeneratedCode_MaxScanSize(t *testing.T) {
+	runGeneratedTests(t, "paths=source_relative,max-scan-size=1024", "max_scan_size_test.go")
+	runGeneratedTests(t, "paths=source_relative,max-scan-size=1024,compress=gzip", "max_scan_size_test.go", "max_scan_size_gzip_test.go")
+	runScratchModule(t, scratchModule{
+		param:    "paths=source_relative,max-scan-size=1024,compress=zstd",
+		tests:    []string{"max_scan_size_test.go", "max_scan_size_zstd_test.go"},
+		requires: []string{zstdModule},
+	})
+}
+
+func TestGenerate_MarshalHooks(t *testing.T) {
+	content := mustGenerate(t, "paths=source_relative,marshal-hooks=true")["test/v1/format_dbtypes.pb.go"]
+	for _, want := range []string{
+		"var Marshal func(proto.Message) ([]byte, error) = proto.Marshal",
+		"var Unmarshal func([]byte, proto.Message) error = proto.Unmarshal",
+		// The codec calls the hooks, which may be replaced after it is declared
+		"dbtypesBinaryCodec Codec = &dbtypesCodec{marshal: func(m proto.Message) ([]byte, error) { return Marshal(m) }, unmarshal: func(b []byte, m proto.Message) error { return Unmarshal(b, m) }}",
+		// Messages with a (dbtypes.format) option keep their format
+		"dbtypesJSONCodec   Codec = &dbtypesCodec{marshal: dbtypesMarshalJSON, unmarshal: dbtypesUnmarshalJSON}",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("marshal-hooks output should contain %q", want)
+		}
+	}
+	// The hooks default to the chosen format, and sit inside compression
+	content = mustGenerate(t, "paths=source_relative,marshal-hooks=true,format=json,discard-unknown=true")["test/v1/format_dbtypes.pb.go"]
+	if !strings.Contains(content, "var Marshal func(proto.Message) ([]byte, error) = dbtypesMarshalJSON") || !strings.Contains(content, "var Unmarshal func([]byte, proto.Message) error = dbtypesUnmarshalJSON") {
+		t.Error("format=json hooks should default to protojson")
+	}
+	content = mustGenerate(t, "paths=source_relative,marshal-hooks=true,compress=gzip,deterministic=true")["test/v1/format_dbtypes.pb.go"]
+	if !strings.Contains(content, "var Marshal func(proto.Message) ([]byte, error) = dbtypesMarshalDeterministic") {
+		t.Error("deterministic hooks should default to the deterministic marshaler")
+	}
+	if got := funcSource(t, content, "func dbtypesMarshalGzip(m proto.Message) ([]byte, error)"); !strings.Contains(got, "raw, err := Marshal(m)") {
+		t.Errorf("dbtypesMarshalGzip should compress the output of Marshal:\n%s", got)
+	}
+	if got := funcSource(t, content, "func dbtypesUnmarshalGzip("); !strings.Contains(got, "return Unmarshal(data, m)") {
+		t.Errorf("dbtypesUnmarshalGzip should inflate before Unmarshal:\n%s", got)
+	}
+
+	// Without the option there are no hooks
+	content = mustGenerate(t, "paths=source_relative")["test/v1/format_dbtypes.pb.go"]
+	if strings.Contains(content, "var Marshal ") {
+		t.Error("Marshal should only be declared with marshal-hooks=true")
+	}
+}
+
+func TestGeneratedCode_MarshalHooks(t *testing.T) {
+	for _, param := range []string{
+		"paths=source_relative,marshal-hooks=true",
+		"paths=source_relative,marshal-hooks=true,compress=gzip,encrypt-hooks=true",
+		"paths=source_relative,marshal-hooks=true,format=cbor",
+	} {
+		runGeneratedTests(t, param, "marshal_hooks_test.go")
+	}
+}
+
+func TestGenerate_Registry(t *testing.T) {
+	for _, param := range []string{"paths=source_relative", "paths=source_relative,generic=true"} {
+		files := mustGenerate(t, param)
+		content := files["test/v1/format_dbtypes.pb.go"]
+		// The first file of the package lists the messages of the others
+		for _, want := range []string{
+			`"test.v1.JSONDocument":    func() dbtypesWrapper { return NewJSONDocumentValue(&JSONDocument{}) },`,
+			`"test.v1.ToolSetSpec":     func() dbtypesWrapper { return NewToolSetSpecValue(&ToolSetSpec{}) },`,
+		} {
+			if !strings.Contains(content, want) {
+				t.Errorf("%s: DBTypeRegistry should contain %q", param, want)
+			}
+		}
+		if strings.Contains(files["test/v1/test_dbtypes.pb.go"], "DBTypeRegistry") {
+			t.Errorf("%s: DBTypeRegistry should be generated once per package", param)
+		}
+	}
+}
+
+func TestGenerate_MessageTooLarge(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative")
+	content := files["test/v1/format_dbtypes.pb.go"]
+	for _, want := range []string{
+		`var ErrMessageTooLarge = errors.New("message too large")`,
+		"var dbtypesMaxMessageSize = math.MaxInt32",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("generated code should contain %q", want)
+		}
+	}
+	if strings.Contains(files["test/v1/test_dbtypes.pb.go"], "var ErrMessageTooLarge") {
+		t.Error("ErrMessageTooLarge should be declared once per package")
+	}
+	if !strings.Contains(funcSource(t, content, "func (c *dbtypesCodec) Encode("), "dbtypesCheckSize(len(data))") {
+		t.Error("Encode should check the size of the encoding")
+	}
+	if !strings.Contains(funcSource(t, content, "func dbtypesMarshalBatch["), "dbtypesCheckSize(n)") {
+		t.Error("dbtypesMarshalBatch should check the size of each message")
+	}
+
+	content = mustGenerate(t, "paths=source_relative,generic=true")["test/v1/format_dbtypes.pb.go"]
+	if !strings.Contains(content, "var ErrMessageTooLarge = dbtypes.ErrMessageTooLarge") {
+		t.Error("generic output should alias dbtypes.ErrMessageTooLarge")
+	}
+}
+
+func TestGenerate_Codec(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative")
+	content := files["test/v1/format_dbtypes.pb.go"]
+	for _, want := range []string{
+		"type Codec interface {",
+		"func JSONDocumentCodec() Codec { return dbtypesJSONCodec }",
+		"func BinaryDocumentCodec() Codec { return dbtypesBinaryCodec }",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("generated code should contain %q", want)
+		}
+	}
+	if strings.Contains(files["test/v1/test_dbtypes.pb.go"], "type Codec interface") {
+		t.Error("Codec should be declared once per package")
+	}
+
+	// The wrappers' encodings all go through the codec
+	for _, fn := range []string{
+		"func (x *BinaryDocumentValue) ScanContext(",
+		"func (x *BinaryDocumentValue) ValueContext(",
+		"func (x *BinaryDocumentValue) MarshalBinary(",
+		"func (x *BinaryDocumentValue) UnmarshalBinary(",
+	} {
+		if got := funcSource(t, content, fn); !strings.Contains(got, "BinaryDocumentCodec()") {
+			t.Errorf("%s should use BinaryDocumentCodec:\n%s", fn, got)
+		}
+	}
+
+	// Encryption is part of the stored encoding
+	content = mustGenerate(t, "paths=source_relative,encrypt-hooks=true")["test/v1/format_dbtypes.pb.go"]
+	if !strings.Contains(funcSource(t, content, "func (c *dbtypesCodec) Encode("), "EncryptCipher(data)") {
+		t.Error("Encode should apply EncryptCipher")
+	}
+	if !strings.Contains(funcSource(t, content, "func (c *dbtypesCodec) Decode("), "DecryptCipher(data)") {
+		t.Error("Decode should apply DecryptCipher")
+	}
+
+	content = mustGenerate(t, "paths=source_relative,generic=true,format=text")["test/v1/test_dbtypes.pb.go"]
+	if !strings.Contains(content, "func ToolSetSpecCodec() Codec { return dbtypes.TextCodec() }") {
+		t.Error("generic accessors should return the runtime codec of their format")
+	}
+}
+
+func TestGenerate_MetricsHooks(t *testing.T) {
+	content := mustGenerate(t, "paths=source_relative,metrics-hooks=true")["test/v1/format_dbtypes.pb.go"]
+	for _, want := range []string{
+		"OnValue      func(typeName string, size int)",
+		"OnScanError  func(typeName string, err error)",
+		"func dbtypesReportValue(msg proto.Message, v driver.Value, err error) {",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("metrics-hooks output should contain %q", want)
+		}
+	}
+	if got := funcSource(t, content, "func (p *ProtoValue[T]) value("); !strings.Contains(got, "defer func() { dbtypesReportValue(p.Message, v, err) }()") {
+		t.Errorf("value should report to the metrics hooks:\n%s", got)
+	}
+	if got := funcSource(t, content, "func (p *ProtoValue[T]) scan("); !strings.Contains(got, "defer func() { dbtypesReportScan(p.Message, len(data), err) }()") {
+		t.Errorf("scan should report to the metrics hooks:\n%s", got)
+	}
+	if got := funcSource(t, content, "func dbtypesMarshalBatch["); !strings.Contains(got, "OnValue != nil || OnValueError != nil") {
+		t.Errorf("batches should take the Value path while a metrics hook is set:\n%s", got)
+	}
+
+	// Without the option there are no hooks
+	content = mustGenerate(t, "paths=source_relative")["test/v1/format_dbtypes.pb.go"]
+	if strings.Contains(content, "OnValue") || strings.Contains(content, "dbtypesReport") {
+		t.Error("metrics hooks should only be generated with metrics-hooks=true")
+	}
+}
+
+func TestGeneratedCode_MetricsHooks(t *testing.T) {
+	for _, param := range []string{
+		"paths=source_relative,metrics-hooks=true",
+		"paths=source_relative,metrics-hooks=true,compress=gzip,format=json",
+	} {
+		runGeneratedTests(t, param, "metrics_hooks_test.go")
+	}
+}
+
+func TestGenerate_OTel(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative,otel=true")
+
+	content, ok := files["test/v1/format_dbtypes_otel.pb.go"]
+	if !ok {
+		t.Fatal("otel=true should generate format_dbtypes_otel.pb.go")
+	}
+	if !strings.Contains(content, "//go:build dbtypes_otel\n") {
+		t.Error("otel file should be guarded by the dbtypes_otel build tag")
+	}
+	for _, want := range []string{"var Tracer trace.Tracer", `span.AddEvent("dbtypes."+op, attrs)`, `attribute.Int("dbtypes.size", size)`} {
+		if !strings.Contains(content, want) {
+			t.Errorf("otel output should contain %q", want)
+		}
+	}
+	if _, ok := files["test/v1/test_dbtypes_otel.pb.go"]; ok {
+		t.Error("the otel file should be generated once per package")
+	}
+
+	main := files["test/v1/format_dbtypes.pb.go"]
+	if strings.Contains(main, `"go.opentelemetry.io/otel`) {
+		t.Error("format_dbtypes.pb.go should not import OpenTelemetry")
+	}
+	if got := funcSource(t, main, "func (p *ProtoValue[T]) value("); !strings.Contains(got, `dbtypesTrace(ctx, "value", p.Message, len(data), err)`) {
+		t.Errorf("value should call the trace hook:\n%s", got)
+	}
+	if got := funcSource(t, main, "func (p *ProtoValue[T]) scan("); !strings.Contains(got, `dbtypesTrace(ctx, "scan", p.Message, len(data), err)`) {
+		t.Errorf("scan should call the trace hook:\n%s", got)
+	}
+	if got := funcSource(t, main, "func dbtypesMarshalBatch["); !strings.Contains(got, "dbtypesTrace != nil") {
+		t.Errorf("batches should be encoded through value while tracing:\n%s", got)
+	}
+
+	files = mustGenerate(t, "paths=source_relative")
+	if _, ok := files["test/v1/format_dbtypes_otel.pb.go"]; ok || strings.Contains(files["test/v1/format_dbtypes.pb.go"], "dbtypesTrace") {
+		t.Error("tracing generated without otel=true")
+	}
+}
+
+func TestGeneratedCode_OTel(t *testing.T) {
+	runScratchModule(t, scratchModule{
+		param:    "paths=source_relative,otel=true",
+		tests:    []string{"otel_test.go"},
+		requires: []string{"go.opentelemetry.io/otel@v1.46.0", "go.opentelemetry.io/otel/sdk@v1.46.0"},
+		tags:     otelBuildTag,
+	})
+}
+
+func TestGenerate_AllowPartial(t *testing.T) {
+	content := mustGenerate(t, "paths=source_relative,allow-partial=true")["test/v1/format_dbtypes.pb.go"]
+	for _, want := range []string{
+		"dbtypesMarshalBinary = proto.MarshalOptions{AllowPartial: true}.Marshal",
+		"dbtypesUnmarshalBinary = proto.UnmarshalOptions{AllowPartial: true}.Unmarshal",
+		"dbtypesMarshalText   = prototext.MarshalOptions{AllowPartial: true}.Marshal",
+		"protojson.MarshalOptions{Resolver: dbtypesJSONResolver(), AllowPartial: true}.Marshal(m)",
+		"protojson.UnmarshalOptions{Resolver: dbtypesJSONResolver(), AllowPartial: true}.Unmarshal(data, m)",
+		"prototext.MarshalOptions{AllowPartial: true}.MarshalAppend([]byte{}, x.ProtoValue.Message)",
+		"proto.MarshalOptions{UseCachedSize: true, AllowPartial: true}",
+		"dbtypesBinaryCodec Codec = &dbtypesCodec{marshal: dbtypesMarshalBinary, unmarshal: dbtypesUnmarshalBinary}",
+		"dbtypesTextCodec   Codec = &dbtypesCodec{marshal: dbtypesMarshalText, unmarshal: dbtypesUnmarshalText}",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("allow-partial output should contain %q", want)
+		}
+	}
+
+	content = mustGenerate(t, "paths=source_relative,allow-partial=true,discard-unknown=true,deterministic=true,compress=gzip")["test/v1/format_dbtypes.pb.go"]
+	for _, want := range []string{
+		"var dbtypesMarshalDeterministic = proto.MarshalOptions{Deterministic: true, AllowPartial: true}.Marshal",
+		"dbtypesUnmarshalBinary = proto.UnmarshalOptions{DiscardUnknown: true, AllowPartial: true}.Unmarshal",
+		"proto.MarshalOptions{Deterministic: true, AllowPartial: true}.MarshalAppend(s.raw[:0], m)",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("allow-partial with discard-unknown, deterministic and gzip should contain %q", want)
+		}
+	}
+	if strings.Contains(content, "dbtypesMarshalBinary") {
+		t.Error("deterministic=true should marshal through dbtypesMarshalDeterministic")
+	}
+
+	for format, want := range map[string]string{
+		"cbor":    "dbtypesMarshalCBOR   = dbtypes.MarshalOptions{AllowPartial: true}.MarshalCBOR",
+		"msgpack": "dbtypesMarshalMsgpack = dbtypes.MarshalOptions{AllowPartial: true}.MarshalMsgpack",
+	} {
+		content = mustGenerate(t, "paths=source_relative,allow-partial=true,format="+format)["test/v1/format_dbtypes.pb.go"]
+		if !strings.Contains(content, want) {
+			t.Errorf("format=%s: allow-partial output should contain %q", format, want)
+		}
+	}
+
+	content = mustGenerate(t, "paths=source_relative,allow-partial=true,emit-bson=true")["test/v1/format_dbtypes_bson.pb.go"]
+	if !strings.Contains(content, "protojson.MarshalOptions{AllowPartial: true}.Marshal(msg)") || !strings.Contains(content, "protojson.UnmarshalOptions{DiscardUnknown: true, AllowPartial: true}") {
+		t.Errorf("BSON helpers should allow partial messages:\n%s", content)
+	}
+}
+
+func TestGeneratedCode_AllowPartial(t *testing.T) {
+	// The fixture tests check that required fields are enforced
+	for _, param := range []string{
+		"paths=source_relative,allow-partial=true",
+		"paths=source_relative,allow-partial=true,format=json",
+		"paths=source_relative,allow-partial=true,format=text,discard-unknown=true",
+		"paths=source_relative,allow-partial=true,format=cbor",
+		"paths=source_relative,allow-partial=true,compress=gzip,deterministic=true",
+	} {
+		runScratchModule(t, scratchModule{
+			param:            param,
+			tests:            []string{"allow_partial_test.go"},
+			skipFixtureTests: true,
+		})
+	}
+}
+
+func TestGenerate_DiscardUnknown(t *testing.T) {
+	content := mustGenerate(t, "paths=source_relative,discard-unknown=true")["test/v1/format_dbtypes.pb.go"]
+	for _, want := range []string{
+		"dbtypesUnmarshalBinary = proto.UnmarshalOptions{DiscardUnknown: true}.Unmarshal",
+		"dbtypesUnmarshalText   = prototext.UnmarshalOptions{DiscardUnknown: true}.Unmarshal",
+		"protojson.UnmarshalOptions{Resolver: dbtypesJSONResolver(), DiscardUnknown: true}.Unmarshal(data, m)",
+		"dbtypesBinaryCodec Codec = &dbtypesCodec{marshal: proto.Marshal, unmarshal: dbtypesUnmarshalBinary}",
+		"dbtypesTextCodec   Codec = &dbtypesCodec{marshal: prototext.Marshal, unmarshal: dbtypesUnmarshalText}",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("discard-unknown output should contain %q", want)
+		}
+	}
+	if got := funcSource(t, content, "func (x *TextDocumentValue) UnmarshalText(data []byte) error"); !strings.Contains(got, "dbtypesUnmarshalText(data, msg)") {
+		t.Errorf("UnmarshalText should drop unknown fields:\n%s", got)
+	}
+
+	content = mustGenerate(t, "paths=source_relative,discard-unknown=true,compress=gzip")["test/v1/format_dbtypes.pb.go"]
+	if got := funcSource(t, content, "func dbtypesUnmarshalGzip("); !strings.Contains(got, "return dbtypesUnmarshalBinary(data, m)") {
+		t.Errorf("dbtypesUnmarshalGzip should drop unknown fields after inflating:\n%s", got)
+	}
+
+	for format, want := range map[string]string{
+		"cbor":    "dbtypesUnmarshalCBOR   = dbtypes.UnmarshalOptions{DiscardUnknown: true}.UnmarshalCBOR",
+		"msgpack": "dbtypesUnmarshalMsgpack = dbtypes.UnmarshalOptions{DiscardUnknown: true}.UnmarshalMsgpack",
+	} {
+		content = mustGenerate(t, "paths=source_relative,discard-unknown=true,format="+format)["test/v1/format_dbtypes.pb.go"]
+		if !strings.Contains(content, want) {
+			t.Errorf("format=%s: discard-unknown output should contain %q", format, want)
+		}
+	}
+
+	// Without the option Scan keeps the strict decoders
+	content = mustGenerate(t, "paths=source_relative")["test/v1/format_dbtypes.pb.go"]
+	if strings.Contains(content, "DiscardUnknown") {
+		t.Error("DiscardUnknown should only be set with discard-unknown=true")
+	}
+}
+
+func TestGeneratedCode_DiscardUnknown(t *testing.T) {
+	for _, param := range []string{
+		"paths=source_relative,discard-unknown=true",
+		"paths=source_relative,discard-unknown=true,compress=gzip",
+		"paths=source_relative,discard-unknown=true,format=cbor",
+		"paths=source_relative,discard-unknown=true,format=msgpack",
+	} {
+		runGeneratedTests(t, param, "discard_unknown_test.go")
+	}
+}
+
+// preserveUnknownParam excludes the fixtures that select a text format.
+const preserveUnknownParam = "paths=source_relative,preserve-unknown=true,exclude=JSONDocument,exclude=TextDocument,exclude=Envelope"
+
+func TestGenerate_PreserveUnknown(t *testing.T) {
+	mustGenerate(t, preserveUnknownParam)
+	mustGenerate(t, preserveUnknownParam+",compress=gzip,encrypt-hooks=true,empty-as-null=true")
+
+	for _, tt := range []struct {
+		param, want string
+	}{
+		{preserveUnknownParam + ",discard-unknown=true", "cannot be combined with discard-unknown"},
+		{preserveUnknownParam + ",format=json", "cannot be combined with format=json"},
+		{preserveUnknownParam + ",format=cbor", "cannot be combined with format=cbor"},
+		{preserveUnknownParam + ",emit-bson=true", "cannot be combined with emit-bson"},
+		{"paths=source_relative,preserve-unknown=true", "test/v1/format.proto: preserve-unknown=true needs the binary format, but test.v1.JSONDocument is stored as json"},
+	} {
+		_, err := generate(t, tt.param)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: error = %v, want it to contain %q", tt.param, err, tt.want)
+		}
+	}
+}
+
+func TestGeneratedCode_PreserveUnknown(t *testing.T) {
+	// The fixture tests use the excluded text-format messages
+	runScratchModule(t, scratchModule{
+		param:            preserveUnknownParam + ",deterministic=true",
+		tests:            []string{"preserve_unknown_test.go"},
+		skipFixtureTests: true,
+	})
+}
+
+func TestGenerate_Base64Text(t *testing.T) {
+	content := mustGenerate(t, "paths=source_relative,base64-text=true")["test/v1/format_dbtypes.pb.go"]
+	if got := funcSource(t, content, "func (x *BinaryDocumentValue) ValueContext(ctx context.Context) (driver.Value, error)"); !strings.Contains(got, "dbtypesValueBase64(x.ProtoValue.value(ctx, BinaryDocumentCodec()))") {
+		t.Errorf("binary Value() should return base64 text:\n%s", got)
+	}
+	if got := funcSource(t, content, "func (x *BinaryDocumentValue) ScanContext(ctx context.Context, src any) error"); !strings.Contains(got, "x.ProtoValue.scanBase64(ctx, src, BinaryDocumentCodec())") {
+		t.Errorf("binary Scan() should decode base64 text:\n%s", got)
+	}
+	// Text formats are stored as they are
+	if got := funcSource(t, content, "func (x *JSONDocumentValue) ValueContext(ctx context.Context) (driver.Value, error)"); strings.Contains(got, "dbtypesValueBase64") {
+		t.Errorf("JSON Value() should not be base64-encoded:\n%s", got)
+	}
+	if strings.Contains(content, "dbtypesMarshalBatch") {
+		t.Error("batches should take the Value path with base64-text=true")
+	}
+
+	// With encryption the text formats are ciphertext too
+	content = mustGenerate(t, "paths=source_relative,base64-text=true,encrypt-hooks=true")["test/v1/format_dbtypes.pb.go"]
+	if got := funcSource(t, content, "func (x *JSONDocumentValue) ScanContext(ctx context.Context, src any) error"); !strings.Contains(got, "x.ProtoValue.scanBase64(ctx, src, JSONDocumentCodec())") {
+		t.Errorf("encrypted JSON Scan() should decode base64 text:\n%s", got)
+	}
+
+	for _, opt := range []string{"generic=true", "postgres-array=true", "driver=pgx", "emit-bson=true", "orm=gorm"} {
+		_, err := generate(t, "paths=source_relative,base64-text=true,"+opt)
+		if err == nil || !strings.Contains(err.Error(), "base64-text=true cannot be combined with") {
+			t.Errorf("base64-text=true,%s: error = %v, want a conflict", opt, err)
+		}
+	}
+}
+
+func TestGeneratedCode_Base64Text(t *testing.T) {
+	// The fixture tests expect binary values as []byte
+	for _, param := range []string{
+		"paths=source_relative,base64-text=true",
+		"paths=source_relative,base64-text=true,compress=gzip,orm=ent",
+	} {
+		runScratchModule(t, scratchModule{
+			param:            param,
+			tests:            []string{"base64_text_test.go"},
+			skipFixtureTests: true,
+		})
+	}
+}
+
+func TestGenerate_ValueAsString(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative,value-as-string=true")
+	content := files["test/v1/format_dbtypes.pb.go"]
+	value := funcSource(t, content, "func (x *JSONDocumentValue) ValueContext(ctx context.Context) (driver.Value, error)")
+	if !strings.Contains(value, "dbtypesValueString(x.ProtoValue.value(ctx, JSONDocumentCodec()))") {
+		t.Errorf("JSON Value() should return a string:\n%s", value)
+	}
+	value = funcSource(t, content, "func (x *BinaryDocumentValue) ValueContext(ctx context.Context) (driver.Value, error)")
+	if strings.Contains(value, "dbtypesValueString") {
+		t.Errorf("binary Value() should keep returning []byte:\n%s", value)
+	}
+
+	// Without the option every format returns []byte
+	content = mustGenerate(t, "paths=source_relative")["test/v1/format_dbtypes.pb.go"]
+	if strings.Contains(content, "dbtypesValueString") {
+		t.Error("dbtypesValueString generated without value-as-string")
+	}
+}
+
+func TestGeneratedCode_ValueAsString(t *testing.T) {
+	// The fixture tests expect []byte values
+	runScratchModule(t, scratchModule{
+		param:            "paths=source_relative,value-as-string=true",
+		tests:            []string{"value_as_string_test.go"},
+		skipFixtureTests: true,
+	})
+	runScratchModule(t, scratchModule{
+		param:            "paths=source_relative,value-as-string=true,postgres-array=true",
+		tests:            []string{"pq_array_test.go"},
+		requires:         []string{"github.com/lib/pq@v1.12.3"},
+		tags:             pqBuildTag,
+		skipFixtureTests: true,
+	})
+	runScratchModule(t, scratchModule{
+		param:            "paths=source_relative,value-as-string=true,driver=pgx",
+		tests:            []string{"pgx_test.go"},
+		requires:         []string{"github.com/jackc/pgx/v5@v5.11.0"},
+		tags:             pgxBuildTag,
+		skipFixtureTests: true,
+	})
+	runScratchModule(t, scratchModule{
+		param:            "paths=source_relative,value-as-string=true,orm=gorm",
+		tests:            []string{"gorm_test.go"},
+		requires:         []string{"gorm.io/gorm@v1.25.12"},
+		tags:             gormBuildTag,
+		skipFixtureTests: true,
+	})
+}
+
+func TestGenerate_JSONOptions(t *testing.T) {
+	for _, tt := range []struct {
+		param, want string
+	}{
+		{"", "protojson.MarshalOptions{Resolver: dbtypesJSONResolver()}.Marshal(m)"},
+		{"json-emit-unpopulated=true", "protojson.MarshalOptions{Resolver: dbtypesJSONResolver(), EmitUnpopulated: true}.Marshal(m)"},
+		{"json-use-proto-names=true", "protojson.MarshalOptions{Resolver: dbtypesJSONResolver(), UseProtoNames: true}.Marshal(m)"},
+		{"json-proto-names=true", "protojson.MarshalOptions{Resolver: dbtypesJSONResolver(), UseProtoNames: true}.Marshal(m)"},
+		{"json-emit-unpopulated=true,json-use-proto-names=true", "protojson.MarshalOptions{Resolver: dbtypesJSONResolver(), EmitUnpopulated: true, UseProtoNames: true}.Marshal(m)"},
+	} {
+		content := mustGenerate(t, "paths=source_relative,"+tt.param)["test/v1/format_dbtypes.pb.go"]
+		if got := funcSource(t, content, "func dbtypesMarshalJSON("); !strings.Contains(got, tt.want) {
+			t.Errorf("%q: dbtypesMarshalJSON should call %s:\n%s", tt.param, tt.want, got)
+		}
+		// Unmarshaling accepts both field names and missing fields anyway
+		if got := funcSource(t, content, "func dbtypesUnmarshalJSON("); !strings.Contains(got, "protojson.UnmarshalOptions{Resolver: dbtypesJSONResolver()}") {
+			t.Errorf("%q: dbtypesUnmarshalJSON should not change:\n%s", tt.param, got)
+		}
+	}
+}
+
+func TestGeneratedCode_JSONOptions(t *testing.T) {
+	// The fixture tests expect protojson's default output
+	runScratchModule(t, scratchModule{
+		param:            "paths=source_relative,json-emit-unpopulated=true,json-use-proto-names=true",
+		tests:            []string{"json_options_test.go"},
+		skipFixtureTests: true,
+	})
+	runScratchModule(t, scratchModule{
+		param:            "paths=source_relative,format=json,json-use-proto-names=true",
+		tests:            []string{"json_proto_names_test.go"},
+		skipFixtureTests: true,
+	})
+	runScratchModule(t, scratchModule{
+		param:            "paths=source_relative,format=json,json-proto-names=true",
+		tests:            []string{"json_proto_names_test.go"},
+		skipFixtureTests: true,
+	})
+}
+
+func TestGenerate_Deterministic(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative,deterministic=true")
+	if marshal, _ := messageCodec(t, files, "ToolSetSpec"); marshal != "dbtypesMarshalDeterministic" {
+		t.Error("deterministic Value() should use dbtypesMarshalDeterministic")
+	}
+
+	// The gzip helper compresses the deterministic encoding.
+	content := mustGenerate(t, "paths=source_relative,deterministic=true,compress=gzip")["test/v1/format_dbtypes.pb.go"]
+	if !strings.Contains(funcSource(t, content, "func dbtypesMarshalGzip(m proto.Message) ([]byte, error)"), "proto.MarshalOptions{Deterministic: true}.MarshalAppend") {
+		t.Error("dbtypesMarshalGzip should marshal deterministically")
+	}
+
+	// JSON-format messages keep protojson, which already orders map keys.
+	if marshal, _ := messageCodec(t, files, "JSONDocument"); marshal != "dbtypesMarshalJSON" {
+		t.Error("JSONDocumentValue should still use protojson")
+	}
+}
+
+func TestGeneratedCode_Deterministic(t *testing.T) {
+	runGeneratedTests(t, "paths=source_relative,deterministic=true", "deterministic_test.go")
+	runGeneratedTests(t, "paths=source_relative,deterministic=true,compress=gzip", "deterministic_test.go")
+}
+
+func TestGenerate_SortRepeated(t *testing.T) {
+	content := mustGenerate(t, "paths=source_relative,deterministic=true,sort-repeated=true")["test/v1/format_dbtypes.pb.go"]
+	if !strings.Contains(funcSource(t, content, "func (c *dbtypesCodec) Encode(msg proto.Message) ([]byte, error) {"), "c.marshal(dbtypesSortRepeated(msg))") {
+		t.Error("sort-repeated=true should sort messages before every codec marshals them")
+	}
+	if strings.Contains(content, "func dbtypesMarshalBatch[") {
+		t.Error("sort-repeated=true batches should go through the codec")
+	}
+	if strings.Contains(mustGenerate(t, "paths=source_relative")["test/v1/format_dbtypes.pb.go"], "dbtypesSortRepeated") {
+		t.Error("dbtypesSortRepeated generated without sort-repeated=true")
+	}
+}
+
+func TestGeneratedCode_SortRepeated(t *testing.T) {
+	// The fixture tests round-trip repeated fields in their original order
+	runScratchModule(t, scratchModule{
+		param:            "paths=source_relative,deterministic=true,sort-repeated=true",
+		tests:            []string{"sort_repeated_test.go"},
+		skipFixtureTests: true,
+	})
+}
+
+func TestGenerate_Checksum(t *testing.T) {
+	for param, want := range map[string]string{
+		"paths=source_relative,emit-checksum=true":                          "sha256.Sum256(data)",
+		"paths=source_relative,emit-checksum=true,checksum-algorithm=crc32": "binary.BigEndian.AppendUint32(nil, crc32.ChecksumIEEE(data))",
+	} {
+		content := mustGenerate(t, param)["test/v1/format_dbtypes.pb.go"]
+		if !strings.Contains(funcSource(t, content, "func dbtypesChecksum(data []byte) []byte"), want) {
+			t.Errorf("%s: dbtypesChecksum should compute %s", param, want)
+		}
+		if !strings.Contains(content, "var ExpectedChecksum func(ctx context.Context, msg protoreflect.FullName) []byte") {
+			t.Errorf("%s: ExpectedChecksum should be declared", param)
+		}
+		if !strings.Contains(funcSource(t, content, "func (p *ProtoValue[T]) scan("), "p.verifyChecksum(ctx, data)") {
+			t.Errorf("%s: scan should verify the checksum of the stored bytes", param)
+		}
+		if !strings.Contains(content, "func (x *JSONDocumentValue) Checksum() []byte {") {
+			t.Errorf("%s: wrappers should have a Checksum method", param)
+		}
+	}
+
+	if strings.Contains(mustGenerate(t, "paths=source_relative")["test/v1/format_dbtypes.pb.go"], "Checksum") {
+		t.Error("checksums generated without emit-checksum=true")
+	}
+
+	for param, wantErr := range map[string]string{
+		"paths=source_relative,emit-checksum=true,checksum-algorithm=md5": `unknown checksum algorithm "md5"`,
+		"paths=source_relative,emit-checksum=true,encrypt-hooks=true":     "emit-checksum=true cannot be combined with encrypt-hooks",
+		"paths=source_relative,emit-checksum=true,generic=true":           "emit-checksum",
+	} {
+		if _, err := generate(t, param); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("%s: error = %v, want %q", param, err, wantErr)
+		}
+	}
+}
+
+func TestGeneratedCode_Checksum(t *testing.T) {
+	runGeneratedTests(t, "paths=source_relative,emit-checksum=true", "checksum_test.go")
+	runGeneratedTests(t, "paths=source_relative,emit-checksum=true,checksum-algorithm=crc32", "checksum_test.go")
+	runGeneratedTests(t, "paths=source_relative,emit-checksum=true,format=json", "checksum_test.go")
+	// The fixture tests expect binary values, not base64 text
+	runScratchModule(t, scratchModule{
+		param:            "paths=source_relative,emit-checksum=true,base64-text=true",
+		tests:            []string{"checksum_test.go"},
+		skipFixtureTests: true,
+	})
+}
+
+func TestGenerate_Generic(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative,generic=true")
+
+	content := files["test/v1/test_dbtypes.pb.go"]
+	if !strings.Contains(content, "type ToolSetSpecValue = dbtypes.DBValue[*ToolSetSpec]") {
+		t.Error("ToolSetSpecValue should alias dbtypes.DBValue")
+	}
+	if strings.Contains(content, "func (x *ToolSetSpecValue)") {
+		t.Error("generic wrappers should not declare their own methods")
+	}
+
+	content = files["test/v1/format_dbtypes.pb.go"]
+	if !strings.Contains(content, "type JSONDocumentValue = dbtypes.JSONValue[*JSONDocument]") {
+		t.Error("JSON-format messages should alias dbtypes.JSONValue")
+	}
+	if strings.Contains(content, "ProtoValue") {
+		t.Error("generic=true should not generate ProtoValue")
+	}
+}
+
+func TestGenerate_GenericUnsupportedOptions(t *testing.T) {
+	for _, opt := range []string{"compress=gzip", "encrypt-hooks=true", "marshal-hooks=true", "metrics-hooks=true", "otel=true", "empty-as-null=true", "nil-message=null", "deterministic=true", "max-scan-size=1024", "discard-unknown=true", "allow-partial=true", "value-as-string=true", "json-emit-unpopulated=true", "json-use-proto-names=true", "validate=true", "format=cbor", "format=msgpack", "emit-bson=true", "emit-migrators=true", "value-receiver=true", "auto-scan=true", "vtproto=true", "functional-options=true", "scan-null-as-empty=true", "orm=gorm"} {
+		if _, err := generate(t, "generic=true,"+opt); err == nil {
+			t.Errorf("generic=true,%s should fail generation", opt)
+		}
+	}
+}
+
+func TestGeneratedCode_Generic(t *testing.T) {
+	for _, format := range []string{"binary", "json", "text"} {
+		t.Run(format, func(t *testing.T) {
+			runGeneratedTests(t, "paths=source_relative,generic=true,format="+format)
+		})
+	}
+}
+
+func TestGeneratedCode_GenericRedact(t *testing.T) {
+	runGeneratedTests(t, "paths=source_relative,generic=true", "generic_redact_test.go")
+}
+
+func TestGenerate_Validate(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative,validate=true")
+
+	content, ok := files["test/v1/test_dbtypes_validate.pb.go"]
+	if !ok {
+		t.Fatal("validate=true should generate test_dbtypes_validate.pb.go")
+	}
+	if !strings.Contains(content, "//go:build dbtypes_validate\n") {
+		t.Error("validate file should be guarded by the dbtypes_validate build tag")
+	}
+	funcSource(t, content, "func (x *ToolSetSpecValue) Validate() error")
+
+	main := files["test/v1/format_dbtypes.pb.go"]
+	if strings.Contains(main, `"buf.build/go/protovalidate"`) {
+		t.Error("format_dbtypes.pb.go should not import protovalidate")
+	}
+	value := funcSource(t, main, "func (p *ProtoValue[T]) value(ctx context.Context, codec Codec) (_ driver.Value, err error)")
+	if !strings.Contains(value, "dbtypesValidate(p.Message)") {
+		t.Errorf("value() should consult the validate hook:\n%s", value)
+	}
+
+	if _, ok := mustGenerate(t, "paths=source_relative")["test/v1/test_dbtypes_validate.pb.go"]; ok {
+		t.Error("validate file generated without validate=true")
+	}
+}
+
+// protovalidateModule is the protovalidate version the validate tests build
+// against.
+const protovalidateModule = "buf.build/go/protovalidate@v1.0.0"
+
+// validateFixture builds the fixture the validate tests check protovalidate
+// rules with, equivalent to
+//
+//	syntax = "proto3";
+//	package test.v1;
+//	import "buf/validate/validate.proto";
+//
+//	message Account {
+//	  string name = 1 [(buf.validate.field).string.min_len = 2];
+//	}
+//
+// The fixture package cannot import buf/validate/validate.proto, so the file
+// refers to it by path only and carries the rule as the encoded extension,
+// which the protovalidate module linked into the scratch module resolves.
+func validateFixture(t *testing.T) protoreflect.FileDescriptor {
+	t.Helper()
+
+	validate, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("buf/validate/validate.proto"),
+		Package: proto.String("buf.validate"),
+		Syntax:  proto.String("proto2"),
+		Options: &descriptorpb.FileOptions{
+			GoPackage: proto.String("buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"),
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var files protoregistry.Files
+	if err := files.RegisterFile(validate); err != nil {
+		t.Fatal(err)
+	}
+
+	// buf.validate.field (1159) { string (14) { min_len (2): 2 } }
+	minLen := protowire.AppendTag(nil, 2, protowire.VarintType)
+	minLen = protowire.AppendVarint(minLen, 2)
+	rules := protowire.AppendTag(nil, 14, protowire.BytesType)
+	rules = protowire.AppendBytes(rules, minLen)
+	options := &descriptorpb.FieldOptions{}
+	options.ProtoReflect().SetUnknown(protowire.AppendBytes(protowire.AppendTag(nil, 1159, protowire.BytesType), rules))
+
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("test/v1/validate.proto"),
+		Package:    proto.String("test.v1"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"buf/validate/validate.proto"},
+		Options: &descriptorpb.FileOptions{
+			GoPackage: proto.String("github.com/cadenya-agents/protoc-gen-go-dbtypes/gen/go/test/v1;testv1"),
+		},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Account"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("name"),
+				JsonName: proto.String("name"),
+				Number:   proto.Int32(1),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				Options:  options,
+			}},
+		}},
+	}, &files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fd
+}
+
+func TestGeneratedCode_Validate(t *testing.T) {
+	runScratchModule(t, scratchModule{
+		param:      "paths=source_relative,validate=true",
+		tests:      []string{"validate_test.go"},
+		requires:   []string{protovalidateModule},
+		tags:       validateBuildTag,
+		protoFiles: []protoreflect.FileDescriptor{validateFixture(t)},
+	})
+}
+
+func TestGenerate_ORMGorm(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative,orm=gorm")
+
+	content, ok := files["test/v1/format_dbtypes_gorm.pb.go"]
+	if !ok {
+		t.Fatal("orm=gorm should generate format_dbtypes_gorm.pb.go")
+	}
+	if !strings.Contains(content, "//go:build dbtypes_gorm\n") {
+		t.Error("gorm file should be guarded by the dbtypes_gorm build tag")
+	}
+
+	binary := funcSource(t, content, "func (*BinaryDocumentValue) GormDBDataType(db *gorm.DB, field *schema.Field) string")
+	if !strings.Contains(binary, `"bytea"`) || !strings.Contains(binary, `"blob"`) {
+		t.Errorf("binary column types missing:\n%s", binary)
+	}
+	json := funcSource(t, content, "func (*JSONDocumentValue) GormDBDataType(db *gorm.DB, field *schema.Field) string")
+	if !strings.Contains(json, `"jsonb"`) {
+		t.Errorf("json column types missing:\n%s", json)
+	}
+
+	// GormValue is only needed for JSON columns.
+	if !strings.Contains(content, "func (x *JSONDocumentValue) GormValue(ctx context.Context, db *gorm.DB) clause.Expr") {
+		t.Error("JSONDocumentValue should implement gorm.Valuer")
+	}
+	text := funcSource(t, content, "func (x *TextDocumentValue) GormValue(ctx context.Context, db *gorm.DB) clause.Expr")
+	if strings.Contains(text, "CAST") {
+		t.Errorf("prototext values should not be cast to JSON:\n%s", text)
+	}
+	if strings.Contains(content, "func (x *BinaryDocumentValue) GormValue(") {
+		t.Error("BinaryDocumentValue should not implement gorm.Valuer")
+	}
+
+	// The main file must stay free of GORM imports.
+	if strings.Contains(files["test/v1/format_dbtypes.pb.go"], "gorm") {
+		t.Error("format_dbtypes.pb.go should not reference gorm")
+	}
+	if _, ok := mustGenerate(t, "paths=source_relative")["test/v1/format_dbtypes_gorm.pb.go"]; ok {
+		t.Error("gorm file generated without orm=gorm")
+	}
+}
+
+func TestGenerate_UnknownORM(t *testing.T) {
+	if _, err := generate(t, "orm=sqlboiler"); err == nil {
+		t.Error("orm=sqlboiler should fail generation")
+	}
+}
+
+func TestGeneratedCode_ORMGorm(t *testing.T) {
+	runScratchModule(t, scratchModule{
+		param:    "paths=source_relative,orm=gorm",
+		tests:    []string{"gorm_test.go"},
+		requires: []string{"gorm.io/gorm@v1.25.12"},
+		tags:     gormBuildTag,
+	})
+}
+
+func TestGenerate_PostgresArray(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative,postgres-array=true")
+
+	content, ok := files["test/v1/format_dbtypes_pq.pb.go"]
+	if !ok {
+		t.Fatal("postgres-array=true should generate format_dbtypes_pq.pb.go")
+	}
+	if !strings.Contains(content, "//go:build dbtypes_pq\n") {
+		t.Error("pq file should be guarded by the dbtypes_pq build tag")
+	}
+	if !strings.Contains(content, "type BinaryDocumentValueArray []BinaryDocumentValue") {
+		t.Error("postgres-array=true should generate BinaryDocumentValueArray")
+	}
+	if !strings.Contains(funcSource(t, content, "func (a BinaryDocumentValueArray) Value() (driver.Value, error)"), "pq.ByteaArray") {
+		t.Error("binary arrays should use the bytea[] encoding")
+	}
+	if !strings.Contains(funcSource(t, content, "func (a JSONDocumentValueArray) Value() (driver.Value, error)"), "pq.StringArray") {
+		t.Error("JSON arrays should use the text[] encoding")
+	}
+
+	if strings.Contains(files["test/v1/format_dbtypes.pb.go"], "lib/pq") {
+		t.Error("format_dbtypes.pb.go should not import lib/pq")
+	}
+	if _, ok := mustGenerate(t, "paths=source_relative")["test/v1/format_dbtypes_pq.pb.go"]; ok {
+		t.Error("pq file generated without postgres-array=true")
+	}
+}
+
+func TestGeneratedCode_PostgresArray(t *testing.T) {
+	for _, param := range []string{"postgres-array=true", "postgres-array=true,generic=true"} {
+		t.Run(param, func(t *testing.T) {
+			runScratchModule(t, scratchModule{
+				param:    "paths=source_relative," + param,
+				tests:    []string{"pq_array_test.go"},
+				requires: []string{"github.com/lib/pq@v1.12.3"},
+				tags:     pqBuildTag,
+			})
+		})
+	}
+}
+
+func TestGenerate_DriverPgx(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative,driver=pgx")
+
+	content, ok := files["test/v1/test_dbtypes_pgx.pb.go"]
+	if !ok {
+		t.Fatal("driver=pgx should generate test_dbtypes_pgx.pb.go")
+	}
+	if !strings.Contains(content, "//go:build dbtypes_pgx\n") {
+		t.Error("pgx file should be guarded by the dbtypes_pgx build tag")
+	}
+
+	// Register is declared once per package; every file adds its messages
+	var registers int
+	for name, content := range files {
+		if strings.HasSuffix(name, "_dbtypes_pgx.pb.go") {
+			registers += strings.Count(content, "func Register(m *pgtype.Map)")
+			if !strings.Contains(content, "dbtypesPgxMessages = append(dbtypesPgxMessages,") {
+				t.Errorf("%s should register its messages", name)
+			}
+		}
+	}
+	if registers != 1 {
+		t.Errorf("Register declared %d times, want once", registers)
+	}
+	if !strings.Contains(files["test/v1/format_dbtypes_pgx.pb.go"], `pgTypes: []string{"jsonb", "json"},`) {
+		t.Error("JSON messages should be stored in jsonb or json columns")
+	}
+
+	if strings.Contains(files["test/v1/test_dbtypes.pb.go"], "jackc/pgx") {
+		t.Error("test_dbtypes.pb.go should not import pgx")
+	}
+	if _, ok := mustGenerate(t, "paths=source_relative")["test/v1/test_dbtypes_pgx.pb.go"]; ok {
+		t.Error("pgx file generated without driver=pgx")
+	}
+	if _, err := generate(t, "paths=source_relative,driver=mysql"); err == nil {
+		t.Error("unknown driver should be rejected")
+	}
+}
+
+func TestGeneratedCode_DriverPgx(t *testing.T) {
+	for _, param := range []string{"driver=pgx", "driver=pgx,generic=true"} {
+		t.Run(param, func(t *testing.T) {
+			runScratchModule(t, scratchModule{
+				param:    "paths=source_relative," + param,
+				tests:    []string{"pgx_test.go"},
+				requires: []string{"github.com/jackc/pgx/v5@v5.11.0"},
+				tags:     pgxBuildTag,
+			})
+		})
+	}
+}
+
+func TestGenerate_EmitDDL(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative,emit-ddl=true")
+
+	sql, ok := files["test/v1/testv1_dbtypes.sql"]
+	if !ok {
+		t.Fatal("emit-ddl=true should generate test/v1/testv1_dbtypes.sql")
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(sql, "\n"), "\n") {
+		if !strings.HasPrefix(line, "--") {
+			t.Errorf("line %q is not an SQL comment", line)
+		}
+	}
+
+	// Every generated wrapper is listed with its column type
+	var wrappers int
+	for name, content := range files {
+		if !strings.HasSuffix(name, "_dbtypes.pb.go") {
+			continue
+		}
+		for _, line := range strings.Split(content, "\n") {
+			if !strings.HasPrefix(line, "type ") || !strings.HasSuffix(line, "Value struct {") || strings.HasPrefix(line, "type Null") {
+				continue
+			}
+			wrappers++
+			wrapper := strings.Fields(line)[1]
+			if !strings.Contains(sql, "  "+wrapper+", ") {
+				t.Errorf("SQL does not list %s", wrapper)
+			}
+		}
+	}
+	if got := len(ddlRows(sql)); got != wrappers {
+		t.Errorf("SQL lists %d messages, want %d", got, wrappers)
+	}
+
+	for _, tt := range []struct {
+		param, message, want string
+	}{
+		{"", "test.v1.ToolSetSpec", "bytea ToolSetSpecValue, binary"},
+		{"", "test.v1.JSONDocument", "jsonb JSONDocumentValue, json"},
+		{"", "test.v1.TextDocument", "text TextDocumentValue, text"},
+		{"dialect=mysql", "test.v1.ToolSetSpec", "blob ToolSetSpecValue, binary"},
+		{"dialect=mysql", "test.v1.JSONDocument", "json JSONDocumentValue, json"},
+		{"dialect=mysql", "test.v1.TextDocument", "longtext TextDocumentValue, text"},
+		{"dialect=sqlite", "test.v1.ToolSetSpec", "blob ToolSetSpecValue, binary"},
+		{"dialect=sqlite,compress=zstd", "test.v1.ToolSetSpec", "blob ToolSetSpecValue, binary, zstd"},
+		{"dialect=sqlite", "test.v1.JSONDocument", "text JSONDocumentValue, json"},
+		{"dialect=sqlite", "test.v1.TextDocument", "text TextDocumentValue, text"},
+		{"dialect=sqlite,encrypt-hooks=true", "test.v1.JSONDocument", "blob JSONDocumentValue, json, encrypted"},
+		{"dialect=sqlite,base64-text=true", "test.v1.ToolSetSpec", "text ToolSetSpecValue, binary, base64"},
+		{"compress=gzip", "test.v1.ToolSetSpec", "bytea ToolSetSpecValue, binary, gzip"},
+		// Ciphertext doesn't fit JSON columns
+		{"encrypt-hooks=true", "test.v1.JSONDocument", "bytea JSONDocumentValue, json, encrypted"},
+		{"base64-text=true", "test.v1.ToolSetSpec", "text ToolSetSpecValue, binary, base64"},
+		{"base64-text=true", "test.v1.JSONDocument", "jsonb JSONDocumentValue, json"},
+	} {
+		files := mustGenerate(t, "paths=source_relative,emit-ddl=true,"+tt.param)
+		if got := ddlRows(files["test/v1/testv1_dbtypes.sql"])[tt.message]; got != tt.want {
+			t.Errorf("%s: %s = %q, want %q", tt.param, tt.message, got, tt.want)
+		}
+	}
+
+	// SQLite takes the affinity of a column from the declared type
+	sqlite := mustGenerate(t, "paths=source_relative,emit-ddl=true,dialect=sqlite")["test/v1/testv1_dbtypes.sql"]
+	if !strings.Contains(sqlite, "-- dialect: sqlite\n") || !strings.Contains(sqlite, "BLOB affinity") {
+		t.Errorf("SQLite DDL should explain the column affinities:\n%s", sqlite)
+	}
+	if strings.Contains(sql, "affinity") {
+		t.Error("the affinity note should only be written for SQLite")
+	}
+
+	excluded := mustGenerate(t, "paths=source_relative,emit-ddl=true,exclude=Container")["test/v1/testv1_dbtypes.sql"]
+	if _, ok := ddlRows(excluded)["test.v1.Container"]; ok {
+		t.Error("excluded messages should not be listed")
+	}
+
+	if _, ok := mustGenerate(t, "paths=source_relative")["test/v1/testv1_dbtypes.sql"]; ok {
+		t.Error("SQL file generated without emit-ddl=true")
+	}
+	if _, err := generate(t, "paths=source_relative,emit-ddl=true,dialect=oracle"); err == nil {
+		t.Error("unknown dialect should be rejected")
+	}
+}
+
+func TestGenerate_EmitSqlcOverrides(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative,emit-sqlc-overrides=true")
+
+	content, ok := files["test/v1/testv1_dbtypes_sqlc.yaml"]
+	if !ok {
+		t.Fatal("emit-sqlc-overrides=true should generate test/v1/testv1_dbtypes_sqlc.yaml")
+	}
+	var fragment struct {
+		Overrides []struct {
+			Column   string `yaml:"column"`
+			Nullable bool   `yaml:"nullable"`
+			GoType   struct {
+				Import  string `yaml:"import"`
+				Package string `yaml:"package"`
+				Type    string `yaml:"type"`
+			} `yaml:"go_type"`
+		} `yaml:"overrides"`
+	}
+	if err := yaml.Unmarshal([]byte(content), &fragment); err != nil {
+		t.Fatalf("invalid YAML: %v\n%s", err, content)
+	}
+
+	type entry struct {
+		column   string
+		nullable bool
+	}
+	got := make(map[string]entry)
+	for _, o := range fragment.Overrides {
+		if o.GoType.Import != "github.com/cadenya-agents/protoc-gen-go-dbtypes/gen/go/test/v1" || o.GoType.Package != "testv1" {
+			t.Errorf("%s: go_type import %q package %q, want the fixture package", o.GoType.Type, o.GoType.Import, o.GoType.Package)
+		}
+		got[o.GoType.Type] = entry{o.Column, o.Nullable}
+	}
+
+	// One entry per generated wrapper and per Null wrapper
+	var wrappers int
+	for name, content := range files {
+		if !strings.HasSuffix(name, "_dbtypes.pb.go") {
+			continue
+		}
+		for _, line := range strings.Split(content, "\n") {
+			if !strings.HasPrefix(line, "type ") || !strings.HasSuffix(line, "Value struct {") {
+				continue
+			}
+			wrappers++
+			wrapper := strings.Fields(line)[1]
+			e, ok := got[wrapper]
+			if !ok {
+				t.Errorf("no override for %s", wrapper)
+			}
+			if e.nullable != strings.HasPrefix(wrapper, "Null") {
+				t.Errorf("%s: nullable = %v", wrapper, e.nullable)
+			}
+		}
+	}
+	if len(fragment.Overrides) != wrappers {
+		t.Errorf("%d overrides, want %d", len(fragment.Overrides), wrappers)
+	}
+	for wrapper, column := range map[string]string{
+		"ToolSetSpecValue":      "*.tool_set_spec",
+		"NullJSONDocumentValue": "*.json_document",
+	} {
+		if got[wrapper].column != column {
+			t.Errorf("%s column = %q, want %q", wrapper, got[wrapper].column, column)
+		}
+	}
+
+	// The import path follows out-package
+	outPkg := mustGenerate(t, "paths=source_relative,emit-sqlc-overrides=true,out-package=dbtypes")
+	if !strings.Contains(outPkg["test/v1/dbtypes/dbtypes_dbtypes_sqlc.yaml"], `import: "github.com/cadenya-agents/protoc-gen-go-dbtypes/gen/go/test/v1/dbtypes"`) {
+		t.Error("out-package overrides should import the subpackage")
+	}
+
+	if _, ok := mustGenerate(t, "paths=source_relative")["test/v1/testv1_dbtypes_sqlc.yaml"]; ok {
+		t.Error("sqlc overrides generated without emit-sqlc-overrides=true")
+	}
+}
+
+// ddlRows maps the messages listed in an emit-ddl file to the rest of their
+// row, with whitespace collapsed.
+func TestGenerate_EmitJSONSchema(t *testing.T) {
+	spec := &testv1.ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "tools", Enabled: true}
+	packed, err := anypb.New(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples := []proto.Message{
+		spec,
+		&testv1.Container{Id: "c", Spec: spec, Items: []*testv1.Container_Item{{Key: "k", Value: "v"}}},
+		&testv1.UserPreferences{Theme: "dark", Settings: map[string]string{"tz": "UTC"}},
+		&testv1.LegacyRecord{Id: proto.String("r"), Priority: proto.Int32(3), Scores: []int32{1, 2}, State: testv1.LegacyState_LEGACY_STATE_RETIRED.Enum(), Detail: &testv1.LegacyRecord_Detail{}},
+		&testv1.Payload{Id: "p", Content: &testv1.Payload_Count{Count: 1 << 60}},
+		&testv1.Payload{Content: &testv1.Payload_Blob{Blob: []byte{0xff}}},
+		&testv1.Envelope{Id: "e", Payload: packed},
+		&testv1.LegacyRecord{Id: proto.String("unpopulated")},
+		&testv1.Container{},
+	}
+	for param, opts := range map[string]protojson.MarshalOptions{
+		"paths=source_relative,emit-jsonschema=true,format=json":                            {},
+		"paths=source_relative,emit-jsonschema=true,format=json,json-use-proto-names=true":  {UseProtoNames: true},
+		"paths=source_relative,emit-jsonschema=true,format=json,json-emit-unpopulated=true": {EmitUnpopulated: true},
+	} {
+		files := mustGenerate(t, param)
+		for _, msg := range samples {
+			name := string(msg.ProtoReflect().Descriptor().FullName())
+			content, ok := files["test/v1/"+name+".schema.json"]
+			if !ok {
+				t.Fatalf("%s: no schema for %s", param, name)
+			}
+			var schema map[string]any
+			if err := json.Unmarshal([]byte(content), &schema); err != nil {
+				t.Fatalf("%s: invalid schema for %s: %v\n%s", param, name, err, content)
+			}
+			data, err := opts.Marshal(msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var doc any
+			if err := json.Unmarshal(data, &doc); err != nil {
+				t.Fatal(err)
+			}
+			if err := validateJSONSchema(schema, schema, doc, "$"); err != nil {
+				t.Errorf("%s: %s does not validate against its schema: %v\n%s", param, data, err, content)
+			}
+		}
+	}
+
+	files := mustGenerate(t, "paths=source_relative,emit-jsonschema=true")
+	var schema map[string]any
+	if err := json.Unmarshal([]byte(files["test/v1/test.v1.ToolSetSpec.schema.json"]), &schema); err == nil {
+		t.Error("schema written for test.v1.ToolSetSpec, which is stored in the binary format")
+	}
+	// Messages with a (dbtypes.format) option of JSON are described
+	if err := json.Unmarshal([]byte(files["test/v1/test.v1.JSONDocument.schema.json"]), &schema); err != nil {
+		t.Fatalf("invalid schema for test.v1.JSONDocument: %v", err)
+	}
+	if schema["title"] != "test.v1.JSONDocument" {
+		t.Errorf("schema title = %v, want the full name of the message", schema["title"])
+	}
+	// and the validator rejects documents of the wrong shape
+	for _, doc := range []string{`{"id": 1}`, `{"labels": {"env": true}}`, `[]`} {
+		var v any
+		if err := json.Unmarshal([]byte(doc), &v); err != nil {
+			t.Fatal(err)
+		}
+		if validateJSONSchema(schema, schema, v, "$") == nil {
+			t.Errorf("%s validates against the JSONDocument schema", doc)
+		}
+	}
+
+	if _, ok := mustGenerate(t, "paths=source_relative,format=json")["test/v1/test.v1.ToolSetSpec.schema.json"]; ok {
+		t.Error("schema written without emit-jsonschema=true")
+	}
+}
+
+// validateJSONSchema checks doc against schema, a subschema of root, for
+// the keywords emit-jsonschema writes: type, properties, required,
+// additionalProperties, items, enum, pattern, anyOf and $ref.
+func validateJSONSchema(root, schema map[string]any, doc any, at string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		if ref == "#" {
+			return validateJSONSchema(root, root, doc, at)
+		}
+		defs, _ := root["$defs"].(map[string]any)
+		def, ok := defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: unresolved $ref %q", at, ref)
+		}
+		return validateJSONSchema(root, def, doc, at)
+	}
+	if anyOf, ok := schema["anyOf"].([]any); ok {
+		for _, sub := range anyOf {
+			if validateJSONSchema(root, sub.(map[string]any), doc, at) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: %v matches no schema of anyOf", at, doc)
+	}
+	if types, ok := schema["type"]; ok {
+		want, _ := types.([]any)
+		if s, ok := types.(string); ok {
+			want = []any{s}
+		}
+		matched := false
+		for _, w := range want {
+			switch v := doc.(type) {
+			case nil:
+				matched = matched || w == "null"
+			case bool:
+				matched = matched || w == "boolean"
+			case string:
+				matched = matched || w == "string"
+			case float64:
+				matched = matched || w == "number" || (w == "integer" && v == float64(int64(v)))
+			case []any:
+				matched = matched || w == "array"
+			case map[string]any:
+				matched = matched || w == "object"
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: %v is not of type %v", at, doc, types)
+		}
+	}
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			found = found || e == doc
+		}
+		if !found {
+			return fmt.Errorf("%s: %v is not one of %v", at, doc, enum)
+		}
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		if s, isString := doc.(string); isString && !regexp.MustCompile(pattern).MatchString(s) {
+			return fmt.Errorf("%s: %q does not match %s", at, s, pattern)
+		}
+	}
+	if items, ok := schema["items"].(map[string]any); ok {
+		for i, v := range doc.([]any) {
+			if err := validateJSONSchema(root, items, v, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+				return err
+			}
+		}
+	}
+	if obj, isObject := doc.(map[string]any); isObject {
+		required, _ := schema["required"].([]any)
+		for _, name := range required {
+			if _, ok := obj[name.(string)]; !ok {
+				return fmt.Errorf("%s: missing required property %q", at, name)
+			}
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		additional, _ := schema["additionalProperties"].(map[string]any)
+		for name, v := range obj {
+			sub, ok := properties[name].(map[string]any)
+			if !ok {
+				sub = additional
+			}
+			if sub == nil {
+				continue
+			}
+			if err := validateJSONSchema(root, sub, v, at+"."+name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func ddlRows(sql string) map[string]string {
+	rows := make(map[string]string)
+	for _, line := range strings.Split(sql, "\n") {
+		fields := strings.Fields(strings.TrimPrefix(line, "--"))
+		if len(fields) > 1 && strings.HasPrefix(line, "--   ") {
+			rows[fields[0]] = strings.Join(fields[1:], " ")
+		}
+	}
+	return rows
+}
+
+func TestGenerate_EmitBSON(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative,emit-bson=true")
+
+	content, ok := files["test/v1/test_dbtypes_bson.pb.go"]
+	if !ok {
+		t.Fatal("emit-bson=true should generate test_dbtypes_bson.pb.go")
+	}
+	if !strings.Contains(content, "//go:build dbtypes_bson\n") {
+		t.Error("bson file should be guarded by the dbtypes_bson build tag")
+	}
+	funcSource(t, content, "func (x ToolSetSpecValue) MarshalBSON() ([]byte, error)")
+	funcSource(t, content, "func (x *ToolSetSpecValue) UnmarshalBSON(data []byte) error")
+
+	// The helpers are declared once per package
+	var helpers int
+	for name, content := range files {
+		if strings.HasSuffix(name, "_dbtypes_bson.pb.go") {
+			helpers += strings.Count(content, "func dbtypesMarshalBSON(")
+		}
+	}
+	if helpers != 1 {
+		t.Errorf("dbtypesMarshalBSON declared %d times, want once", helpers)
+	}
+
+	if strings.Contains(files["test/v1/test_dbtypes.pb.go"], "mongo-driver") {
+		t.Error("test_dbtypes.pb.go should not import the MongoDB driver")
+	}
+	if _, ok := mustGenerate(t, "paths=source_relative")["test/v1/test_dbtypes_bson.pb.go"]; ok {
+		t.Error("bson file generated without emit-bson=true")
+	}
+}
+
+func TestGeneratedCode_EmitBSON(t *testing.T) {
+	runScratchModule(t, scratchModule{
+		param:    "paths=source_relative,emit-bson=true",
+		tests:    []string{"bson_test.go"},
+		requires: []string{"go.mongodb.org/mongo-driver/v2@v2.9.1"},
+		tags:     bsonBuildTag,
+	})
+}
+
+func TestGenerate_EmitJSONPaths(t *testing.T) {
+	content := mustGenerate(t, "paths=source_relative,emit-json-paths=true")["test/v1/test_dbtypes.pb.go"]
+	for _, decl := range []string{
+		`ToolSetSpecPathToolIds = "toolIds"`,
+		`ToolSetSpecPathName    = "name"`,
+		`ToolSetSpecPathEnabled = "enabled"`,
+		`UserPreferencesPathTheme    = "theme"`,
+		`UserPreferencesPathLanguage = "language"`,
+	} {
+		if !strings.Contains(content, decl) {
+			t.Errorf("missing JSON path constant %s", decl)
+		}
+	}
+
+	content = mustGenerate(t, "paths=source_relative,emit-json-paths=true,json-use-proto-names=true")["test/v1/test_dbtypes.pb.go"]
+	if !strings.Contains(content, `ToolSetSpecPathToolIds = "tool_ids"`) {
+		t.Error("JSON path constants should use proto names with json-use-proto-names")
+	}
+
+	if strings.Contains(mustGenerate(t, "paths=source_relative")["test/v1/test_dbtypes.pb.go"], "ToolSetSpecPathName") {
+		t.Error("JSON path constants generated without emit-json-paths")
+	}
+}
+
+func TestGenerate_EmitMigrators(t *testing.T) {
+	content := mustGenerate(t, "paths=source_relative,emit-migrators=true")["test/v1/test_dbtypes.pb.go"]
+	convert := funcSource(t, content, "func ConvertToolSetSpec(src []byte) ([]byte, error)")
+	for _, want := range []string{"proto.Unmarshal(src, msg)", "dbtypesMarshalJSON(msg)"} {
+		if !strings.Contains(convert, want) {
+			t.Errorf("ConvertToolSetSpec should call %s:\n%s", want, convert)
+		}
+	}
+
+	content = mustGenerate(t, "paths=source_relative,emit-migrators=true,migrate-from=json,migrate-to=cbor")["test/v1/test_dbtypes.pb.go"]
+	convert = funcSource(t, content, "func ConvertToolSetSpec(src []byte) ([]byte, error)")
+	for _, want := range []string{"dbtypesUnmarshalJSON(src, msg)", "dbtypes.MarshalCBOR(msg)"} {
+		if !strings.Contains(convert, want) {
+			t.Errorf("ConvertToolSetSpec should call %s:\n%s", want, convert)
+		}
+	}
+
+	if strings.Contains(mustGenerate(t, "paths=source_relative")["test/v1/test_dbtypes.pb.go"], "func ConvertToolSetSpec") {
+		t.Error("ConvertToolSetSpec generated without emit-migrators")
+	}
+	for _, param := range []string{"emit-migrators=true,migrate-from=json", "emit-migrators=true,migrate-to=yaml"} {
+		if _, err := generate(t, param); err == nil {
+			t.Errorf("%s should fail generation", param)
+		}
+	}
+}
+
+func TestGeneratedCode_EmitMigrators(t *testing.T) {
+	runGeneratedTests(t, "paths=source_relative,emit-migrators=true", "migrate_test.go")
+}
+
+func TestGenerate_Builders(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative,builders=true")
+	content := files["test/v1/test_dbtypes.pb.go"]
+	for _, decl := range []string{
+		"func NewToolSetSpecValueBuilder() *ToolSetSpecValueBuilder",
+		"func (b *ToolSetSpecValueBuilder) SetToolIds(v []string) *ToolSetSpecValueBuilder",
+		"func (b *ToolSetSpecValueBuilder) SetName(v string) *ToolSetSpecValueBuilder",
+		"func (b *ToolSetSpecValueBuilder) SetEnabled(v bool) *ToolSetSpecValueBuilder",
+		"func (b *UserPreferencesValueBuilder) SetSettings(v map[string]string) *UserPreferencesValueBuilder",
+		"func (b *ToolSetSpecValueBuilder) Build() *ToolSetSpecValue",
+	} {
+		funcSource(t, content, decl)
+	}
+
+	// Oneof members select their case, and fields with presence take a value
+	setSpec := funcSource(t, files["test/v1/oneof_dbtypes.pb.go"], "func (b *PayloadValueBuilder) SetSpec(v *ToolSetSpec) *PayloadValueBuilder")
+	if !strings.Contains(setSpec, "b.msg.Content = &Payload_Spec{Spec: v}") {
+		t.Errorf("SetSpec should select the oneof member:\n%s", setSpec)
+	}
+	setName := funcSource(t, files["test/v1/legacy_dbtypes.pb.go"], "func (b *LegacyRecordValueBuilder) SetName(v string) *LegacyRecordValueBuilder")
+	if !strings.Contains(setName, "b.msg.Name = &v") {
+		t.Errorf("SetName should store the address of a proto2 optional value:\n%s", setName)
+	}
+
+	if strings.Contains(mustGenerate(t, "paths=source_relative")["test/v1/test_dbtypes.pb.go"], "Builder") {
+		t.Error("builders generated without builders=true")
+	}
+}
+
+func TestGeneratedCode_Builders(t *testing.T) {
+	runGeneratedTests(t, "paths=source_relative,builders=true", "builder_test.go")
+	runGeneratedTests(t, "paths=source_relative,builders=true,generic=true", "builder_test.go")
+}
+
+func TestGenerate_ValueReceiver(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative,value-receiver=true")
+	content := files["test/v1/test_dbtypes.pb.go"]
+	for _, want := range []string{
+		"func (x ToolSetSpecValue) Value() (driver.Value, error) {",
+		"func (x ToolSetSpecValue) ValueContext(ctx context.Context) (driver.Value, error) {",
+		"func (x ToolSetSpecValue) Unwrap() *ToolSetSpec {",
+		"func (x ToolSetSpecValue) ProtoMessage() proto.Message {",
+		"func (x ToolSetSpecValue) Size() int {",
+		"func (x ToolSetSpecValue) Equal(other *ToolSetSpecValue) bool {",
+		"func (x ToolSetSpecValue) String() string {",
+		// Methods that modify the wrapper keep pointer receivers
+		"func (x *ToolSetSpecValue) Scan(src any) error {",
+		"func (x *ToolSetSpecValue) GetOrInit() *ToolSetSpec {",
+		"func (x *ToolSetSpecValue) Reset() {",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("value-receiver=true should generate %q", want)
+		}
+	}
+
+	content = mustGenerate(t, "paths=source_relative")["test/v1/test_dbtypes.pb.go"]
+	if strings.Contains(content, "func (x ToolSetSpecValue) Value()") {
+		t.Error("Value has a value receiver without value-receiver=true")
+	}
+	if !strings.Contains(content, "func (x *ToolSetSpecValue) String() string {") {
+		t.Error("String should have a pointer receiver, safe to call on nil, without value-receiver=true")
+	}
+}
+
+func TestGeneratedCode_ValueReceiver(t *testing.T) {
+	// The fixture tests call Equal on nil pointers, which value receivers
+	// cannot take
+	runScratchModule(t, scratchModule{
+		param:            "paths=source_relative,value-receiver=true",
+		tests:            []string{"value_receiver_test.go"},
+		skipFixtureTests: true,
+	})
+}
+
+func TestGenerate_AutoScan(t *testing.T) {
+	content := mustGenerate(t, "paths=source_relative,auto-scan=true")["test/v1/format_dbtypes.pb.go"]
+	if !strings.Contains(content, "var DetectFormat func(data []byte, codec Codec) Codec = dbtypesDetectFormat") {
+		t.Error("auto-scan=true should declare DetectFormat")
+	}
+	if !strings.Contains(funcSource(t, content, "func (p *ProtoValue[T]) scan("), "codec = DetectFormat(data, codec)") {
+		t.Error("scan should pick its codec with DetectFormat")
+	}
+
+	if strings.Contains(mustGenerate(t, "paths=source_relative")["test/v1/format_dbtypes.pb.go"], "DetectFormat") {
+		t.Error("DetectFormat generated without auto-scan=true")
+	}
+
+	_, err := generate(t, "paths=source_relative,auto-scan=true,encrypt-hooks=true")
+	if err == nil || !strings.Contains(err.Error(), "auto-scan=true cannot be combined with encrypt-hooks") {
+		t.Errorf("auto-scan=true,encrypt-hooks=true: error = %v, want a conflict", err)
+	}
+}
+
+func TestGeneratedCode_AutoScan(t *testing.T) {
+	runGeneratedTests(t, "paths=source_relative,auto-scan=true", "auto_scan_test.go")
+	runGeneratedTests(t, "paths=source_relative,auto-scan=true,format=json", "auto_scan_test.go")
+	runGeneratedTests(t, "paths=source_relative,auto-scan=true,compress=gzip", "auto_scan_test.go")
+}
+
+func TestGenerate_EmitColumns(t *testing.T) {
+	for param, want := range map[string][]string{
+		"paths=source_relative,emit-columns=true": {
+			`Message:    "test.v1.ToolSetSpec",`,
+			`GoType:     "*testv1.ToolSetSpec",`,
+			`Format:     "binary",`,
+			`Compressed: false,`,
+		},
+		"paths=source_relative,emit-columns=true,format=json": {
+			`Format:     "json",`,
+			`Compressed: false,`,
+		},
+		"paths=source_relative,emit-columns=true,compress=zstd": {
+			`Format:     "binary",`,
+			`Compressed: true,`,
+		},
+		"paths=source_relative,emit-columns=true,generic=true,format=text": {
+			`Format:     "text",`,
+		},
+	} {
+		files := mustGenerate(t, param)
+		column := funcSource(t, files["test/v1/test_dbtypes.pb.go"], "var ToolSetSpecColumn = ColumnInfo{")
+		for _, w := range want {
+			if !strings.Contains(column, w) {
+				t.Errorf("%s: ToolSetSpecColumn should contain %q:\n%s", param, w, column)
+			}
+		}
+		// The type is declared once, in the first file of the package
+		if !strings.Contains(files["test/v1/format_dbtypes.pb.go"], "type ColumnInfo struct {") || strings.Contains(files["test/v1/test_dbtypes.pb.go"], "type ColumnInfo struct {") {
+			t.Errorf("%s: ColumnInfo should be declared in format_dbtypes.pb.go only", param)
+		}
+	}
+
+	// Messages with a (dbtypes.format) option report their own format
+	content := mustGenerate(t, "paths=source_relative,emit-columns=true,compress=gzip")["test/v1/format_dbtypes.pb.go"]
+	if column := funcSource(t, content, "var JSONDocumentColumn = ColumnInfo{"); !strings.Contains(column, `Format:     "json",`) || !strings.Contains(column, "Compressed: false,") {
+		t.Errorf("JSONDocumentColumn should report uncompressed json:\n%s", column)
+	}
+
+	if strings.Contains(mustGenerate(t, "paths=source_relative")["test/v1/format_dbtypes.pb.go"], "ColumnInfo") {
+		t.Error("ColumnInfo generated without emit-columns=true")
+	}
+}
+
+func TestGeneratedCode_EmitColumns(t *testing.T) {
+	runGeneratedTests(t, "paths=source_relative,emit-columns=true,compress=gzip", "columns_test.go")
+}
+
+func TestGenerate_EmitDiff(t *testing.T) {
+	for _, param := range []string{"paths=source_relative,emit-diff=true", "paths=source_relative,emit-diff=true,generic=true"} {
+		files := mustGenerate(t, param)
+		diff := funcSource(t, files["test/v1/test_dbtypes.pb.go"], "func DiffToolSetSpec(old, new *ToolSetSpec) []FieldChange {")
+		if !strings.Contains(diff, "dbtypesDiff(old.ProtoReflect(), new.ProtoReflect())") {
+			t.Errorf("%s: DiffToolSetSpec should compare through protoreflect:\n%s", param, diff)
+		}
+		// The type is declared once, in the first file of the package
+		if !strings.Contains(files["test/v1/format_dbtypes.pb.go"], "type FieldChange struct {") || strings.Contains(files["test/v1/test_dbtypes.pb.go"], "type FieldChange struct {") {
+			t.Errorf("%s: FieldChange should be declared in format_dbtypes.pb.go only", param)
+		}
+	}
+
+	if strings.Contains(mustGenerate(t, "paths=source_relative")["test/v1/test_dbtypes.pb.go"], "DiffToolSetSpec") {
+		t.Error("DiffXxx generated without emit-diff=true")
+	}
+}
+
+func TestGeneratedCode_EmitDiff(t *testing.T) {
+	runGeneratedTests(t, "paths=source_relative,emit-diff=true", "diff_test.go")
+	runGeneratedTests(t, "paths=source_relative,emit-diff=true,generic=true", "diff_test.go")
+}
+
+func TestGenerate_GlobalRegistry(t *testing.T) {
+	for _, param := range []string{"paths=source_relative,global-registry=true", "paths=source_relative,global-registry=true,generic=true"} {
+		content := mustGenerate(t, param)["test/v1/format_dbtypes.pb.go"]
+		init := funcSource(t, content, "func init() {")
+		for _, want := range []string{
+			`dbtypes.RegisterWrapper("test.v1.ToolSetSpec", func() dbtypes.Wrapper { return NewToolSetSpecValue(&ToolSetSpec{}) })`,
+			`dbtypes.RegisterWrapper("test.v1.JSONDocument", func() dbtypes.Wrapper { return NewJSONDocumentValue(&JSONDocument{}) })`,
+		} {
+			if !strings.Contains(init, want) {
+				t.Errorf("%s: init should contain %q:\n%s", param, want, init)
+			}
+		}
+	}
+
+	// Without the option the package does not depend on the runtime
+	if content := mustGenerate(t, "paths=source_relative")["test/v1/format_dbtypes.pb.go"]; strings.Contains(content, "RegisterWrapper") {
+		t.Error("wrappers registered without global-registry=true")
+	}
+}
+
+func TestGeneratedCode_GlobalRegistry(t *testing.T) {
+	runGeneratedTests(t, "paths=source_relative,global-registry=true", "global_registry_test.go")
+	runGeneratedTests(t, "paths=source_relative,global-registry=true,generic=true", "global_registry_test.go")
+}
+
+func TestGenerate_VTProto(t *testing.T) {
+	content := mustGenerate(t, "paths=source_relative,vtproto=true")["test/v1/format_dbtypes.pb.go"]
+	for _, want := range []string{
+		"dbtypesBinaryCodec Codec = &dbtypesCodec{marshal: dbtypesMarshalVT, unmarshal: dbtypesUnmarshalVT}",
+		"if vt, ok := m.(interface{ MarshalVT() ([]byte, error) }); ok {",
+		"return proto.Marshal(m)",
+		"return proto.Unmarshal(b, m)",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("vtproto output should contain %q", want)
+		}
+	}
+
+	// The fallback keeps allow-partial
+	content = mustGenerate(t, "paths=source_relative,vtproto=true,allow-partial=true")["test/v1/format_dbtypes.pb.go"]
+	if !strings.Contains(funcSource(t, content, "func dbtypesMarshalVT("), "return dbtypesMarshalBinary(m)") {
+		t.Error("dbtypesMarshalVT should fall back to dbtypesMarshalBinary with allow-partial")
+	}
+
+	if strings.Contains(mustGenerate(t, "paths=source_relative")["test/v1/format_dbtypes.pb.go"], "MarshalVT") {
+		t.Error("MarshalVT used without vtproto=true")
+	}
+
+	for _, opt := range []string{"deterministic", "discard-unknown"} {
+		_, err := generate(t, "paths=source_relative,vtproto=true,"+opt+"=true")
+		if err == nil || !strings.Contains(err.Error(), "vtproto=true cannot be combined with "+opt) {
+			t.Errorf("vtproto=true,%s=true: error = %v, want a conflict", opt, err)
+		}
+	}
+}
+
+func TestGeneratedCode_VTProto(t *testing.T) {
+	runGeneratedTests(t, "paths=source_relative,vtproto=true", "vtproto_test.go")
+	runGeneratedTests(t, "paths=source_relative,vtproto=true,compress=gzip,marshal-hooks=true", "vtproto_test.go")
+}
+
+func TestGenerate_ScanNullAsEmpty(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative,scan-null-as-empty=true")
+	content := files["test/v1/test_dbtypes.pb.go"]
+	if !strings.Contains(funcSource(t, content, "func (n *NullToolSetSpecValue) Scan("), "n.ToolSetSpecValue, n.Valid = *NewToolSetSpecValue(&ToolSetSpec{}), false") {
+		t.Error("NullToolSetSpecValue.Scan should leave an empty message on NULL")
+	}
+	if !strings.Contains(funcSource(t, files["test/v1/format_dbtypes.pb.go"], "func (p *ProtoValue[T]) scan("), "p.Message = p.Message.ProtoReflect().Type().New().Interface().(T)") {
+		t.Error("ProtoValue.scan should leave a new empty message on NULL")
+	}
+
+	// By default NULL leaves no message, as NewNullXxxValue(nil) does
+	content = mustGenerate(t, "paths=source_relative")["test/v1/test_dbtypes.pb.go"]
+	if !strings.Contains(funcSource(t, content, "func (n *NullToolSetSpecValue) Scan("), "n.ToolSetSpecValue, n.Valid = ToolSetSpecValue{}, false") {
+		t.Error("NullToolSetSpecValue.Scan should leave a zero wrapper on NULL by default")
+	}
+}
+
+func TestGeneratedCode_ScanNullAsEmpty(t *testing.T) {
+	// The fixture tests check the default, a nil message after NULL
+	runScratchModule(t, scratchModule{
+		param:            "paths=source_relative,scan-null-as-empty=true",
+		tests:            []string{"scan_null_as_empty_test.go"},
+		skipFixtureTests: true,
+	})
+}
+
+func TestGenerate_EmitRepo(t *testing.T) {
+	for dialect, want := range map[string]string{
+		"postgres":  `"INSERT INTO " + s.Table + " (" + s.Column + ") VALUES ($1)"`,
+		"mysql":     `"INSERT INTO " + s.Table + " (" + s.Column + ") VALUES (?)"`,
+		"sqlite":    `"INSERT INTO " + s.Table + " (" + s.Column + ") VALUES (?)"`,
+		"sqlserver": `"INSERT INTO " + s.Table + " (" + s.Column + ") VALUES (@p1)"`,
+	} {
+		files := mustGenerate(t, "paths=source_relative,emit-repo=true,dialect="+dialect)
+		content := files["test/v1/test_dbtypes.pb.go"]
+		if got := funcSource(t, content, "func (s ToolSetSpecStore) Insert("); !strings.Contains(got, want) {
+			t.Errorf("dialect=%s: Insert should build %s:\n%s", dialect, want, got)
+		}
+		if !strings.Contains(files["test/v1/format_dbtypes.pb.go"], "type DBTX interface {") || strings.Contains(content, "type DBTX interface") {
+			t.Errorf("dialect=%s: DBTX should be declared once per package", dialect)
+		}
+	}
+
+	if strings.Contains(mustGenerate(t, "paths=source_relative")["test/v1/test_dbtypes.pb.go"], "ToolSetSpecStore") {
+		t.Error("ToolSetSpecStore generated without emit-repo=true")
+	}
+}
+
+func TestGeneratedCode_EmitRepo(t *testing.T) {
+	runGeneratedTests(t, "paths=source_relative,emit-repo=true", "repo_test.go")
+	runGeneratedTests(t, "paths=source_relative,emit-repo=true,generic=true", "repo_test.go")
+}
+
+func TestGenerate_FunctionalOptions(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative,functional-options=true")
+	content := files["test/v1/test_dbtypes.pb.go"]
+	for _, want := range []string{
+		"func NewToolSetSpecValue(msg *ToolSetSpec, opts ...Option) *ToolSetSpecValue {",
+		"return x.ProtoValue.value(ctx, x.ProtoValue.codecOr(ToolSetSpecCodec()))",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("functional-options output should contain %q", want)
+		}
+	}
+	format := files["test/v1/format_dbtypes.pb.go"]
+	for _, want := range []string{"type Option func(*dbtypesOptions)", "func WithClone() Option {", "func WithFormat(f Format) Option {", "func WithCodec(c Codec) Option {"} {
+		if !strings.Contains(format, want) {
+			t.Errorf("functional-options output should contain %q", want)
+		}
+	}
+	if strings.Contains(content, "type Option ") {
+		t.Error("Option should be declared once per package")
+	}
+
+	// Without the option the constructor and the output are unchanged
+	content = mustGenerate(t, "paths=source_relative")["test/v1/test_dbtypes.pb.go"]
+	if !strings.Contains(content, "func NewToolSetSpecValue(msg *ToolSetSpec) *ToolSetSpecValue {") || strings.Contains(content, "codecOr") {
+		t.Error("functional options generated without functional-options=true")
+	}
+
+	for _, opt := range []string{"value-as-string", "base64-text"} {
+		_, err := generate(t, "paths=source_relative,functional-options=true,"+opt+"=true")
+		if err == nil || !strings.Contains(err.Error(), "functional-options=true cannot be combined with "+opt) {
+			t.Errorf("functional-options=true,%s=true: error = %v, want a conflict", opt, err)
+		}
+	}
+}
+
+func TestGeneratedCode_FunctionalOptions(t *testing.T) {
+	runGeneratedTests(t, "paths=source_relative,functional-options=true", "functional_options_test.go")
+	runGeneratedTests(t, "paths=source_relative,functional-options=true,compress=gzip", "functional_options_test.go")
+}
+
+func TestGenerate_Skip(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative")
+	content := files["test/v1/skip_dbtypes.pb.go"]
+	if !strings.Contains(content, "var dbtypesProfileCacheCodec Codec = dbtypesRedactCodec{Codec: dbtypesBinaryCodec, redact: dbtypesRedactProfileCache, skip: dbtypesSkipProfileCache}") {
+		t.Error("ProfileCacheCodec should clear the skipped field when encoding and decoding")
+	}
+	for _, fn := range []string{"func dbtypesRedactProfileCache(m proto.Message) {", "func dbtypesSkipProfileCache(m proto.Message) {"} {
+		if got := funcSource(t, content, fn); !strings.Contains(got, "msg.Clear(fields.ByNumber(3)) // biography") || strings.Contains(got, "ByNumber(2)") {
+			t.Errorf("%s should clear biography only:\n%s", fn, got)
+		}
+	}
+
+	// Redacted fields are only cleared when encoding
+	if strings.Contains(files["test/v1/redact_dbtypes.pb.go"], "dbtypesSkip") {
+		t.Error("skip generated for a message without skipped fields")
+	}
+}
+
+func TestGeneratedCode_Skip(t *testing.T) {
+	runGeneratedTests(t, "paths=source_relative,format=json", "skip_test.go")
+}
+
+func TestGeneratedCode_GenericSkip(t *testing.T) {
+	runGeneratedTests(t, "paths=source_relative,generic=true,format=json", "skip_test.go")
+	runGeneratedTests(t, "paths=source_relative,generic=true", "generic_skip_test.go")
+}
+
+func TestGenerate_ScanFields(t *testing.T) {
+	// Only the plain wire format can skip fields before decoding them
+	for param, filtered := range map[string]bool{
+		"paths=source_relative":               true,
+		"paths=source_relative,format=json":   false,
+		"paths=source_relative,compress=gzip": false,
+	} {
+		content := mustGenerate(t, param)["test/v1/test_dbtypes.pb.go"]
+		got := funcSource(t, content, "func (x *ContainerValue) ScanFields(src any, fieldPaths ...string) error {")
+		if strings.Contains(got, "dbtypesFieldsCodec{") != filtered {
+			t.Errorf("%q: ScanFields filters the stored bytes = %v, want %v:\n%s", param, !filtered, filtered, got)
+		}
+		if !strings.Contains(got, "dbtypesProject(x.ProtoValue.Message, fields)") {
+			t.Errorf("%q: ScanFields should clear the fields not requested:\n%s", param, got)
+		}
+	}
+}
+
+func TestGenerate_ValueMasked(t *testing.T) {
+	for _, param := range []string{"paths=source_relative", "paths=source_relative,value-receiver=true"} {
+		files := mustGenerate(t, param)
+		if !strings.Contains(files["test/v1/test_dbtypes.pb.go"], ") ValueMasked(mask *fieldmaskpb.FieldMask) (driver.Value, error) {") {
+			t.Errorf("%q: ToolSetSpecValue should have ValueMasked", param)
+		}
+		if !strings.Contains(files["test/v1/format_dbtypes.pb.go"], "func dbtypesApplyMask(") || strings.Contains(files["test/v1/test_dbtypes.pb.go"], "func dbtypesApplyMask(") {
+			t.Errorf("%q: dbtypesApplyMask should be declared once per package", param)
+		}
+	}
+}
+
+func TestGeneratedCode_JSONErrors(t *testing.T) {
+	runGeneratedTests(t, "paths=source_relative,format=json", "json_errors_test.go")
+}
+
+func TestGenerate_Editions(t *testing.T) {
+	// protoc refuses to run a plugin over an editions file unless the
+	// response declares the editions it supports
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{testv1.File_test_v1_presence_proto.Path()},
+		Parameter:      proto.String("paths=source_relative"),
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{protodesc.ToFileDescriptorProto(testv1.File_test_v1_presence_proto)},
+	}
+	var flags flag.FlagSet
+	gen, err := protogen.Options{ParamFunc: flags.Set}.New(req)
+	if err != nil {
+		t.Fatalf("protogen.Options.New error: %v", err)
+	}
+	if err := run(gen, bindFlags(&flags)); err != nil {
+		t.Fatalf("run error: %v", err)
+	}
+	resp := gen.Response()
+	if resp.Error != nil {
+		t.Fatalf("generation error: %s", resp.GetError())
+	}
+	if resp.GetSupportedFeatures()&uint64(pluginpb.CodeGeneratorResponse_FEATURE_SUPPORTS_EDITIONS) == 0 {
+		t.Error("response should declare FEATURE_SUPPORTS_EDITIONS")
+	}
+	if got := descriptorpb.Edition(resp.GetMaximumEdition()); got < descriptorpb.Edition_EDITION_2023 {
+		t.Errorf("maximum edition = %v, want at least EDITION_2023", got)
+	}
+
+	// Presence follows the features of each field, not the syntax
+	files := mustGenerate(t, "paths=source_relative,builders=true")
+	content := files["test/v1/presence_dbtypes.pb.go"]
+	funcSource(t, content, "func NewEditionRecordValue(msg *EditionRecord) *EditionRecordValue")
+	if got := funcSource(t, content, "func (b *EditionRecordValueBuilder) SetId(v string) *EditionRecordValueBuilder"); !strings.Contains(got, "b.msg.Id = &v") {
+		t.Errorf("SetId should store the address of an explicit-presence value:\n%s", got)
+	}
+	if got := funcSource(t, content, "func (b *EditionRecordValueBuilder) SetNote(v string) *EditionRecordValueBuilder"); !strings.Contains(got, "b.msg.Note = v") {
+		t.Errorf("SetNote should store an implicit-presence value:\n%s", got)
+	}
+}
+
+func TestGenerate_Redact(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative")
+	content := files["test/v1/redact_dbtypes.pb.go"]
+	for _, want := range []string{
+		"var dbtypesServiceAccountCodec Codec = dbtypesRedactCodec{Codec: dbtypesBinaryCodec, redact: dbtypesRedactServiceAccount}",
+		"msg.Clear(fields.ByNumber(2)) // api_token",
+		"msg.Clear(fields.ByNumber(3)) // recovery_codes",
+		"msg = proto.Clone(msg)",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("redact output should contain %q", want)
+		}
+	}
+	if strings.Contains(content, "fields.ByNumber(1)") {
+		t.Error("name is not marked (dbtypes.redact) and should be stored")
+	}
+
+	// Messages without redacted fields encode as before
+	if strings.Contains(files["test/v1/test_dbtypes.pb.go"], "dbtypesRedact") {
+		t.Error("redaction generated for messages without redacted fields")
+	}
+
+	content = mustGenerate(t, "paths=source_relative,functional-options=true")["test/v1/redact_dbtypes.pb.go"]
+	if !strings.Contains(content, "dbtypesRedactCodec{Codec: o.codec, redact: dbtypesRedactServiceAccount}") {
+		t.Error("WithFormat should keep the redaction of ServiceAccountValue")
+	}
+}
+
+func TestGenerate_Enums(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative,enums=true")
+
+	content, ok := files["test/v1/enum_dbtypes.pb.go"]
+	if !ok {
+		t.Fatal("enums=true should generate enum_dbtypes.pb.go")
+	}
+	for _, decl := range []string{
+		"func NewPriorityValue(e Priority) *PriorityValue",
+		"func (x *PriorityValue) Scan(src any) error",
+		"func (x PriorityValue) Value() (driver.Value, error)",
+		"func (x Priority) DatabaseValue() *PriorityValue",
+	} {
+		funcSource(t, content, decl)
+	}
+
+	// The enum-only file comes first, so it carries the package-level
+	// declarations, but the per-package integration code still follows the
+	// first wrapped message.
+	var protoValues int
+	for _, content := range files {
+		protoValues += strings.Count(content, "type ProtoValue[")
+	}
+	if protoValues != 1 {
+		t.Errorf("ProtoValue declared %d times, want once", protoValues)
+	}
+	var registers int
+	for name, content := range mustGenerate(t, "paths=source_relative,enums=true,driver=pgx") {
+		if strings.HasSuffix(name, "_dbtypes_pgx.pb.go") {
+			registers += strings.Count(content, "func Register(")
+		}
+	}
+	if registers != 1 {
+		t.Errorf("Register declared %d times with enums=true, want once", registers)
+	}
+
+	if _, ok := mustGenerate(t, "paths=source_relative")["test/v1/enum_dbtypes.pb.go"]; ok {
+		t.Error("enum wrappers generated without enums=true")
+	}
+	if _, ok := mustGenerate(t, "paths=source_relative,enums=true,exclude=Priority")["test/v1/enum_dbtypes.pb.go"]; ok {
+		t.Error("exclude=Priority should skip the enum")
+	}
+	sub := mustGenerate(t, "paths=source_relative,enums=true,out-package=dbv")["test/v1/dbv/enum_dbtypes.pb.go"]
+	if strings.Contains(sub, "DatabaseValue") {
+		t.Error("out-package wrappers cannot declare DatabaseValue on the enum")
+	}
+	if !strings.Contains(sub, "Enum v1.Priority") {
+		t.Error("out-package wrapper should refer to the enum by its package")
+	}
+}
+
+func TestGeneratedCode_Enums(t *testing.T) {
+	for _, param := range []string{"enums=true", "enums=true,generic=true"} {
+		t.Run(param, func(t *testing.T) {
+			runScratchModule(t, scratchModule{
+				param: "paths=source_relative," + param,
+				tests: []string{"enums_test.go"},
+			})
+		})
+	}
+}
+
+func TestGenerate_ORMEnt(t *testing.T) {
+	files := mustGenerate(t, "paths=source_relative,orm=ent")
+
+	content, ok := files["test/v1/test_dbtypes_ent.pb.go"]
+	if !ok {
+		t.Fatal("orm=ent should generate test_dbtypes_ent.pb.go")
+	}
+	if !strings.Contains(content, "//go:build dbtypes_ent\n") {
+		t.Error("ent file should be guarded by the dbtypes_ent build tag")
+	}
+
+	// The method set must match field.TypeValueScanner[*ToolSetSpec].
+	for _, decl := range []string{
+		"func (ToolSetSpecValueScanner) Value(msg *ToolSetSpec) (driver.Value, error)",
+		"func (ToolSetSpecValueScanner) ScanValue() field.ValueScanner",
+		"func (ToolSetSpecValueScanner) FromValue(v driver.Value) (*ToolSetSpec, error)",
+	} {
+		funcSource(t, content, decl)
+	}
+
+	if strings.Contains(files["test/v1/test_dbtypes.pb.go"], "entgo.io") {
+		t.Error("test_dbtypes.pb.go should not reference ent")
+	}
+	if _, ok := files["test/v1/test_dbtypes_gorm.pb.go"]; ok {
+		t.Error("gorm file generated without orm=gorm")
+	}
+
+	both := mustGenerate(t, "paths=source_relative,orm=gorm,orm=ent")
+	for _, name := range []string{"test/v1/test_dbtypes_gorm.pb.go", "test/v1/test_dbtypes_ent.pb.go"} {
+		if _, ok := both[name]; !ok {
+			t.Errorf("orm=gorm,orm=ent should generate %s", name)
+		}
+	}
+}
+
+func TestGeneratedCode_ORMEnt(t *testing.T) {
+	runScratchModule(t, scratchModule{
+		param:    "paths=source_relative,orm=ent",
+		tests:    []string{"ent_test.go"},
+		requires: []string{"entgo.io/ent@v0.14.6"},
+		tags:     entBuildTag,
+	})
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+const dbtypesPackage = protogen.GoImportPath("github.com/cadenya/protoc-gen-go-dbtypes/dbtypes")
+
+// validateGeneric reports options that need per-package generated code and
+// therefore cannot be combined with the generic runtime types.
+func validateGeneric(config *GeneratorConfig) error {
+	if !config.Generic {
+		return nil
+	}
+	var unsupported string
+	switch {
+	case config.Compression != CompressionNone:
+		unsupported = "compress"
+	case config.EncryptHooks:
+		unsupported = "encrypt-hooks"
+	case config.MarshalHooks:
+		unsupported = "marshal-hooks"
+	case config.MetricsHooks:
+		unsupported = "metrics-hooks"
+	case config.OTel:
+		unsupported = "otel"
+	case config.EmptyAsNull:
+		unsupported = "empty-as-null"
+	case config.NilMessage == NilMessageNull:
+		unsupported = "nil-message=null"
+	case config.Deterministic:
+		unsupported = "deterministic"
+	case config.SortRepeated:
+		unsupported = "sort-repeated"
+	case config.EmitChecksum:
+		unsupported = "emit-checksum"
+	case config.MaxScanSize > 0:
+		unsupported = "max-scan-size"
+	case config.DiscardUnknown:
+		unsupported = "discard-unknown"
+	case config.AllowPartial:
+		unsupported = "allow-partial"
+	case config.ValueAsString:
+		unsupported = "value-as-string"
+	case config.JSONEmitUnpopulated:
+		unsupported = "json-emit-unpopulated"
+	case config.JSONUseProtoNames:
+		unsupported = "json-use-proto-names"
+	case config.Validate:
+		unsupported = "validate"
+	case config.Format == FormatCBOR, config.Format == FormatMsgpack:
+		unsupported = "format=" + string(config.Format)
+	case config.EmitBSON:
+		unsupported = "emit-bson"
+	case config.EmitMigrators:
+		unsupported = "emit-migrators"
+	case config.ValueReceiver:
+		unsupported = "value-receiver"
+	case config.AutoScan:
+		unsupported = "auto-scan"
+	case config.VTProto:
+		unsupported = "vtproto"
+	case config.FunctionalOptions:
+		unsupported = "functional-options"
+	case config.ScanNullAsEmpty:
+		unsupported = "scan-null-as-empty"
+	case config.ORMs[ORMGorm]:
+		unsupported = "orm=gorm"
+	default:
+		return nil
+	}
+	return fmt.Errorf("generic=true cannot be combined with %s", unsupported)
+}
+
+// generateGenericPackage emits the package-level declarations that other
+// modes generate alongside ProtoValue.
+func generateGenericPackage(g *protogen.GeneratedFile) {
+	g.P("// ErrInvalidScanType is wrapped by the error Scan returns for a source of an")
+	g.P("// unsupported type.")
+	g.P("var ErrInvalidScanType = ", dbtypesPackage.Ident("ErrInvalidScanType"))
+	g.P()
+	g.P("// ErrMessageTooLarge is wrapped by the error Value returns for a message")
+	g.P("// whose encoding is longer than the 2 GiB the protobuf wire format allows.")
+	g.P("var ErrMessageTooLarge = ", dbtypesPackage.Ident("ErrMessageTooLarge"))
+	g.P()
+	g.P("type (")
+	g.P("	// ScanTypeError is returned by Scan for a source of an unsupported type.")
+	g.P("	ScanTypeError = ", dbtypesPackage.Ident("ScanTypeError"))
+	g.P("	// DecodeError is returned by Scan when the stored bytes do not decode.")
+	g.P("	DecodeError = ", dbtypesPackage.Ident("DecodeError"))
+	g.P("	// Codec converts messages to and from the bytes Value stores and Scan")
+	g.P("	// reads. The XxxCodec functions return the codec of each message type.")
+	g.P("	Codec = ", dbtypesPackage.Ident("Codec"))
+	g.P(")")
+	g.P()
+	generateScanSource(g)
+}
+
+// generateGenericWrapper emits XxxValue as an alias of the runtime type for
+// format, plus the constructor and helpers that must be declared locally.
+func generateGenericWrapper(g *protogen.GeneratedFile, m *protogen.Message, config *GeneratorConfig, format Format) {
+	typeName := m.GoIdent.GoName
+	wrapperName := config.wrapperName(m)
+
+	valueType, constructor, batch, values := "DBValue", "New", "EncodeBatch", "Values"
+	switch format {
+	case FormatJSON:
+		valueType, constructor, batch, values = "JSONValue", "NewJSON", "EncodeJSONBatch", "JSONValues"
+	case FormatText:
+		valueType, constructor, batch, values = "TextValue", "NewText", "EncodeTextBatch", "TextValues"
+	}
+
+	// Type alias
+	generateWrapperDoc(g, wrapperName, m.Desc, m.Comments.Leading, config.storageDescription(format))
+	g.P("type ", wrapperName, " = ", dbtypesPackage.Ident(valueType), "[*", m.GoIdent, "]")
+	g.P()
+
+	// Constructor
+	g.P("// New", wrapperName, " creates a new ", wrapperName, " wrapper. A nil msg")
+	g.P("// gives an empty wrapper, which is stored as NULL like a nil one.")
+	g.P("func New", wrapperName, "(msg *", m.GoIdent, ") *", wrapperName, " {")
+	g.P("	if msg == nil {")
+	g.P("		return &", wrapperName, "{}")
+	g.P("	}")
+	g.P("	return ", dbtypesPackage.Ident(constructor), "(msg)")
+	g.P("}")
+	g.P()
+	generateCodecAccessor(g, m, config, format)
+	generateSQLAssertions(g, wrapperName)
+
+	// Batch encoder for bulk inserts
+	g.P("// Encode", typeName, "Batch encodes msgs as ", wrapperName, ".Value would, for")
+	g.P("// bulk inserts such as COPY. Nil messages give nil entries.")
+	g.P("func Encode", typeName, "Batch(msgs []*", m.GoIdent, ") ([][]byte, error) {")
+	g.P("	return ", dbtypesPackage.Ident(batch), "(msgs)")
+	g.P("}")
+	g.P()
+
+	// Driver values for passing many messages as query arguments
+	generateValuesDoc(g, m, config)
+	g.P("func ", config.valuesName(m), "(msgs []*", m.GoIdent, ") ([]", driverPackage.Ident("Value"), ", error) {")
+	g.P("	return ", dbtypesPackage.Ident(values), "(msgs)")
+	g.P("}")
+	g.P()
+
+	// DatabaseValue method on the proto message, which can only be declared
+	// in the message's own package
+	if config.OutPackage == "" {
+		g.P("// DatabaseValue returns a database-compatible wrapper for this message.")
+		g.P("func (x *", typeName, ") DatabaseValue() *", wrapperName, " {")
+		g.P("	return New", wrapperName, "(x)")
+		g.P("}")
+		g.P()
+	}
+
+	generateNullWrapper(g, m, config)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+const (
+	gormPackage       = protogen.GoImportPath("gorm.io/gorm")
+	gormSchemaPackage = protogen.GoImportPath("gorm.io/gorm/schema")
+	gormClausePackage = protogen.GoImportPath("gorm.io/gorm/clause")
+)
+
+// gormBuildTag guards the GORM integration so that only builds which opt in
+// depend on gorm.io/gorm.
+const gormBuildTag = "dbtypes_gorm"
+
+// generateGormFile emits the GORM methods for messages into a separate,
+// build-tagged file.
+func generateGormFile(gen *protogen.Plugin, file *protogen.File, messages []*protogen.Message, config *GeneratorConfig) {
+	filename := config.filename(file, "gorm")
+	g := gen.NewGeneratedFile(filename, config.importPath(file))
+
+	g.P("// Code generated by protoc-gen-go-dbtypes. DO NOT EDIT.")
+	g.P("// source: ", file.Desc.Path())
+	g.P()
+	g.P("//go:build ", gormBuildTag)
+	g.P()
+	g.P("package ", config.packageName(file))
+	g.P()
+
+	for _, m := range messages {
+		generateGormMethods(g, m, config, messageFormat(m, config))
+	}
+}
+
+func generateGormMethods(g *protogen.GeneratedFile, m *protogen.Message, config *GeneratorConfig, format Format) {
+	wrapperName := config.wrapperName(m)
+
+	// Column type per dialect
+	g.P("// GormDBDataType returns the column type GORM uses when migrating ", wrapperName, ".")
+	g.P("func (*", wrapperName, ") GormDBDataType(db *", gormPackage.Ident("DB"), ", field *", gormSchemaPackage.Ident("Field"), ") string {")
+	g.P("	switch db.Dialector.Name() {")
+	// SQLite takes the generic types, which other dialects fall back to
+	for _, dialect := range []Dialect{DialectPostgres, DialectMySQL, DialectSQLServer} {
+		if format.columnType(dialect) != format.columnType(DialectSQLite) {
+			g.P(`	case "`, dialect, `":`)
+			g.P(`		return "`, format.columnType(dialect), `"`)
+		}
+	}
+	g.P("	}")
+	g.P(`	return "`, format.columnType(DialectSQLite), `"`)
+	g.P("}")
+	g.P()
+
+	if !format.isText() {
+		return
+	}
+
+	// Text columns reject bytea parameters, so bind the encoding as a string.
+	g.P("// GormValue implements gorm.Valuer. It binds the encoded message as text")
+	g.P("// so that text and JSON columns accept it.")
+	g.P("func (x *", wrapperName, ") GormValue(ctx ", contextPackage.Ident("Context"), ", db *", gormPackage.Ident("DB"), ") ", gormClausePackage.Ident("Expr"), " {")
+	g.P("	v, err := x.Value()")
+	g.P("	if err != nil {")
+	g.P("		_ = db.AddError(err)")
+	g.P("	}")
+	// With value-as-string Value already returns the text
+	text := "string(data)"
+	if config.valueString(format) {
+		text = "data"
+		g.P("	data, ok := v.(string)")
+	} else {
+		g.P("	data, ok := v.([]byte)")
+	}
+	g.P("	if err != nil || !ok {")
+	g.P(`		return `, gormClausePackage.Ident("Expr"), `{SQL: "NULL"}`)
+	g.P("	}")
+	if format == FormatJSON {
+		g.P(`	if db.Dialector.Name() == "mysql" {`)
+		g.P(`		return `, gormClausePackage.Ident("Expr"), `{SQL: "CAST(? AS JSON)", Vars: []any{`, text, `}}`)
+		g.P("	}")
+	}
+	g.P(`	return `, gormClausePackage.Ident("Expr"), `{SQL: "?", Vars: []any{`, text, `}}`)
+	g.P("}")
+	g.P()
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// jsonPathConstants returns the names and values of the constants
+// emit-json-paths declares for m: one per field, named after the message and
+// the field's Go name and holding the field's key in the protojson encoding.
+func jsonPathConstants(m *protogen.Message, config *GeneratorConfig) (names, keys []string) {
+	for _, field := range m.Fields {
+		key := field.Desc.JSONName()
+		if config.JSONUseProtoNames {
+			key = string(field.Desc.Name())
+		}
+		names = append(names, m.GoIdent.GoName+"Path"+field.GoName)
+		keys = append(keys, key)
+	}
+	return names, keys
+}
+
+// generateJSONPaths emits the JSON path constants of m, for index
+// definitions and queries on JSON columns that would otherwise spell out the
+// keys.
+func generateJSONPaths(g *protogen.GeneratedFile, m *protogen.Message, config *GeneratorConfig) {
+	names, keys := jsonPathConstants(m, config)
+	if len(names) == 0 {
+		return
+	}
+	g.P("// JSON keys of the fields of ", m.GoIdent.GoName, " in its protojson encoding.")
+	g.P("const (")
+	for i, name := range names {
+		g.P("	", name, ` = "`, keys[i], `"`)
+	}
+	g.P(")")
+	g.P()
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// jsonSchemaDraft is the JSON Schema dialect of the emitted schemas.
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// generateJSONSchemaFiles writes a <full name>.schema.json file for every
+// wrapped message stored as protojson, describing the documents Value writes
+// for data catalogs and other tools that read the column without the .proto
+// files.
+func generateJSONSchemaFiles(gen *protogen.Plugin, config *GeneratorConfig) error {
+	for _, pkg := range outputPackages(gen, config) {
+		for _, m := range pkg.messages {
+			if messageFormat(m, config) != FormatJSON {
+				continue
+			}
+			data, err := json.MarshalIndent(jsonSchema(m.Desc, config, leadingComment(m.Comments)), "", "  ")
+			if err != nil {
+				return err
+			}
+			g := gen.NewGeneratedFile(path.Join(pkg.dir, string(m.Desc.FullName())+".schema.json"), "")
+			if _, err := g.Write(append(data, '\n')); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// jsonSchema returns the schema of the protojson encoding of root. Other
+// messages it refers to are described under $defs, so that recursive
+// messages are supported.
+func jsonSchema(root protoreflect.MessageDescriptor, config *GeneratorConfig, description string) jsonObject {
+	s := &schemaBuilder{root: root, config: config, defined: make(map[protoreflect.FullName]bool)}
+	schema := jsonObject{
+		{"$schema", jsonSchemaDraft},
+		{"title", string(root.FullName())},
+	}
+	if description != "" {
+		schema = append(schema, jsonMember{"description", description})
+	}
+	schema = append(schema, s.object(root)...)
+	if len(s.defs) > 0 {
+		schema = append(schema, jsonMember{"$defs", s.defs})
+	}
+	return schema
+}
+
+// schemaBuilder collects the $defs of a schema while it is built.
+type schemaBuilder struct {
+	root    protoreflect.MessageDescriptor
+	config  *GeneratorConfig
+	defs    jsonObject
+	defined map[protoreflect.FullName]bool
+}
+
+// object returns the members of the schema of a message with fields.
+func (s *schemaBuilder) object(md protoreflect.MessageDescriptor) jsonObject {
+	properties := jsonObject{}
+	var required []string
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		name := fd.JSONName()
+		if s.config.JSONUseProtoNames {
+			name = string(fd.Name())
+		}
+		properties = append(properties, jsonMember{name, s.field(fd)})
+		if fd.Cardinality() == protoreflect.Required {
+			required = append(required, name)
+		}
+	}
+	schema := jsonObject{{"type", "object"}, {"properties", properties}}
+	if len(required) > 0 {
+		schema = append(schema, jsonMember{"required", required})
+	}
+	return schema
+}
+
+// field returns the schema of the value of fd.
+func (s *schemaBuilder) field(fd protoreflect.FieldDescriptor) any {
+	switch {
+	case fd.IsMap():
+		// protojson writes map keys as strings whatever their type
+		return jsonObject{{"type", "object"}, {"additionalProperties", s.singular(fd.MapValue())}}
+	case fd.IsList():
+		return jsonObject{{"type", "array"}, {"items", s.singular(fd)}}
+	}
+	schema := s.singular(fd)
+	if s.config.JSONEmitUnpopulated && fd.HasPresence() && fd.ContainingOneof() == nil {
+		// Unset fields with presence are written as null
+		return jsonObject{{"anyOf", []any{schema, jsonObject{{"type", "null"}}}}}
+	}
+	return schema
+}
+
+// singular returns the schema of a single value of fd.
+func (s *schemaBuilder) singular(fd protoreflect.FieldDescriptor) any {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return jsonObject{{"type", "boolean"}}
+	case protoreflect.StringKind:
+		return jsonObject{{"type", "string"}}
+	case protoreflect.BytesKind:
+		return jsonObject{{"type", "string"}, {"contentEncoding", "base64"}}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return jsonObject{{"type", "integer"}}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// protojson writes 64-bit integers as strings, which JavaScript
+		// numbers cannot hold exactly
+		return jsonObject{{"type", "string"}, {"pattern", "^-?[0-9]+$"}}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		// NaN and the infinities are written as strings
+		return jsonObject{{"type", []string{"number", "string"}}}
+	case protoreflect.EnumKind:
+		return enumSchema(fd.Enum())
+	}
+	return s.message(fd.Message())
+}
+
+// enumSchema returns the schema of an enum, which protojson writes as the
+// name of its value.
+func enumSchema(ed protoreflect.EnumDescriptor) any {
+	if ed.FullName() == "google.protobuf.NullValue" {
+		return jsonObject{{"type", "null"}}
+	}
+	var names []string
+	values := ed.Values()
+	for i := 0; i < values.Len(); i++ {
+		names = append(names, string(values.Get(i).Name()))
+	}
+	return jsonObject{{"type", "string"}, {"enum", names}}
+}
+
+// message returns the schema of a message field: that of the JSON form of a
+// well-known type, or a reference to the definition of the message.
+func (s *schemaBuilder) message(md protoreflect.MessageDescriptor) any {
+	if schema := wellKnownSchema(md.FullName()); schema != nil {
+		return schema
+	}
+	if md.FullName() == s.root.FullName() {
+		return jsonObject{{"$ref", "#"}}
+	}
+	ref := jsonObject{{"$ref", "#/$defs/" + string(md.FullName())}}
+	if !s.defined[md.FullName()] {
+		// Mark the message first, so that recursive fields refer to it
+		s.defined[md.FullName()] = true
+		i := len(s.defs)
+		s.defs = append(s.defs, jsonMember{key: string(md.FullName())})
+		s.defs[i].value = s.object(md)
+	}
+	return ref
+}
+
+// wellKnownSchema returns the schema of the special JSON form protojson
+// gives a well-known type, or nil for other messages. google.protobuf.Any is
+// described loosely, since the fields depend on the packed message.
+func wellKnownSchema(name protoreflect.FullName) any {
+	switch name {
+	case "google.protobuf.Any":
+		return jsonObject{
+			{"type", "object"},
+			{"properties", jsonObject{{"@type", jsonObject{{"type", "string"}}}}},
+			{"required", []string{"@type"}},
+		}
+	case "google.protobuf.Timestamp":
+		return jsonObject{{"type", "string"}, {"format", "date-time"}}
+	case "google.protobuf.Duration":
+		return jsonObject{{"type", "string"}, {"pattern", `^-?[0-9]+(\.[0-9]+)?s$`}}
+	case "google.protobuf.FieldMask":
+		return jsonObject{{"type", "string"}}
+	case "google.protobuf.Struct", "google.protobuf.Empty":
+		return jsonObject{{"type", "object"}}
+	case "google.protobuf.ListValue":
+		return jsonObject{{"type", "array"}}
+	case "google.protobuf.Value":
+		return jsonObject{}
+	case "google.protobuf.BoolValue":
+		return jsonObject{{"type", "boolean"}}
+	case "google.protobuf.StringValue":
+		return jsonObject{{"type", "string"}}
+	case "google.protobuf.BytesValue":
+		return jsonObject{{"type", "string"}, {"contentEncoding", "base64"}}
+	case "google.protobuf.Int32Value", "google.protobuf.UInt32Value":
+		return jsonObject{{"type", "integer"}}
+	case "google.protobuf.Int64Value", "google.protobuf.UInt64Value":
+		return jsonObject{{"type", "string"}, {"pattern", "^-?[0-9]+$"}}
+	case "google.protobuf.FloatValue", "google.protobuf.DoubleValue":
+		return jsonObject{{"type", []string{"number", "string"}}}
+	}
+	return nil
+}
+
+// leadingComment returns the leading comments of a message as a description.
+func leadingComment(c protogen.CommentSet) string {
+	return strings.TrimSpace(string(c.Leading))
+}
+
+// jsonObject is a JSON object that keeps its members in order, so that the
+// properties of a schema are listed as the fields are declared.
+type jsonObject []jsonMember
+
+type jsonMember struct {
+	key   string
+	value any
+}
+
+// MarshalJSON implements json.Marshaler.
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(m.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
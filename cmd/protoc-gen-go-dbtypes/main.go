@@ -2,52 +2,429 @@ package main
 
 import (
 	"flag"
+	"fmt"
+	"regexp"
 	"strings"
 
 	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/descriptorpb"
 	"google.golang.org/protobuf/types/pluginpb"
 )
 
 func main() {
 	var flags flag.FlagSet
-
-	// Flag to exclude types by name (comma-separated list)
-	excludeTypes := flags.String("exclude", "", "comma-separated list of message names to exclude from generation")
-	// Flag to only generate for a specific package
-	onlyPackage := flags.String("package", "", "only generate for this proto package (e.g., 'example.v1')")
+	params := bindFlags(&flags)
 
 	opts := protogen.Options{
 		ParamFunc: flags.Set,
 	}
 
 	opts.Run(func(gen *protogen.Plugin) error {
-		// Declare support for proto3 optional fields
-		gen.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
-
-		// Parse excluded types into a set
-		excluded := make(map[string]bool)
-		if *excludeTypes != "" {
-			for _, name := range strings.Split(*excludeTypes, ",") {
-				excluded[strings.TrimSpace(name)] = true
+		return run(gen, params)
+	})
+}
+
+// pluginParams holds the raw values of the plugin options.
+type pluginParams struct {
+	excludeTypes        *stringList
+	includeRegex        *string
+	onlyPackage         *stringList
+	requireOptIn        *bool
+	nested              *string
+	format              *string
+	compress            *string
+	zstdDict            *string
+	encryptHooks        *bool
+	marshalHooks        *bool
+	metricsHooks        *bool
+	otel                *bool
+	emptyAsNull         *bool
+	nilMessage          *string
+	deterministic       *bool
+	sortRepeated        *bool
+	emitChecksum        *bool
+	checksumAlgorithm   *string
+	maxScanSize         *int
+	discardUnknown      *bool
+	allowPartial        *bool
+	preserveUnknown     *bool
+	valueAsString       *bool
+	base64Text          *bool
+	jsonEmitUnpopulated *bool
+	jsonUseProtoNames   *bool
+	generic             *bool
+	validate            *bool
+	postgresArray       *bool
+	driver              *string
+	emitDDL             *bool
+	dialect             *string
+	emitSqlc            *bool
+	emitJSONSchema      *bool
+	emitBSON            *bool
+	emitJSONPaths       *bool
+	emitMigrators       *bool
+	migrateFrom         *string
+	migrateTo           *string
+	builders            *bool
+	valueReceiver       *bool
+	autoScan            *bool
+	emitColumns         *bool
+	emitDiff            *bool
+	globalRegistry      *bool
+	vtproto             *bool
+	scanNullAsEmpty     *bool
+	emitRepo            *bool
+	functionalOptions   *bool
+	enums               *bool
+	typeSuffix          *string
+	fileSuffix          *string
+	outPackage          *string
+	orm                 *stringList
+}
+
+// stringList is a flag that may be given more than once.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+func bindFlags(flags *flag.FlagSet) *pluginParams {
+	params := &pluginParams{
+		// Flag to only generate types whose full name matches a regular expression
+		includeRegex: flags.String("include-regex", "", "only generate for messages whose full proto name matches this regular expression"),
+		// Flag to only generate for messages marked with (dbtypes.generate)
+		requireOptIn: flags.Bool("require-opt-in", false, "only generate for messages that set the (dbtypes.generate) option"),
+		// Flag to also wrap messages declared inside other messages
+		nested: flags.String("nested", string(NestedSkip), "which nested messages get wrappers: skip or include"),
+		// Flag to select the serialization format used by Value/Scan
+		format: flags.String("format", string(FormatBinary), "serialization format for database values: binary, json, text, cbor or msgpack"),
+		// Flag to compress serialized values
+		compress: flags.String("compress", "", "compress serialized values: gzip or zstd"),
+		// Flag to compress with a zstd dictionary
+		zstdDict: flags.String("zstd-dict", "", "path of a zstd dictionary embedded in the generated code and used by compress=zstd"),
+		// Flag to generate EncryptCipher/DecryptCipher hooks
+		encryptHooks: flags.Bool("encrypt-hooks", false, "generate EncryptCipher/DecryptCipher hooks applied in Value/Scan"),
+		// Flag to generate Marshal/Unmarshal hooks
+		marshalHooks: flags.Bool("marshal-hooks", false, "generate Marshal/Unmarshal function variables that Value/Scan encode the default format with"),
+		// Flag to generate per-message metrics hooks
+		metricsHooks: flags.Bool("metrics-hooks", false, "generate OnValue/OnValueError/OnScan/OnScanError hooks called by Value/Scan with the message type"),
+		// Flag to record OpenTelemetry span events in Value/Scan
+		otel: flags.Bool("otel", false, "record OpenTelemetry span events for Value/Scan in a dbtypes_otel build-tagged file, once a Tracer is set"),
+		// Flag to store empty messages as SQL NULL
+		emptyAsNull: flags.Bool("empty-as-null", false, "make Value return NULL for messages with no fields set"),
+		// Flag to choose what Value stores for a nil message
+		nilMessage: flags.String("nil-message", string(NilMessageEmpty), "what Value returns for a wrapper holding a nil message: empty bytes or null"),
+		// Flag to marshal maps in a stable order
+		deterministic: flags.Bool("deterministic", false, "marshal binary values deterministically so equal messages produce equal bytes"),
+		// Flag to sort repeated scalar fields before encoding
+		sortRepeated: flags.Bool("sort-repeated", false, "make Value sort the elements of repeated string and number fields on a copy of the message; combine with deterministic"),
+		// Flag to generate checksums for a companion integrity column
+		emitChecksum: flags.Bool("emit-checksum", false, "generate a Checksum method per wrapper and make Scan verify the stored bytes against an ExpectedChecksum hook; combine with deterministic for messages with map fields"),
+		// Flag to pick the checksum algorithm
+		checksumAlgorithm: flags.String("checksum-algorithm", string(ChecksumSHA256), "algorithm of the checksums emit-checksum generates: crc32 or sha256"),
+		// Flag to bound the size of scanned values
+		maxScanSize: flags.Int("max-scan-size", 0, "make Scan reject sources longer than this many bytes; 0 means no limit"),
+		// Flag to drop unknown fields when scanning
+		discardUnknown: flags.Bool("discard-unknown", false, "make Scan drop fields the generated messages don't declare instead of failing or keeping them"),
+		// Flag to accept messages with missing required fields
+		allowPartial: flags.Bool("allow-partial", false, "make Value, Scan and the other encodings accept proto2 messages with missing required fields"),
+		// Flag to guarantee that unknown fields survive Scan and Value
+		preserveUnknown: flags.Bool("preserve-unknown", false, "fail generation if any option or message format would drop unknown fields between Scan and Value"),
+		// Flag to return text-format values as strings
+		valueAsString: flags.Bool("value-as-string", false, "make Value return a string rather than []byte for json and text formats"),
+		// Flag to store binary messages as base64 text
+		base64Text: flags.Bool("base64-text", false, "make Value return base64 text for binary messages and Scan decode base64 strings, for text columns"),
+		// Flags to set protojson marshal options
+		jsonEmitUnpopulated: flags.Bool("json-emit-unpopulated", false, "write unpopulated fields with their zero values in JSON-format values and MarshalJSON"),
+		jsonUseProtoNames:   flags.Bool("json-use-proto-names", false, "name fields as in the .proto file rather than in lowerCamelCase in JSON-format values and MarshalJSON"),
+		// Flag to alias wrappers to the generic runtime types
+		generic: flags.Bool("generic", false, "alias wrappers to the generic types in the dbtypes runtime package"),
+		// Flag to generate protovalidate checks
+		validate: flags.Bool("validate", false, "generate protovalidate Validate methods, also applied by Value"),
+		// Flag to generate lib/pq array types
+		postgresArray: flags.Bool("postgres-array", false, "generate XxxValueArray types for PostgreSQL array columns using github.com/lib/pq"),
+		// Flag to register the messages with a driver's native type system
+		driver: flags.String("driver", "", "generate native codecs for a database driver: pgx"),
+		// Flag to write suggested column types as SQL comments
+		emitDDL: flags.Bool("emit-ddl", false, "write a <package>_dbtypes.sql file per package listing the suggested column type of each message"),
+		// Flag to select the database the column types are suggested for
+		dialect: flags.String("dialect", string(DialectPostgres), "database dialect for emit-ddl and emit-repo: postgres, mysql, sqlite or sqlserver"),
+		// Flag to write sqlc type overrides for the wrappers
+		emitSqlc: flags.Bool("emit-sqlc-overrides", false, "write a <package>_dbtypes_sqlc.yaml file per package with sqlc overrides mapping columns to the wrappers"),
+		// Flag to write JSON Schemas of the messages stored as protojson
+		emitJSONSchema: flags.Bool("emit-jsonschema", false, "write a <message>.schema.json file describing the protojson documents of each message stored in the json format"),
+		// Flag to generate MongoDB BSON methods
+		emitBSON: flags.Bool("emit-bson", false, "generate MarshalBSON/UnmarshalBSON methods in a dbtypes_bson build-tagged file"),
+		// Flag to generate constants naming the JSON keys of each field
+		emitJSONPaths: flags.Bool("emit-json-paths", false, "generate XxxPathField constants holding the JSON key of each message field"),
+		// Flags to generate functions converting stored messages between formats
+		emitMigrators: flags.Bool("emit-migrators", false, "generate ConvertXxx functions re-encoding messages from migrate-from to migrate-to"),
+		migrateFrom:   flags.String("migrate-from", string(FormatBinary), "format ConvertXxx decodes: binary, json, text, cbor or msgpack"),
+		migrateTo:     flags.String("migrate-to", string(FormatJSON), "format ConvertXxx encodes: binary, json, text, cbor or msgpack"),
+		// Flag to generate a ColumnInfo variable per message
+		emitColumns: flags.Bool("emit-columns", false, "generate XxxColumn variables describing the storage format of each message"),
+		// Flag to generate a DiffXxx function per message
+		emitDiff: flags.Bool("emit-diff", false, "generate DiffXxx functions listing the top-level fields that differ between two messages"),
+		// Flag to register the wrappers with the runtime package
+		globalRegistry: flags.Bool("global-registry", false, "register each wrapper by message name with the dbtypes runtime package, for dbtypes.NewWrapper"),
+		// Flag to generate a store per message with Insert and Get
+		emitRepo: flags.Bool("emit-repo", false, "generate XxxStore types inserting and reading messages with database/sql, using the dialect's bind parameters"),
+		// Flag to generate fluent builders for the wrappers
+		builders: flags.Bool("builders", false, "generate XxxValueBuilder types with chained setters for each message field"),
+		// Flag to make the constructors take functional options
+		functionalOptions: flags.Bool("functional-options", false, "make NewXxxValue constructors take Options such as WithClone and WithFormat"),
+		// Flag to declare the read-only wrapper methods on value receivers
+		valueReceiver: flags.Bool("value-receiver", false, "declare Value, Unwrap, Equal and the other read-only wrapper methods on value receivers"),
+		// Flag to detect the format of each value in Scan
+		autoScan: flags.Bool("auto-scan", false, "detect protojson and binary values in Scan through a DetectFormat hook, while Value keeps writing the configured format"),
+		// Flag to use the vtprotobuf fast paths of messages that have them
+		vtproto: flags.Bool("vtproto", false, "encode and decode the binary format with the MarshalVT and UnmarshalVT methods vtprotobuf generates, where a message has them"),
+		// Flag to leave an empty message in the wrappers on NULL
+		scanNullAsEmpty: flags.Bool("scan-null-as-empty", false, "leave an empty message rather than nil in XxxValue and NullXxxValue wrappers scanned from NULL"),
+		// Flag to generate wrappers for enums
+		enums: flags.Bool("enums", false, "generate XxxValue wrappers storing top-level enums as integers"),
+		// Flag to name the generated wrapper types
+		typeSuffix: flags.String("type-suffix", "Value", "suffix appended to message names to form wrapper type names"),
+		// Flag to name the generated files
+		fileSuffix: flags.String("filename-suffix", "_dbtypes.pb.go", "suffix appended to proto file names to form generated file names"),
+		// Flag to generate the wrappers into a subpackage
+		outPackage:   flags.String("out-package", "", "generate wrappers into this subpackage of the proto package (e.g. 'dbtypes')"),
+		excludeTypes: new(stringList),
+		onlyPackage:  new(stringList),
+		orm:          new(stringList),
+	}
+	// Flag to exclude types by name (repeatable, e.g. exclude=Debug,exclude=example.v1.Audit)
+	flags.Var(params.excludeTypes, "exclude", "exclude this message or enum, by Go name, full proto name or name within its package; may be repeated")
+	// Flag to only generate for some packages (repeatable, e.g. package=a.v1,package=b.v1)
+	flags.Var(params.onlyPackage, "package", "only generate for this proto package (e.g., 'example.v1'); may be repeated")
+	// Flag to generate ORM integrations (repeatable, e.g. orm=gorm,orm=ent)
+	flags.Var(params.orm, "orm", "generate ORM integration: gorm or ent; may be repeated")
+	// Flag json-proto-names is a shorter name for json-use-proto-names
+	flags.BoolVar(params.jsonUseProtoNames, "json-proto-names", false, "same as json-use-proto-names")
+	return params
+}
+
+func run(gen *protogen.Plugin, params *pluginParams) error {
+	// Declare support for proto3 optional fields and editions. Field presence
+	// is read from the descriptors, so editions need no handling of their own.
+	gen.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL |
+		pluginpb.CodeGeneratorResponse_FEATURE_SUPPORTS_EDITIONS)
+	gen.SupportedEditionsMinimum = descriptorpb.Edition_EDITION_PROTO2
+	gen.SupportedEditionsMaximum = descriptorpb.Edition_EDITION_2023
+
+	// Parse excluded types into a set. Protoc splits parameters on commas, so
+	// a list only reaches here as one value when the option is set directly.
+	excluded := make(map[string]bool)
+	for _, value := range *params.excludeTypes {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				excluded[name] = true
 			}
 		}
+	}
 
-		config := &GeneratorConfig{
-			ExcludedTypes: excluded,
-			OnlyPackage:   strings.TrimSpace(*onlyPackage),
+	// Collect the package filter into a set
+	packages := make(map[string]bool)
+	for _, name := range *params.onlyPackage {
+		if name = strings.TrimSpace(name); name != "" {
+			packages[name] = true
 		}
+	}
 
-		// Track which packages have had ProtoValue generated
-		generatedPackages := make(map[protogen.GoImportPath]bool)
+	var include *regexp.Regexp
+	if *params.includeRegex != "" {
+		re, err := regexp.Compile(*params.includeRegex)
+		if err != nil {
+			return fmt.Errorf("invalid include-regex %q: %v", *params.includeRegex, err)
+		}
+		include = re
+	}
 
-		for _, f := range gen.Files {
-			if !f.Generate {
-				continue
-			}
-			if err := generateFile(gen, f, config, generatedPackages); err != nil {
-				return err
-			}
+	nested, err := parseNested(strings.TrimSpace(*params.nested))
+	if err != nil {
+		return err
+	}
+
+	nilMessage, err := parseNilMessage(strings.TrimSpace(*params.nilMessage))
+	if err != nil {
+		return err
+	}
+
+	format, err := parseFormat(*params.format)
+	if err != nil {
+		return err
+	}
+
+	compression, err := parseCompression(*params.compress)
+	if err != nil {
+		return err
+	}
+
+	zstdDict, err := readZstdDict(strings.TrimSpace(*params.zstdDict), compression)
+	if err != nil {
+		return err
+	}
+
+	driver, err := parseDriver(strings.TrimSpace(*params.driver))
+	if err != nil {
+		return err
+	}
+
+	dialect, err := parseDialect(strings.TrimSpace(*params.dialect))
+	if err != nil {
+		return err
+	}
+
+	orms, err := parseORMs(*params.orm)
+	if err != nil {
+		return err
+	}
+
+	typeSuffix, err := parseTypeSuffix(*params.typeSuffix)
+	if err != nil {
+		return err
+	}
+
+	fileSuffix, err := parseFileSuffix(*params.fileSuffix)
+	if err != nil {
+		return err
+	}
+
+	outPackage, err := parseOutPackage(strings.TrimSpace(*params.outPackage))
+	if err != nil {
+		return err
+	}
+
+	migrateFrom, err := parseFormat(*params.migrateFrom)
+	if err != nil {
+		return fmt.Errorf("invalid migrate-from: %w", err)
+	}
+	migrateTo, err := parseFormat(*params.migrateTo)
+	if err != nil {
+		return fmt.Errorf("invalid migrate-to: %w", err)
+	}
+	if *params.emitMigrators && migrateFrom == migrateTo {
+		return fmt.Errorf("migrate-from and migrate-to are both %s", migrateFrom)
+	}
+
+	checksumAlgorithm, err := parseChecksumAlgorithm(strings.TrimSpace(*params.checksumAlgorithm))
+	if err != nil {
+		return err
+	}
+
+	if *params.maxScanSize < 0 {
+		return fmt.Errorf("invalid max-scan-size %d: must not be negative", *params.maxScanSize)
+	}
+
+	config := &GeneratorConfig{
+		ExcludedTypes:       excluded,
+		IncludeRegex:        include,
+		OnlyPackages:        packages,
+		RequireOptIn:        *params.requireOptIn,
+		Nested:              nested,
+		Format:              format,
+		Compression:         compression,
+		ZstdDict:            zstdDict,
+		EncryptHooks:        *params.encryptHooks,
+		MarshalHooks:        *params.marshalHooks,
+		MetricsHooks:        *params.metricsHooks,
+		OTel:                *params.otel,
+		EmptyAsNull:         *params.emptyAsNull,
+		NilMessage:          nilMessage,
+		Deterministic:       *params.deterministic,
+		SortRepeated:        *params.sortRepeated,
+		EmitChecksum:        *params.emitChecksum,
+		ChecksumAlgorithm:   checksumAlgorithm,
+		MaxScanSize:         *params.maxScanSize,
+		DiscardUnknown:      *params.discardUnknown,
+		AllowPartial:        *params.allowPartial,
+		PreserveUnknown:     *params.preserveUnknown,
+		ValueAsString:       *params.valueAsString,
+		Base64Text:          *params.base64Text,
+		JSONEmitUnpopulated: *params.jsonEmitUnpopulated,
+		JSONUseProtoNames:   *params.jsonUseProtoNames,
+		Generic:             *params.generic,
+		Validate:            *params.validate,
+		PostgresArray:       *params.postgresArray,
+		Driver:              driver,
+		EmitDDL:             *params.emitDDL,
+		Dialect:             dialect,
+		EmitSqlc:            *params.emitSqlc,
+		EmitJSONSchema:      *params.emitJSONSchema,
+		EmitBSON:            *params.emitBSON,
+		EmitJSONPaths:       *params.emitJSONPaths,
+		EmitMigrators:       *params.emitMigrators,
+		MigrateFrom:         migrateFrom,
+		MigrateTo:           migrateTo,
+		Builders:            *params.builders,
+		ValueReceiver:       *params.valueReceiver,
+		AutoScan:            *params.autoScan,
+		EmitColumns:         *params.emitColumns,
+		EmitDiff:            *params.emitDiff,
+		GlobalRegistry:      *params.globalRegistry,
+		VTProto:             *params.vtproto,
+		ScanNullAsEmpty:     *params.scanNullAsEmpty,
+		EmitRepo:            *params.emitRepo,
+		FunctionalOptions:   *params.functionalOptions,
+		Enums:               *params.enums,
+		TypeSuffix:          typeSuffix,
+		FileSuffix:          fileSuffix,
+		OutPackage:          outPackage,
+		ORMs:                orms,
+	}
+	if err := validateGeneric(config); err != nil {
+		return err
+	}
+	if err := validateBase64Text(config); err != nil {
+		return err
+	}
+	if err := validateAutoScan(config); err != nil {
+		return err
+	}
+	if err := validateChecksum(config); err != nil {
+		return err
+	}
+	if err := validateVTProto(config); err != nil {
+		return err
+	}
+	if err := validateFunctionalOptions(config); err != nil {
+		return err
+	}
+	if err := checkPreserveUnknown(gen, config); err != nil {
+		return err
+	}
+	if err := collectMessageFormats(gen, config); err != nil {
+		return err
+	}
+	if err := checkCollisions(gen, config); err != nil {
+		return err
+	}
+
+	// Track which packages have had ProtoValue generated
+	generatedPackages := make(map[protogen.GoImportPath]*packageState)
+
+	for _, f := range gen.Files {
+		if !f.Generate {
+			continue
 		}
-		return nil
-	})
+		if err := generateFile(gen, f, config, generatedPackages); err != nil {
+			return err
+		}
+	}
+	if config.EmitDDL {
+		generateDDLFiles(gen, config)
+	}
+	if config.EmitSqlc {
+		generateSqlcFiles(gen, config)
+	}
+	if config.EmitJSONSchema {
+		if err := generateJSONSchemaFiles(gen, config); err != nil {
+			return err
+		}
+	}
+	return nil
 }
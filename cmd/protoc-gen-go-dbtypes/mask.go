@@ -0,0 +1,89 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+const (
+	fieldmaskpbPackage = protogen.GoImportPath("google.golang.org/protobuf/types/known/fieldmaskpb")
+	stringsPackage     = protogen.GoImportPath("strings")
+)
+
+// generateMaskHelpers emits the helper the ValueMasked methods share, once
+// per package.
+func generateMaskHelpers(g *protogen.GeneratedFile) {
+	fieldNumber := g.QualifiedGoIdent(protoreflectPackage.Ident("FieldNumber"))
+
+	g.P("// dbtypesApplyMask clears the fields of m that paths, the paths of a")
+	g.P("// google.protobuf.FieldMask, don't cover, and its unknown fields. A path may")
+	g.P(`// name a field of a singular message field, as in "spec.name".`)
+	g.P("func dbtypesApplyMask(m ", protoreflectPackage.Ident("Message"), ", paths []string) error {")
+	g.P("	md := m.Descriptor()")
+	g.P("	// keep maps each field named to the paths below it, or to nil if the")
+	g.P("	// whole field is kept")
+	g.P("	keep := make(map[", fieldNumber, "][]string, len(paths))")
+	g.P("	for _, path := range paths {")
+	g.P("		name, rest, nested := ", stringsPackage.Ident("Cut"), `(path, ".")`)
+	g.P("		fd := md.Fields().ByName(", protoreflectPackage.Ident("Name"), "(name))")
+	g.P("		if fd == nil || nested && (fd.Message() == nil || fd.IsList() || fd.IsMap()) {")
+	g.P("			return ", fmtPackage.Ident("Errorf"), `("dbtypes: %s: invalid field mask path %q", md.Name(), path)`)
+	g.P("		}")
+	g.P("		sub, seen := keep[fd.Number()]")
+	g.P("		switch {")
+	g.P("		case !nested:")
+	g.P("			keep[fd.Number()] = nil")
+	g.P("		case !seen || sub != nil:")
+	g.P("			keep[fd.Number()] = append(sub, rest)")
+	g.P("		}")
+	g.P("	}")
+	g.P("	fields := md.Fields()")
+	g.P("	for i := 0; i < fields.Len(); i++ {")
+	g.P("		fd := fields.Get(i)")
+	g.P("		sub, ok := keep[fd.Number()]")
+	g.P("		switch {")
+	g.P("		case !ok:")
+	g.P("			m.Clear(fd)")
+	g.P("		case sub != nil:")
+	g.P("			// Check the paths below an unset field too")
+	g.P("			child := m.NewField(fd).Message()")
+	g.P("			if m.Has(fd) {")
+	g.P("				child = m.Mutable(fd).Message()")
+	g.P("			}")
+	g.P("			if err := dbtypesApplyMask(child, sub); err != nil {")
+	g.P("				return err")
+	g.P("			}")
+	g.P("		}")
+	g.P("	}")
+	g.P("	if len(m.GetUnknown()) > 0 {")
+	g.P("		m.SetUnknown(nil)")
+	g.P("	}")
+	g.P("	return nil")
+	g.P("}")
+	g.P()
+}
+
+// generateValueMasked emits the ValueMasked method of the wrapper of m.
+func generateValueMasked(g *protogen.GeneratedFile, m *protogen.Message, config *GeneratorConfig, recv string) {
+	wrapperName := config.wrapperName(m)
+
+	g.P("// ValueMasked is like Value but stores a copy of the message with only the")
+	g.P("// fields mask names set, for partial updates. It returns an error for a path")
+	g.P("// that is not a field of ", m.Desc.FullName(), ". A nil or empty mask leaves no")
+	g.P("// field set.")
+	g.P("func (x ", recv, ") ValueMasked(mask *", fieldmaskpbPackage.Ident("FieldMask"), ") (", driverPackage.Ident("Value"), ", error) {")
+	g.P("	msg := &", m.GoIdent, "{}")
+	g.P("	if x.ProtoValue != nil && x.ProtoValue.Message != nil {")
+	g.P("		msg = ", protoPackage.Ident("Clone"), "(x.ProtoValue.Message).(*", m.GoIdent, ")")
+	g.P("	}")
+	g.P("	if err := dbtypesApplyMask(msg.ProtoReflect(), mask.GetPaths()); err != nil {")
+	g.P("		return nil, err")
+	g.P("	}")
+	g.P("	if x.ProtoValue == nil || x.ProtoValue.Message == nil {")
+	g.P("		return x.ValueContext(", contextPackage.Ident("Background"), "())")
+	g.P("	}")
+	g.P("	masked := *x.ProtoValue")
+	g.P("	masked.Message = msg")
+	g.P("	return (&", wrapperName, "{ProtoValue: &masked}).ValueContext(", contextPackage.Ident("Background"), "())")
+	g.P("}")
+	g.P()
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// generateMigrator emits ConvertXxx, which re-encodes a message stored in
+// migrate-from as migrate-to, for scripts that convert a column in place.
+// Both sides use the plain format, without the compression, encryption or
+// hooks Value may apply.
+func generateMigrator(g *protogen.GeneratedFile, m *protogen.Message, config *GeneratorConfig) {
+	typeName := m.GoIdent.GoName
+	from, to := config.MigrateFrom, config.MigrateTo
+
+	g.P("// Convert", typeName, " re-encodes a ", typeName, " stored in the ", from, " format")
+	g.P("// as ", to, ", for migrating a column between formats. A nil src, as scanned")
+	g.P("// from NULL, gives nil.")
+	g.P("func Convert", typeName, "(src []byte) ([]byte, error) {")
+	g.P("	if src == nil {")
+	g.P("		return nil, nil")
+	g.P("	}")
+	g.P("	msg := &", m.GoIdent, "{}")
+	g.P("	if err := ", config.formatUnmarshalFunc(from), "(src, msg); err != nil {")
+	g.P("		return nil, dbtypesWrapError(msg, ", fmtPackage.Ident("Errorf"), `("convert from `, from, `: %w", err))`)
+	g.P("	}")
+	if len(redactedFields(m)) > 0 {
+		g.P("	// Rows written before the fields were redacted or skipped may still hold them")
+		g.P("	", redactFunc(m), "(msg)")
+	}
+	g.P("	dst, err := ", config.formatMarshalFunc(to), "(msg)")
+	g.P("	if err != nil {")
+	g.P("		return nil, dbtypesWrapError(msg, ", fmtPackage.Ident("Errorf"), `("convert to `, to, `: %w", err))`)
+	g.P("	}")
+	g.P("	return dst, nil")
+	g.P("}")
+	g.P()
+}
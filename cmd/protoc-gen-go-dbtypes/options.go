@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// validateFunctionalOptions reports options under which a wrapper cannot
+// store its message in another format than that of its type.
+func validateFunctionalOptions(config *GeneratorConfig) error {
+	if !config.FunctionalOptions {
+		return nil
+	}
+	switch {
+	case config.ValueAsString:
+		// Binary values cannot be returned as strings
+		return fmt.Errorf("functional-options=true cannot be combined with value-as-string")
+	case config.Base64Text:
+		return fmt.Errorf("functional-options=true cannot be combined with base64-text")
+	}
+	return nil
+}
+
+// formatConstName returns the name of the Format constant of format.
+func formatConstName(format Format) string {
+	return "Format" + format.codecName()
+}
+
+// functionalOptionNames returns the package-level identifiers
+// generateFunctionalOptions declares.
+func (c *GeneratorConfig) functionalOptionNames() []string {
+	if !c.FunctionalOptions {
+		return nil
+	}
+	names := []string{"Option", "WithClone", "WithFormat", "WithCodec", "Format"}
+	for _, format := range c.codecFormats() {
+		names = append(names, formatConstName(format))
+	}
+	return names
+}
+
+// generateFunctionalOptions emits the Option type the NewXxxValue
+// constructors take with functional-options, and the options themselves.
+func generateFunctionalOptions(g *protogen.GeneratedFile, config *GeneratorConfig) {
+	g.P("// Option configures a wrapper created by a NewXxxValue constructor.")
+	g.P("type Option func(*dbtypesOptions)")
+	g.P()
+	g.P("// dbtypesOptions holds what the Options given to a constructor select.")
+	g.P("type dbtypesOptions struct {")
+	g.P("	clone bool")
+	g.P("	codec Codec")
+	g.P("}")
+	g.P()
+	g.P("// WithClone makes the constructor wrap a deep copy of the message, so that")
+	g.P("// changing the message afterwards doesn't change what the wrapper stores.")
+	g.P("func WithClone() Option {")
+	g.P("	return func(o *dbtypesOptions) { o.clone = true }")
+	g.P("}")
+	g.P()
+	g.P("// Format is a storage format WithFormat selects.")
+	g.P("type Format string")
+	g.P()
+	g.P("// The formats WithFormat accepts.")
+	g.P("const (")
+	for _, format := range config.codecFormats() {
+		g.P("	", formatConstName(format), " Format = ", fmt.Sprintf("%q", string(format)))
+	}
+	g.P(")")
+	g.P()
+	g.P("// WithFormat makes the wrapper store its message in format f instead of the")
+	g.P("// format of the message type, for a column written in another format.")
+	g.P("// Value, Scan, MarshalBinary and UnmarshalBinary all use f. An unknown")
+	g.P("// format leaves the format of the message type.")
+	g.P("func WithFormat(f Format) Option {")
+	g.P("	return func(o *dbtypesOptions) {")
+	g.P("		switch f {")
+	for _, format := range config.codecFormats() {
+		g.P("		case ", formatConstName(format), ":")
+		g.P("			o.codec = ", config.codecVar(format))
+	}
+	g.P("		}")
+	g.P("	}")
+	g.P("}")
+	g.P()
+	g.P("// WithCodec makes the wrapper encode and decode its message with c instead")
+	g.P("// of the codec of the message type, for example one encrypting with a key")
+	g.P("// of its own for each tenant or connection. Value, Scan, MarshalBinary and")
+	g.P("// UnmarshalBinary all use c. A nil c leaves the codec of the message type.")
+	g.P("func WithCodec(c Codec) Option {")
+	g.P("	return func(o *dbtypesOptions) {")
+	g.P("		if c != nil {")
+	g.P("			o.codec = c")
+	g.P("		}")
+	g.P("	}")
+	g.P("}")
+	g.P()
+	g.P("// codecOr returns the codec WithFormat or WithCodec selected for p, or c if")
+	g.P("// there is none.")
+	g.P("func (p *ProtoValue[T]) codecOr(c Codec) Codec {")
+	g.P("	if p != nil && p.codec != nil {")
+	g.P("		return p.codec")
+	g.P("	}")
+	g.P("	return c")
+	g.P("}")
+	g.P()
+}
+
+// generateOptionsConstructor emits the NewXxxValue constructor of m taking
+// Options.
+func generateOptionsConstructor(g *protogen.GeneratedFile, m *protogen.Message, wrapperName string) {
+	g.P("// New", wrapperName, " creates a new ", wrapperName, " wrapper configured by")
+	g.P("// opts. A nil msg gives an empty wrapper, which is stored as NULL like a nil")
+	g.P("// one, whatever the options, and which Scan decodes with the codec")
+	g.P("// WithFormat or WithCodec selected.")
+	g.P("func New", wrapperName, "(msg *", m.GoIdent, ", opts ...Option) *", wrapperName, " {")
+	g.P("	var o dbtypesOptions")
+	g.P("	for _, opt := range opts {")
+	g.P("		opt(&o)")
+	g.P("	}")
+	g.P("	if o.clone {")
+	g.P("		msg, _ = ", protoPackage.Ident("Clone"), "(msg).(*", m.GoIdent, ")")
+	g.P("	}")
+	if len(redactedFields(m)) > 0 {
+		g.P("	if o.codec != nil {")
+		g.P("		o.codec = ", redactCodec(m, "o.codec"))
+		g.P("	}")
+	}
+	g.P("	if msg == nil && o.codec == nil {")
+	g.P("		return &", wrapperName, "{}")
+	g.P("	}")
+	g.P("	return &", wrapperName, "{")
+	g.P("		ProtoValue: &ProtoValue[*", m.GoIdent, "]{Message: msg, codec: o.codec},")
+	g.P("	}")
+	g.P("}")
+	g.P()
+}
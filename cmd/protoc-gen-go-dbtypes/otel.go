@@ -0,0 +1,64 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+const (
+	otelTracePackage     = protogen.GoImportPath("go.opentelemetry.io/otel/trace")
+	otelAttributePackage = protogen.GoImportPath("go.opentelemetry.io/otel/attribute")
+)
+
+// otelBuildTag guards the OpenTelemetry integration so that only builds
+// which opt in depend on go.opentelemetry.io/otel.
+const otelBuildTag = "dbtypes_otel"
+
+// generateOTelFile emits the Tracer variable and the span events Value and
+// Scan record into a separate, build-tagged file, once per package. The file
+// installs itself as the hook that Value and Scan call.
+func generateOTelFile(gen *protogen.Plugin, file *protogen.File, config *GeneratorConfig) {
+	filename := config.filename(file, "otel")
+	g := gen.NewGeneratedFile(filename, config.importPath(file))
+
+	g.P("// Code generated by protoc-gen-go-dbtypes. DO NOT EDIT.")
+	g.P("// source: ", file.Desc.Path())
+	g.P()
+	g.P("//go:build ", otelBuildTag)
+	g.P()
+	g.P("package ", config.packageName(file))
+	g.P()
+
+	g.P("// Tracer, when non-nil, makes Value and Scan record a \"dbtypes.value\" or")
+	g.P("// \"dbtypes.scan\" span event with the full name of the message and the number")
+	g.P("// of bytes stored or read. The event is added to the span of the context")
+	g.P("// given to ValueContext or ScanContext, or to a span started with Tracer if")
+	g.P("// that span is not recording. NULL values are not recorded. Set it before")
+	g.P("// first use.")
+	g.P("var Tracer ", otelTracePackage.Ident("Tracer"))
+	g.P()
+	g.P("func init() {")
+	g.P("	dbtypesTrace = dbtypesTraceOTel")
+	g.P("}")
+	g.P()
+	g.P("// dbtypesTraceOTel records an operation on msg as a span event, if Tracer")
+	g.P("// is set.")
+	g.P("func dbtypesTraceOTel(ctx ", contextPackage.Ident("Context"), ", op string, msg ", protoPackage.Ident("Message"), ", size int, err error) {")
+	g.P("	if Tracer == nil {")
+	g.P("		return")
+	g.P("	}")
+	g.P("	span := ", otelTracePackage.Ident("SpanFromContext"), "(ctx)")
+	g.P("	if !span.IsRecording() {")
+	g.P(`		_, span = Tracer.Start(ctx, "dbtypes."+op)`)
+	g.P("		defer span.End()")
+	g.P("	}")
+	g.P("	attrs := ", otelTracePackage.Ident("WithAttributes"), "(")
+	g.P(`		`, otelAttributePackage.Ident("String"), `("dbtypes.message", string(msg.ProtoReflect().Descriptor().FullName())),`)
+	g.P(`		`, otelAttributePackage.Ident("Int"), `("dbtypes.size", size),`)
+	g.P("	)")
+	g.P(`	span.AddEvent("dbtypes."+op, attrs)`)
+	g.P("	if err != nil {")
+	g.P("		span.RecordError(err, attrs)")
+	g.P("	}")
+	g.P("}")
+	g.P()
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+const pgtypePackage = protogen.GoImportPath("github.com/jackc/pgx/v5/pgtype")
+
+// pgxBuildTag guards the pgx codecs so that only builds which opt in depend
+// on github.com/jackc/pgx/v5.
+const pgxBuildTag = "dbtypes_pgx"
+
+// generatePgxFile emits the pgx registrations for messages into a separate,
+// build-tagged file. The first file of each package also declares Register
+// and the codec it installs.
+func generatePgxFile(gen *protogen.Plugin, file *protogen.File, messages []*protogen.Message, config *GeneratorConfig, firstInPackage bool) {
+	filename := config.filename(file, "pgx")
+	g := gen.NewGeneratedFile(filename, config.importPath(file))
+
+	g.P("// Code generated by protoc-gen-go-dbtypes. DO NOT EDIT.")
+	g.P("// source: ", file.Desc.Path())
+	g.P()
+	g.P("//go:build ", pgxBuildTag)
+	g.P()
+	g.P("package ", config.packageName(file))
+	g.P()
+
+	if firstInPackage {
+		generatePgxCodec(g, config)
+	}
+
+	g.P("func init() {")
+	g.P("	dbtypesPgxMessages = append(dbtypesPgxMessages,")
+	for _, m := range messages {
+		generatePgxMessage(g, m, config, messageFormat(m, config))
+	}
+	g.P("	)")
+	g.P("}")
+}
+
+// generatePgxMessage emits the dbtypesPgxMessage entry for m, which encodes
+// and decodes through the wrapper so that pgx stores exactly what Value
+// would.
+func generatePgxMessage(g *protogen.GeneratedFile, m *protogen.Message, config *GeneratorConfig, format Format) {
+	wrapperName := config.wrapperName(m)
+
+	pgTypes := `"bytea"`
+	switch format {
+	case FormatJSON:
+		pgTypes = `"jsonb", "json"`
+	case FormatText:
+		pgTypes = `"text"`
+	}
+
+	g.P("		dbtypesPgxMessage{")
+	g.P("			pgTypes: []string{", pgTypes, "},")
+	g.P("			goType:  ", reflectPackage.Ident("TypeOf"), "((*", m.GoIdent, ")(nil)),")
+	g.P("			value: func(msg any) (", driverPackage.Ident("Value"), ", error) {")
+	g.P("				return New", wrapperName, "(msg.(*", m.GoIdent, ")).Value()")
+	g.P("			},")
+	g.P("			scan: func(data []byte, msg any) error {")
+	g.P("				return New", wrapperName, "(msg.(*", m.GoIdent, ")).Scan(data)")
+	g.P("			},")
+	g.P("		},")
+}
+
+// generatePgxCodec emits Register and the pgtype.Codec it installs. The
+// codec wraps the codec already registered for a PostgreSQL type, handling
+// the package's messages and delegating everything else, so that the wire
+// formats of bytea, json, jsonb and text are left to pgx.
+func generatePgxCodec(g *protogen.GeneratedFile, config *GeneratorConfig) {
+	g.P("// Register installs codecs in m that encode the messages of this package as")
+	g.P("// query arguments and scan columns into them, like their wrappers' Value")
+	g.P("// and Scan do. Binary messages are stored in bytea columns, JSON messages in")
+	g.P("// jsonb or json columns and text messages in text columns; other Go types")
+	g.P("// keep their existing codecs. Call Register before m is used, e.g. from")
+	g.P("// pgxpool.Config.AfterConnect with conn.TypeMap().")
+	g.P("func Register(m *", pgtypePackage.Ident("Map"), ") {")
+	g.P("	for _, name := range []string{\"bytea\", \"json\", \"jsonb\", \"text\"} {")
+	g.P("		t, ok := m.TypeForName(name)")
+	g.P("		if !ok {")
+	g.P("			continue")
+	g.P("		}")
+	g.P("		var messages []dbtypesPgxMessage")
+	g.P("		for _, msg := range dbtypesPgxMessages {")
+	g.P("			if ", slicesPackage.Ident("Contains"), "(msg.pgTypes, name) {")
+	g.P("				messages = append(messages, msg)")
+	g.P("			}")
+	g.P("		}")
+	g.P("		if len(messages) == 0 {")
+	g.P("			continue")
+	g.P("		}")
+	g.P("		m.RegisterType(&", pgtypePackage.Ident("Type"), "{")
+	g.P("			Name:  t.Name,")
+	g.P("			OID:   t.OID,")
+	g.P("			Codec: &dbtypesPgxCodec{Codec: t.Codec, messages: messages},")
+	g.P("		})")
+	g.P("	}")
+	g.P("	// Used when pgx doesn't know the parameter type, e.g. with the simple")
+	g.P("	// protocol")
+	g.P("	for _, msg := range dbtypesPgxMessages {")
+	g.P("		m.RegisterDefaultPgType(", reflectPackage.Ident("Zero"), "(msg.goType).Interface(), msg.pgTypes[0])")
+	g.P("	}")
+	g.P("}")
+	g.P()
+
+	g.P("// dbtypesPgxMessages lists the messages Register installs codecs for.")
+	g.P("var dbtypesPgxMessages []dbtypesPgxMessage")
+	g.P()
+
+	g.P("// dbtypesPgxMessage describes how a message is stored by the pgx codecs.")
+	g.P("type dbtypesPgxMessage struct {")
+	g.P("	// pgTypes are the PostgreSQL types the message can be stored in. The")
+	g.P("	// first is used when the parameter type is unknown.")
+	g.P("	pgTypes []string")
+	g.P("	// goType is the pointer type of the generated message.")
+	g.P("	goType ", reflectPackage.Ident("Type"))
+	g.P("	// value encodes a non-nil message like the wrapper's Value.")
+	g.P("	value func(msg any) (", driverPackage.Ident("Value"), ", error)")
+	g.P("	// scan decodes data into a non-nil message like the wrapper's Scan.")
+	g.P("	scan func(data []byte, msg any) error")
+	g.P("}")
+	g.P()
+
+	g.P("// dbtypesPgxCodec extends a registered codec with the messages stored in its")
+	g.P("// PostgreSQL type.")
+	g.P("type dbtypesPgxCodec struct {")
+	g.P("	", pgtypePackage.Ident("Codec"))
+	g.P("	messages []dbtypesPgxMessage")
+	g.P("}")
+	g.P()
+
+	g.P("func (c *dbtypesPgxCodec) message(v any) (dbtypesPgxMessage, bool) {")
+	g.P("	t := ", reflectPackage.Ident("TypeOf"), "(v)")
+	g.P("	for _, msg := range c.messages {")
+	g.P("		if msg.goType == t {")
+	g.P("			return msg, true")
+	g.P("		}")
+	g.P("	}")
+	g.P("	return dbtypesPgxMessage{}, false")
+	g.P("}")
+	g.P()
+
+	g.P("// PlanEncode implements pgtype.Codec.")
+	g.P("func (c *dbtypesPgxCodec) PlanEncode(m *", pgtypePackage.Ident("Map"), ", oid uint32, format int16, value any) ", pgtypePackage.Ident("EncodePlan"), " {")
+	g.P("	msg, ok := c.message(value)")
+	g.P("	if !ok {")
+	g.P("		return c.Codec.PlanEncode(m, oid, format, value)")
+	g.P("	}")
+	g.P("	next := c.Codec.PlanEncode(m, oid, format, []byte(nil))")
+	g.P("	if next == nil {")
+	g.P("		return nil")
+	g.P("	}")
+	g.P("	return &dbtypesPgxEncodePlan{message: msg, next: next}")
+	g.P("}")
+	g.P()
+
+	g.P("// PlanScan implements pgtype.Codec. Scanning into **Message is handled by")
+	g.P("// pgx, which sets the pointer to nil for NULL and otherwise scans into a new")
+	g.P("// message.")
+	g.P("func (c *dbtypesPgxCodec) PlanScan(m *", pgtypePackage.Ident("Map"), ", oid uint32, format int16, target any) ", pgtypePackage.Ident("ScanPlan"), " {")
+	g.P("	msg, ok := c.message(target)")
+	g.P("	if !ok {")
+	g.P("		return c.Codec.PlanScan(m, oid, format, target)")
+	g.P("	}")
+	g.P("	next := c.Codec.PlanScan(m, oid, format, new([]byte))")
+	g.P("	if next == nil {")
+	g.P("		return nil")
+	g.P("	}")
+	g.P("	return &dbtypesPgxScanPlan{message: msg, next: next}")
+	g.P("}")
+	g.P()
+
+	g.P("// dbtypesPgxEncodePlan encodes a message into the bytes of its column and")
+	g.P("// hands them to the plan of the wrapped codec.")
+	g.P("type dbtypesPgxEncodePlan struct {")
+	g.P("	message dbtypesPgxMessage")
+	g.P("	next    ", pgtypePackage.Ident("EncodePlan"))
+	g.P("}")
+	g.P()
+	g.P("func (p *dbtypesPgxEncodePlan) Encode(value any, buf []byte) ([]byte, error) {")
+	g.P("	v, err := p.message.value(value)")
+	g.P("	if err != nil || v == nil {")
+	g.P("		return nil, err")
+	g.P("	}")
+	if config.ValueAsString {
+		g.P("	// The next plan was chosen for []byte")
+		g.P("	if s, ok := v.(string); ok {")
+		g.P("		v = []byte(s)")
+		g.P("	}")
+	}
+	g.P("	return p.next.Encode(v, buf)")
+	g.P("}")
+	g.P()
+
+	g.P("// dbtypesPgxScanPlan decodes the column bytes read by the plan of the")
+	g.P("// wrapped codec into a message.")
+	g.P("type dbtypesPgxScanPlan struct {")
+	g.P("	message dbtypesPgxMessage")
+	g.P("	next    ", pgtypePackage.Ident("ScanPlan"))
+	g.P("}")
+	g.P()
+	g.P("func (p *dbtypesPgxScanPlan) Scan(src []byte, target any) error {")
+	g.P("	if src == nil {")
+	g.P("		return ", fmtPackage.Ident("Errorf"), `("dbtypes: cannot scan NULL into %T", target)`)
+	g.P("	}")
+	g.P("	var data []byte")
+	g.P("	if err := p.next.Scan(src, &data); err != nil {")
+	g.P("		return err")
+	g.P("	}")
+	g.P("	return p.message.scan(data, target)")
+	g.P("}")
+	g.P()
+}
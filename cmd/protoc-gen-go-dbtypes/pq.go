@@ -0,0 +1,99 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+const pqPackage = protogen.GoImportPath("github.com/lib/pq")
+
+// pqBuildTag guards the PostgreSQL array types so that only builds which opt
+// in depend on github.com/lib/pq.
+const pqBuildTag = "dbtypes_pq"
+
+// generatePQFile emits the PostgreSQL array types for messages into a
+// separate, build-tagged file.
+func generatePQFile(gen *protogen.Plugin, file *protogen.File, messages []*protogen.Message, config *GeneratorConfig) {
+	filename := config.filename(file, "pq")
+	g := gen.NewGeneratedFile(filename, config.importPath(file))
+
+	g.P("// Code generated by protoc-gen-go-dbtypes. DO NOT EDIT.")
+	g.P("// source: ", file.Desc.Path())
+	g.P()
+	g.P("//go:build ", pqBuildTag)
+	g.P()
+	g.P("package ", config.packageName(file))
+	g.P()
+
+	for _, m := range messages {
+		generatePQArray(g, m, config, messageFormat(m, config))
+	}
+}
+
+func generatePQArray(g *protogen.GeneratedFile, m *protogen.Message, config *GeneratorConfig, format Format) {
+	typeName := m.GoIdent.GoName
+	wrapperName := config.wrapperName(m)
+	arrayName := wrapperName + "Array"
+
+	// Binary values travel as bytea[]; text formats as text[] or jsonb[]
+	rawType, column, element := "ByteaArray", "bytea[]", "data"
+	if format.isText() {
+		rawType, column, element = "StringArray", "text[] or jsonb[]", "string(data)"
+	}
+
+	// Type definition
+	g.P("// ", arrayName, " stores *", typeName, " messages in a PostgreSQL ", column)
+	g.P("// column using the array encoding of github.com/lib/pq. Each element is")
+	g.P("// encoded like ", wrapperName, ".Value; nil elements are stored as empty messages.")
+	g.P("type ", arrayName, " []", wrapperName)
+	g.P()
+
+	// Scan method
+	g.P("// Scan implements sql.Scanner.")
+	g.P("func (a *", arrayName, ") Scan(src any) error {")
+	g.P("	var raw ", pqPackage.Ident(rawType))
+	g.P("	if err := raw.Scan(src); err != nil {")
+	g.P("		return ", fmtPackage.Ident("Errorf"), `("dbtypes: `, typeName, ` array: %w", err)`)
+	g.P("	}")
+	g.P("	if raw == nil {")
+	g.P("		*a = nil")
+	g.P("		return nil")
+	g.P("	}")
+	g.P("	elems := make(", arrayName, ", len(raw))")
+	g.P("	for i, v := range raw {")
+	g.P("		if err := elems[i].Scan(v); err != nil {")
+	g.P("			return ", fmtPackage.Ident("Errorf"), `("array element %d: %w", i, err)`)
+	g.P("		}")
+	g.P("	}")
+	g.P("	*a = elems")
+	g.P("	return nil")
+	g.P("}")
+	g.P()
+
+	// Value method
+	g.P("// Value implements driver.Valuer.")
+	g.P("func (a ", arrayName, ") Value() (", driverPackage.Ident("Value"), ", error) {")
+	g.P("	if a == nil {")
+	g.P("		return nil, nil")
+	g.P("	}")
+	g.P("	raw := make(", pqPackage.Ident(rawType), ", len(a))")
+	g.P("	for i := range a {")
+	g.P("		msg := a[i].Unwrap()")
+	g.P("		if msg == nil {")
+	g.P("			msg = &", m.GoIdent, "{}")
+	g.P("		}")
+	g.P("		v, err := New", wrapperName, "(msg).Value()")
+	g.P("		if err != nil {")
+	g.P("			return nil, ", fmtPackage.Ident("Errorf"), `("array element %d: %w", i, err)`)
+	g.P("		}")
+	if config.valueString(format) {
+		g.P("		raw[i], _ = v.(string)")
+	} else {
+		g.P("		data, _ := v.([]byte)")
+		g.P("		raw[i] = ", element)
+	}
+	g.P("	}")
+	g.P("	return raw.Value()")
+	g.P("}")
+	g.P()
+	generateSQLAssertions(g, arrayName)
+}
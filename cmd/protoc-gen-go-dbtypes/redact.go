@@ -0,0 +1,114 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+
+	dbtypespb "github.com/cadenya/protoc-gen-go-dbtypes/gen/go/dbtypes"
+)
+
+// redactedFields returns the fields of m that Value leaves out of the stored
+// message: those marked (dbtypes.redact) or (dbtypes.skip).
+func redactedFields(m *protogen.Message) []*protogen.Field {
+	var fields []*protogen.Field
+	for _, f := range m.Fields {
+		opts := f.Desc.Options()
+		if opts != nil && (proto.GetExtension(opts, dbtypespb.E_Redact).(bool) || proto.GetExtension(opts, dbtypespb.E_Skip).(bool)) {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// skippedFields returns the fields of m marked (dbtypes.skip), which Scan
+// also clears.
+func skippedFields(m *protogen.Message) []*protogen.Field {
+	var fields []*protogen.Field
+	for _, f := range m.Fields {
+		if opts := f.Desc.Options(); opts != nil && proto.GetExtension(opts, dbtypespb.E_Skip).(bool) {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// redactFunc returns the name of the function clearing the redacted fields
+// of m.
+func redactFunc(m *protogen.Message) string {
+	return "dbtypesRedact" + m.GoIdent.GoName
+}
+
+// skipFunc returns the name of the function clearing the skipped fields of m.
+func skipFunc(m *protogen.Message) string {
+	return "dbtypesSkip" + m.GoIdent.GoName
+}
+
+// redactCodec returns the expression wrapping codec in the dbtypesRedactCodec
+// of m.
+func redactCodec(m *protogen.Message, codec string) string {
+	expr := "dbtypesRedactCodec{Codec: " + codec + ", redact: " + redactFunc(m)
+	if len(skippedFields(m)) > 0 {
+		expr += ", skip: " + skipFunc(m)
+	}
+	return expr + "}"
+}
+
+// generateRedactCodec emits the Codec that redacting messages are encoded
+// with, once per package.
+func generateRedactCodec(g *protogen.GeneratedFile) {
+	g.P("// dbtypesRedactCodec is the codec of messages with fields marked")
+	g.P("// (dbtypes.redact) or (dbtypes.skip). It encodes a copy of each message with")
+	g.P("// redact applied, so that the fields are never stored and the caller's")
+	g.P("// message is left unchanged, and applies skip, if set, to decoded messages.")
+	g.P("type dbtypesRedactCodec struct {")
+	g.P("	Codec")
+	g.P("	redact func(", protoPackage.Ident("Message"), ")")
+	g.P("	skip   func(", protoPackage.Ident("Message"), ")")
+	g.P("}")
+	g.P()
+	g.P("// Encode implements Codec.")
+	g.P("func (c dbtypesRedactCodec) Encode(msg ", protoPackage.Ident("Message"), ") ([]byte, error) {")
+	g.P("	msg = ", protoPackage.Ident("Clone"), "(msg)")
+	g.P("	c.redact(msg)")
+	g.P("	return c.Codec.Encode(msg)")
+	g.P("}")
+	g.P()
+	g.P("// Decode implements Codec.")
+	g.P("func (c dbtypesRedactCodec) Decode(data []byte, msg ", protoPackage.Ident("Message"), ") error {")
+	g.P("	if err := c.Codec.Decode(data, msg); err != nil {")
+	g.P("		return err")
+	g.P("	}")
+	g.P("	if c.skip != nil {")
+	g.P("		// Rows written before the fields were skipped may still hold them")
+	g.P("		c.skip(msg)")
+	g.P("	}")
+	g.P("	return nil")
+	g.P("}")
+	g.P()
+}
+
+// generateRedact emits the function clearing the redacted fields of m, and
+// the one clearing its skipped fields if it has any.
+func generateRedact(g *protogen.GeneratedFile, m *protogen.Message) {
+	g.P("// ", redactFunc(m), " clears the fields of a ", m.GoIdent.GoName)
+	g.P("// marked (dbtypes.redact) or (dbtypes.skip).")
+	generateClearFields(g, redactFunc(m), redactedFields(m))
+	if skipped := skippedFields(m); len(skipped) > 0 {
+		g.P("// ", skipFunc(m), " clears the fields of a ", m.GoIdent.GoName)
+		g.P("// marked (dbtypes.skip).")
+		generateClearFields(g, skipFunc(m), skipped)
+	}
+}
+
+// generateClearFields emits the body of a function named name clearing
+// fields, following its doc comment.
+func generateClearFields(g *protogen.GeneratedFile, name string, fields []*protogen.Field) {
+	g.P("func ", name, "(m ", protoPackage.Ident("Message"), ") {")
+	g.P("	msg := m.ProtoReflect()")
+	g.P("	fields := msg.Descriptor().Fields()")
+	for _, f := range fields {
+		g.P("	msg.Clear(fields.ByNumber(", f.Desc.Number(), ")) // ", f.Desc.Name())
+	}
+	g.P("}")
+	g.P()
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// generateRegistry emits DBTypeRegistry, which maps the full name of every
+// message wrapped in the output package to a constructor of its wrapper. It
+// is generated once per package, into the first file with wrappers, and so
+// lists the messages of the package's other files too. With global-registry
+// the same constructors are registered with the runtime package.
+func generateRegistry(g *protogen.GeneratedFile, messages []*protogen.Message, config *GeneratorConfig) {
+	g.P("// dbtypesWrapper is the wrapper type DBTypeRegistry returns.")
+	g.P("type dbtypesWrapper = interface {")
+	g.P("	", driverPackage.Ident("Valuer"))
+	g.P("	", sqlPackage.Ident("Scanner"))
+	g.P("}")
+	g.P()
+	g.P("// DBTypeRegistry maps the full name of each message wrapped in this package")
+	g.P("// to a function returning a new wrapper around an empty message, for code")
+	g.P("// that picks the wrapper of a message type at runtime.")
+	g.P("var DBTypeRegistry = map[string]func() interface {")
+	g.P("	", driverPackage.Ident("Valuer"))
+	g.P("	", sqlPackage.Ident("Scanner"))
+	g.P("}{")
+	for _, m := range messages {
+		g.P("	", `"`, m.Desc.FullName(), `": func() dbtypesWrapper { return New`, config.wrapperName(m), "(&", m.GoIdent, "{}) },")
+	}
+	g.P("}")
+	g.P()
+	if config.GlobalRegistry {
+		g.P("func init() {")
+		for _, m := range messages {
+			g.P("	", dbtypesPackage.Ident("RegisterWrapper"), `("`, m.Desc.FullName(), `", func() `, dbtypesPackage.Ident("Wrapper"), " { return New", config.wrapperName(m), "(&", m.GoIdent, "{}) })")
+		}
+		g.P("}")
+		g.P()
+	}
+}
+
+// packageMessages returns the wrapped messages of the output package at path.
+func packageMessages(gen *protogen.Plugin, config *GeneratorConfig, path protogen.GoImportPath) []*protogen.Message {
+	for _, pkg := range outputPackages(gen, config) {
+		if pkg.path == path {
+			return pkg.messages
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"strconv"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// storeName returns the name of the store type emit-repo declares for m.
+func storeName(m *protogen.Message) string {
+	return m.GoIdent.GoName + "Store"
+}
+
+// placeholder returns the nth bind parameter of a query on the dialect.
+func (d Dialect) placeholder(n int) string {
+	switch d {
+	case DialectPostgres:
+		return "$" + strconv.Itoa(n)
+	case DialectSQLServer:
+		return "@p" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+// generateDBTX emits the DBTX interface the stores take, once per package.
+func generateDBTX(g *protogen.GeneratedFile) {
+	g.P("// DBTX is the part of *sql.DB, *sql.Tx and *sql.Conn that the XxxStore")
+	g.P("// types use, so that they work inside and outside transactions.")
+	g.P("type DBTX interface {")
+	g.P("	ExecContext(ctx ", contextPackage.Ident("Context"), ", query string, args ...any) (", sqlPackage.Ident("Result"), ", error)")
+	g.P("	QueryRowContext(ctx ", contextPackage.Ident("Context"), ", query string, args ...any) *", sqlPackage.Ident("Row"))
+	g.P("}")
+	g.P()
+}
+
+// generateStore emits the store of m, which inserts and reads messages
+// through the wrapper with queries written for the configured dialect.
+func generateStore(g *protogen.GeneratedFile, m *protogen.Message, config *GeneratorConfig) {
+	name := storeName(m)
+	wrapperName := config.wrapperName(m)
+	param := config.Dialect.placeholder(1)
+
+	g.P("// ", name, " inserts and reads ", m.GoIdent.GoName, " messages stored in a")
+	g.P("// column of a table, through ", wrapperName, ". The table and column names")
+	g.P("// are written into the queries as they are, so they must not come from")
+	g.P("// user input.")
+	g.P("type ", name, " struct {")
+	g.P("	// Table is the name of the table, optionally qualified by its schema.")
+	g.P("	Table string")
+	g.P("	// IDColumn is the name of the column Get looks rows up by.")
+	g.P("	IDColumn string")
+	g.P("	// Column is the name of the column holding the message.")
+	g.P("	Column string")
+	g.P("}")
+	g.P()
+	g.P("// Insert inserts a row holding msg, leaving the other columns, including")
+	g.P("// the id, to their defaults.")
+	g.P("func (s ", name, ") Insert(ctx ", contextPackage.Ident("Context"), ", db DBTX, msg *", m.GoIdent, ") error {")
+	g.P(`	query := "INSERT INTO " + s.Table + " (" + s.Column + ") VALUES (`, param, `)"`)
+	g.P("	_, err := db.ExecContext(ctx, query, New", wrapperName, "(msg))")
+	g.P("	return err")
+	g.P("}")
+	g.P()
+	g.P("// Get returns the message of the row whose id column equals id, or")
+	g.P("// sql.ErrNoRows if there is none.")
+	g.P("func (s ", name, ") Get(ctx ", contextPackage.Ident("Context"), ", db DBTX, id string) (*", m.GoIdent, ", error) {")
+	g.P(`	query := "SELECT " + s.Column + " FROM " + s.Table + " WHERE " + s.IDColumn + " = `, param, `"`)
+	g.P("	var v ", wrapperName)
+	g.P("	if err := db.QueryRowContext(ctx, query, id).Scan(&v); err != nil {")
+	g.P("		return nil, err")
+	g.P("	}")
+	g.P("	return v.Unwrap(), nil")
+	g.P("}")
+	g.P()
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+const protowirePackage = protogen.GoImportPath("google.golang.org/protobuf/encoding/protowire")
+
+// generateScanFieldsHelpers emits the helpers the ScanFields methods share,
+// once per package.
+func generateScanFieldsHelpers(g *protogen.GeneratedFile) {
+	fieldSet := "map[" + g.QualifiedGoIdent(protoreflectPackage.Ident("FieldNumber")) + "]bool"
+
+	g.P("// dbtypesFieldMask returns the numbers of the top-level fields of md named")
+	g.P("// in paths, as in a google.protobuf.FieldMask.")
+	g.P("func dbtypesFieldMask(md ", protoreflectPackage.Ident("MessageDescriptor"), ", paths []string) (", fieldSet, ", error) {")
+	g.P("	fields := make(", fieldSet, ", len(paths))")
+	g.P("	for _, path := range paths {")
+	g.P("		fd := md.Fields().ByName(", protoreflectPackage.Ident("Name"), "(path))")
+	g.P("		if fd == nil {")
+	g.P("			return nil, ", fmtPackage.Ident("Errorf"), `("dbtypes: %s: no top-level field %q", md.Name(), path)`)
+	g.P("		}")
+	g.P("		fields[fd.Number()] = true")
+	g.P("	}")
+	g.P("	return fields, nil")
+	g.P("}")
+	g.P()
+	g.P("// dbtypesFilterFields returns the wire-format data with only the fields")
+	g.P("// numbered in fields, so that the others are skipped without being decoded.")
+	g.P("// Malformed data is returned as is for the codec to report.")
+	g.P("func dbtypesFilterFields(data []byte, fields ", fieldSet, ") []byte {")
+	g.P("	out := make([]byte, 0, len(data))")
+	g.P("	for b := data; len(b) > 0; {")
+	g.P("		num, typ, n := ", protowirePackage.Ident("ConsumeTag"), "(b)")
+	g.P("		if n < 0 {")
+	g.P("			return data")
+	g.P("		}")
+	g.P("		m := ", protowirePackage.Ident("ConsumeFieldValue"), "(num, typ, b[n:])")
+	g.P("		if m < 0 {")
+	g.P("			return data")
+	g.P("		}")
+	g.P("		if fields[num] {")
+	g.P("			out = append(out, b[:n+m]...)")
+	g.P("		}")
+	g.P("		b = b[n+m:]")
+	g.P("	}")
+	g.P("	return out")
+	g.P("}")
+	g.P()
+	g.P("// dbtypesFieldsCodec is a wire-format codec decoding only the fields")
+	g.P("// numbered in fields, for ScanFields.")
+	g.P("type dbtypesFieldsCodec struct {")
+	g.P("	Codec")
+	g.P("	fields ", fieldSet)
+	g.P("}")
+	g.P()
+	g.P("// Decode implements Codec.")
+	g.P("func (c dbtypesFieldsCodec) Decode(data []byte, msg ", protoPackage.Ident("Message"), ") error {")
+	g.P("	return c.Codec.Decode(dbtypesFilterFields(data, c.fields), msg)")
+	g.P("}")
+	g.P()
+	g.P("// dbtypesProject clears the fields of msg not numbered in fields, and its")
+	g.P("// unknown fields, for the formats that can only be decoded whole.")
+	g.P("func dbtypesProject(msg ", protoPackage.Ident("Message"), ", fields ", fieldSet, ") {")
+	g.P("	m := msg.ProtoReflect()")
+	g.P("	if !m.IsValid() {")
+	g.P("		return")
+	g.P("	}")
+	g.P("	var drop []", protoreflectPackage.Ident("FieldDescriptor"))
+	g.P("	m.Range(func(fd ", protoreflectPackage.Ident("FieldDescriptor"), ", _ ", protoreflectPackage.Ident("Value"), ") bool {")
+	g.P("		if !fields[fd.Number()] {")
+	g.P("			drop = append(drop, fd)")
+	g.P("		}")
+	g.P("		return true")
+	g.P("	})")
+	g.P("	for _, fd := range drop {")
+	g.P("		m.Clear(fd)")
+	g.P("	}")
+	g.P("	if len(m.GetUnknown()) > 0 {")
+	g.P("		m.SetUnknown(nil)")
+	g.P("	}")
+	g.P("}")
+	g.P()
+}
+
+// generateScanFields emits the ScanFields method of the wrapper of m.
+// Messages stored in the plain wire format skip the other fields in the
+// stored bytes; other formats are decoded whole and then cleared.
+func generateScanFields(g *protogen.GeneratedFile, m *protogen.Message, config *GeneratorConfig, format Format, codec string) {
+	wrapperName := config.wrapperName(m)
+
+	g.P("// ScanFields is like Scan but keeps only the top-level fields named in")
+	g.P("// fieldPaths, by their names in the .proto file as in a")
+	g.P("// google.protobuf.FieldMask, leaving the others unset. It returns an error")
+	g.P("// for a name that is not a top-level field of ", m.Desc.FullName(), ".")
+	if config.plainWireFormat(format) && !config.FunctionalOptions {
+		g.P("// The other fields are skipped in the stored bytes without being decoded.")
+	}
+	g.P("func (x *", wrapperName, ") ScanFields(src any, fieldPaths ...string) error {")
+	g.P("	fields, err := dbtypesFieldMask((*", m.GoIdent, ")(nil).ProtoReflect().Descriptor(), fieldPaths)")
+	g.P("	if err != nil {")
+	g.P("		return err")
+	g.P("	}")
+	g.P("	if x.ProtoValue == nil {")
+	g.P("		x.ProtoValue = &ProtoValue[*", m.GoIdent, "]{Message: &", m.GoIdent, "{}}")
+	g.P("	}")
+	g.P("	if x.ProtoValue.Message == nil {")
+	g.P("		x.ProtoValue.Message = &", m.GoIdent, "{}")
+	g.P("	}")
+	ctx := g.QualifiedGoIdent(contextPackage.Ident("Background")) + "()"
+	switch {
+	case config.base64Text(format):
+		g.P("	err = x.ProtoValue.scanBase64(", ctx, ", src, ", codec, ")")
+	case format == FormatJSON:
+		g.P("	err = x.ProtoValue.scanJSON(", ctx, ", src, ", codec, ")")
+	case config.plainWireFormat(format) && !config.FunctionalOptions:
+		g.P("	err = x.ProtoValue.scan(", ctx, ", src, dbtypesFieldsCodec{Codec: ", codec, ", fields: fields})")
+	default:
+		g.P("	err = x.ProtoValue.scan(", ctx, ", src, ", codec, ")")
+	}
+	g.P("	if err != nil {")
+	g.P("		return err")
+	g.P("	}")
+	g.P("	// Clear the other fields of the formats that are decoded whole")
+	g.P("	dbtypesProject(x.ProtoValue.Message, fields)")
+	g.P("	return nil")
+	g.P("}")
+	g.P()
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+const cmpPackage = protogen.GoImportPath("cmp")
+
+// generateSortRepeated emits the helpers that sort-repeated=true encodes
+// messages through, once per package.
+func generateSortRepeated(g *protogen.GeneratedFile) {
+	fieldDescriptor := g.QualifiedGoIdent(protoreflectPackage.Ident("FieldDescriptor"))
+	list := g.QualifiedGoIdent(protoreflectPackage.Ident("List"))
+	value := g.QualifiedGoIdent(protoreflectPackage.Ident("Value"))
+
+	g.P("// dbtypesSortRepeated returns msg, or a copy of it if needed, with the")
+	g.P("// elements of its repeated string and number fields, and those of the")
+	g.P("// messages it holds, in ascending order, so that messages differing only in")
+	g.P("// that order are stored alike.")
+	g.P("func dbtypesSortRepeated(msg ", protoPackage.Ident("Message"), ") ", protoPackage.Ident("Message"), " {")
+	g.P("	if dbtypesScalarLists(msg.ProtoReflect(), dbtypesListSorted) {")
+	g.P("		return msg")
+	g.P("	}")
+	g.P("	msg = ", protoPackage.Ident("Clone"), "(msg)")
+	g.P("	dbtypesScalarLists(msg.ProtoReflect(), func(fd ", fieldDescriptor, ", list ", list, ") bool {")
+	g.P("		values := make([]", value, ", list.Len())")
+	g.P("		for i := range values {")
+	g.P("			values[i] = list.Get(i)")
+	g.P("		}")
+	g.P("		", slicesPackage.Ident("SortStableFunc"), "(values, func(a, b ", value, ") int {")
+	g.P("			return dbtypesCompareScalars(fd.Kind(), a, b)")
+	g.P("		})")
+	g.P("		for i, v := range values {")
+	g.P("			list.Set(i, v)")
+	g.P("		}")
+	g.P("		return true")
+	g.P("	})")
+	g.P("	return msg")
+	g.P("}")
+	g.P()
+	g.P("// dbtypesListSorted reports whether the elements of list, the value of fd,")
+	g.P("// are in ascending order.")
+	g.P("func dbtypesListSorted(fd ", fieldDescriptor, ", list ", list, ") bool {")
+	g.P("	for i := 1; i < list.Len(); i++ {")
+	g.P("		if dbtypesCompareScalars(fd.Kind(), list.Get(i-1), list.Get(i)) > 0 {")
+	g.P("			return false")
+	g.P("		}")
+	g.P("	}")
+	g.P("	return true")
+	g.P("}")
+	g.P()
+	g.P("// dbtypesScalarLists calls f with each populated repeated string or number")
+	g.P("// field of m and of the messages it holds, stopping when f returns false,")
+	g.P("// and reports whether f returned true for all of them.")
+	g.P("func dbtypesScalarLists(m ", protoreflectPackage.Ident("Message"), ", f func(", fieldDescriptor, ", ", list, ") bool) bool {")
+	g.P("	ok := true")
+	g.P("	m.Range(func(fd ", fieldDescriptor, ", v ", value, ") bool {")
+	g.P("		switch {")
+	g.P("		case fd.IsMap():")
+	g.P("			if fd.MapValue().Message() != nil {")
+	g.P("				v.Map().Range(func(_ ", protoreflectPackage.Ident("MapKey"), ", mv ", value, ") bool {")
+	g.P("					ok = dbtypesScalarLists(mv.Message(), f)")
+	g.P("					return ok")
+	g.P("				})")
+	g.P("			}")
+	g.P("		case fd.IsList() && fd.Message() != nil:")
+	g.P("			list := v.List()")
+	g.P("			for i := 0; i < list.Len() && ok; i++ {")
+	g.P("				ok = dbtypesScalarLists(list.Get(i).Message(), f)")
+	g.P("			}")
+	g.P("		case fd.IsList():")
+	g.P("			if dbtypesSortable(fd.Kind()) {")
+	g.P("				ok = f(fd, v.List())")
+	g.P("			}")
+	g.P("		case fd.Message() != nil:")
+	g.P("			ok = dbtypesScalarLists(v.Message(), f)")
+	g.P("		}")
+	g.P("		return ok")
+	g.P("	})")
+	g.P("	return ok")
+	g.P("}")
+	g.P()
+	g.P("// dbtypesSortable reports whether repeated fields of kind are sorted: those")
+	g.P("// of strings and numbers. Enums are left in the order given, since their")
+	g.P("// numbers rarely say anything about the order of the values.")
+	g.P("func dbtypesSortable(kind ", protoreflectPackage.Ident("Kind"), ") bool {")
+	g.P("	switch kind {")
+	g.P("	case ", protoreflectPackage.Ident("BoolKind"), ", ", protoreflectPackage.Ident("EnumKind"), ", ", protoreflectPackage.Ident("BytesKind"), ", ", protoreflectPackage.Ident("MessageKind"), ", ", protoreflectPackage.Ident("GroupKind"), ":")
+	g.P("		return false")
+	g.P("	}")
+	g.P("	return true")
+	g.P("}")
+	g.P()
+	g.P("// dbtypesCompareScalars compares two elements of a repeated field of kind,")
+	g.P("// which dbtypesSortable accepts.")
+	g.P("func dbtypesCompareScalars(kind ", protoreflectPackage.Ident("Kind"), ", a, b ", value, ") int {")
+	g.P("	switch kind {")
+	g.P("	case ", protoreflectPackage.Ident("StringKind"), ":")
+	g.P("		return ", cmpPackage.Ident("Compare"), "(a.String(), b.String())")
+	g.P("	case ", protoreflectPackage.Ident("FloatKind"), ", ", protoreflectPackage.Ident("DoubleKind"), ":")
+	g.P("		return ", cmpPackage.Ident("Compare"), "(a.Float(), b.Float())")
+	g.P("	case ", protoreflectPackage.Ident("Uint32Kind"), ", ", protoreflectPackage.Ident("Fixed32Kind"), ", ", protoreflectPackage.Ident("Uint64Kind"), ", ", protoreflectPackage.Ident("Fixed64Kind"), ":")
+	g.P("		return ", cmpPackage.Ident("Compare"), "(a.Uint(), b.Uint())")
+	g.P("	}")
+	g.P("	return ", cmpPackage.Ident("Compare"), "(a.Int(), b.Int())")
+	g.P("}")
+	g.P()
+}
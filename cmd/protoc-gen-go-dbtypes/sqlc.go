@@ -0,0 +1,67 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// generateSqlcFiles writes a sqlc overrides fragment per output package that
+// maps columns to the wrapper types, so sqlc.yaml doesn't have to be kept in
+// sync by hand.
+func generateSqlcFiles(gen *protogen.Plugin, config *GeneratorConfig) {
+	for _, pkg := range outputPackages(gen, config) {
+		generateSqlcFile(gen, pkg, config)
+	}
+}
+
+func generateSqlcFile(gen *protogen.Plugin, pkg *outputPackage, config *GeneratorConfig) {
+	g := gen.NewGeneratedFile(config.packageFilename(pkg, "_sqlc.yaml"), "")
+
+	g.P("# Code generated by protoc-gen-go-dbtypes. DO NOT EDIT.")
+	g.P("# package: ", string(pkg.path))
+	g.P("#")
+	g.P("# sqlc overrides for the wrapped messages of this package. Each message is")
+	g.P("# mapped for columns named after it in any table, e.g. \"*.tool_set_spec\";")
+	g.P("# NOT NULL columns use the wrapper and nullable columns its Null wrapper.")
+	g.P("# Copy the entries into the overrides of a sql block in sqlc.yaml and")
+	g.P("# adjust the column patterns to your schema.")
+	g.P("overrides:")
+	for _, m := range pkg.messages {
+		column := strconv.Quote("*." + snakeCase(string(m.Desc.Name())))
+		wrapperName := config.wrapperName(m)
+		for _, nullable := range []bool{false, true} {
+			typeName := wrapperName
+			g.P("  - column: ", column)
+			if nullable {
+				typeName = "Null" + wrapperName
+				g.P("    nullable: true")
+			}
+			g.P("    go_type:")
+			g.P("      import: ", strconv.Quote(string(pkg.path)))
+			g.P("      package: ", strconv.Quote(string(pkg.name)))
+			g.P("      type: ", strconv.Quote(typeName))
+		}
+	}
+}
+
+// snakeCase converts a message name to the lower_snake_case column name it
+// is matched by, keeping acronyms together: "JSONDocument" becomes
+// "json_document".
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
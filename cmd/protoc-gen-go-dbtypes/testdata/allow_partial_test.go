@@ -0,0 +1,84 @@
+package testv1
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// draftRecord is a staged LegacyRecord that doesn't have its required id yet.
+func draftRecord() *LegacyRecord {
+	return &LegacyRecord{Name: proto.String("draft"), Scores: []int32{1, 2}}
+}
+
+func TestAllowPartial_ValueScan(t *testing.T) {
+	dbVal, err := NewLegacyRecordValue(draftRecord()).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+
+	var got LegacyRecordValue
+	if err := got.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(got.Unwrap(), draftRecord()) {
+		t.Errorf("Scan() = %v, want %v", got.Unwrap(), draftRecord())
+	}
+	if got.Unwrap().Id != nil {
+		t.Errorf("Scan() set the missing id to %q", got.Unwrap().GetId())
+	}
+}
+
+func TestAllowPartial_Encodings(t *testing.T) {
+	wrapper := NewLegacyRecordValue(draftRecord())
+
+	data, err := wrapper.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error: %v", err)
+	}
+	var binary LegacyRecordValue
+	if err := binary.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary() error: %v", err)
+	}
+	if !proto.Equal(binary.Unwrap(), draftRecord()) {
+		t.Errorf("UnmarshalBinary() = %v, want %v", binary.Unwrap(), draftRecord())
+	}
+
+	data, err = wrapper.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error: %v", err)
+	}
+	var json LegacyRecordValue
+	if err := json.UnmarshalJSON(data); err != nil {
+		t.Fatalf("UnmarshalJSON() error: %v", err)
+	}
+	if !proto.Equal(json.Unwrap(), draftRecord()) {
+		t.Errorf("UnmarshalJSON() = %v, want %v", json.Unwrap(), draftRecord())
+	}
+
+	data, err = wrapper.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText() error: %v", err)
+	}
+	var text LegacyRecordValue
+	if err := text.UnmarshalText(data); err != nil {
+		t.Fatalf("UnmarshalText() error: %v", err)
+	}
+	if !proto.Equal(text.Unwrap(), draftRecord()) {
+		t.Errorf("UnmarshalText() = %v, want %v", text.Unwrap(), draftRecord())
+	}
+}
+
+func TestAllowPartial_Batch(t *testing.T) {
+	rows, err := EncodeLegacyRecordBatch([]*LegacyRecord{draftRecord()})
+	if err != nil {
+		t.Fatalf("EncodeLegacyRecordBatch() error: %v", err)
+	}
+	var got LegacyRecordValue
+	if err := got.Scan(rows[0]); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(got.Unwrap(), draftRecord()) {
+		t.Errorf("Scan() = %v, want %v", got.Unwrap(), draftRecord())
+	}
+}
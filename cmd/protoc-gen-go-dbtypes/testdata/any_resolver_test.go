@@ -0,0 +1,67 @@
+package testv1
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// messageResolver resolves messages only, like most custom resolvers.
+type messageResolver struct {
+	types *protoregistry.Types
+}
+
+func (r messageResolver) FindMessageByName(name protoreflect.FullName) (protoreflect.MessageType, error) {
+	return r.types.FindMessageByName(name)
+}
+
+func (r messageResolver) FindMessageByURL(url string) (protoreflect.MessageType, error) {
+	return r.types.FindMessageByURL(url)
+}
+
+func TestAnyResolver_Override(t *testing.T) {
+	t.Cleanup(func() { AnyResolver = protoregistry.GlobalTypes })
+
+	payload, err := anypb.New(&ToolSetSpec{Name: "embedded"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := NewEnvelopeValue(&Envelope{Id: "env-1", Payload: payload})
+
+	// A resolver that doesn't know the embedded type fails loudly
+	AnyResolver = new(protoregistry.Types)
+	if _, err := env.Value(); err == nil {
+		t.Error("Value() succeeded with a resolver that cannot find the embedded type")
+	}
+	if err := new(EnvelopeValue).Scan([]byte(`{"payload":{"@type":"type.googleapis.com/test.v1.ToolSetSpec"}}`)); err == nil {
+		t.Error("Scan() succeeded with a resolver that cannot find the embedded type")
+	}
+	if _, err := env.MarshalJSON(); err == nil {
+		t.Error("MarshalJSON() succeeded with a resolver that cannot find the embedded type")
+	}
+
+	// A resolver without extension lookups is enough
+	types := new(protoregistry.Types)
+	if err := types.RegisterMessage((&ToolSetSpec{}).ProtoReflect().Type()); err != nil {
+		t.Fatal(err)
+	}
+	for name, resolver := range map[string]protoregistry.MessageTypeResolver{
+		"custom": messageResolver{types},
+		"nil":    nil,
+	} {
+		AnyResolver = resolver
+		dbVal, err := env.Value()
+		if err != nil {
+			t.Fatalf("%s: Value() error: %v", name, err)
+		}
+		var got EnvelopeValue
+		if err := got.Scan(dbVal); err != nil {
+			t.Fatalf("%s: Scan() error: %v", name, err)
+		}
+		if !got.Equal(env) {
+			t.Errorf("%s: round-trip failed:\ngot:  %v\nwant: %v", name, got.Unwrap(), env.Unwrap())
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package testv1
+
+import (
+	"bytes"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestAutoScan_MixedFormats(t *testing.T) {
+	spec := &ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "mixed", Enabled: true}
+	binary, err := proto.Marshal(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	json, err := protojson.Marshal(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, src := range map[string]any{
+		"binary":      binary,
+		"json":        json,
+		"json string": string(json),
+	} {
+		var got ToolSetSpecValue
+		if err := got.Scan(src); err != nil {
+			t.Errorf("Scan(%s) error: %v", name, err)
+			continue
+		}
+		if !proto.Equal(spec, got.Unwrap()) {
+			t.Errorf("Scan(%s) = %v, want %v", name, got.Unwrap(), spec)
+		}
+	}
+
+	// JSON wrappers read binary rows written before the migration
+	doc := &JSONDocument{Id: "doc-1", Labels: map[string]string{"env": "prod"}}
+	binary, err = proto.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got JSONDocumentValue
+	if err := got.Scan(binary); err != nil {
+		t.Fatalf("Scan(binary) into a JSON wrapper error: %v", err)
+	}
+	if !proto.Equal(doc, got.Unwrap()) {
+		t.Errorf("Scan(binary) = %v, want %v", got.Unwrap(), doc)
+	}
+}
+
+func TestAutoScan_ValueKeepsFormat(t *testing.T) {
+	spec := &ToolSetSpec{Name: "written"}
+	want, err := ToolSetSpecCodec().Encode(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	val, err := NewToolSetSpecValue(spec).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if data, _ := val.([]byte); !bytes.Equal(data, want) {
+		t.Errorf("Value() = %q, want %q in the configured format", val, want)
+	}
+}
+
+func TestAutoScan_CustomDetector(t *testing.T) {
+	defer func(detect func([]byte, Codec) Codec) { DetectFormat = detect }(DetectFormat)
+	DetectFormat = func(data []byte, codec Codec) Codec {
+		if bytes.HasPrefix(data, []byte("name:")) {
+			return TextDocumentCodec()
+		}
+		return dbtypesDetectFormat(data, codec)
+	}
+
+	var got ToolSetSpecValue
+	if err := got.Scan(`name: "text"`); err != nil {
+		t.Fatalf("Scan(prototext) error: %v", err)
+	}
+	if got.Unwrap().GetName() != "text" {
+		t.Errorf("Scan(prototext) = %v, want name \"text\"", got.Unwrap())
+	}
+
+	// Without detection every row is decoded in the wrapper's format
+	DetectFormat = nil
+	if err := got.Scan(`name: "text"`); err == nil {
+		t.Error("Scan(prototext) succeeded with DetectFormat = nil")
+	}
+}
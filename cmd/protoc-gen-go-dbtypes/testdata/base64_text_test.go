@@ -0,0 +1,105 @@
+package testv1
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestBase64Text_RoundTrip(t *testing.T) {
+	spec := &ToolSetSpec{ToolIds: []string{"tool-1", "tool-2"}, Name: "legacy", Enabled: true}
+	dbVal, err := NewToolSetSpecValue(spec).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	text, ok := dbVal.(string)
+	if !ok {
+		t.Fatalf("Value() = %T, want string", dbVal)
+	}
+	if _, err := base64.StdEncoding.DecodeString(text); err != nil {
+		t.Errorf("Value() = %q is not base64: %v", text, err)
+	}
+
+	var got ToolSetSpecValue
+	if err := got.Scan(text); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(got.Unwrap(), spec) {
+		t.Errorf("Scan() = %v, want %v", got.Unwrap(), spec)
+	}
+
+	var null NullToolSetSpecValue
+	if err := null.Scan(text); err != nil {
+		t.Fatalf("Null Scan() error: %v", err)
+	}
+	if !null.Valid || !proto.Equal(null.ToolSetSpecValue.Unwrap(), spec) {
+		t.Errorf("Null Scan() = %v, want %v", null.ToolSetSpecValue.Unwrap(), spec)
+	}
+}
+
+// An existing row written by a legacy service: the plain wire format of
+// ToolSetSpec{ToolIds: ["tool-1"], Name: "legacy"}, base64-encoded. Scan
+// reads it with compress=gzip too, which inflates only gzip data.
+const legacyRow = "CgZ0b29sLTESBmxlZ2FjeQ=="
+
+func TestBase64Text_ScanExisting(t *testing.T) {
+	var got ToolSetSpecValue
+	if err := got.Scan(legacyRow); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if want := (&ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "legacy"}); !proto.Equal(got.Unwrap(), want) {
+		t.Errorf("Scan() = %v, want %v", got.Unwrap(), want)
+	}
+}
+
+func TestBase64Text_RawBytes(t *testing.T) {
+	spec := &ToolSetSpec{Name: "raw"}
+	raw, err := NewToolSetSpecValue(spec).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error: %v", err)
+	}
+
+	var got ToolSetSpecValue
+	if err := got.Scan(raw); err != nil {
+		t.Fatalf("Scan([]byte) error: %v", err)
+	}
+	if !proto.Equal(got.Unwrap(), spec) {
+		t.Errorf("Scan([]byte) = %v, want %v", got.Unwrap(), spec)
+	}
+}
+
+func TestBase64Text_InvalidBase64(t *testing.T) {
+	var got ToolSetSpecValue
+	err := got.Scan("not base64!")
+	if err == nil {
+		t.Fatal("Scan() of invalid base64 should fail")
+	}
+	var corrupt base64.CorruptInputError
+	if !errors.As(err, &corrupt) {
+		t.Errorf("Scan() error = %v, want a base64.CorruptInputError", err)
+	}
+	if !strings.Contains(err.Error(), "ToolSetSpec") || !strings.Contains(err.Error(), "base64") {
+		t.Errorf("Scan() error = %q, want it to name the message and base64", err)
+	}
+}
+
+func TestBase64Text_TextFormatsUnchanged(t *testing.T) {
+	dbVal, err := NewJSONDocumentValue(&JSONDocument{Id: "d1"}).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	data, ok := dbVal.([]byte)
+	if !ok {
+		t.Fatalf("JSON Value() = %T, want []byte", dbVal)
+	}
+	var got JSONDocumentValue
+	if err := got.Scan(string(data)); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if got.Unwrap().GetId() != "d1" {
+		t.Errorf("Scan() id = %q, want d1", got.Unwrap().GetId())
+	}
+}
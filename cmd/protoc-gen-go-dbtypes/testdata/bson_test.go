@@ -0,0 +1,86 @@
+//go:build dbtypes_bson
+
+package testv1
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	_ bson.Marshaler   = ToolSetSpecValue{}
+	_ bson.Unmarshaler = (*ToolSetSpecValue)(nil)
+)
+
+func TestBSON_RoundTrip(t *testing.T) {
+	spec := &ToolSetSpec{ToolIds: []string{"a", "b"}, Name: "tools", Enabled: true}
+	prefs := &UserPreferences{Theme: "dark", Settings: map[string]string{"tz": "UTC"}}
+	container := &Container{
+		Id:    "c1",
+		Spec:  &ToolSetSpec{Name: "nested"},
+		Items: []*Container_Item{{Key: "k", Value: "v"}},
+	}
+
+	type record struct {
+		Spec      *ToolSetSpecValue     `bson:"spec"`
+		Prefs     *UserPreferencesValue `bson:"prefs"`
+		Container *ContainerValue       `bson:"container"`
+	}
+	data, err := bson.Marshal(record{
+		Spec:      NewToolSetSpecValue(spec),
+		Prefs:     NewUserPreferencesValue(prefs),
+		Container: NewContainerValue(container),
+	})
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v", err)
+	}
+
+	// Fields are stored as subdocuments that can be queried
+	if name, err := bson.Raw(data).LookupErr("spec", "name"); err != nil || name.StringValue() != "tools" {
+		t.Errorf("spec.name = %v, %v; want tools", name, err)
+	}
+
+	var got record
+	if err := bson.Unmarshal(data, &got); err != nil {
+		t.Fatalf("bson.Unmarshal() error = %v", err)
+	}
+	for _, pair := range [][2]proto.Message{
+		{got.Spec.Unwrap(), spec},
+		{got.Prefs.Unwrap(), prefs},
+		{got.Container.Unwrap(), container},
+	} {
+		if !proto.Equal(pair[0], pair[1]) {
+			t.Errorf("round trip = %v, want %v", pair[0], pair[1])
+		}
+	}
+}
+
+func TestBSON_Document(t *testing.T) {
+	// Top-level documents carry an _id, which is ignored
+	data, err := bson.Marshal(bson.D{{Key: "_id", Value: bson.NewObjectID()}, {Key: "name", Value: "doc"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v ToolSetSpecValue
+	if err := v.UnmarshalBSON(data); err != nil {
+		t.Fatalf("UnmarshalBSON() error = %v", err)
+	}
+	if v.Unwrap().GetName() != "doc" {
+		t.Errorf("UnmarshalBSON() = %v, want name doc", v.Unwrap())
+	}
+
+	// A nil message is an empty document
+	empty, err := ToolSetSpecValue{}.MarshalBSON()
+	if err != nil {
+		t.Fatalf("MarshalBSON() error = %v", err)
+	}
+	if elems, err := bson.Raw(empty).Elements(); err != nil || len(elems) != 0 {
+		t.Errorf("MarshalBSON(nil) = %v, %v; want an empty document", bson.Raw(empty), err)
+	}
+
+	if err := v.UnmarshalBSON([]byte{1, 2, 3}); err == nil {
+		t.Error("UnmarshalBSON() accepted a malformed document")
+	}
+}
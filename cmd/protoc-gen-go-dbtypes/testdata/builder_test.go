@@ -0,0 +1,44 @@
+package testv1
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestBuilder_ToolSetSpecRoundTrip(t *testing.T) {
+	wrapper := NewToolSetSpecValueBuilder().
+		SetToolIds([]string{"tool-1", "tool-2"}).
+		SetName("fixture").
+		SetEnabled(true).
+		Build()
+
+	want := &ToolSetSpec{ToolIds: []string{"tool-1", "tool-2"}, Name: "fixture", Enabled: true}
+	if !proto.Equal(want, wrapper.Unwrap()) {
+		t.Fatalf("Build() = %v, want %v", wrapper.Unwrap(), want)
+	}
+
+	dbVal, err := wrapper.Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	var got ToolSetSpecValue
+	if err := got.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(want, got.Unwrap()) {
+		t.Errorf("round-trip failed:\ngot:  %v\nwant: %v", got.Unwrap(), want)
+	}
+}
+
+func TestBuilder_OneofAndPresence(t *testing.T) {
+	payload := NewPayloadValueBuilder().SetId("p").SetCount(0).Build().Unwrap()
+	if c, ok := payload.GetContent().(*Payload_Count); !ok || c.Count != 0 {
+		t.Errorf("SetCount(0) content = %#v, want the count member selected", payload.GetContent())
+	}
+
+	record := NewLegacyRecordValueBuilder().SetId("r").SetArchived(false).SetState(LegacyState_LEGACY_STATE_RETIRED).Build().Unwrap()
+	if record.Archived == nil || record.GetState() != LegacyState_LEGACY_STATE_RETIRED {
+		t.Errorf("Build() = %v, want archived set to false and state RETIRED", record)
+	}
+}
@@ -0,0 +1,78 @@
+package testv1
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// checksumKey is the context key carrying the checksum column of a row.
+type checksumKey struct{}
+
+func withExpectedChecksum(t *testing.T) {
+	t.Helper()
+	ExpectedChecksum = func(ctx context.Context, _ protoreflect.FullName) []byte {
+		sum, _ := ctx.Value(checksumKey{}).([]byte)
+		return sum
+	}
+	t.Cleanup(func() { ExpectedChecksum = nil })
+}
+
+func TestChecksum_RoundTrip(t *testing.T) {
+	withExpectedChecksum(t)
+
+	spec := &ToolSetSpec{ToolIds: []string{"tool-1", "tool-2"}, Name: "tools"}
+	wrapper := NewToolSetSpecValue(spec)
+	stored, err := wrapper.Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	sum := wrapper.Checksum()
+	if len(sum) == 0 {
+		t.Fatal("Checksum() returned no checksum for a set message")
+	}
+	if again := NewToolSetSpecValue(proto.Clone(spec).(*ToolSetSpec)).Checksum(); !bytes.Equal(again, sum) {
+		t.Errorf("Checksum() of an equal message = %x, want %x", again, sum)
+	}
+
+	var scanned ToolSetSpecValue
+	if err := scanned.ScanContext(context.WithValue(context.Background(), checksumKey{}, sum), stored); err != nil {
+		t.Fatalf("ScanContext() with the matching checksum error: %v", err)
+	}
+	if !proto.Equal(scanned.Unwrap(), spec) {
+		t.Errorf("scanned %v, want %v", scanned.Unwrap(), spec)
+	}
+	if got := scanned.Checksum(); !bytes.Equal(got, sum) {
+		t.Errorf("Checksum() after the round-trip = %x, want %x", got, sum)
+	}
+
+	// Without a checksum in the context the check is skipped
+	if err := scanned.Scan(stored); err != nil {
+		t.Errorf("Scan() without an expected checksum error: %v", err)
+	}
+}
+
+func TestChecksum_Mismatch(t *testing.T) {
+	withExpectedChecksum(t)
+
+	sum := NewToolSetSpecValue(&ToolSetSpec{Name: "tools"}).Checksum()
+	tampered, err := NewToolSetSpecValue(&ToolSetSpec{Name: "t00ls"}).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	var scanned ToolSetSpecValue
+	err = scanned.ScanContext(context.WithValue(context.Background(), checksumKey{}, sum), tampered)
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("ScanContext() of bytes with another checksum error = %v, want ErrChecksumMismatch", err)
+	}
+}
+
+func TestChecksum_NoMessage(t *testing.T) {
+	if sum := (&ToolSetSpecValue{}).Checksum(); sum != nil {
+		t.Errorf("Checksum() of an empty wrapper = %x, want nil", sum)
+	}
+}
@@ -0,0 +1,30 @@
+package testv1
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestColumns(t *testing.T) {
+	for _, tt := range []struct {
+		column ColumnInfo
+		msg    any
+		format string
+	}{
+		{ToolSetSpecColumn, &ToolSetSpec{}, "binary"},
+		{JSONDocumentColumn, &JSONDocument{}, "json"},
+		{TextDocumentColumn, &TextDocument{}, "text"},
+		{ContainerColumn, &Container{}, "binary"},
+	} {
+		if got, want := tt.column.GoType, fmt.Sprintf("%T", tt.msg); got != want {
+			t.Errorf("%s: GoType = %q, want %q", tt.column.Message, got, want)
+		}
+		if tt.column.Format != tt.format {
+			t.Errorf("%s: Format = %q, want %q", tt.column.Message, tt.column.Format, tt.format)
+		}
+		// Only binary messages are compressed
+		if want := tt.format == "binary"; tt.column.Compressed != want {
+			t.Errorf("%s: Compressed = %v, want %v with compress=gzip", tt.column.Message, tt.column.Compressed, want)
+		}
+	}
+}
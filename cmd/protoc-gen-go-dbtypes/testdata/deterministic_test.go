@@ -0,0 +1,28 @@
+package testv1
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestDeterministic_MapOrdering(t *testing.T) {
+	prefs := &UserPreferences{Theme: "dark", Settings: map[string]string{}}
+	for i := 0; i < 32; i++ {
+		prefs.Settings[fmt.Sprintf("key-%d", i)] = fmt.Sprintf("value-%d", i)
+	}
+
+	first, err := NewUserPreferencesValue(prefs).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		got, err := NewUserPreferencesValue(prefs).Value()
+		if err != nil {
+			t.Fatalf("Value() error: %v", err)
+		}
+		if !bytes.Equal(got.([]byte), first.([]byte)) {
+			t.Fatalf("Value() produced different bytes on iteration %d", i)
+		}
+	}
+}
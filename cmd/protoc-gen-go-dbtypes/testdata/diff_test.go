@@ -0,0 +1,62 @@
+package testv1
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDiff_ChangedFields(t *testing.T) {
+	old := &ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "tools", Enabled: true}
+	updated := &ToolSetSpec{ToolIds: []string{"tool-1", "tool-2"}, Name: "tools", Enabled: false}
+
+	want := []FieldChange{
+		{Field: "tool_ids", Old: []any{"tool-1"}, New: []any{"tool-1", "tool-2"}},
+		{Field: "enabled", Old: true, New: false},
+	}
+	if got := DiffToolSetSpec(old, updated); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffToolSetSpec() = %+v, want %+v", got, want)
+	}
+	if got := DiffToolSetSpec(old, proto.Clone(old).(*ToolSetSpec)); len(got) != 0 {
+		t.Errorf("DiffToolSetSpec() of equal messages = %+v, want no changes", got)
+	}
+}
+
+func TestDiff_Nil(t *testing.T) {
+	spec := &ToolSetSpec{Name: "tools"}
+
+	want := []FieldChange{{Field: "name", Old: nil, New: "tools"}}
+	if got := DiffToolSetSpec(nil, spec); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffToolSetSpec(nil, spec) = %+v, want %+v", got, want)
+	}
+	want = []FieldChange{{Field: "name", Old: "tools", New: nil}}
+	if got := DiffToolSetSpec(spec, nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffToolSetSpec(spec, nil) = %+v, want %+v", got, want)
+	}
+	if got := DiffToolSetSpec(nil, &ToolSetSpec{}); len(got) != 0 {
+		t.Errorf("DiffToolSetSpec(nil, empty) = %+v, want no changes", got)
+	}
+}
+
+func TestDiff_MessagesAndMaps(t *testing.T) {
+	old := &Container{Id: "c", Spec: &ToolSetSpec{Name: "old"}}
+	updated := &Container{Id: "c"}
+
+	changes := DiffContainer(old, updated)
+	if len(changes) != 1 || changes[0].Field != "spec" || changes[0].New != nil {
+		t.Fatalf("DiffContainer() = %+v, want spec cleared", changes)
+	}
+	if spec, ok := changes[0].Old.(proto.Message); !ok || !proto.Equal(spec, old.Spec) {
+		t.Errorf("Old = %v, want %v", changes[0].Old, old.Spec)
+	}
+
+	prefs := DiffUserPreferences(
+		&UserPreferences{Settings: map[string]string{"tz": "UTC"}},
+		&UserPreferences{Settings: map[string]string{"tz": "CET"}},
+	)
+	want := []FieldChange{{Field: "settings", Old: map[any]any{"tz": "UTC"}, New: map[any]any{"tz": "CET"}}}
+	if !reflect.DeepEqual(prefs, want) {
+		t.Errorf("DiffUserPreferences() = %+v, want %+v", prefs, want)
+	}
+}
@@ -0,0 +1,62 @@
+package testv1
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// A Container stored by a newer schema reads as a BinaryDocument: both have
+// an id, but items is unknown to BinaryDocument. Value writes whichever
+// format and compression the package was generated with.
+func TestDiscardUnknown_DefaultFormat(t *testing.T) {
+	dbVal, err := NewContainerValue(&Container{Id: "c1", Items: []*Container_Item{{Key: "k"}}}).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+
+	var got BinaryDocumentValue
+	if err := got.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if want := (&BinaryDocument{Id: "c1"}); !proto.Equal(got.Unwrap(), want) {
+		t.Errorf("Scan() = %v, want %v", got.Unwrap(), want)
+	}
+	if unknown := got.Unwrap().ProtoReflect().GetUnknown(); len(unknown) != 0 {
+		t.Errorf("Scan() kept unknown fields %x", unknown)
+	}
+}
+
+func TestDiscardUnknown_JSON(t *testing.T) {
+	var got JSONDocumentValue
+	if err := got.Scan(`{"id": "d1", "extra": {"nested": [1, 2]}}`); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if got.Unwrap().GetId() != "d1" {
+		t.Errorf("Scan() id = %q, want d1", got.Unwrap().GetId())
+	}
+
+	if err := got.UnmarshalJSON([]byte(`{"id": "d2", "extra": true}`)); err != nil {
+		t.Fatalf("UnmarshalJSON() error: %v", err)
+	}
+	if got.Unwrap().GetId() != "d2" {
+		t.Errorf("UnmarshalJSON() id = %q, want d2", got.Unwrap().GetId())
+	}
+}
+
+func TestDiscardUnknown_Text(t *testing.T) {
+	var got TextDocumentValue
+	if err := got.Scan(`id: "t1" extra: 1`); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if got.Unwrap().GetId() != "t1" {
+		t.Errorf("Scan() id = %q, want t1", got.Unwrap().GetId())
+	}
+
+	if err := got.UnmarshalText([]byte(`id: "t2" extra: "x"`)); err != nil {
+		t.Fatalf("UnmarshalText() error: %v", err)
+	}
+	if got.Unwrap().GetId() != "t2" {
+		t.Errorf("UnmarshalText() id = %q, want t2", got.Unwrap().GetId())
+	}
+}
@@ -0,0 +1,69 @@
+package testv1
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestEmptyAsNull_EmptyMessage(t *testing.T) {
+	val, err := NewUserPreferencesValue(&UserPreferences{}).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if val != nil {
+		t.Errorf("Value() = %v for an empty message, want NULL", val)
+	}
+
+	// NULL scans back into no message.
+	var got UserPreferencesValue
+	if err := got.Scan(val); err != nil {
+		t.Fatalf("Scan(nil) error: %v", err)
+	}
+	if got.Unwrap() != nil {
+		t.Errorf("Unwrap() = %v, want nil", got.Unwrap())
+	}
+}
+
+func TestEmptyAsNull_PopulatedMessage(t *testing.T) {
+	prefs := &UserPreferences{Settings: map[string]string{"autoSave": "true"}}
+
+	val, err := NewUserPreferencesValue(prefs).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if val == nil {
+		t.Fatal("Value() = NULL for a populated message")
+	}
+
+	var got UserPreferencesValue
+	if err := got.Scan(val); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(prefs, got.Unwrap()) {
+		t.Errorf("round-trip failed:\ngot:  %v\nwant: %v", got.Unwrap(), prefs)
+	}
+}
+
+func TestEmptyAsNull_NullWrapper(t *testing.T) {
+	val, err := NewNullUserPreferencesValue(&UserPreferences{}).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if val != nil {
+		t.Errorf("Value() = %v for a valid empty message, want NULL", val)
+	}
+}
+
+func TestEmptyAsNull_Batch(t *testing.T) {
+	batch, err := EncodeUserPreferencesBatch([]*UserPreferences{{}, {Theme: "dark"}})
+	if err != nil {
+		t.Fatalf("EncodeUserPreferencesBatch() error: %v", err)
+	}
+	if batch[0] != nil {
+		t.Errorf("entry 0 = %x for an empty message, want nil as Value stores NULL", batch[0])
+	}
+	if len(batch[1]) == 0 {
+		t.Error("entry 1 is empty for a populated message")
+	}
+}
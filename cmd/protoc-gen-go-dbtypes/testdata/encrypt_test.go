@@ -0,0 +1,84 @@
+package testv1
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// xorCipher is a trivial reversible cipher standing in for AES-GCM.
+func xorCipher(data []byte) ([]byte, error) {
+	out := make([]byte, len(data))
+	for i, b := range data {
+		out[i] = b ^ 0x5a
+	}
+	return out, nil
+}
+
+func withCiphers(t *testing.T, encrypt, decrypt func([]byte) ([]byte, error)) {
+	t.Helper()
+	EncryptCipher, DecryptCipher = encrypt, decrypt
+	t.Cleanup(func() { EncryptCipher, DecryptCipher = nil, nil })
+}
+
+func TestEncryptHooks_RoundTrip(t *testing.T) {
+	withCiphers(t, xorCipher, xorCipher)
+
+	prefs := &UserPreferences{
+		Theme:    "dark",
+		Settings: map[string]string{"token": "secret"},
+	}
+
+	dbVal, err := NewUserPreferencesValue(prefs).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+
+	// The stored bytes must not be the plain serialization
+	if bytes.Contains(dbVal.([]byte), []byte("secret")) {
+		t.Error("Value() leaked plaintext")
+	}
+
+	wrapper := &UserPreferencesValue{}
+	if err := wrapper.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(prefs, wrapper.Unwrap()) {
+		t.Errorf("round-trip failed:\ngot:  %v\nwant: %v", wrapper.Unwrap(), prefs)
+	}
+}
+
+func TestEncryptHooks_NilHooks(t *testing.T) {
+	spec := &ToolSetSpec{Name: "plain"}
+
+	// Without hooks, rows hold the plain serialization
+	data, err := proto.Marshal(spec)
+	if err != nil {
+		t.Fatalf("proto.Marshal error: %v", err)
+	}
+
+	wrapper := &ToolSetSpecValue{}
+	if err := wrapper.Scan(data); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(spec, wrapper.Unwrap()) {
+		t.Errorf("scan without hooks failed:\ngot:  %v\nwant: %v", wrapper.Unwrap(), spec)
+	}
+}
+
+func TestEncryptHooks_Errors(t *testing.T) {
+	errCipher := errors.New("cipher failed")
+
+	withCiphers(t, func([]byte) ([]byte, error) { return nil, errCipher }, nil)
+	if _, err := NewToolSetSpecValue(&ToolSetSpec{}).Value(); !errors.Is(err, errCipher) {
+		t.Errorf("Value() error = %v, want %v", err, errCipher)
+	}
+
+	withCiphers(t, nil, func([]byte) ([]byte, error) { return nil, errCipher })
+	wrapper := &ToolSetSpecValue{}
+	if err := wrapper.Scan([]byte{}); !errors.Is(err, errCipher) {
+		t.Errorf("Scan() error = %v, want %v", err, errCipher)
+	}
+}
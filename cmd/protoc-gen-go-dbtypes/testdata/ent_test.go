@@ -0,0 +1,81 @@
+//go:build dbtypes_ent
+
+package testv1
+
+import (
+	"database/sql"
+	"testing"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	_ field.TypeValueScanner[*ToolSetSpec]  = ToolSetSpecValueScanner{}
+	_ field.TypeValueScanner[*JSONDocument] = JSONDocumentValueScanner{}
+)
+
+// Agent is an example ent schema storing protobuf messages in fields.
+type Agent struct {
+	ent.Schema
+}
+
+// Fields of the Agent.
+func (Agent) Fields() []ent.Field {
+	return []ent.Field{
+		field.Bytes("spec").
+			GoType(&ToolSetSpec{}).
+			ValueScanner(ToolSetSpecValueScanner{}),
+		field.Bytes("document").
+			GoType(&JSONDocument{}).
+			ValueScanner(JSONDocumentValueScanner{}).
+			Optional(),
+	}
+}
+
+func TestEnt_SchemaFields(t *testing.T) {
+	for _, f := range (Agent{}).Fields() {
+		if d := f.Descriptor(); d.Err != nil {
+			t.Errorf("field %s: %v", d.Name, d.Err)
+		}
+	}
+}
+
+func TestEnt_RoundTrip(t *testing.T) {
+	spec := &ToolSetSpec{Name: "toolset", ToolIds: []string{"a", "b"}, Enabled: true}
+
+	var vs ToolSetSpecValueScanner
+	v, err := vs.Value(spec)
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+
+	scanned := vs.ScanValue()
+	if err := scanned.Scan(v); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	got, err := vs.FromValue(scanned)
+	if err != nil {
+		t.Fatalf("FromValue() error = %v", err)
+	}
+	if !proto.Equal(got, spec) {
+		t.Errorf("FromValue() = %v, want %v", got, spec)
+	}
+}
+
+func TestEnt_Null(t *testing.T) {
+	var vs ToolSetSpecValueScanner
+	if v, err := vs.Value(nil); err != nil || v != nil {
+		t.Errorf("Value(nil) = %v, %v; want nil, nil", v, err)
+	}
+
+	got, err := vs.FromValue(&sql.NullString{})
+	if err != nil || got != nil {
+		t.Errorf("FromValue(NULL) = %v, %v; want nil, nil", got, err)
+	}
+
+	if _, err := vs.FromValue("not a scanner"); err == nil {
+		t.Error("FromValue() should reject unexpected input")
+	}
+}
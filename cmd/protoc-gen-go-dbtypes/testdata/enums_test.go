@@ -0,0 +1,92 @@
+package testv1
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+func TestEnums_RoundTrip(t *testing.T) {
+	for _, p := range []Priority{Priority_PRIORITY_UNSPECIFIED, Priority_PRIORITY_HIGH, Priority(42), Priority(-7)} {
+		v, err := NewPriorityValue(p).Value()
+		if err != nil {
+			t.Fatalf("Value() error = %v", err)
+		}
+		if v != int64(p) {
+			t.Errorf("Value() = %#v, want int64(%d)", v, p)
+		}
+		if !driver.IsValue(v) {
+			t.Errorf("Value() = %T, which drivers don't accept", v)
+		}
+		var got PriorityValue
+		if err := got.Scan(v); err != nil {
+			t.Fatalf("Scan(%v) error = %v", v, err)
+		}
+		if got.Unwrap() != p {
+			t.Errorf("Scan(%v) = %v, want %v", v, got.Unwrap(), p)
+		}
+	}
+}
+
+func TestEnums_ScanSources(t *testing.T) {
+	for _, src := range []any{int64(2), int32(2), []byte("2"), "2"} {
+		var got PriorityValue
+		if err := got.Scan(src); err != nil {
+			t.Errorf("Scan(%#v) error = %v", src, err)
+			continue
+		}
+		if got.Enum != Priority_PRIORITY_HIGH {
+			t.Errorf("Scan(%#v) = %v, want PRIORITY_HIGH", src, got.Enum)
+		}
+	}
+
+	got := PriorityValue{Enum: Priority_PRIORITY_LOW}
+	if err := got.Scan(nil); err != nil || got.Enum != Priority_PRIORITY_UNSPECIFIED {
+		t.Errorf("Scan(nil) = %v, %v; want PRIORITY_UNSPECIFIED", got.Enum, err)
+	}
+}
+
+func TestEnums_UnknownNumber(t *testing.T) {
+	var got PriorityValue
+	if err := got.Scan([]byte("42")); err != nil {
+		t.Fatalf("Scan(42) error = %v", err)
+	}
+	if got.String() != "42" {
+		t.Errorf("String() = %q, want \"42\"", got.String())
+	}
+	v, err := got.Value()
+	if err != nil || v != int64(42) {
+		t.Errorf("Value() = %#v, %v; want int64(42)", v, err)
+	}
+}
+
+func TestEnums_ScanErrors(t *testing.T) {
+	var got PriorityValue
+	if err := got.Scan(1.5); !errors.Is(err, ErrInvalidScanType) {
+		t.Errorf("Scan(float64) error = %v, want ErrInvalidScanType", err)
+	}
+	for _, src := range []any{int64(1) << 40, "high", []byte("99999999999")} {
+		if err := got.Scan(src); err == nil {
+			t.Errorf("Scan(%#v) succeeded, want an error", src)
+		}
+	}
+}
+
+func TestEnums_DatabaseValue(t *testing.T) {
+	if got := Priority_PRIORITY_HIGH.DatabaseValue(); got.Enum != Priority_PRIORITY_HIGH || got.String() != "PRIORITY_HIGH" {
+		t.Errorf("DatabaseValue() = %v", got)
+	}
+}
+
+func TestEnums_Closed(t *testing.T) {
+	// LegacyState is declared in a proto2 file, so its enum is closed
+	var got LegacyStateValue
+	for _, src := range []any{int64(7), "7", []byte("-1")} {
+		if err := got.Scan(src); err == nil {
+			t.Errorf("Scan(%#v) succeeded, want an error for an undeclared number", src)
+		}
+	}
+	if err := got.Scan(int64(2)); err != nil || got.Enum != LegacyState_LEGACY_STATE_RETIRED {
+		t.Errorf("Scan(2) = %v, %v; want LEGACY_STATE_RETIRED", got.Enum, err)
+	}
+}
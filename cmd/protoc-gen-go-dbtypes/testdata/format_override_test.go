@@ -0,0 +1,57 @@
+package testv1
+
+import (
+	"testing"
+
+	"github.com/cadenya/protoc-gen-go-dbtypes/dbtypes"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestFormatOverride_CBOR(t *testing.T) {
+	doc := &CBORDocument{Name: "doc", Count: 3}
+	val, err := NewCBORDocumentValue(doc).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+
+	// The package default is binary, but the message is stored as CBOR
+	stored := &CBORDocument{}
+	if err := dbtypes.UnmarshalCBOR(val.([]byte), stored); err != nil {
+		t.Fatalf("dbtypes.UnmarshalCBOR() error: %v", err)
+	}
+	if !proto.Equal(stored, doc) {
+		t.Errorf("stored %v, want %v", stored, doc)
+	}
+
+	var scanned CBORDocumentValue
+	if err := scanned.Scan(val); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(scanned.Unwrap(), doc) {
+		t.Errorf("Scan() = %v, want %v", scanned.Unwrap(), doc)
+	}
+}
+
+func TestFormatOverride_Msgpack(t *testing.T) {
+	doc := &MsgpackDocument{Name: "doc", Count: 3}
+	val, err := NewMsgpackDocumentValue(doc).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+
+	stored := &MsgpackDocument{}
+	if err := dbtypes.UnmarshalMsgpack(val.([]byte), stored); err != nil {
+		t.Fatalf("dbtypes.UnmarshalMsgpack() error: %v", err)
+	}
+	if !proto.Equal(stored, doc) {
+		t.Errorf("stored %v, want %v", stored, doc)
+	}
+
+	var scanned MsgpackDocumentValue
+	if err := scanned.Scan(val); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(scanned.Unwrap(), doc) {
+		t.Errorf("Scan() = %v, want %v", scanned.Unwrap(), doc)
+	}
+}
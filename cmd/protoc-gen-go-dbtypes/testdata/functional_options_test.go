@@ -0,0 +1,183 @@
+package testv1
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestFunctionalOptions_Default(t *testing.T) {
+	spec := &ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "default"}
+	w := NewToolSetSpecValue(spec)
+	if w.Unwrap() != spec {
+		t.Error("NewToolSetSpecValue(spec) should wrap spec itself")
+	}
+	got, err := w.Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	want, err := ToolSetSpecCodec().Encode(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.([]byte), want) {
+		t.Errorf("Value() = %q, want %q in the format of the message type", got, want)
+	}
+}
+
+func TestFunctionalOptions_WithClone(t *testing.T) {
+	spec := &ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "original"}
+	w := NewToolSetSpecValue(spec, WithClone())
+
+	spec.Name = "changed"
+	spec.ToolIds[0] = "tool-2"
+	if got := w.Unwrap(); got.GetName() != "original" || got.GetToolIds()[0] != "tool-1" {
+		t.Errorf("Unwrap() = %v after changing the source, want the message as it was", got)
+	}
+}
+
+func TestFunctionalOptions_WithFormat(t *testing.T) {
+	spec := &ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "json"}
+	w := NewToolSetSpecValue(spec, WithFormat(FormatJSON))
+
+	val, err := w.Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if !json.Valid(val.([]byte)) {
+		t.Fatalf("Value() = %q, want protojson", val)
+	}
+
+	// The wrapper scans the format it writes
+	if err := w.Scan(val); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(spec, w.Unwrap()) {
+		t.Errorf("Scan() = %v, want %v", w.Unwrap(), spec)
+	}
+	data, err := w.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error: %v", err)
+	}
+	if err := w.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary() error: %v", err)
+	}
+	if !proto.Equal(spec, w.Unwrap()) {
+		t.Errorf("UnmarshalBinary() = %v, want %v", w.Unwrap(), spec)
+	}
+	if again, err := w.Clone().Value(); err != nil || !bytes.Equal(again.([]byte), val.([]byte)) {
+		t.Errorf("Clone().Value() = %q, %v; want %q", again, err, val)
+	}
+
+	// So does a NullXxxValue holding it
+	n := NullToolSetSpecValue{ToolSetSpecValue: *w, Valid: true}
+	if nullVal, err := n.Value(); err != nil || !json.Valid(nullVal.([]byte)) {
+		t.Errorf("NullToolSetSpecValue.Value() = %q, %v; want protojson", nullVal, err)
+	}
+
+	// A plain wrapper keeps the binary format of ToolSetSpec
+	var plain ToolSetSpecValue
+	if err := plain.Scan(val); err == nil {
+		t.Error("Scan(protojson) into a binary wrapper succeeded")
+	}
+}
+
+func TestFunctionalOptions_WithFormatRedacts(t *testing.T) {
+	acct := &ServiceAccount{Name: "ci", ApiToken: "secret-api-token"}
+	val, err := NewServiceAccountValue(acct, WithFormat(FormatJSON)).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if data, _ := val.([]byte); bytes.Contains(data, []byte("secret-api-token")) || !bytes.Contains(data, []byte("ci")) {
+		t.Errorf("Value() = %s, want JSON without the redacted token", val)
+	}
+	if acct.GetApiToken() != "secret-api-token" {
+		t.Error("Value() cleared the token of the caller's message")
+	}
+}
+
+// tenantCodec stands in for a codec encrypting with the key of one tenant.
+type tenantCodec struct {
+	key byte
+}
+
+func (c tenantCodec) Encode(msg proto.Message) ([]byte, error) {
+	data, err := ToolSetSpecCodec().Encode(msg)
+	for i := range data {
+		data[i] ^= c.key
+	}
+	return data, err
+}
+
+func (c tenantCodec) Decode(data []byte, msg proto.Message) error {
+	plain := make([]byte, len(data))
+	for i, b := range data {
+		plain[i] = b ^ c.key
+	}
+	return ToolSetSpecCodec().Decode(plain, msg)
+}
+
+func TestFunctionalOptions_WithCodec(t *testing.T) {
+	spec := &ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "tenant"}
+	a := NewToolSetSpecValue(spec, WithCodec(tenantCodec{key: 0x5a}))
+	b := NewToolSetSpecValue(spec, WithCodec(tenantCodec{key: 0x3c}))
+
+	valA, err := a.Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	valB, err := b.Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if bytes.Equal(valA.([]byte), valB.([]byte)) {
+		t.Error("wrappers with different codecs stored the same bytes")
+	}
+	if plain, _ := NewToolSetSpecValue(spec).Value(); bytes.Equal(plain.([]byte), valA.([]byte)) {
+		t.Error("WithCodec stored what the codec of the message type does")
+	}
+
+	// Each wrapper scans what it writes
+	for key, val := range map[byte]any{0x5a: valA, 0x3c: valB} {
+		scanned := NewToolSetSpecValue(&ToolSetSpec{}, WithCodec(tenantCodec{key: key}))
+		if err := scanned.Scan(val); err != nil {
+			t.Fatalf("key %#x: Scan() error: %v", key, err)
+		}
+		if !proto.Equal(scanned.Unwrap(), spec) {
+			t.Errorf("key %#x: Scan() = %v, want %v", key, scanned.Unwrap(), spec)
+		}
+	}
+
+	if w := NewToolSetSpecValue(spec, WithCodec(nil)); w.ProtoValue.codec != nil {
+		t.Error("WithCodec(nil) should keep the codec of the message type")
+	}
+}
+
+func TestFunctionalOptions_NilMessageKeepsCodec(t *testing.T) {
+	spec := &ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "tenant"}
+	codec := tenantCodec{key: 0x5a}
+	val, err := NewToolSetSpecValue(spec, WithCodec(codec)).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+
+	// A wrapper built for scanning, with no message yet, still stores NULL
+	scanned := NewToolSetSpecValue(nil, WithCodec(codec))
+	if empty, err := scanned.Value(); empty != nil || err != nil {
+		t.Errorf("Value() of an empty wrapper = %v, %v; want nil, nil", empty, err)
+	}
+
+	// but decodes with the codec it was given
+	if err := scanned.Scan(val); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(scanned.Unwrap(), spec) {
+		t.Errorf("Scan() = %v, want %v", scanned.Unwrap(), spec)
+	}
+
+	if w := NewToolSetSpecValue(nil, WithClone()); w.ProtoValue != nil {
+		t.Errorf("NewToolSetSpecValue(nil, WithClone()) = %v, want an empty wrapper", w)
+	}
+}
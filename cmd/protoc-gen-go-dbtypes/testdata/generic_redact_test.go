@@ -0,0 +1,80 @@
+package testv1
+
+import (
+	"bytes"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// storedServiceAccount decodes what a generic ServiceAccountValue stored.
+func storedServiceAccount(t *testing.T, v any) *ServiceAccount {
+	t.Helper()
+	data, ok := v.([]byte)
+	if !ok {
+		t.Fatalf("stored value = %T, want []byte", v)
+	}
+	msg := &ServiceAccount{}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		t.Fatalf("proto.Unmarshal() error: %v", err)
+	}
+	return msg
+}
+
+func TestGenericRedact_NotStored(t *testing.T) {
+	acct := &ServiceAccount{Name: "ci", ApiToken: "secret-api-token", RecoveryCodes: []string{"recovery-1"}}
+	want := &ServiceAccount{Name: "ci"}
+
+	v, err := NewServiceAccountValue(acct).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if got := storedServiceAccount(t, v); !proto.Equal(got, want) {
+		t.Errorf("Value() stored %v, want %v", got, want)
+	}
+
+	v, err = NewNullServiceAccountValue(acct).Value()
+	if err != nil {
+		t.Fatalf("NullServiceAccountValue.Value() error: %v", err)
+	}
+	if got := storedServiceAccount(t, v); !proto.Equal(got, want) {
+		t.Errorf("NullServiceAccountValue.Value() stored %v, want %v", got, want)
+	}
+
+	v, err = NewServiceAccountValue(acct).ValueMasked(&fieldmaskpb.FieldMask{Paths: []string{"name", "api_token"}})
+	if err != nil {
+		t.Fatalf("ValueMasked() error: %v", err)
+	}
+	if got := storedServiceAccount(t, v); !proto.Equal(got, want) {
+		t.Errorf("ValueMasked() stored %v, want %v", got, want)
+	}
+
+	values, err := ServiceAccountValues([]*ServiceAccount{acct})
+	if err != nil {
+		t.Fatalf("ServiceAccountValues() error: %v", err)
+	}
+	if got := storedServiceAccount(t, values[0]); !proto.Equal(got, want) {
+		t.Errorf("ServiceAccountValues() stored %v, want %v", got, want)
+	}
+
+	data, err := NewServiceAccountValue(acct).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error: %v", err)
+	}
+	if got := storedServiceAccount(t, data); !proto.Equal(got, want) {
+		t.Errorf("MarshalBinary() encoded %v, want %v", got, want)
+	}
+
+	batch, err := EncodeServiceAccountBatch([]*ServiceAccount{acct})
+	if err != nil {
+		t.Fatalf("EncodeServiceAccountBatch() error: %v", err)
+	}
+	if bytes.Contains(batch[0], []byte("secret-api-token")) {
+		t.Errorf("EncodeServiceAccountBatch() = %q, want the redacted fields left out", batch[0])
+	}
+
+	if acct.ApiToken == "" || len(acct.RecoveryCodes) == 0 {
+		t.Errorf("encoding cleared the caller's redacted fields: %v", acct)
+	}
+}
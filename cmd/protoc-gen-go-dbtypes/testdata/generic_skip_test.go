@@ -0,0 +1,56 @@
+package testv1
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGenericSkip_NotStored(t *testing.T) {
+	cache := &ProfileCache{UserId: "u1", Biography: "long biography"}
+	want := &ProfileCache{UserId: "u1"}
+
+	v, err := NewProfileCacheValue(cache).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	stored := &ProfileCache{}
+	if err := proto.Unmarshal(v.([]byte), stored); err != nil {
+		t.Fatalf("proto.Unmarshal() error: %v", err)
+	}
+	if !proto.Equal(stored, want) {
+		t.Errorf("Value() stored %v, want %v", stored, want)
+	}
+
+	values, err := ProfileCacheValues([]*ProfileCache{cache})
+	if err != nil {
+		t.Fatalf("ProfileCacheValues() error: %v", err)
+	}
+	stored = &ProfileCache{}
+	if err := proto.Unmarshal(values[0].([]byte), stored); err != nil {
+		t.Fatalf("proto.Unmarshal() error: %v", err)
+	}
+	if !proto.Equal(stored, want) {
+		t.Errorf("ProfileCacheValues() stored %v, want %v", stored, want)
+	}
+
+	if cache.Biography == "" {
+		t.Errorf("encoding cleared the caller's skipped field: %v", cache)
+	}
+}
+
+func TestGenericSkip_ScanOldRow(t *testing.T) {
+	// A row written before biography was marked (dbtypes.skip)
+	old, err := proto.Marshal(&ProfileCache{UserId: "u1", Biography: "long biography"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var scanned ProfileCacheValue
+	if err := scanned.Scan(old); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if want := (&ProfileCache{UserId: "u1"}); !proto.Equal(scanned.Unwrap(), want) {
+		t.Errorf("Scan() = %v, want %v", scanned.Unwrap(), want)
+	}
+}
@@ -0,0 +1,46 @@
+package testv1
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/cadenya/protoc-gen-go-dbtypes/dbtypes"
+)
+
+func TestGlobalRegistry_RoundTrip(t *testing.T) {
+	spec := &ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "registered", Enabled: true}
+
+	w, ok := dbtypes.NewWrapper("test.v1.ToolSetSpec")
+	if !ok {
+		t.Fatal("NewWrapper(test.v1.ToolSetSpec) found no wrapper after importing the package")
+	}
+	if _, ok := w.(*ToolSetSpecValue); !ok {
+		t.Fatalf("NewWrapper() = %T, want *ToolSetSpecValue", w)
+	}
+	val, err := NewToolSetSpecValue(spec).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if err := w.Scan(val); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(w.ProtoMessage(), spec) {
+		t.Errorf("ProtoMessage() = %v, want %v", w.ProtoMessage(), spec)
+	}
+	stored, err := w.Value()
+	if err != nil {
+		t.Fatalf("Value() of the registered wrapper error: %v", err)
+	}
+	var back ToolSetSpecValue
+	if err := back.Scan(stored); err != nil || !proto.Equal(back.Unwrap(), spec) {
+		t.Errorf("round-trip = %v, %v; want %v", back.Unwrap(), err, spec)
+	}
+
+	// Messages of every file of the package are registered
+	for name := range DBTypeRegistry {
+		if _, ok := dbtypes.NewWrapper(name); !ok {
+			t.Errorf("NewWrapper(%s) found no wrapper", name)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+//go:build dbtypes_gorm
+
+package testv1
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+var (
+	_ interface {
+		GormDBDataType(*gorm.DB, *schema.Field) string
+	} = (*ToolSetSpecValue)(nil)
+	_ gorm.Valuer = (*JSONDocumentValue)(nil)
+)
+
+// fakeDialector reports a dialect name; no other method is exercised.
+type fakeDialector struct {
+	gorm.Dialector
+	name string
+}
+
+func (d fakeDialector) Name() string { return d.name }
+
+func dbFor(dialect string) *gorm.DB {
+	return &gorm.DB{Config: &gorm.Config{Dialector: fakeDialector{name: dialect}}}
+}
+
+func TestGorm_DBDataType(t *testing.T) {
+	tests := []struct {
+		dialect    string
+		binary     string
+		jsonColumn string
+	}{
+		{"postgres", "bytea", "jsonb"},
+		{"mysql", "blob", "json"},
+		{"sqlite", "blob", "text"},
+	}
+	for _, tt := range tests {
+		db := dbFor(tt.dialect)
+		if got := (&BinaryDocumentValue{}).GormDBDataType(db, nil); got != tt.binary {
+			t.Errorf("%s: BinaryDocumentValue column = %q, want %q", tt.dialect, got, tt.binary)
+		}
+		if got := (&JSONDocumentValue{}).GormDBDataType(db, nil); got != tt.jsonColumn {
+			t.Errorf("%s: JSONDocumentValue column = %q, want %q", tt.dialect, got, tt.jsonColumn)
+		}
+	}
+}
+
+func TestGorm_Value(t *testing.T) {
+	doc := NewJSONDocumentValue(&JSONDocument{Id: "doc-1"})
+
+	expr := doc.GormValue(context.Background(), dbFor("postgres"))
+	if expr.SQL != "?" || len(expr.Vars) != 1 {
+		t.Fatalf("GormValue() = %+v, want a single bound parameter", expr)
+	}
+	if _, ok := expr.Vars[0].(string); !ok {
+		t.Errorf("GormValue() binds %T, want string", expr.Vars[0])
+	}
+
+	expr = doc.GormValue(context.Background(), dbFor("mysql"))
+	if expr.SQL != "CAST(? AS JSON)" {
+		t.Errorf("GormValue() SQL = %q, want CAST for mysql", expr.SQL)
+	}
+
+	var _ clause.Expr = expr
+}
@@ -0,0 +1,136 @@
+package testv1
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"sync"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGzip_ValueIsCompressed(t *testing.T) {
+	dbVal, err := NewToolSetSpecValue(&ToolSetSpec{Name: "compressed"}).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if !bytes.HasPrefix(dbVal.([]byte), []byte{0x1f, 0x8b}) {
+		t.Errorf("Value() = %x, want gzip payload", dbVal)
+	}
+}
+
+func TestGzip_LargeContainerShrinks(t *testing.T) {
+	container := &Container{Id: "big"}
+	for i := 0; i < 500; i++ {
+		container.Items = append(container.Items, &Container_Item{
+			Key:   fmt.Sprintf("key-%d", i),
+			Value: "a fairly repetitive value",
+		})
+	}
+
+	dbVal, err := NewContainerValue(container).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if got, raw := len(dbVal.([]byte)), proto.Size(container); got >= raw {
+		t.Errorf("compressed size %d, want less than raw size %d", got, raw)
+	}
+
+	wrapper := &ContainerValue{}
+	if err := wrapper.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(container, wrapper.Unwrap()) {
+		t.Error("round-trip of compressed container failed")
+	}
+}
+
+func TestGzip_ScanUncompressedRow(t *testing.T) {
+	spec := &ToolSetSpec{ToolIds: []string{"legacy"}, Name: "pre-compression"}
+
+	// Rows written before compression was enabled hold plain protobuf
+	data, err := proto.Marshal(spec)
+	if err != nil {
+		t.Fatalf("proto.Marshal error: %v", err)
+	}
+
+	wrapper := &ToolSetSpecValue{}
+	if err := wrapper.Scan(data); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(spec, wrapper.Unwrap()) {
+		t.Errorf("scan of uncompressed row failed:\ngot:  %v\nwant: %v", wrapper.Unwrap(), spec)
+	}
+}
+
+func TestGzip_Level(t *testing.T) {
+	defer func(level int) { GzipLevel = level }(GzipLevel)
+	GzipLevel = gzip.BestSpeed
+
+	spec := &ToolSetSpec{Name: "fast"}
+	dbVal, err := NewToolSetSpecValue(spec).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+
+	wrapper := &ToolSetSpecValue{}
+	if err := wrapper.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(spec, wrapper.Unwrap()) {
+		t.Error("round-trip at BestSpeed failed")
+	}
+
+	// An invalid level surfaces as a Value error
+	GzipLevel = 42
+	if _, err := NewToolSetSpecValue(spec).Value(); err == nil {
+		t.Error("Value() with invalid GzipLevel should fail")
+	}
+}
+
+func TestGzip_ValueReusesWriters(t *testing.T) {
+	v := NewToolSetSpecValue(&ToolSetSpec{ToolIds: []string{"tool-1", "tool-2"}, Name: "pooled"})
+	if _, err := v.Value(); err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+
+	// Only the returned slice and its interface conversion should allocate;
+	// a fresh gzip.Writer costs dozens of allocations.
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := v.Value(); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs > 4 {
+		t.Errorf("Value() allocates %v times per call, want pooled gzip state", allocs)
+	}
+}
+
+func TestGzip_ValueConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				spec := &ToolSetSpec{Name: fmt.Sprintf("spec-%d-%d", i, j)}
+				dbVal, err := NewToolSetSpecValue(spec).Value()
+				if err != nil {
+					t.Errorf("Value() error: %v", err)
+					return
+				}
+				got := &ToolSetSpecValue{}
+				if err := got.Scan(dbVal); err != nil {
+					t.Errorf("Scan() error: %v", err)
+					return
+				}
+				if !proto.Equal(spec, got.Unwrap()) {
+					t.Errorf("round-trip failed:\ngot:  %v\nwant: %v", got.Unwrap(), spec)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
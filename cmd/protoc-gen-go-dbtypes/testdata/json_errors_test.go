@@ -0,0 +1,26 @@
+package testv1
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+)
+
+func TestJSONErrors_InvalidUTF8(t *testing.T) {
+	for _, tt := range []struct {
+		value driver.Valuer
+		want  string
+	}{
+		{NewToolSetSpecValue(&ToolSetSpec{Name: "bad \xff"}), "dbtypes: ToolSetSpec: field name: "},
+		{NewToolSetSpecValue(&ToolSetSpec{ToolIds: []string{"ok", "\xff"}}), "dbtypes: ToolSetSpec: field tool_ids[1]: "},
+		{NewContainerValue(&Container{Items: []*Container_Item{{Key: "k"}, {Value: "\xff"}}}), "dbtypes: Container: field items[1].value: "},
+		{NewContainerValue(&Container{Spec: &ToolSetSpec{Name: "\xff"}}), "dbtypes: Container: field spec.name: "},
+		{NewUserPreferencesValue(&UserPreferences{Settings: map[string]string{"tz": "\xff"}}), `dbtypes: UserPreferences: field settings["tz"]: `},
+		{NewUserPreferencesValue(&UserPreferences{Settings: map[string]string{"\xff": "UTC"}}), `dbtypes: UserPreferences: field settings["\xff"]: `},
+	} {
+		_, err := tt.value.Value()
+		if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+			t.Errorf("Value() error = %v, want it to start with %q", err, tt.want)
+		}
+	}
+}
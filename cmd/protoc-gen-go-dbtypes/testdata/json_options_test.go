@@ -0,0 +1,48 @@
+package testv1
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJSONOptions_EmitUnpopulated(t *testing.T) {
+	dbVal, err := NewJSONDocumentValue(&JSONDocument{}).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(dbVal.([]byte), &fields); err != nil {
+		t.Fatalf("stored value is not JSON: %v", err)
+	}
+	if len(fields) != 2 || fields["id"] != "" {
+		t.Errorf("Value() = %s, want every field with its zero value", dbVal)
+	}
+
+	// The stored value scans back
+	var got JSONDocumentValue
+	if err := got.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+}
+
+func TestJSONOptions_UseProtoNames(t *testing.T) {
+	data, err := json.Marshal(NewToolSetSpecValue(&ToolSetSpec{ToolIds: []string{"a"}}))
+	if err != nil {
+		t.Fatalf("MarshalJSON() error: %v", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := fields["tool_ids"]; !ok {
+		t.Errorf("MarshalJSON() = %s, want the field named tool_ids", data)
+	}
+	if _, ok := fields["enabled"]; !ok {
+		t.Errorf("MarshalJSON() = %s, want the unpopulated field enabled", data)
+	}
+
+	var got ToolSetSpecValue
+	if err := json.Unmarshal(data, &got); err != nil || len(got.Unwrap().GetToolIds()) != 1 {
+		t.Errorf("UnmarshalJSON() = %v, %v; want the tool ids back", got.Unwrap(), err)
+	}
+}
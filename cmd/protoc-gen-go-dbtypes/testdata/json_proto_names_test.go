@@ -0,0 +1,39 @@
+package testv1
+
+import (
+	"bytes"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestJSONProtoNames_RoundTrip(t *testing.T) {
+	spec := &ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "tools"}
+	dbVal, err := NewToolSetSpecValue(spec).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	data := dbVal.([]byte)
+	if !bytes.Contains(data, []byte(`"tool_ids"`)) || bytes.Contains(data, []byte(`"toolIds"`)) {
+		t.Errorf("Value() = %s, want the key tool_ids", data)
+	}
+
+	var got ToolSetSpecValue
+	if err := got.Scan(data); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(got.Unwrap(), spec) {
+		t.Errorf("round-trip failed:\ngot:  %v\nwant: %v", got.Unwrap(), spec)
+	}
+}
+
+func TestJSONProtoNames_ScansCamelCase(t *testing.T) {
+	// Rows written before the option was set keep reading
+	var got ToolSetSpecValue
+	if err := got.Scan(`{"toolIds": ["tool-1"]}`); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if ids := got.Unwrap().GetToolIds(); len(ids) != 1 || ids[0] != "tool-1" {
+		t.Errorf("Scan() tool ids = %q, want [tool-1]", ids)
+	}
+}
@@ -0,0 +1,129 @@
+package testv1
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// columnarMagic marks values written by the custom format below.
+var columnarMagic = []byte("COL1")
+
+// useColumnar swaps in a custom format, which prefixes the default encoding
+// with columnarMagic, and counts the calls. The hooks are restored when the
+// test ends.
+func useColumnar(t *testing.T) (marshals, unmarshals *int) {
+	marshals, unmarshals = new(int), new(int)
+	defaultMarshal, defaultUnmarshal := Marshal, Unmarshal
+	t.Cleanup(func() { Marshal, Unmarshal = defaultMarshal, defaultUnmarshal })
+
+	Marshal = func(m proto.Message) ([]byte, error) {
+		*marshals++
+		data, err := defaultMarshal(m)
+		if err != nil {
+			return nil, err
+		}
+		return append(append([]byte(nil), columnarMagic...), data...), nil
+	}
+	Unmarshal = func(data []byte, m proto.Message) error {
+		*unmarshals++
+		rest, ok := bytes.CutPrefix(data, columnarMagic)
+		if !ok {
+			return errors.New("not a columnar value")
+		}
+		return defaultUnmarshal(rest, m)
+	}
+	return marshals, unmarshals
+}
+
+func TestMarshalHooks_ValueAndScan(t *testing.T) {
+	marshals, unmarshals := useColumnar(t)
+
+	spec := &ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "columnar"}
+	dbVal, err := NewToolSetSpecValue(spec).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if *marshals != 1 {
+		t.Errorf("Value() called Marshal %d times, want 1", *marshals)
+	}
+
+	var got ToolSetSpecValue
+	if err := got.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if *unmarshals != 1 {
+		t.Errorf("Scan() called Unmarshal %d times, want 1", *unmarshals)
+	}
+	if !proto.Equal(spec, got.Unwrap()) {
+		t.Errorf("round-trip failed:\ngot:  %v\nwant: %v", got.Unwrap(), spec)
+	}
+
+	// A value in the default format no longer scans
+	raw, err := proto.Marshal(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decodeErr *DecodeError
+	if err := got.Scan(raw); !errors.As(err, &decodeErr) {
+		t.Errorf("Scan(default format) error = %v, want a *DecodeError from the hook", err)
+	}
+}
+
+func TestMarshalHooks_BinaryMethods(t *testing.T) {
+	marshals, unmarshals := useColumnar(t)
+
+	data, err := NewToolSetSpecValue(&ToolSetSpec{Name: "binary"}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error: %v", err)
+	}
+	var got ToolSetSpecValue
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary() error: %v", err)
+	}
+	if *marshals != 1 || *unmarshals != 1 || got.Unwrap().GetName() != "binary" {
+		t.Errorf("binary round-trip = %v with %d marshals and %d unmarshals, want the message through one of each", got.Unwrap(), *marshals, *unmarshals)
+	}
+}
+
+func TestMarshalHooks_FormatOption(t *testing.T) {
+	marshals, unmarshals := useColumnar(t)
+
+	// JSONDocument sets (dbtypes.format) = JSON, so it keeps protojson
+	dbVal, err := NewJSONDocumentValue(&JSONDocument{Id: "doc-1"}).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if !json.Valid(dbVal.([]byte)) {
+		t.Errorf("Value() = %q, want protojson", dbVal)
+	}
+	var got JSONDocumentValue
+	if err := got.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if *marshals != 0 || *unmarshals != 0 {
+		t.Errorf("JSONDocument used the hooks: %d marshals, %d unmarshals", *marshals, *unmarshals)
+	}
+}
+
+func TestMarshalHooks_MessageTooLarge(t *testing.T) {
+	defer func(limit int) { dbtypesMaxMessageSize = limit }(dbtypesMaxMessageSize)
+	defaultMarshal := Marshal
+	defer func() { Marshal = defaultMarshal }()
+
+	// Stand in for a message past the wire limit without allocating 2 GiB
+	dbtypesMaxMessageSize = 16
+	Marshal = func(proto.Message) ([]byte, error) { return make([]byte, 17), nil }
+
+	_, err := NewToolSetSpecValue(&ToolSetSpec{Name: "oversized"}).Value()
+	if !errors.Is(err, ErrMessageTooLarge) {
+		t.Fatalf("Value() error = %v, want ErrMessageTooLarge", err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "ToolSetSpec") || !strings.Contains(msg, "2 GiB") {
+		t.Errorf("Value() error = %q, want the message type and the wire limit", msg)
+	}
+}
@@ -0,0 +1,34 @@
+package testv1
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMaxScanSize_LimitsInflatedSize(t *testing.T) {
+	// A small compressed row that inflates far beyond the limit
+	blob, err := proto.Marshal(&ToolSetSpec{Name: string(bytes.Repeat([]byte{'x'}, 100_000))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(blob); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() > MaxScanSize {
+		t.Fatalf("compressed test row is %d bytes, want at most %d", buf.Len(), MaxScanSize)
+	}
+
+	var got ToolSetSpecValue
+	if err := got.Scan(buf.Bytes()); !errors.Is(err, ErrScanTooLarge) {
+		t.Errorf("Scan() error = %v, want ErrScanTooLarge", err)
+	}
+}
@@ -0,0 +1,95 @@
+package testv1
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMaxScanSize_RejectsOversized(t *testing.T) {
+	if MaxScanSize != 1024 {
+		t.Fatalf("MaxScanSize = %d, want the generated 1024", MaxScanSize)
+	}
+	blob, err := proto.Marshal(&ToolSetSpec{Name: strings.Repeat("x", 2000)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, src := range map[string]any{
+		"bytes":  blob,
+		"string": string(blob),
+		"reader": bytes.NewReader(blob),
+	} {
+		var got ToolSetSpecValue
+		err := got.Scan(src)
+		if !errors.Is(err, ErrScanTooLarge) {
+			t.Errorf("%s: Scan() error = %v, want ErrScanTooLarge", name, err)
+		}
+	}
+
+	// ReadFrom stops reading one byte past the limit
+	var got ToolSetSpecValue
+	n, err := got.ReadFrom(bytes.NewReader(blob))
+	if !errors.Is(err, ErrScanTooLarge) || n != int64(MaxScanSize)+1 {
+		t.Errorf("ReadFrom() = %d, %v; want %d, ErrScanTooLarge", n, err, MaxScanSize+1)
+	}
+}
+
+func TestMaxScanSize_AcceptsLimit(t *testing.T) {
+	spec := &ToolSetSpec{Name: "small"}
+	blob, err := proto.Marshal(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Pad with an unknown field so the source is exactly at the limit
+	pad := MaxScanSize - len(blob) - 3
+	blob = protoAppendBytesField(blob, 15, bytes.Repeat([]byte{'p'}, pad))
+	if len(blob) != MaxScanSize {
+		t.Fatalf("test blob is %d bytes, want %d", len(blob), MaxScanSize)
+	}
+
+	for name, src := range map[string]any{
+		"bytes":  blob,
+		"reader": bytes.NewReader(blob),
+	} {
+		var got ToolSetSpecValue
+		if err := got.Scan(src); err != nil {
+			t.Fatalf("%s: Scan() error: %v", name, err)
+		}
+		if got.Unwrap().GetName() != "small" {
+			t.Errorf("%s: Scan() = %v", name, got.Unwrap())
+		}
+	}
+}
+
+func TestMaxScanSize_Runtime(t *testing.T) {
+	t.Cleanup(func() { MaxScanSize = 1024 })
+	blob, err := proto.Marshal(&ToolSetSpec{Name: strings.Repeat("x", 2000)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	MaxScanSize = 0
+	var got ToolSetSpecValue
+	if err := got.Scan(blob); err != nil {
+		t.Errorf("Scan() with no limit error: %v", err)
+	}
+
+	MaxScanSize = 8
+	if err := got.Scan([]byte{0x12, 0x01, 'x'}); err != nil {
+		t.Errorf("Scan() under a lowered limit error: %v", err)
+	}
+	if err := got.Scan(blob[:16]); !errors.Is(err, ErrScanTooLarge) {
+		t.Errorf("Scan() over a lowered limit error = %v, want ErrScanTooLarge", err)
+	}
+}
+
+// protoAppendBytesField appends a length-delimited field with a two-byte
+// length to b.
+func protoAppendBytesField(b []byte, num int, v []byte) []byte {
+	b = append(b, byte(num<<3|2), byte(len(v)&0x7f|0x80), byte(len(v)>>7))
+	return append(b, v...)
+}
@@ -0,0 +1,31 @@
+package testv1
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMaxScanSize_LimitsZstdInflatedSize(t *testing.T) {
+	// A small compressed row that inflates far beyond the limit
+	blob, err := proto.Marshal(&ToolSetSpec{Name: string(bytes.Repeat([]byte{'x'}, 100_000))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compressed := enc.EncodeAll(blob, nil)
+	if len(compressed) > MaxScanSize {
+		t.Fatalf("compressed test row is %d bytes, want at most %d", len(compressed), MaxScanSize)
+	}
+
+	var got ToolSetSpecValue
+	if err := got.Scan(compressed); !errors.Is(err, ErrScanTooLarge) {
+		t.Errorf("Scan() error = %v, want ErrScanTooLarge", err)
+	}
+}
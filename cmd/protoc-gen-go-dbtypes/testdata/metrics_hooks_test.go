@@ -0,0 +1,98 @@
+package testv1
+
+import (
+	"errors"
+	"testing"
+)
+
+// metric is one call to a metrics hook.
+type metric struct {
+	typeName string
+	size     int
+	err      error
+}
+
+// recordMetrics installs metrics hooks that append to the returned slices.
+// The hooks are cleared when the test ends.
+func recordMetrics(t *testing.T) (values, scans *[]metric) {
+	values, scans = new([]metric), new([]metric)
+	t.Cleanup(func() { OnValue, OnValueError, OnScan, OnScanError = nil, nil, nil, nil })
+
+	OnValue = func(typeName string, size int) { *values = append(*values, metric{typeName: typeName, size: size}) }
+	OnValueError = func(typeName string, err error) { *values = append(*values, metric{typeName: typeName, err: err}) }
+	OnScan = func(typeName string, size int) { *scans = append(*scans, metric{typeName: typeName, size: size}) }
+	OnScanError = func(typeName string, err error) { *scans = append(*scans, metric{typeName: typeName, err: err}) }
+	return values, scans
+}
+
+// storedSize is the length of a value as the driver receives it.
+func storedSize(t *testing.T, v any) int {
+	t.Helper()
+	data, ok := v.([]byte)
+	if !ok {
+		t.Fatalf("Value() = %T, want []byte", v)
+	}
+	return len(data)
+}
+
+func TestMetricsHooks_ValueAndScan(t *testing.T) {
+	values, scans := recordMetrics(t)
+
+	dbVal, err := NewToolSetSpecValue(&ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "metrics"}).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	want := metric{typeName: "test.v1.ToolSetSpec", size: storedSize(t, dbVal)}
+	if len(*values) != 1 || (*values)[0] != want {
+		t.Errorf("OnValue calls = %+v, want [%+v]", *values, want)
+	}
+
+	var got ToolSetSpecValue
+	if err := got.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if len(*scans) != 1 || (*scans)[0] != want {
+		t.Errorf("OnScan calls = %+v, want [%+v]", *scans, want)
+	}
+}
+
+func TestMetricsHooks_FormatOption(t *testing.T) {
+	values, scans := recordMetrics(t)
+
+	dbVal, err := NewJSONDocumentValue(&JSONDocument{Id: "d1"}).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	var got JSONDocumentValue
+	if err := got.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	want := metric{typeName: "test.v1.JSONDocument", size: storedSize(t, dbVal)}
+	if len(*values) != 1 || (*values)[0] != want {
+		t.Errorf("OnValue calls = %+v, want [%+v]", *values, want)
+	}
+	if len(*scans) != 1 || (*scans)[0] != want {
+		t.Errorf("OnScan calls = %+v, want [%+v]", *scans, want)
+	}
+}
+
+func TestMetricsHooks_ScanError(t *testing.T) {
+	_, scans := recordMetrics(t)
+
+	var got ToolSetSpecValue
+	err := got.Scan([]byte{0xff, 0xff, 0xff})
+	if err == nil {
+		t.Fatal("Scan() of corrupt data should fail")
+	}
+	if len(*scans) != 1 || (*scans)[0].typeName != "test.v1.ToolSetSpec" || !errors.Is((*scans)[0].err, err) {
+		t.Errorf("OnScanError calls = %+v, want one for test.v1.ToolSetSpec with %v", *scans, err)
+	}
+
+	*scans = nil
+	if err := got.Scan(nil); err != nil {
+		t.Fatalf("Scan(nil) error: %v", err)
+	}
+	if len(*scans) != 0 {
+		t.Errorf("Scan(nil) should not be reported, got %+v", *scans)
+	}
+}
@@ -0,0 +1,64 @@
+package testv1
+
+import (
+	"encoding/json"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestConvert_BinaryToJSON(t *testing.T) {
+	spec := &ToolSetSpec{ToolIds: []string{"tool-1", "tool-2"}, Name: "migrated", Enabled: true}
+	blob, err := proto.Marshal(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	converted, err := ConvertToolSetSpec(blob)
+	if err != nil {
+		t.Fatalf("ConvertToolSetSpec() error: %v", err)
+	}
+	if !json.Valid(converted) {
+		t.Fatalf("ConvertToolSetSpec() = %q, want JSON", converted)
+	}
+	got := &ToolSetSpec{}
+	if err := protojson.Unmarshal(converted, got); err != nil {
+		t.Fatalf("protojson.Unmarshal() error: %v", err)
+	}
+	if !proto.Equal(spec, got) {
+		t.Errorf("converted message differs:\ngot:  %v\nwant: %v", got, spec)
+	}
+
+	// The result is what a JSON-format wrapper scans
+	var wrapper JSONDocumentValue
+	doc, err := ConvertJSONDocument(mustMarshal(t, &JSONDocument{Id: "doc"}))
+	if err != nil {
+		t.Fatalf("ConvertJSONDocument() error: %v", err)
+	}
+	if err := wrapper.Scan(doc); err != nil || wrapper.Unwrap().GetId() != "doc" {
+		t.Errorf("Scan(converted) = %v, message %v", err, wrapper.Unwrap())
+	}
+}
+
+func TestConvert_Null(t *testing.T) {
+	converted, err := ConvertToolSetSpec(nil)
+	if err != nil || converted != nil {
+		t.Errorf("ConvertToolSetSpec(nil) = %q, %v; want nil, nil", converted, err)
+	}
+}
+
+func TestConvert_Corrupt(t *testing.T) {
+	if _, err := ConvertToolSetSpec([]byte{0xff, 0xff}); err == nil {
+		t.Error("ConvertToolSetSpec() of corrupt bytes should fail")
+	}
+}
+
+func mustMarshal(t *testing.T, m proto.Message) []byte {
+	t.Helper()
+	data, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
@@ -0,0 +1,29 @@
+package testv1
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNested_RoundTrip(t *testing.T) {
+	item := NewContainer_ItemValue(&Container_Item{Key: "k1", Value: "v1"})
+	dbVal, err := item.Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+
+	var scanned Container_ItemValue
+	if err := scanned.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(scanned.Unwrap(), item.Unwrap()) {
+		t.Errorf("round-trip failed:\ngot:  %v\nwant: %v", scanned.Unwrap(), item.Unwrap())
+	}
+
+	// Null wrappers are generated for nested messages too
+	var null NullContainer_ItemValue
+	if err := null.Scan(nil); err != nil || null.Valid {
+		t.Errorf("Scan(nil) = %v, Valid = %v; want a NULL value", err, null.Valid)
+	}
+}
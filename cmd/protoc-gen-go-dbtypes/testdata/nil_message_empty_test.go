@@ -0,0 +1,25 @@
+package testv1
+
+import "testing"
+
+func TestNilMessageEmpty_NilWrapper(t *testing.T) {
+	val, err := (&UserPreferencesValue{}).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if val != nil {
+		t.Errorf("Value() = %v for a nil wrapper, want NULL", val)
+	}
+}
+
+func TestNilMessageEmpty_NilMessage(t *testing.T) {
+	x := &UserPreferencesValue{ProtoValue: &ProtoValue[*UserPreferences]{}}
+	val, err := x.Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	data, ok := val.([]byte)
+	if !ok || len(data) != 0 {
+		t.Errorf("Value() = %#v for a nil message, want empty bytes", val)
+	}
+}
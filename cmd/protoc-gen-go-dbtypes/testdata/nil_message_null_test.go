@@ -0,0 +1,34 @@
+package testv1
+
+import "testing"
+
+func TestNilMessageNull_NilWrapper(t *testing.T) {
+	val, err := (&UserPreferencesValue{}).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if val != nil {
+		t.Errorf("Value() = %v for a nil wrapper, want NULL", val)
+	}
+}
+
+func TestNilMessageNull_NilMessage(t *testing.T) {
+	x := &UserPreferencesValue{ProtoValue: &ProtoValue[*UserPreferences]{}}
+	val, err := x.Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if val != nil {
+		t.Errorf("Value() = %v for a nil message, want NULL", val)
+	}
+}
+
+func TestNilMessageNull_EmptyMessage(t *testing.T) {
+	val, err := NewUserPreferencesValue(&UserPreferences{}).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if val == nil {
+		t.Error("Value() = NULL for an empty message, want empty bytes")
+	}
+}
@@ -0,0 +1,77 @@
+package testv1
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type observeKey struct{}
+
+type observation struct {
+	op   string
+	name string
+	ctx  any
+	err  error
+}
+
+func observe(t *testing.T) *[]observation {
+	t.Helper()
+	var got []observation
+	saved := ObserveSerialization
+	t.Cleanup(func() { ObserveSerialization = saved })
+	ObserveSerialization = func(ctx context.Context, op string, msg proto.Message, elapsed time.Duration, err error) {
+		if elapsed < 0 {
+			t.Errorf("%s: negative elapsed time %v", op, elapsed)
+		}
+		got = append(got, observation{op, string(msg.ProtoReflect().Descriptor().Name()), ctx.Value(observeKey{}), err})
+	}
+	return &got
+}
+
+func TestObserveSerialization(t *testing.T) {
+	got := observe(t)
+	ctx := context.WithValue(context.Background(), observeKey{}, "span")
+
+	data, err := NewToolSetSpecValue(&ToolSetSpec{Name: "observed"}).ValueContext(ctx)
+	if err != nil {
+		t.Fatalf("ValueContext() error = %v", err)
+	}
+	if err := (&ToolSetSpecValue{}).ScanContext(ctx, data); err != nil {
+		t.Fatalf("ScanContext() error = %v", err)
+	}
+	scanErr := (&ToolSetSpecValue{}).Scan([]byte{0xff})
+
+	want := []observation{
+		{"value", "ToolSetSpec", "span", nil},
+		{"scan", "ToolSetSpec", "span", nil},
+		{"scan", "ToolSetSpec", nil, scanErr},
+	}
+	if scanErr == nil {
+		t.Fatal("Scan() of invalid data should fail")
+	}
+	if len(*got) != len(want) {
+		t.Fatalf("observed %+v, want %+v", *got, want)
+	}
+	for i := range want {
+		if (*got)[i] != want[i] {
+			t.Errorf("observation %d = %+v, want %+v", i, (*got)[i], want[i])
+		}
+	}
+}
+
+func TestObserveSerialization_CancelledNotObserved(t *testing.T) {
+	got := observe(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := NewToolSetSpecValue(&ToolSetSpec{}).ValueContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("ValueContext() error = %v, want %v", err, context.Canceled)
+	}
+	if len(*got) != 0 {
+		t.Errorf("a cancelled call should do no work, observed %+v", *got)
+	}
+}
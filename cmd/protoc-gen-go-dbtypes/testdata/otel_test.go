@@ -0,0 +1,99 @@
+//go:build dbtypes_otel
+
+package testv1
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// eventAttributes returns the attributes of a recorded event by key.
+func eventAttributes(ev sdktrace.Event) map[string]attribute.Value {
+	attrs := make(map[string]attribute.Value)
+	for _, kv := range ev.Attributes {
+		attrs[string(kv.Key)] = kv.Value
+	}
+	return attrs
+}
+
+// useTracer sets Tracer to one recording into the returned recorder for the
+// duration of the test.
+func useTracer(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	restore := Tracer
+	t.Cleanup(func() { Tracer = restore })
+	Tracer = provider.Tracer("dbtypes-test")
+	return recorder
+}
+
+func TestOTel_SpanEvents(t *testing.T) {
+	recorder := useTracer(t)
+	spec := &ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "traced"}
+
+	ctx, span := Tracer.Start(context.Background(), "query")
+	val, err := NewToolSetSpecValue(spec).ValueContext(ctx)
+	if err != nil {
+		t.Fatalf("ValueContext() error: %v", err)
+	}
+	var scanned ToolSetSpecValue
+	if err := scanned.ScanContext(ctx, val); err != nil {
+		t.Fatalf("ScanContext() error: %v", err)
+	}
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, want the caller's span only", len(spans))
+	}
+	events := spans[0].Events()
+	if len(events) != 2 {
+		t.Fatalf("recorded %d events, want one per operation", len(events))
+	}
+	size := int64(len(val.([]byte)))
+	for i, name := range []string{"dbtypes.value", "dbtypes.scan"} {
+		if events[i].Name != name {
+			t.Errorf("event %d = %q, want %q", i, events[i].Name, name)
+		}
+		attrs := eventAttributes(events[i])
+		if got := attrs["dbtypes.message"].AsString(); got != "test.v1.ToolSetSpec" {
+			t.Errorf("%s: dbtypes.message = %q, want test.v1.ToolSetSpec", name, got)
+		}
+		if got := attrs["dbtypes.size"].AsInt64(); got != size {
+			t.Errorf("%s: dbtypes.size = %d, want %d", name, got, size)
+		}
+	}
+}
+
+func TestOTel_OwnSpan(t *testing.T) {
+	recorder := useTracer(t)
+
+	// Without a recording span in the context the event gets a span of its own
+	if _, err := NewToolSetSpecValue(&ToolSetSpec{Name: "untraced"}).Value(); err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "dbtypes.value" || len(spans[0].Events()) != 1 {
+		t.Fatalf("recorded %v, want one dbtypes.value span with its event", spans)
+	}
+}
+
+func TestOTel_NoTracer(t *testing.T) {
+	recorder := useTracer(t)
+	tracer := Tracer
+	Tracer = nil
+
+	ctx, span := tracer.Start(context.Background(), "query")
+	if _, err := NewToolSetSpecValue(&ToolSetSpec{Name: "off"}).ValueContext(ctx); err != nil {
+		t.Fatalf("ValueContext() error: %v", err)
+	}
+	span.End()
+	if events := recorder.Ended()[0].Events(); len(events) != 0 {
+		t.Errorf("recorded %d events with Tracer = nil, want none", len(events))
+	}
+}
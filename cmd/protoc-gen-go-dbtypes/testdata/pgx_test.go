@@ -0,0 +1,130 @@
+//go:build dbtypes_pgx
+
+package testv1
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPgx_RoundTrip(t *testing.T) {
+	m := pgtype.NewMap()
+	Register(m)
+
+	tests := []struct {
+		pgType string
+		msg    proto.Message
+		empty  func() proto.Message
+	}{
+		{"bytea", &ToolSetSpec{Name: "tools", ToolIds: []string{"a", "b"}, Enabled: true}, func() proto.Message { return &ToolSetSpec{} }},
+		{"jsonb", &JSONDocument{Id: "doc", Labels: map[string]string{"k": "v"}}, func() proto.Message { return &JSONDocument{} }},
+		{"json", &JSONDocument{Id: "doc"}, func() proto.Message { return &JSONDocument{} }},
+		{"text", &TextDocument{Id: "doc", Tags: []string{"x"}}, func() proto.Message { return &TextDocument{} }},
+	}
+	for _, tt := range tests {
+		typ, ok := m.TypeForName(tt.pgType)
+		if !ok {
+			t.Fatalf("no %s type", tt.pgType)
+		}
+		for _, format := range []int16{pgtype.TextFormatCode, pgtype.BinaryFormatCode} {
+			buf, err := m.Encode(typ.OID, format, tt.msg, nil)
+			if err != nil {
+				t.Fatalf("Encode(%s, %d) error = %v", tt.pgType, format, err)
+			}
+			got := tt.empty()
+			if err := m.Scan(typ.OID, format, buf, got); err != nil {
+				t.Fatalf("Scan(%s, %d) error = %v", tt.pgType, format, err)
+			}
+			if !proto.Equal(got, tt.msg) {
+				t.Errorf("%s format %d round trip = %v, want %v", tt.pgType, format, got, tt.msg)
+			}
+		}
+	}
+}
+
+func TestPgx_MatchesValue(t *testing.T) {
+	m := pgtype.NewMap()
+	Register(m)
+
+	msg := &ToolSetSpec{Name: "tools", ToolIds: []string{"a"}}
+	buf, err := m.Encode(pgtype.ByteaOID, pgtype.BinaryFormatCode, msg, nil)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	v, err := NewToolSetSpecValue(msg).Value()
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+	if string(buf) != string(v.([]byte)) {
+		t.Errorf("Encode() = %x, want the bytes Value returns (%x)", buf, v)
+	}
+}
+
+func TestPgx_Null(t *testing.T) {
+	m := pgtype.NewMap()
+	Register(m)
+
+	buf, err := m.Encode(pgtype.ByteaOID, pgtype.BinaryFormatCode, (*ToolSetSpec)(nil), nil)
+	if err != nil || buf != nil {
+		t.Fatalf("Encode(nil) = %v, %v; want NULL", buf, err)
+	}
+
+	got := &ToolSetSpec{Name: "stale"}
+	if err := m.Scan(pgtype.ByteaOID, pgtype.BinaryFormatCode, nil, &got); err != nil {
+		t.Fatalf("Scan(NULL) into **ToolSetSpec error = %v", err)
+	}
+	if got != nil {
+		t.Errorf("Scan(NULL) = %v, want nil", got)
+	}
+	if err := m.Scan(pgtype.ByteaOID, pgtype.BinaryFormatCode, nil, &ToolSetSpec{}); err == nil {
+		t.Error("Scan(NULL) into *ToolSetSpec should fail")
+	}
+
+	data, _ := proto.Marshal(&ToolSetSpec{Name: "fresh"})
+	if err := m.Scan(pgtype.ByteaOID, pgtype.BinaryFormatCode, data, &got); err != nil {
+		t.Fatalf("Scan() into **ToolSetSpec error = %v", err)
+	}
+	if got.GetName() != "fresh" {
+		t.Errorf("Scan() = %v, want name fresh", got)
+	}
+}
+
+func TestPgx_KeepsOtherTypes(t *testing.T) {
+	m := pgtype.NewMap()
+	Register(m)
+
+	buf, err := m.Encode(pgtype.ByteaOID, pgtype.BinaryFormatCode, []byte("raw"), nil)
+	if err != nil {
+		t.Fatalf("Encode([]byte) error = %v", err)
+	}
+	var got []byte
+	if err := m.Scan(pgtype.ByteaOID, pgtype.BinaryFormatCode, buf, &got); err != nil || string(got) != "raw" {
+		t.Errorf("bytea round trip = %q, %v; want raw", got, err)
+	}
+
+	var s string
+	if err := m.Scan(pgtype.TextOID, pgtype.TextFormatCode, []byte("plain"), &s); err != nil || s != "plain" {
+		t.Errorf("text scan = %q, %v; want plain", s, err)
+	}
+}
+
+func TestPgx_DefaultTypes(t *testing.T) {
+	m := pgtype.NewMap()
+	Register(m)
+
+	for _, tt := range []struct {
+		value any
+		want  string
+	}{
+		{&ToolSetSpec{}, "bytea"},
+		{&JSONDocument{}, "jsonb"},
+		{&TextDocument{}, "text"},
+	} {
+		typ, ok := m.TypeForValue(tt.value)
+		if !ok || typ.Name != tt.want {
+			t.Errorf("TypeForValue(%T) = %v, want %s", tt.value, typ, tt.want)
+		}
+	}
+}
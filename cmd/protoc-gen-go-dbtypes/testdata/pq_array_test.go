@@ -0,0 +1,105 @@
+//go:build dbtypes_pq
+
+package testv1
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPQArray_RoundTrip(t *testing.T) {
+	a := ToolSetSpecValueArray{
+		*NewToolSetSpecValue(&ToolSetSpec{Name: "first", ToolIds: []string{"a"}}),
+		*NewToolSetSpecValue(&ToolSetSpec{Name: "second", Enabled: true}),
+	}
+
+	v, err := a.Value()
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+	if s, _ := v.(string); !strings.HasPrefix(s, `{"\\x`) {
+		t.Fatalf("Value() = %#v, want a bytea[] literal", v)
+	}
+
+	for _, src := range []any{v, []byte(v.(string))} {
+		var got ToolSetSpecValueArray
+		if err := got.Scan(src); err != nil {
+			t.Fatalf("Scan(%T) error = %v", src, err)
+		}
+		if len(got) != len(a) {
+			t.Fatalf("Scan(%T) = %d elements, want %d", src, len(got), len(a))
+		}
+		for i := range a {
+			if !got[i].Equal(&a[i]) {
+				t.Errorf("Scan(%T)[%d] = %v, want %v", src, i, got[i], a[i])
+			}
+		}
+	}
+}
+
+func TestPQArray_ScanLiteral(t *testing.T) {
+	// The text form PostgreSQL returns for a bytea[] column.
+	spec := &ToolSetSpec{Name: "literal"}
+	data, err := proto.Marshal(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	literal := `{"\\x` + hex.EncodeToString(data) + `","\\x"}`
+
+	var got ToolSetSpecValueArray
+	if err := got.Scan(literal); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(got) != 2 || !proto.Equal(got[0].Unwrap(), spec) || !proto.Equal(got[1].Unwrap(), &ToolSetSpec{}) {
+		t.Errorf("Scan(%s) = %v", literal, got)
+	}
+}
+
+func TestPQArray_TextFormat(t *testing.T) {
+	a := JSONDocumentValueArray{
+		*NewJSONDocumentValue(&JSONDocument{Id: "doc-1", Labels: map[string]string{"k": "v, \"quoted\""}}),
+		{}, // nil element
+	}
+
+	v, err := a.Value()
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+	var got JSONDocumentValueArray
+	if err := got.Scan(v); err != nil {
+		t.Fatalf("Scan(%v) error = %v", v, err)
+	}
+	if len(got) != 2 || !got[0].Equal(&a[0]) {
+		t.Fatalf("Scan(%v) = %v, want %v", v, got, a)
+	}
+	if !proto.Equal(got[1].Unwrap(), &JSONDocument{}) {
+		t.Errorf("nil element scanned as %v, want an empty message", got[1].Unwrap())
+	}
+}
+
+func TestPQArray_Null(t *testing.T) {
+	if v, err := ToolSetSpecValueArray(nil).Value(); v != nil || err != nil {
+		t.Errorf("nil array Value() = %v, %v; want nil, nil", v, err)
+	}
+
+	a := ToolSetSpecValueArray{*NewToolSetSpecValue(&ToolSetSpec{})}
+	if err := a.Scan(nil); err != nil || a != nil {
+		t.Errorf("Scan(nil) = %v, %v; want a nil array", a, err)
+	}
+	if err := a.Scan("{}"); err != nil || a == nil || len(a) != 0 {
+		t.Errorf("Scan({}) = %#v, %v; want an empty array", a, err)
+	}
+}
+
+func TestPQArray_ScanErrors(t *testing.T) {
+	var a ToolSetSpecValueArray
+	if err := a.Scan(`{"\\xff"}`); err == nil || !strings.Contains(err.Error(), "array element 0") {
+		t.Errorf("Scan(corrupt element) error = %v, want the element index", err)
+	}
+	if err := a.Scan("not an array"); err == nil || !strings.HasPrefix(err.Error(), "dbtypes: ToolSetSpec array: ") {
+		t.Errorf("Scan(not an array) error = %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package testv1
+
+import (
+	"bytes"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// futureFields encodes fields that only a newer schema of UserPreferences
+// declares.
+func futureFields() []byte {
+	var b []byte
+	b = protowire.AppendTag(b, 99, protowire.BytesType)
+	b = protowire.AppendString(b, "added later")
+	b = protowire.AppendTag(b, 100, protowire.VarintType)
+	return protowire.AppendVarint(b, 7)
+}
+
+// futureRow is a row written by that newer schema, with map entries in the
+// deterministic order and the new fields last, as proto.Marshal writes them.
+func futureRow(t *testing.T) []byte {
+	t.Helper()
+	known, err := proto.MarshalOptions{Deterministic: true}.Marshal(&UserPreferences{
+		Theme:    "dark",
+		Settings: map[string]string{"b": "2", "a": "1", "c": "3"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(known, futureFields()...)
+}
+
+func TestPreserveUnknown_ScanValueRoundTrip(t *testing.T) {
+	row := futureRow(t)
+
+	var prefs UserPreferencesValue
+	if err := prefs.Scan(row); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	dbVal, err := prefs.Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if !bytes.Equal(dbVal.([]byte), row) {
+		t.Errorf("Value() = %x, want the scanned bytes %x", dbVal, row)
+	}
+}
+
+func TestPreserveUnknown_ReadModifyWrite(t *testing.T) {
+	var prefs UserPreferencesValue
+	if err := prefs.Scan(futureRow(t)); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	prefs.Unwrap().Theme = "light"
+	dbVal, err := prefs.Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+
+	saved := &UserPreferences{}
+	if err := proto.Unmarshal(dbVal.([]byte), saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.GetTheme() != "light" {
+		t.Errorf("saved theme = %q, want light", saved.GetTheme())
+	}
+	if got := saved.ProtoReflect().GetUnknown(); !bytes.Equal(got, futureFields()) {
+		t.Errorf("saved unknown fields = %x, want %x", got, futureFields())
+	}
+}
+
+func TestPreserveUnknown_Binary(t *testing.T) {
+	row := futureRow(t)
+
+	var prefs UserPreferencesValue
+	if err := prefs.UnmarshalBinary(row); err != nil {
+		t.Fatalf("UnmarshalBinary() error: %v", err)
+	}
+	got, err := prefs.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error: %v", err)
+	}
+	if !bytes.Equal(got, row) {
+		t.Errorf("MarshalBinary() = %x, want the unmarshaled bytes %x", got, row)
+	}
+
+	var null NullUserPreferencesValue
+	if err := null.Scan(row); err != nil {
+		t.Fatalf("Null Scan() error: %v", err)
+	}
+	dbVal, err := null.Value()
+	if err != nil {
+		t.Fatalf("Null Value() error: %v", err)
+	}
+	if !bytes.Equal(dbVal.([]byte), row) {
+		t.Errorf("Null Value() = %x, want the scanned bytes %x", dbVal, row)
+	}
+}
@@ -0,0 +1,111 @@
+package testv1
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeConn is a database/sql driver connection recording the statements it
+// is given. The value of the last insert is the row queries return.
+type fakeConn struct {
+	queries []string
+	args    [][]driver.NamedValue
+	row     driver.Value
+}
+
+func (c *fakeConn) Connect(context.Context) (driver.Conn, error) { return c, nil }
+func (c *fakeConn) Driver() driver.Driver                        { return nil }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.queries, c.args = append(c.queries, query), append(c.args, args)
+	c.row = args[0].Value
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.queries, c.args = append(c.queries, query), append(c.args, args)
+	return &fakeRows{row: c.row}, nil
+}
+
+// fakeRows returns row, if non-nil, as the only row of a one-column result.
+type fakeRows struct {
+	row driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return []string{"spec"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.row == nil {
+		return io.EOF
+	}
+	dest[0], r.row = r.row, nil
+	return nil
+}
+
+func TestStore_InsertGet(t *testing.T) {
+	conn := &fakeConn{}
+	db := sql.OpenDB(conn)
+	defer db.Close()
+	ctx := context.Background()
+	store := ToolSetSpecStore{Table: "tools", IDColumn: "id", Column: "spec"}
+	spec := &ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "stored", Enabled: true}
+
+	if err := store.Insert(ctx, db, spec); err != nil {
+		t.Fatalf("Insert() error: %v", err)
+	}
+	if want := "INSERT INTO tools (spec) VALUES ($1)"; conn.queries[0] != want {
+		t.Errorf("Insert() query = %q, want %q", conn.queries[0], want)
+	}
+	want, err := NewToolSetSpecValue(spec).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := conn.args[0][0].Value; !proto.Equal(decodeSpec(t, got), decodeSpec(t, want)) {
+		t.Errorf("Insert() argument = %v, want %v as returned by Value", got, want)
+	}
+
+	got, err := store.Get(ctx, db, "tool-set-1")
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	if want := "SELECT spec FROM tools WHERE id = $1"; conn.queries[1] != want {
+		t.Errorf("Get() query = %q, want %q", conn.queries[1], want)
+	}
+	if id := conn.args[1][0].Value; id != "tool-set-1" {
+		t.Errorf("Get() argument = %v, want the id", id)
+	}
+	if !proto.Equal(spec, got) {
+		t.Errorf("Get() = %v, want %v", got, spec)
+	}
+}
+
+func TestStore_GetNoRows(t *testing.T) {
+	db := sql.OpenDB(&fakeConn{})
+	defer db.Close()
+	store := ToolSetSpecStore{Table: "tools", IDColumn: "id", Column: "spec"}
+
+	if _, err := store.Get(context.Background(), db, "missing"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Get() error = %v, want sql.ErrNoRows", err)
+	}
+}
+
+// decodeSpec scans a value written for a ToolSetSpec back into a message.
+func decodeSpec(t *testing.T, v driver.Value) *ToolSetSpec {
+	t.Helper()
+	var w ToolSetSpecValue
+	if err := w.Scan(v); err != nil {
+		t.Fatalf("Scan(%v) error: %v", v, err)
+	}
+	return w.Unwrap()
+}
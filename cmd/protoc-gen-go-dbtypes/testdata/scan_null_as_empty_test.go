@@ -0,0 +1,53 @@
+package testv1
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestScanNullAsEmpty_NullWrapper(t *testing.T) {
+	n := NewNullUserPreferencesValue(&UserPreferences{Theme: "dark"})
+	if err := n.Scan(nil); err != nil {
+		t.Fatalf("Scan(nil) error: %v", err)
+	}
+	if n.Valid {
+		t.Error("Valid = true after scanning NULL")
+	}
+	// The message is empty but usable without a nil check
+	if got := n.UserPreferencesValue.Unwrap(); got == nil || !proto.Equal(got, &UserPreferences{}) {
+		t.Errorf("Unwrap() = %v after scanning NULL, want an empty message", got)
+	}
+	if theme := n.UserPreferencesValue.Unwrap().Theme; theme != "" {
+		t.Errorf("Unwrap().Theme = %q, want \"\"", theme)
+	}
+
+	// An invalid value is still written as NULL
+	if val, err := n.Value(); val != nil || err != nil {
+		t.Errorf("Value() = %v, %v; want nil, nil", val, err)
+	}
+}
+
+func TestScanNullAsEmpty_Wrapper(t *testing.T) {
+	var w UserPreferencesValue
+	if err := w.Scan(nil); err != nil {
+		t.Fatalf("Scan(nil) error: %v", err)
+	}
+	if got := w.Unwrap(); got == nil || !proto.Equal(got, &UserPreferences{}) {
+		t.Errorf("Unwrap() = %v after scanning NULL, want an empty message", got)
+	}
+}
+
+func TestScanNullAsEmpty_ReusedWrapper(t *testing.T) {
+	w := NewUserPreferencesValue(&UserPreferences{Theme: "dark"})
+	prev := w.Unwrap()
+	if err := w.Scan(nil); err != nil {
+		t.Fatalf("Scan(nil) error: %v", err)
+	}
+	if got := w.Unwrap(); got == nil || got == prev || !proto.Equal(got, &UserPreferences{}) {
+		t.Errorf("Unwrap() = %v after scanning NULL, want a new empty message", got)
+	}
+	if prev.Theme != "dark" {
+		t.Errorf("Scan(nil) changed the message of the previous row to %v", prev)
+	}
+}
@@ -0,0 +1,36 @@
+package testv1
+
+import "testing"
+
+func TestSize_MatchesValue(t *testing.T) {
+	for _, spec := range []*ToolSetSpec{
+		{ToolIds: []string{"tool-1", "tool-2"}, Name: "my-toolset", Enabled: true},
+		{Name: "small"},
+		{},
+	} {
+		x := NewToolSetSpecValue(spec)
+		dbVal, err := x.Value()
+		if err != nil {
+			t.Fatalf("Value() error: %v", err)
+		}
+		if got, want := x.Size(), len(dbVal.([]byte)); got != want {
+			t.Errorf("Size() = %d, len(Value()) = %d", got, want)
+		}
+	}
+
+	if got := new(ToolSetSpecValue).Size(); got != 0 {
+		t.Errorf("Size() of a nil wrapper = %d, want 0", got)
+	}
+}
+
+func TestSize_OtherFormats(t *testing.T) {
+	// JSON-format values store more than the wire size
+	doc := NewJSONDocumentValue(&JSONDocument{Id: "doc-1", Labels: map[string]string{"env": "prod"}})
+	dbVal, err := doc.Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if doc.Size() == 0 || doc.Size() >= len(dbVal.([]byte)) {
+		t.Errorf("Size() = %d, len(Value()) = %d; want the smaller wire size", doc.Size(), len(dbVal.([]byte)))
+	}
+}
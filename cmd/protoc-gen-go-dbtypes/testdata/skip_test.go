@@ -0,0 +1,32 @@
+package testv1
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSkip_ScanOldRow(t *testing.T) {
+	// A row written before biography was marked (dbtypes.skip)
+	old, err := protojson.Marshal(&ProfileCache{UserId: "u1", Biography: "long biography"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var scanned ProfileCacheValue
+	if err := scanned.Scan(old); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if want := (&ProfileCache{UserId: "u1"}); !proto.Equal(scanned.Unwrap(), want) {
+		t.Errorf("Scan() = %v, want %v", scanned.Unwrap(), want)
+	}
+
+	msg := &ProfileCache{}
+	if err := ProfileCacheCodec().Decode(old, msg); err != nil {
+		t.Fatalf("Decode() error: %v", err)
+	}
+	if msg.Biography != "" {
+		t.Errorf("Decode() kept the skipped field: %v", msg)
+	}
+}
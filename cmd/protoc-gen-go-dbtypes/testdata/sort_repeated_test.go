@@ -0,0 +1,71 @@
+package testv1
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSortRepeated_SameBytes(t *testing.T) {
+	spec := &ToolSetSpec{ToolIds: []string{"tool-2", "tool-3", "tool-1"}, Name: "tools"}
+	first, err := NewToolSetSpecValue(spec).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	second, err := NewToolSetSpecValue(&ToolSetSpec{ToolIds: []string{"tool-1", "tool-3", "tool-2"}, Name: "tools"}).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if !bytes.Equal(first.([]byte), second.([]byte)) {
+		t.Error("Value() of messages differing in the order of tool_ids produced different bytes")
+	}
+	if want := []string{"tool-2", "tool-3", "tool-1"}; !slices.Equal(spec.ToolIds, want) {
+		t.Errorf("Value() reordered the caller's tool_ids to %v", spec.ToolIds)
+	}
+
+	var scanned ToolSetSpecValue
+	if err := scanned.Scan(first); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if want := []string{"tool-1", "tool-2", "tool-3"}; !slices.Equal(scanned.Unwrap().GetToolIds(), want) {
+		t.Errorf("stored tool_ids = %v, want %v", scanned.Unwrap().GetToolIds(), want)
+	}
+
+	// Batches are encoded alike
+	batch, err := EncodeToolSetSpecBatch([]*ToolSetSpec{spec})
+	if err != nil {
+		t.Fatalf("EncodeToolSetSpecBatch() error: %v", err)
+	}
+	if !bytes.Equal(batch[0], first.([]byte)) {
+		t.Error("EncodeToolSetSpecBatch() did not sort tool_ids")
+	}
+}
+
+func TestSortRepeated_NestedAndNumbers(t *testing.T) {
+	container := &Container{Spec: &ToolSetSpec{ToolIds: []string{"b", "a"}}}
+	data, err := ContainerCodec().Encode(container)
+	if err != nil {
+		t.Fatalf("Encode() error: %v", err)
+	}
+	decoded := &Container{}
+	if err := ContainerCodec().Decode(data, decoded); err != nil {
+		t.Fatalf("Decode() error: %v", err)
+	}
+	if want := (&Container{Spec: &ToolSetSpec{ToolIds: []string{"a", "b"}}}); !proto.Equal(decoded, want) {
+		t.Errorf("stored %v, want %v", decoded, want)
+	}
+
+	record := &EditionRecord{Scores: []int32{3, -1, 2}}
+	if data, err = EditionRecordCodec().Encode(record); err != nil {
+		t.Fatalf("Encode() error: %v", err)
+	}
+	decodedRecord := &EditionRecord{}
+	if err := EditionRecordCodec().Decode(data, decodedRecord); err != nil {
+		t.Fatalf("Decode() error: %v", err)
+	}
+	if want := []int32{-1, 2, 3}; !slices.Equal(decodedRecord.GetScores(), want) {
+		t.Errorf("stored scores = %v, want %v", decodedRecord.GetScores(), want)
+	}
+}
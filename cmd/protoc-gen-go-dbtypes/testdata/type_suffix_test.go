@@ -0,0 +1,40 @@
+package testv1
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestTypeSuffix_RoundTrip(t *testing.T) {
+	spec := &ToolSetSpec{Name: "suffixed", ToolIds: []string{"a", "b"}}
+
+	data, err := NewToolSetSpecDB(spec).Value()
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+	var got ToolSetSpecDB
+	if err := got.Scan(data); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if !proto.Equal(got.Unwrap(), spec) {
+		t.Errorf("Scan() = %v, want %v", got.Unwrap(), spec)
+	}
+	if !spec.DatabaseValue().Equal(&got) {
+		t.Error("DatabaseValue() should return the suffixed wrapper")
+	}
+}
+
+func TestTypeSuffix_Null(t *testing.T) {
+	var n NullToolSetSpecDB
+	if err := n.Scan(nil); err != nil {
+		t.Fatalf("Scan(nil) error = %v", err)
+	}
+	if n.Valid {
+		t.Error("Scan(nil) should leave Valid false")
+	}
+	n = NewNullToolSetSpecDB(&ToolSetSpec{Name: "x"})
+	if !n.Valid || n.ToolSetSpecDB.Unwrap().GetName() != "x" {
+		t.Errorf("NewNullToolSetSpecDB() = %+v", n)
+	}
+}
@@ -0,0 +1,51 @@
+//go:build dbtypes_validate
+
+package testv1
+
+import (
+	"errors"
+	"testing"
+
+	"buf.build/go/protovalidate"
+)
+
+func TestValidate_InstallsHook(t *testing.T) {
+	if dbtypesValidate == nil {
+		t.Fatal("building with dbtypes_validate should install the protovalidate hook")
+	}
+}
+
+func TestValidate_NoRules(t *testing.T) {
+	// ToolSetSpec carries no protovalidate rules, so every message is valid.
+	spec := NewToolSetSpecValue(&ToolSetSpec{Name: "valid"})
+	if err := spec.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if err := (&ToolSetSpecValue{}).Validate(); err != nil {
+		t.Errorf("Validate() of a nil wrapper error = %v", err)
+	}
+	if _, err := spec.Value(); err != nil {
+		t.Errorf("Value() error = %v", err)
+	}
+}
+
+func TestValidate_ValueRejectsInvalidMessage(t *testing.T) {
+	// Account.name carries (buf.validate.field).string.min_len = 2, which
+	// protovalidate itself enforces.
+	account := NewAccountValue(&Account{Name: "a"})
+	var verr *protovalidate.ValidationError
+	if err := account.Validate(); !errors.As(err, &verr) {
+		t.Errorf("Validate() error = %v, want a *protovalidate.ValidationError", err)
+	}
+	if _, err := account.Value(); !errors.As(err, &verr) {
+		t.Errorf("Value() error = %v, want a *protovalidate.ValidationError", err)
+	}
+
+	valid := NewAccountValue(&Account{Name: "ab"})
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if _, err := valid.Value(); err != nil {
+		t.Errorf("Value() error = %v", err)
+	}
+}
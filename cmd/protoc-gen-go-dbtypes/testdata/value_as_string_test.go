@@ -0,0 +1,67 @@
+package testv1
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"testing"
+)
+
+func TestValueAsString_Types(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		value driver.Valuer
+		want  string
+	}{
+		{"binary", NewToolSetSpecValue(&ToolSetSpec{Name: "bytes"}), "[]uint8"},
+		{"binary by default", NewBinaryDocumentValue(&BinaryDocument{Id: "doc"}), "[]uint8"},
+		{"json", NewJSONDocumentValue(&JSONDocument{Id: "doc"}), "string"},
+		{"text", NewTextDocumentValue(&TextDocument{Id: "doc"}), "string"},
+		{"null json", NewNullJSONDocumentValue(&JSONDocument{Id: "doc"}), "string"},
+		{"ProtoValue uses the plugin format", &ProtoValue[*JSONDocument]{Message: &JSONDocument{Id: "doc"}}, "[]uint8"},
+	} {
+		v, err := tt.value.Value()
+		if err != nil {
+			t.Fatalf("%s: Value() error: %v", tt.name, err)
+		}
+		if got := fmt.Sprintf("%T", v); got != tt.want {
+			t.Errorf("%s: Value() returned %s, want %s", tt.name, got, tt.want)
+		}
+		if !driver.IsValue(v) {
+			t.Errorf("%s: Value() = %T, which drivers don't accept", tt.name, v)
+		}
+	}
+
+	// NULL stays untyped
+	if v, err := NewNullJSONDocumentValue(nil).Value(); v != nil || err != nil {
+		t.Errorf("NULL Value() = %#v, %v; want nil", v, err)
+	}
+}
+
+func TestValueAsString_RoundTrip(t *testing.T) {
+	doc := &JSONDocument{Id: "doc-1", Labels: map[string]string{"env": "prod"}}
+	v, err := NewJSONDocumentValue(doc).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	var got JSONDocumentValue
+	if err := got.Scan(v); err != nil {
+		t.Fatalf("Scan(%T) error: %v", v, err)
+	}
+	if !got.Equal(NewJSONDocumentValue(doc)) {
+		t.Errorf("round-trip failed:\ngot:  %v\nwant: %v", got.Unwrap(), doc)
+	}
+}
+
+func TestValueAsString_Batch(t *testing.T) {
+	batch, err := EncodeJSONDocumentBatch([]*JSONDocument{{Id: "doc"}, nil})
+	if err != nil {
+		t.Fatalf("EncodeJSONDocumentBatch() error: %v", err)
+	}
+	v, err := NewJSONDocumentValue(&JSONDocument{Id: "doc"}).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if string(batch[0]) != v.(string) || batch[1] != nil {
+		t.Errorf("EncodeJSONDocumentBatch() = %q, want [%q nil]", batch, v)
+	}
+}
@@ -0,0 +1,72 @@
+package testv1
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// cachedSpec embeds a wrapper by value, as a cache entry would.
+type cachedSpec struct {
+	Spec ToolSetSpecValue
+}
+
+// Value copies of the wrapper are valuers themselves.
+var _ driver.Valuer = ToolSetSpecValue{}
+
+func TestValueReceiver_Copy(t *testing.T) {
+	spec := &ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "cached"}
+	entry := cachedSpec{Spec: *NewToolSetSpecValue(spec)}
+
+	val, err := entry.Spec.Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	var decoded ToolSetSpecValue
+	if err := decoded.Scan(val); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+
+	if !proto.Equal(entry.Spec.Unwrap(), spec) {
+		t.Errorf("Unwrap() = %v, want %v", entry.Spec.Unwrap(), spec)
+	}
+	if !entry.Spec.Equal(&decoded) {
+		t.Errorf("Equal() = false after a round-trip")
+	}
+	if got, want := entry.Spec.String(), decoded.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got, want := entry.Spec.Size(), proto.Size(spec); got != want {
+		t.Errorf("Size() = %d, want %d", got, want)
+	}
+	if entry.Spec.ProtoMessage() != proto.Message(spec) {
+		t.Errorf("ProtoMessage() = %v, want the wrapped message", entry.Spec.ProtoMessage())
+	}
+}
+
+func TestValueReceiver_ZeroValue(t *testing.T) {
+	var entry cachedSpec
+
+	if val, err := entry.Spec.Value(); val != nil || err != nil {
+		t.Errorf("Value() = %v, %v; want nil, nil", val, err)
+	}
+	if entry.Spec.Unwrap() != nil {
+		t.Errorf("Unwrap() = %v, want nil", entry.Spec.Unwrap())
+	}
+	if !entry.Spec.Equal(nil) {
+		t.Error("Equal(nil) = false for a zero wrapper")
+	}
+	if got := entry.Spec.String(); got != "<nil>" {
+		t.Errorf("String() = %q, want <nil>", got)
+	}
+}
+
+func TestValueReceiver_NilPointer(t *testing.T) {
+	// database/sql binds a nil pointer whose Value method has a value
+	// receiver as NULL rather than calling it.
+	val, err := driver.DefaultParameterConverter.ConvertValue((*ToolSetSpecValue)(nil))
+	if val != nil || err != nil {
+		t.Errorf("ConvertValue(nil pointer) = %v, %v; want nil, nil", val, err)
+	}
+}
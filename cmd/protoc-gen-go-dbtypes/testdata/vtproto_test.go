@@ -0,0 +1,159 @@
+package testv1
+
+import (
+	"errors"
+	"testing"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// vtCalls counts the calls of the vtprotobuf methods below.
+var vtCalls struct{ marshal, unmarshal int }
+
+// errInvalidUTF8 is returned for proto3 strings that aren't valid UTF-8.
+var errInvalidUTF8 = errors.New("string field contains invalid UTF-8")
+
+// MarshalVT stands in for the method vtprotobuf generates, encoding the
+// fields without reflection.
+func (x *ToolSetSpec) MarshalVT() ([]byte, error) {
+	vtCalls.marshal++
+	if !utf8.ValidString(x.Name) {
+		return nil, errInvalidUTF8
+	}
+	var b []byte
+	for _, id := range x.ToolIds {
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendString(b, id)
+	}
+	if x.Name != "" {
+		b = protowire.AppendTag(b, 2, protowire.BytesType)
+		b = protowire.AppendString(b, x.Name)
+	}
+	if x.Enabled {
+		b = protowire.AppendTag(b, 3, protowire.VarintType)
+		b = protowire.AppendVarint(b, 1)
+	}
+	return append(b, x.ProtoReflect().GetUnknown()...), nil
+}
+
+// UnmarshalVT merges b into x, like the method vtprotobuf generates.
+func (x *ToolSetSpec) UnmarshalVT(b []byte) error {
+	vtCalls.unmarshal++
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		field := b
+		b = b[n:]
+		switch {
+		case num == 1 && typ == protowire.BytesType:
+			var id string
+			id, n = protowire.ConsumeString(b)
+			x.ToolIds = append(x.ToolIds, id)
+		case num == 2 && typ == protowire.BytesType:
+			x.Name, n = protowire.ConsumeString(b)
+			if n >= 0 && !utf8.ValidString(x.Name) {
+				return errInvalidUTF8
+			}
+		case num == 3 && typ == protowire.VarintType:
+			var v uint64
+			v, n = protowire.ConsumeVarint(b)
+			x.Enabled = v != 0
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n >= 0 {
+				unknown := x.ProtoReflect().GetUnknown()
+				x.ProtoReflect().SetUnknown(append(unknown, field[:len(field)-len(b)+n]...))
+			}
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+func TestVTProto_RoundTrip(t *testing.T) {
+	vtCalls.marshal, vtCalls.unmarshal = 0, 0
+	spec := &ToolSetSpec{ToolIds: []string{"tool-1", "tool-2"}, Name: "fast", Enabled: true}
+
+	val, err := NewToolSetSpecValue(spec).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if vtCalls.marshal != 1 {
+		t.Errorf("Value() called MarshalVT %d times, want 1", vtCalls.marshal)
+	}
+	// Scan into a wrapper holding another message, which UnmarshalVT
+	// would merge into
+	got := NewToolSetSpecValue(&ToolSetSpec{ToolIds: []string{"stale"}})
+	if err := got.Scan(val); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if vtCalls.unmarshal != 1 {
+		t.Errorf("Scan() called UnmarshalVT %d times, want 1", vtCalls.unmarshal)
+	}
+	if !proto.Equal(spec, got.Unwrap()) {
+		t.Errorf("round-trip failed:\ngot:  %v\nwant: %v", got.Unwrap(), spec)
+	}
+}
+
+func TestVTProto_Fallback(t *testing.T) {
+	// UserPreferences has no vtprotobuf methods
+	prefs := &UserPreferences{Theme: "dark", Settings: map[string]string{"tz": "UTC"}}
+	val, err := NewUserPreferencesValue(prefs).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	var got UserPreferencesValue
+	if err := got.Scan(val); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(prefs, got.Unwrap()) {
+		t.Errorf("round-trip failed:\ngot:  %v\nwant: %v", got.Unwrap(), prefs)
+	}
+}
+
+func BenchmarkVTProto_Marshal(b *testing.B) {
+	spec := &ToolSetSpec{ToolIds: []string{"tool-1", "tool-2", "tool-3"}, Name: "bench", Enabled: true}
+	b.Run("MarshalVT", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := dbtypesMarshalVT(spec); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("proto.Marshal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := proto.Marshal(spec); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkVTProto_Unmarshal(b *testing.B) {
+	data, err := proto.Marshal(&ToolSetSpec{ToolIds: []string{"tool-1", "tool-2", "tool-3"}, Name: "bench", Enabled: true})
+	if err != nil {
+		b.Fatal(err)
+	}
+	var spec ToolSetSpec
+	b.Run("UnmarshalVT", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := dbtypesUnmarshalVT(data, &spec); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("proto.Unmarshal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := proto.Unmarshal(data, &spec); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,42 @@
+package testv1
+
+import (
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestZstdDict_FramesUseDictionary(t *testing.T) {
+	spec := &ToolSetSpec{ToolIds: []string{"tool-001"}, Name: "search-tool", Enabled: true}
+	dbVal, err := NewToolSetSpecValue(spec).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+
+	var h zstd.Header
+	if err := h.Decode(dbVal.([]byte)); err != nil {
+		t.Fatalf("Header.Decode() error: %v", err)
+	}
+	if h.DictionaryID != 1 {
+		t.Errorf("frame dictionary ID = %d, want 1 from testdata/zstd.dict", h.DictionaryID)
+	}
+
+	// Without the dictionary the frame can't be decoded
+	dec, err := zstd.NewReader(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dec.Close()
+	if _, err := dec.DecodeAll(dbVal.([]byte), nil); err == nil {
+		t.Error("frame decoded without the dictionary")
+	}
+
+	wrapper := &ToolSetSpecValue{}
+	if err := wrapper.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(spec, wrapper.Unwrap()) {
+		t.Errorf("round-trip failed:\ngot:  %v\nwant: %v", wrapper.Unwrap(), spec)
+	}
+}
@@ -0,0 +1,131 @@
+package testv1
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestZstd_ValueIsCompressed(t *testing.T) {
+	dbVal, err := NewToolSetSpecValue(&ToolSetSpec{Name: "compressed"}).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if !bytes.HasPrefix(dbVal.([]byte), []byte{0x28, 0xb5, 0x2f, 0xfd}) {
+		t.Errorf("Value() = %x, want zstd payload", dbVal)
+	}
+}
+
+func TestZstd_LargeContainerShrinks(t *testing.T) {
+	container := &Container{Id: "big"}
+	for i := 0; i < 500; i++ {
+		container.Items = append(container.Items, &Container_Item{
+			Key:   fmt.Sprintf("key-%d", i),
+			Value: "a fairly repetitive value",
+		})
+	}
+
+	dbVal, err := NewContainerValue(container).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if got, raw := len(dbVal.([]byte)), proto.Size(container); got >= raw {
+		t.Errorf("compressed size %d, want less than raw size %d", got, raw)
+	}
+
+	wrapper := &ContainerValue{}
+	if err := wrapper.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(container, wrapper.Unwrap()) {
+		t.Error("round-trip of compressed container failed")
+	}
+}
+
+func TestZstd_ScanUncompressedRow(t *testing.T) {
+	spec := &ToolSetSpec{ToolIds: []string{"legacy"}, Name: "pre-compression"}
+
+	// Rows written before compression was enabled hold plain protobuf
+	data, err := proto.Marshal(spec)
+	if err != nil {
+		t.Fatalf("proto.Marshal error: %v", err)
+	}
+
+	wrapper := &ToolSetSpecValue{}
+	if err := wrapper.Scan(data); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(spec, wrapper.Unwrap()) {
+		t.Errorf("scan of uncompressed row failed:\ngot:  %v\nwant: %v", wrapper.Unwrap(), spec)
+	}
+}
+
+func TestZstd_ScanCorruptFrame(t *testing.T) {
+	dbVal, err := NewToolSetSpecValue(&ToolSetSpec{Name: "truncated"}).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	data := dbVal.([]byte)
+
+	wrapper := &ToolSetSpecValue{}
+	if err := wrapper.Scan(data[:len(data)-2]); err == nil {
+		t.Error("Scan() of a truncated frame should fail")
+	}
+}
+
+func TestZstd_Level(t *testing.T) {
+	defer func(level zstd.EncoderLevel) { ZstdLevel = level }(ZstdLevel)
+	ZstdLevel = zstd.SpeedBestCompression
+
+	spec := &ToolSetSpec{Name: "best"}
+	dbVal, err := NewToolSetSpecValue(spec).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+
+	wrapper := &ToolSetSpecValue{}
+	if err := wrapper.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(spec, wrapper.Unwrap()) {
+		t.Error("round-trip at SpeedBestCompression failed")
+	}
+
+	// An invalid level surfaces as a Value error
+	ZstdLevel = 42
+	if _, err := NewToolSetSpecValue(spec).Value(); err == nil {
+		t.Error("Value() with invalid ZstdLevel should fail")
+	}
+}
+
+func TestZstd_ValueConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				spec := &ToolSetSpec{Name: fmt.Sprintf("spec-%d-%d", i, j)}
+				dbVal, err := NewToolSetSpecValue(spec).Value()
+				if err != nil {
+					t.Errorf("Value() error: %v", err)
+					return
+				}
+				got := &ToolSetSpecValue{}
+				if err := got.Scan(dbVal); err != nil {
+					t.Errorf("Scan() error: %v", err)
+					return
+				}
+				if !proto.Equal(spec, got.Unwrap()) {
+					t.Errorf("round-trip failed:\ngot:  %v\nwant: %v", got.Unwrap(), spec)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// checkPreserveUnknown reports options and messages that would lose unknown
+// fields between Scan and Value. Only the binary wire format carries them
+// through a read-modify-write, so preserve-unknown turns everything else into
+// a generation error rather than a silent data loss.
+func checkPreserveUnknown(gen *protogen.Plugin, config *GeneratorConfig) error {
+	if !config.PreserveUnknown {
+		return nil
+	}
+	var conflict string
+	switch {
+	case config.DiscardUnknown:
+		conflict = "discard-unknown"
+	case config.Format != FormatBinary:
+		conflict = "format=" + string(config.Format)
+	case config.EmitBSON:
+		conflict = "emit-bson"
+	}
+	if conflict != "" {
+		return fmt.Errorf("preserve-unknown=true cannot be combined with %s", conflict)
+	}
+
+	for _, f := range gen.Files {
+		if !f.Generate {
+			continue
+		}
+		for _, m := range wrappedMessages(f, config) {
+			if format := messageFormat(m, config); format != FormatBinary {
+				return fmt.Errorf("%s: preserve-unknown=true needs the binary format, but %s is stored as %s; exclude it or drop its (dbtypes.format) option", f.Desc.Path(), m.Desc.FullName(), format)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+const protovalidatePackage = protogen.GoImportPath("buf.build/go/protovalidate")
+
+// validateBuildTag guards the protovalidate integration so that only builds
+// which opt in depend on buf.build/go/protovalidate.
+const validateBuildTag = "dbtypes_validate"
+
+// generateValidateFile emits the Validate methods for messages into a
+// separate, build-tagged file. The file also installs protovalidate as the
+// hook that Value consults before writing.
+func generateValidateFile(gen *protogen.Plugin, file *protogen.File, messages []*protogen.Message, config *GeneratorConfig) {
+	filename := config.filename(file, "validate")
+	g := gen.NewGeneratedFile(filename, config.importPath(file))
+
+	g.P("// Code generated by protoc-gen-go-dbtypes. DO NOT EDIT.")
+	g.P("// source: ", file.Desc.Path())
+	g.P()
+	g.P("//go:build ", validateBuildTag)
+	g.P()
+	g.P("package ", config.packageName(file))
+	g.P()
+
+	g.P("func init() {")
+	g.P("	dbtypesValidate = func(m ", protoPackage.Ident("Message"), ") error {")
+	g.P("		return ", protovalidatePackage.Ident("Validate"), "(m)")
+	g.P("	}")
+	g.P("}")
+	g.P()
+
+	for _, m := range messages {
+		wrapperName := config.wrapperName(m)
+
+		g.P("// Validate checks the message against its protovalidate rules. A nil")
+		g.P("// message is valid.")
+		g.P("func (x *", wrapperName, ") Validate() error {")
+		g.P("	msg := x.Unwrap()")
+		g.P("	if msg == nil {")
+		g.P("		return nil")
+		g.P("	}")
+		g.P("	return ", protovalidatePackage.Ident("Validate"), "(msg)")
+		g.P("}")
+		g.P()
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// validateVTProto reports options whose encoding the vtprotobuf methods
+// don't reproduce.
+func validateVTProto(config *GeneratorConfig) error {
+	if !config.VTProto {
+		return nil
+	}
+	switch {
+	case config.Deterministic:
+		// MarshalVT writes map entries in iteration order
+		return fmt.Errorf("vtproto=true cannot be combined with deterministic")
+	case config.DiscardUnknown:
+		// UnmarshalVT keeps the fields it doesn't know
+		return fmt.Errorf("vtproto=true cannot be combined with discard-unknown")
+	}
+	return nil
+}
+
+// generateVTProto emits the binary marshal and unmarshal functions that use
+// the MarshalVT and UnmarshalVT methods vtprotobuf generates, for messages
+// that have them.
+func generateVTProto(g *protogen.GeneratedFile, config *GeneratorConfig) {
+	marshal, unmarshal := any(FormatBinary.marshalIdent()), any(FormatBinary.unmarshalIdent())
+	if config.AllowPartial {
+		marshal, unmarshal = "dbtypesMarshalBinary", "dbtypesUnmarshalBinary"
+	}
+	g.P("// dbtypesMarshalVT marshals m with the MarshalVT method vtprotobuf")
+	g.P("// generates, which avoids reflection, or like any other message if m has")
+	g.P("// none.")
+	g.P("func dbtypesMarshalVT(m ", protoPackage.Ident("Message"), ") ([]byte, error) {")
+	g.P("	if vt, ok := m.(interface{ MarshalVT() ([]byte, error) }); ok {")
+	g.P("		return vt.MarshalVT()")
+	g.P("	}")
+	g.P("	return ", marshal, "(m)")
+	g.P("}")
+	g.P()
+	g.P("// dbtypesUnmarshalVT is the counterpart of dbtypesMarshalVT. UnmarshalVT")
+	g.P("// merges into m, so m is reset first like proto.Unmarshal does.")
+	g.P("func dbtypesUnmarshalVT(b []byte, m ", protoPackage.Ident("Message"), ") error {")
+	g.P("	if vt, ok := m.(interface{ UnmarshalVT([]byte) error }); ok {")
+	g.P("		", protoPackage.Ident("Reset"), "(m)")
+	g.P("		return vt.UnmarshalVT(b)")
+	g.P("	}")
+	g.P("	return ", unmarshal, "(b, m)")
+	g.P("}")
+	g.P()
+}
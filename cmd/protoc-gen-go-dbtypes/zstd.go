@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+const zstdPackage = protogen.GoImportPath("github.com/klauspost/compress/zstd")
+
+// zstdDictMagic starts every zstd dictionary in the standard format, which
+// carries the ID that compressed frames refer to it by.
+var zstdDictMagic = []byte{0x37, 0xa4, 0x30, 0xec}
+
+// readZstdDict reads the dictionary named by the zstd-dict option, which
+// only applies to compress=zstd.
+func readZstdDict(path string, compression Compression) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if compression != CompressionZstd {
+		return nil, fmt.Errorf("zstd-dict requires compress=zstd")
+	}
+	dict, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid zstd-dict: %v", err)
+	}
+	if !bytes.HasPrefix(dict, zstdDictMagic) {
+		return nil, fmt.Errorf("invalid zstd-dict %s: not a zstd dictionary", path)
+	}
+	return dict, nil
+}
+
+// generateZstdHelpers emits the zstd counterparts of the gzip helpers. The
+// dictionary, if any, is embedded in the generated code so that the package
+// needs no file at runtime.
+func generateZstdHelpers(g *protogen.GeneratedFile, config *GeneratorConfig) {
+	g.P("// ZstdLevel is the compression level used by Value.")
+	g.P("var ZstdLevel = ", zstdPackage.Ident("SpeedDefault"))
+	g.P()
+	g.P("// dbtypesZstdMagic is the zstd frame header. A protobuf payload would only")
+	g.P("// start with it if its first fields were 5 and 31 with particular values, so")
+	g.P("// Scan uses it to tell compressed rows from uncompressed ones.")
+	g.P("var dbtypesZstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}")
+	g.P()
+	encoderOptions := ", " + g.QualifiedGoIdent(zstdPackage.Ident("WithEncoderConcurrency")) + "(1)"
+	decoderOptions := ""
+	if len(config.ZstdDict) > 0 {
+		g.P("// dbtypesZstdDict is the dictionary given with zstd-dict. Rows compressed")
+		g.P("// with it can only be read with the same dictionary.")
+		g.P("const dbtypesZstdDict = ", quoteChunked(config.ZstdDict, 32))
+		g.P()
+		encoderOptions += ", " + g.QualifiedGoIdent(zstdPackage.Ident("WithEncoderDict")) + "([]byte(dbtypesZstdDict))"
+		decoderOptions = ", " + g.QualifiedGoIdent(zstdPackage.Ident("WithDecoderDicts")) + "([]byte(dbtypesZstdDict))"
+	}
+
+	// Encoders are pooled like the gzip writers: creating one allocates its
+	// tables, which would dominate the cost of Value.
+	marshalOptions := "{" + config.withPartial("") + "}"
+	if config.Deterministic {
+		marshalOptions = "{" + config.withPartial("Deterministic: true") + "}"
+	}
+	g.P("// dbtypesZstdState holds the encoder and buffer reused to compress a value.")
+	g.P("type dbtypesZstdState struct {")
+	g.P("	raw   []byte")
+	g.P("	enc   *", zstdPackage.Ident("Encoder"))
+	g.P("	level ", zstdPackage.Ident("EncoderLevel"))
+	g.P("}")
+	g.P()
+	g.P("// dbtypesZstdMaxPooled caps the size of buffers returned to the pool so that")
+	g.P("// one huge message doesn't pin its buffer for the life of the process.")
+	g.P("const dbtypesZstdMaxPooled = 1 << 20")
+	g.P()
+	g.P("var dbtypesZstdPool = ", syncPackage.Ident("Pool"), "{New: func() any { return new(dbtypesZstdState) }}")
+	g.P()
+	g.P("// dbtypesMarshalZstd marshals m and zstd-compresses the result.")
+	g.P("func dbtypesMarshalZstd(m ", protoPackage.Ident("Message"), ") ([]byte, error) {")
+	g.P("	s := dbtypesZstdPool.Get().(*dbtypesZstdState)")
+	g.P("	defer func() {")
+	g.P("		if cap(s.raw) <= dbtypesZstdMaxPooled {")
+	g.P("			dbtypesZstdPool.Put(s)")
+	g.P("		}")
+	g.P("	}()")
+	g.P()
+	if config.hooked(FormatBinary) {
+		g.P("	raw, err := Marshal(m)")
+	} else {
+		g.P("	raw, err := ", protoPackage.Ident("MarshalOptions"), marshalOptions, ".MarshalAppend(s.raw[:0], m)")
+	}
+	g.P("	if err != nil {")
+	g.P("		return nil, err")
+	g.P("	}")
+	if !config.hooked(FormatBinary) {
+		g.P("	s.raw = raw")
+	}
+	g.P("	if s.enc == nil || s.level != ZstdLevel {")
+	g.P("		enc, err := ", zstdPackage.Ident("NewWriter"), "(nil, ", zstdPackage.Ident("WithEncoderLevel"), "(ZstdLevel)", encoderOptions, ")")
+	g.P("		if err != nil {")
+	g.P("			return nil, err")
+	g.P("		}")
+	g.P("		s.enc, s.level = enc, ZstdLevel")
+	g.P("	}")
+	g.P("	// EncodeAll appends to a new slice, which the driver may retain.")
+	g.P("	return s.enc.EncodeAll(raw, nil), nil")
+	g.P("}")
+	g.P()
+	g.P("// dbtypesZstdDecoder returns the decoder shared by all wrappers. DecodeAll")
+	g.P("// is safe for concurrent use.")
+	g.P("var dbtypesZstdDecoder = ", syncPackage.Ident("OnceValues"), "(func() (*", zstdPackage.Ident("Decoder"), ", error) {")
+	g.P("	return ", zstdPackage.Ident("NewReader"), "(nil", decoderOptions, ")")
+	g.P("})")
+	g.P()
+	g.P("// dbtypesUnmarshalZstd unmarshals data into m, decompressing it first if it")
+	g.P("// is zstd-compressed.")
+	g.P("func dbtypesUnmarshalZstd(data []byte, m ", protoPackage.Ident("Message"), ") error {")
+	g.P("	if !", bytesPackage.Ident("HasPrefix"), "(data, dbtypesZstdMagic) {")
+	g.P("		return ", config.plainUnmarshalFunc(FormatBinary), "(data, m)")
+	g.P("	}")
+	g.P("	dec, err := dbtypesZstdDecoder()")
+	g.P("	if err != nil {")
+	g.P("		return ", fmtPackage.Ident("Errorf"), `("zstd: %w", err)`)
+	g.P("	}")
+	if config.MaxScanSize > 0 {
+		// Frames written by Value record their size, so oversized ones are
+		// rejected before any memory is allocated for them.
+		g.P("	if MaxScanSize > 0 {")
+		g.P("		var h ", zstdPackage.Ident("Header"))
+		g.P("		if h.Decode(data) == nil && h.HasFCS && h.FrameContentSize > uint64(MaxScanSize) {")
+		g.P("			return ", fmtPackage.Ident("Errorf"), `("zstd: %w: inflates to more than %d bytes", ErrScanTooLarge, MaxScanSize)`)
+		g.P("		}")
+		g.P("	}")
+	}
+	g.P("	inflated, err := dec.DecodeAll(data, nil)")
+	g.P("	if err != nil {")
+	g.P("		return ", fmtPackage.Ident("Errorf"), `("zstd: %w", err)`)
+	g.P("	}")
+	if config.MaxScanSize > 0 {
+		g.P("	if MaxScanSize > 0 && len(inflated) > MaxScanSize {")
+		g.P("		return ", fmtPackage.Ident("Errorf"), `("zstd: %w: inflates to more than %d bytes", ErrScanTooLarge, MaxScanSize)`)
+		g.P("	}")
+	}
+	g.P("	return ", config.plainUnmarshalFunc(FormatBinary), "(inflated, m)")
+	g.P("}")
+	g.P()
+}
+
+// quoteChunked returns data as a Go string expression, split into
+// concatenated literals of at most n bytes each so that the generated line
+// lengths stay readable.
+func quoteChunked(data []byte, n int) string {
+	var chunks []string
+	for len(data) > n {
+		chunks = append(chunks, strconv.Quote(string(data[:n])))
+		data = data[n:]
+	}
+	chunks = append(chunks, strconv.Quote(string(data)))
+	return strings.Join(chunks, " +\n\t")
+}
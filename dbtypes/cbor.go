@@ -0,0 +1,324 @@
+package dbtypes
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// MarshalCBOR encodes msg as CBOR (RFC 8949), walking its fields with
+// protoreflect. It has the signature of proto.Marshal, so that code generated
+// with format=cbor can use it in Value.
+//
+// A message becomes a map keyed by proto field name, repeated fields become
+// arrays, map fields maps and enums their numbers. Only populated fields are
+// written and map entries are sorted, so equal messages produce equal bytes.
+// Unknown fields and extensions are dropped; google.protobuf.Any and groups
+// fail with ErrUnsupportedField.
+func MarshalCBOR(msg proto.Message) ([]byte, error) {
+	return MarshalOptions{}.MarshalCBOR(msg)
+}
+
+// MarshalCBOR is like the package-level MarshalCBOR, but follows o.
+func (o MarshalOptions) MarshalCBOR(msg proto.Message) ([]byte, error) {
+	e := &cborEncoder{}
+	if err := o.encodeTree(e, msg); err != nil {
+		return nil, fmt.Errorf("cbor: %w", err)
+	}
+	return e.buf, nil
+}
+
+// UnmarshalCBOR decodes CBOR produced by MarshalCBOR, or by another encoder
+// using the same data model, into msg. It has the signature of
+// proto.Unmarshal and likewise resets msg first.
+//
+// Definite and indefinite lengths are accepted. Tags, unknown field names
+// and values that don't fit a field's type are errors.
+func UnmarshalCBOR(data []byte, msg proto.Message) error {
+	return UnmarshalOptions{}.UnmarshalCBOR(data, msg)
+}
+
+// UnmarshalCBOR is like the package-level UnmarshalCBOR, but follows o.
+func (o UnmarshalOptions) UnmarshalCBOR(data []byte, msg proto.Message) error {
+	proto.Reset(msg)
+	d := &cborDecoder{data: data}
+	tree, err := d.value(0)
+	if err == nil && d.pos < len(d.data) {
+		err = fmt.Errorf("%d trailing bytes", len(d.data)-d.pos)
+	}
+	if err == nil {
+		err = o.decodeTree(tree, msg)
+	}
+	if err != nil {
+		return fmt.Errorf("cbor: %w", err)
+	}
+	return nil
+}
+
+// CBOR major types.
+const (
+	cborUint   = 0
+	cborNegInt = 1
+	cborBytes  = 2
+	cborText   = 3
+	cborArray  = 4
+	cborMap    = 5
+	cborTag    = 6
+	cborSimple = 7
+)
+
+// cborIndefinite is the additional information of an item whose length is
+// given by a terminating break.
+const cborIndefinite = 31
+
+const cborBreak = 0xff
+
+type cborEncoder struct {
+	buf []byte
+}
+
+// head appends the initial byte and argument of an item.
+func (e *cborEncoder) head(major byte, n uint64) {
+	switch {
+	case n < 24:
+		e.buf = append(e.buf, major<<5|byte(n))
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, major<<5|24, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = binary.BigEndian.AppendUint16(append(e.buf, major<<5|25), uint16(n))
+	case n <= math.MaxUint32:
+		e.buf = binary.BigEndian.AppendUint32(append(e.buf, major<<5|26), uint32(n))
+	default:
+		e.buf = binary.BigEndian.AppendUint64(append(e.buf, major<<5|27), n)
+	}
+}
+
+func (e *cborEncoder) mapHeader(n int)   { e.head(cborMap, uint64(n)) }
+func (e *cborEncoder) arrayHeader(n int) { e.head(cborArray, uint64(n)) }
+func (e *cborEncoder) unsigned(v uint64) { e.head(cborUint, v) }
+
+func (e *cborEncoder) boolean(v bool) {
+	if v {
+		e.buf = append(e.buf, cborSimple<<5|21)
+	} else {
+		e.buf = append(e.buf, cborSimple<<5|20)
+	}
+}
+
+func (e *cborEncoder) integer(v int64) {
+	if v >= 0 {
+		e.head(cborUint, uint64(v))
+	} else {
+		e.head(cborNegInt, uint64(-1-v))
+	}
+}
+
+func (e *cborEncoder) float32(v float32) {
+	e.buf = binary.BigEndian.AppendUint32(append(e.buf, cborSimple<<5|26), math.Float32bits(v))
+}
+
+func (e *cborEncoder) float64(v float64) {
+	e.buf = binary.BigEndian.AppendUint64(append(e.buf, cborSimple<<5|27), math.Float64bits(v))
+}
+
+func (e *cborEncoder) text(s string) {
+	e.head(cborText, uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *cborEncoder) bytes(b []byte) {
+	e.head(cborBytes, uint64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+var errCBORTruncated = errors.New("unexpected end of data")
+
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+// value decodes the item at the current position.
+func (d *cborDecoder) value(depth int) (any, error) {
+	if depth > maxDepth {
+		return nil, errors.New("nesting too deep")
+	}
+	major, info, n, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	indefinite := info == cborIndefinite
+	if indefinite && (major == cborUint || major == cborNegInt || major == cborTag) {
+		return nil, fmt.Errorf("malformed item 0x%02x", major<<5|info)
+	}
+
+	switch major {
+	case cborUint:
+		return n, nil
+	case cborNegInt:
+		if n > math.MaxInt64 {
+			return nil, errors.New("negative integer overflows int64")
+		}
+		return -1 - int64(n), nil
+	case cborBytes, cborText:
+		b, err := d.str(major, indefinite, n)
+		if err != nil {
+			return nil, err
+		}
+		if major == cborBytes {
+			return b, nil
+		}
+		if !utf8.Valid(b) {
+			return nil, errors.New("text is not valid UTF-8")
+		}
+		return string(b), nil
+	case cborArray:
+		var items []any
+		for i := uint64(0); indefinite || i < n; i++ {
+			if indefinite && d.atBreak() {
+				break
+			}
+			item, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		if items == nil {
+			items = []any{}
+		}
+		return items, nil
+	case cborMap:
+		entries := []mapEntry{}
+		for i := uint64(0); indefinite || i < n; i++ {
+			if indefinite && d.atBreak() {
+				break
+			}
+			key, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			val, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, mapEntry{key, val})
+		}
+		return entries, nil
+	case cborTag:
+		return nil, fmt.Errorf("unsupported tag %d", n)
+	}
+
+	// Simple values and floats
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		// null and undefined
+		return nil, nil
+	case 25:
+		return halfToFloat64(uint16(n)), nil
+	case 26:
+		return float64(math.Float32frombits(uint32(n))), nil
+	case 27:
+		return math.Float64frombits(n), nil
+	case cborIndefinite:
+		return nil, errors.New("unexpected break")
+	}
+	return nil, fmt.Errorf("unsupported simple value %d", n)
+}
+
+// head reads the initial byte of an item and its argument. For indefinite
+// lengths the argument is zero.
+func (d *cborDecoder) head() (major, info byte, n uint64, err error) {
+	if d.pos >= len(d.data) {
+		return 0, 0, 0, errCBORTruncated
+	}
+	ib := d.data[d.pos]
+	d.pos++
+	major, info = ib>>5, ib&0x1f
+
+	var size int
+	switch {
+	case info < 24:
+		return major, info, uint64(info), nil
+	case info <= 27:
+		size = 1 << (info - 24)
+	case info == cborIndefinite:
+		return major, info, 0, nil
+	default:
+		return 0, 0, 0, fmt.Errorf("malformed item 0x%02x", ib)
+	}
+	if len(d.data)-d.pos < size {
+		return 0, 0, 0, errCBORTruncated
+	}
+	for _, b := range d.data[d.pos : d.pos+size] {
+		n = n<<8 | uint64(b)
+	}
+	d.pos += size
+	return major, info, n, nil
+}
+
+// str reads the content of a byte or text string, joining the chunks of an
+// indefinite-length string.
+func (d *cborDecoder) str(major byte, indefinite bool, n uint64) ([]byte, error) {
+	if !indefinite {
+		if uint64(len(d.data)-d.pos) < n {
+			return nil, errCBORTruncated
+		}
+		b := append([]byte{}, d.data[d.pos:d.pos+int(n)]...)
+		d.pos += int(n)
+		return b, nil
+	}
+	b := []byte{}
+	for !d.atBreak() {
+		chunkMajor, info, n, err := d.head()
+		if err != nil {
+			return nil, err
+		}
+		if chunkMajor != major || info == cborIndefinite {
+			return nil, errors.New("malformed indefinite-length string")
+		}
+		chunk, err := d.str(major, false, n)
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, chunk...)
+	}
+	return b, nil
+}
+
+// atBreak consumes a break if it is the next byte.
+func (d *cborDecoder) atBreak() bool {
+	if d.pos < len(d.data) && d.data[d.pos] == cborBreak {
+		d.pos++
+		return true
+	}
+	return false
+}
+
+// halfToFloat64 converts an IEEE 754 half-precision float.
+func halfToFloat64(h uint16) float64 {
+	sign := 1.0
+	if h&0x8000 != 0 {
+		sign = -1
+	}
+	exp := int(h>>10) & 0x1f
+	frac := float64(h & 0x3ff)
+	switch exp {
+	case 0:
+		return sign * math.Ldexp(frac, -24)
+	case 0x1f:
+		if frac == 0 {
+			return math.Inf(int(sign))
+		}
+		return math.NaN()
+	}
+	return sign * math.Ldexp(frac+1024, exp-25)
+}
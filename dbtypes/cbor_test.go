@@ -0,0 +1,243 @@
+package dbtypes_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/typepb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/cadenya/protoc-gen-go-dbtypes/dbtypes"
+	testv1 "github.com/cadenya/protoc-gen-go-dbtypes/gen/go/test/v1"
+)
+
+func TestCBOR_RoundTrip(t *testing.T) {
+	for _, msg := range []proto.Message{
+		&testv1.ToolSetSpec{ToolIds: []string{"a", "b"}, Name: "tools", Enabled: true},
+		&testv1.UserPreferences{Theme: "dark", Language: "en", Settings: map[string]string{"tz": "UTC", "font": "mono"}},
+		&testv1.Container{
+			Id:    "c1",
+			Spec:  &testv1.ToolSetSpec{Name: "nested"},
+			Items: []*testv1.Container_Item{{Key: "k1", Value: "v1"}, {Key: "k2"}},
+		},
+		&testv1.Container{},
+		&testv1.Payload{Id: "p1", Content: &testv1.Payload_Text{}},
+		&testv1.Payload{Content: &testv1.Payload_Spec{Spec: &testv1.ToolSetSpec{}}},
+		&testv1.LegacyRecord{Id: proto.String("r"), Priority: proto.Int32(5), Archived: proto.Bool(false), State: testv1.LegacyState_LEGACY_STATE_ACTIVE.Enum()},
+		&durationpb.Duration{Seconds: -5, Nanos: -1},
+		wrapperspb.UInt64(1 << 63),
+		wrapperspb.Float(1.5),
+		wrapperspb.Double(-0.25),
+		wrapperspb.Bytes([]byte{0, 1, 2}),
+		&typepb.Field{Kind: typepb.Field_TYPE_STRING, Number: 7, Packed: true},
+		&structpb.Struct{Fields: map[string]*structpb.Value{"n": structpb.NewNumberValue(3), "s": structpb.NewStringValue("x")}},
+	} {
+		data, err := dbtypes.MarshalCBOR(msg)
+		if err != nil {
+			t.Fatalf("MarshalCBOR(%T) error: %v", msg, err)
+		}
+		got := msg.ProtoReflect().New().Interface()
+		if err := dbtypes.UnmarshalCBOR(data, got); err != nil {
+			t.Fatalf("UnmarshalCBOR(%T) error: %v", msg, err)
+		}
+		if !proto.Equal(got, msg) {
+			t.Errorf("round-trip failed:\ngot:  %v\nwant: %v", got, msg)
+		}
+	}
+}
+
+func TestCBOR_Encoding(t *testing.T) {
+	data, err := dbtypes.MarshalCBOR(&testv1.ToolSetSpec{ToolIds: []string{"a"}, Enabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// {"tool_ids": ["a"], "enabled": true}
+	if want := "a268746f6f6c5f696473816161" + "67656e61626c6564f5"; hex.EncodeToString(data) != want {
+		t.Errorf("MarshalCBOR() = %x, want %s", data, want)
+	}
+
+	// Map entries are sorted, so equal messages encode equally
+	settings := map[string]string{}
+	for _, k := range []string{"e", "d", "c", "b", "a"} {
+		settings[k] = k
+	}
+	first, _ := dbtypes.MarshalCBOR(&testv1.UserPreferences{Settings: settings})
+	for range 10 {
+		again, _ := dbtypes.MarshalCBOR(&testv1.UserPreferences{Settings: settings})
+		if !bytes.Equal(first, again) {
+			t.Fatalf("MarshalCBOR() not deterministic: %x != %x", first, again)
+		}
+	}
+}
+
+func TestCBOR_Any(t *testing.T) {
+	value, err := anypb.New(wrapperspb.String("x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = dbtypes.MarshalCBOR(&typepb.Option{Name: "opt", Value: value})
+	if !errors.Is(err, dbtypes.ErrUnsupportedField) {
+		t.Errorf("MarshalCBOR(Any field) error = %v, want ErrUnsupportedField", err)
+	}
+
+	// {"value": {}}
+	err = dbtypes.UnmarshalCBOR([]byte{0xa1, 0x65, 'v', 'a', 'l', 'u', 'e', 0xa0}, &typepb.Option{})
+	if !errors.Is(err, dbtypes.ErrUnsupportedField) {
+		t.Errorf("UnmarshalCBOR(Any field) error = %v, want ErrUnsupportedField", err)
+	}
+}
+
+func TestCBOR_Decode(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		hex  string
+		want *testv1.ToolSetSpec
+	}{
+		// {_ "name": "x", "tool_ids": [_ "a"]}, indefinite lengths
+		{"indefinite", "bf646e616d6561786874" + "6f6f6c5f6964739f6161ffff", &testv1.ToolSetSpec{Name: "x", ToolIds: []string{"a"}}},
+		// {"name": (_ "a" "b")}, chunked text
+		{"chunked text", "a1646e616d657f61616162ff", &testv1.ToolSetSpec{Name: "ab"}},
+		// {"toolIds": ["a"]}, JSON name
+		{"json name", "a167746f6f6c49647381" + "6161", &testv1.ToolSetSpec{ToolIds: []string{"a"}}},
+		// {"name": null}
+		{"null", "a1646e616d65f6", &testv1.ToolSetSpec{}},
+	} {
+		data, err := hex.DecodeString(tt.hex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := &testv1.ToolSetSpec{Name: "stale"}
+		if err := dbtypes.UnmarshalCBOR(data, got); err != nil {
+			t.Errorf("%s: UnmarshalCBOR() error: %v", tt.name, err)
+			continue
+		}
+		if !proto.Equal(got, tt.want) {
+			t.Errorf("%s: UnmarshalCBOR() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Half-precision floats decode too: {"value": 1.5}
+	got := &wrapperspb.DoubleValue{}
+	if err := dbtypes.UnmarshalCBOR([]byte{0xa1, 0x65, 'v', 'a', 'l', 'u', 'e', 0xf9, 0x3e, 0x00}, got); err != nil || got.GetValue() != 1.5 {
+		t.Errorf("UnmarshalCBOR(half float) = %v, %v; want 1.5", got, err)
+	}
+}
+
+func TestCBOR_DecodeErrors(t *testing.T) {
+	for name, hexData := range map[string]string{
+		"empty":          "",
+		"truncated":      "a1646e616d",
+		"trailing bytes": "a000",
+		"not a map":      "80",
+		"unknown field":  "a163666f6f01",
+		"wrong type":     "a1646e616d6501",
+		"null element":   "a168746f6f6c5f69647381f6",
+		"tag":            "a1646e616d65c06161",
+		"invalid utf-8":  "a1646e616d6561ff",
+		"not a boolean":  "a167656e61626c656401",
+		"unexpected end": "bf",
+		"deep nesting":   repeatHex("81", 200),
+	} {
+		data, err := hex.DecodeString(hexData)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := dbtypes.UnmarshalCBOR(data, &testv1.ToolSetSpec{}); err == nil {
+			t.Errorf("%s: UnmarshalCBOR() succeeded, want an error", name)
+		}
+	}
+
+	// Integers must fit the field
+	if err := dbtypes.UnmarshalCBOR([]byte{0xa1, 0x65, 'n', 'a', 'n', 'o', 's', 0x1b, 1, 0, 0, 0, 0, 0, 0, 0}, &durationpb.Duration{}); err == nil {
+		t.Error("UnmarshalCBOR() accepted an int32 overflow")
+	}
+	if err := dbtypes.UnmarshalCBOR([]byte{0xa1, 0x65, 'v', 'a', 'l', 'u', 'e', 0x20}, &wrapperspb.UInt32Value{}); err == nil {
+		t.Error("UnmarshalCBOR() accepted a negative uint32")
+	}
+}
+
+func TestCBOR_OneofConflict(t *testing.T) {
+	// {"text": "a", "count": 1} selects two cases of the same oneof
+	data, err := hex.DecodeString("a26474657874616165636f756e7401")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dbtypes.UnmarshalCBOR(data, &testv1.Payload{}); err == nil || !strings.Contains(err.Error(), "oneof content") {
+		t.Errorf("UnmarshalCBOR() error = %v, want an error naming the oneof", err)
+	}
+}
+
+func TestCBOR_DiscardUnknown(t *testing.T) {
+	// {"name": "x", "foo": {"bar": [1]}}
+	data, err := hex.DecodeString("a2646e616d65617863666f6fa163626172" + "8101")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dbtypes.UnmarshalCBOR(data, &testv1.ToolSetSpec{}); err == nil {
+		t.Error("UnmarshalCBOR() accepted an unknown field")
+	}
+	got := &testv1.ToolSetSpec{}
+	if err := (dbtypes.UnmarshalOptions{DiscardUnknown: true}).UnmarshalCBOR(data, got); err != nil {
+		t.Fatalf("UnmarshalCBOR(DiscardUnknown) error: %v", err)
+	}
+	if want := (&testv1.ToolSetSpec{Name: "x"}); !proto.Equal(got, want) {
+		t.Errorf("UnmarshalCBOR(DiscardUnknown) = %v, want %v", got, want)
+	}
+}
+
+func TestCBOR_Proto2(t *testing.T) {
+	// Required fields must be set on both sides
+	if _, err := dbtypes.MarshalCBOR(&testv1.LegacyRecord{Name: proto.String("no id")}); err == nil {
+		t.Error("MarshalCBOR() accepted a message without its required field")
+	}
+	empty, err := dbtypes.MarshalCBOR(&testv1.ToolSetSpec{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dbtypes.UnmarshalCBOR(empty, &testv1.LegacyRecord{}); err == nil {
+		t.Error("UnmarshalCBOR() accepted a document without the required field")
+	}
+
+	// Unless partial messages are allowed
+	draft := &testv1.LegacyRecord{Name: proto.String("draft")}
+	data, err := dbtypes.MarshalOptions{AllowPartial: true}.MarshalCBOR(draft)
+	if err != nil {
+		t.Fatalf("MarshalCBOR(AllowPartial) error: %v", err)
+	}
+	got := &testv1.LegacyRecord{}
+	if err := (dbtypes.UnmarshalOptions{AllowPartial: true}).UnmarshalCBOR(data, got); err != nil {
+		t.Fatalf("UnmarshalCBOR(AllowPartial) error: %v", err)
+	}
+	if !proto.Equal(got, draft) {
+		t.Errorf("UnmarshalCBOR(AllowPartial) = %v, want %v", got, draft)
+	}
+
+	// A closed enum only holds its declared numbers: {"id": "r", "state": 7}
+	data, err = hex.DecodeString("a2626964617265737461746507")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dbtypes.UnmarshalCBOR(data, &testv1.LegacyRecord{}); err == nil || !strings.Contains(err.Error(), "closed enum") {
+		t.Errorf("UnmarshalCBOR() error = %v, want an error naming the closed enum", err)
+	}
+	if _, err := dbtypes.MarshalCBOR(&testv1.LegacyRecord{Id: proto.String("r"), State: testv1.LegacyState(7).Enum()}); err == nil {
+		t.Error("MarshalCBOR() accepted an undeclared number of a closed enum")
+	}
+}
+
+func TestCBOR_InvalidUTF8(t *testing.T) {
+	if _, err := dbtypes.MarshalCBOR(&testv1.ToolSetSpec{Name: "\xff"}); err == nil {
+		t.Error("MarshalCBOR() accepted a string that is not valid UTF-8")
+	}
+}
+
+func repeatHex(s string, n int) string {
+	return string(bytes.Repeat([]byte(s), n))
+}
@@ -0,0 +1,1384 @@
+// Package dbtypes is the runtime support for code generated by
+// protoc-gen-go-dbtypes with generic=true. Instead of emitting a wrapper type
+// with its own methods per message, the plugin then emits aliases such as
+//
+//	type ToolSetSpecValue = dbtypes.DBValue[*ToolSetSpec]
+//
+// so that the method bodies exist once, here.
+//
+// It also provides MarshalCBOR and UnmarshalCBOR, and MarshalMsgpack and
+// UnmarshalMsgpack, which code generated with format=cbor and format=msgpack
+// uses to encode values.
+package dbtypes
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	dbtypespb "github.com/cadenya/protoc-gen-go-dbtypes/gen/go/dbtypes"
+)
+
+// ErrInvalidScanType is wrapped by the error Scan returns for a source of an
+// unsupported type.
+var ErrInvalidScanType = errors.New("unsupported scan type")
+
+// ScanTypeError is returned by Scan for a source of an unsupported type,
+// usually a sign that the wrong column was scanned. It wraps
+// ErrInvalidScanType.
+type ScanTypeError struct {
+	// Message is the full name of the message being scanned into.
+	Message protoreflect.FullName
+	// SourceType is the dynamic type of the source.
+	SourceType reflect.Type
+}
+
+func (e *ScanTypeError) Error() string {
+	return fmt.Sprintf("dbtypes: %s: %v: %v", e.Message.Name(), ErrInvalidScanType, e.SourceType)
+}
+
+func (e *ScanTypeError) Unwrap() error { return ErrInvalidScanType }
+
+// ErrMessageTooLarge is wrapped by the error Value returns for a message
+// whose encoding is longer than the 2 GiB the protobuf wire format allows.
+var ErrMessageTooLarge = errors.New("message too large")
+
+// maxMessageSize is the length in bytes of the longest encoding Value
+// stores. Protobuf parsers reject longer messages, so such a row could not
+// be read back by every client.
+var maxMessageSize = math.MaxInt32
+
+// checkSize returns an error wrapping ErrMessageTooLarge if an encoding of n
+// bytes is longer than maxMessageSize.
+func checkSize(n int) error {
+	if n <= maxMessageSize {
+		return nil
+	}
+	return fmt.Errorf("%w: %d bytes exceed the 2 GiB limit of the protobuf wire format; split the message or store large fields outside it", ErrMessageTooLarge, n)
+}
+
+// DecodeError is returned by Scan and UnmarshalBinary when the stored bytes do
+// not decode into the message, for example because they are corrupt or in
+// another format.
+type DecodeError struct {
+	// Message is the full name of the message being decoded.
+	Message protoreflect.FullName
+	// Err is the error from the decoder.
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("dbtypes: %s: %v", e.Message.Name(), e.Err)
+}
+
+func (e *DecodeError) Unwrap() error { return e.Err }
+
+// AnyResolver resolves the message types embedded in google.protobuf.Any
+// fields when messages are encoded as protojson: in JSONValue and in the
+// MarshalJSON and UnmarshalJSON methods. It defaults to
+// protoregistry.GlobalTypes.
+var AnyResolver protoregistry.MessageTypeResolver = protoregistry.GlobalTypes
+
+// DBValue stores a protobuf message in the binary wire format.
+type DBValue[T proto.Message] struct {
+	msg T
+}
+
+// New returns a DBValue for msg. A nil msg is replaced by an empty message.
+func New[T proto.Message](msg T) *DBValue[T] {
+	return &DBValue[T]{msg: orEmpty(msg)}
+}
+
+// Scan implements sql.Scanner.
+func (x *DBValue[T]) Scan(src any) error {
+	return x.ScanContext(context.Background(), src)
+}
+
+// ScanContext is like Scan but returns ctx.Err() without decoding if ctx is
+// done.
+func (x *DBValue[T]) ScanContext(ctx context.Context, src any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if scanNull(src, &x.msg) {
+		return nil
+	}
+	x.msg = orEmpty(x.msg)
+	return scan(src, x.msg, binaryCodec)
+}
+
+// ScanFields is like Scan but keeps only the top-level fields named in
+// fieldPaths, by their names in the .proto file as in a
+// google.protobuf.FieldMask, leaving the others unset. It returns an error
+// for a name that is not a top-level field of T.
+// The other fields are skipped in the stored bytes without being decoded.
+func (x *DBValue[T]) ScanFields(src any, fieldPaths ...string) error {
+	fields, err := fieldMask[T](fieldPaths)
+	if err != nil {
+		return err
+	}
+	if scanNull(src, &x.msg) {
+		return nil
+	}
+	x.msg = orEmpty(x.msg)
+	if err := scan(src, x.msg, fieldsCodec{Codec: binaryCodec, fields: fields}); err != nil {
+		return err
+	}
+	project(x.msg, fields)
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (x *DBValue[T]) Value() (driver.Value, error) {
+	return x.ValueContext(context.Background())
+}
+
+// ValueContext is like Value but returns ctx.Err() without encoding if ctx
+// is done.
+func (x *DBValue[T]) ValueContext(ctx context.Context) (driver.Value, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if !isSet(x.msg) {
+		return nil, nil
+	}
+	return binaryCodec.Encode(x.msg)
+}
+
+// ValueMasked is like Value but stores a copy of the message with only the
+// fields mask names set, for partial updates. It returns an error for a path
+// that is not a field of T. A nil or empty mask leaves no field set.
+func (x *DBValue[T]) ValueMasked(mask *fieldmaskpb.FieldMask) (driver.Value, error) {
+	msg, err := masked(x.msg, mask)
+	if err != nil || !isSet(msg) {
+		return nil, err
+	}
+	return binaryCodec.Encode(msg)
+}
+
+// Unwrap returns the underlying protobuf message.
+func (x *DBValue[T]) Unwrap() T {
+	return x.msg
+}
+
+// ProtoMessage returns the underlying message as a proto.Message, for code
+// that handles wrappers of different types alike. It returns a nil interface,
+// not a typed nil, for a nil wrapper or one holding no message.
+func (x *DBValue[T]) ProtoMessage() proto.Message {
+	if x == nil || !isSet(x.msg) {
+		return nil
+	}
+	return x.msg
+}
+
+// GetOrInit returns the underlying protobuf message, first storing an empty
+// one in the wrapper if there is none, so that it never returns nil.
+func (x *DBValue[T]) GetOrInit() T {
+	if !isSet(x.msg) {
+		x.msg = newMessage[T]()
+	}
+	return x.msg
+}
+
+// Merge merges src into the message as proto.Merge does, first storing an
+// empty message in the wrapper if there is none: populated scalar fields of
+// src overwrite those of the message, repeated fields are appended to and map
+// entries added. A nil src leaves the wrapper unchanged.
+func (x *DBValue[T]) Merge(src T) {
+	if !isSet(src) {
+		return
+	}
+	proto.Merge(x.GetOrInit(), src)
+}
+
+// Size returns the length of the message in the protobuf wire format, or 0
+// if there is none. Value stores that many bytes.
+func (x *DBValue[T]) Size() int {
+	if !isSet(x.msg) {
+		return 0
+	}
+	return proto.Size(x.msg)
+}
+
+// Reset drops the message, leaving the wrapper like its zero value, so that
+// it can be reused. The message itself is not modified, since callers may
+// still hold it; use proto.Reset to clear a message in place.
+func (x *DBValue[T]) Reset() {
+	var zero T
+	x.msg = zero
+}
+
+// Clone returns a wrapper around a deep copy of the message, or nil for a
+// nil wrapper.
+func (x *DBValue[T]) Clone() *DBValue[T] {
+	if x == nil || !isSet(x.msg) {
+		return nil
+	}
+	return &DBValue[T]{msg: proto.Clone(x.msg).(T)}
+}
+
+// Equal reports whether x and other wrap equal messages. Nil wrappers are
+// equal to each other but not to a wrapper holding a message.
+func (x *DBValue[T]) Equal(other *DBValue[T]) bool {
+	var a, b T
+	if x != nil {
+		a = x.msg
+	}
+	if other != nil {
+		b = other.msg
+	}
+	return equal(a, b)
+}
+
+// String returns the message in prototext form, or "<nil>" if there is none
+// or x is nil.
+func (x *DBValue[T]) String() string {
+	if x == nil {
+		return "<nil>"
+	}
+	return format(x.msg)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler with the same encoding as
+// Value. A nil message encodes as an empty one.
+func (x *DBValue[T]) MarshalBinary() ([]byte, error) {
+	return binaryCodec.Encode(orEmpty(x.msg))
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler with the same decoding
+// as Scan.
+func (x *DBValue[T]) UnmarshalBinary(data []byte) error {
+	x.msg = newMessage[T]()
+	return binaryCodec.Decode(data, x.msg)
+}
+
+// WriteTo implements io.WriterTo, writing the encoding of MarshalBinary to w.
+func (x *DBValue[T]) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(w, x.MarshalBinary)
+}
+
+// ReadFrom implements io.ReaderFrom, reading r to EOF and decoding the bytes
+// like UnmarshalBinary.
+func (x *DBValue[T]) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(r, x.UnmarshalBinary)
+}
+
+// GobEncode implements gob.GobEncoder with the same encoding as MarshalBinary.
+// A nil wrapper or message encodes as no bytes.
+func (x DBValue[T]) GobEncode() ([]byte, error) {
+	if !isSet(x.msg) {
+		return []byte{}, nil
+	}
+	return x.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder with the same decoding as
+// UnmarshalBinary. No bytes decode as a nil message.
+func (x *DBValue[T]) GobDecode(data []byte) error {
+	if len(data) == 0 {
+		x.Reset()
+		return nil
+	}
+	return x.UnmarshalBinary(data)
+}
+
+// MarshalJSON implements json.Marshaler using protojson. A nil message
+// encodes as null.
+func (x DBValue[T]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(x.msg)
+}
+
+// UnmarshalJSON implements json.Unmarshaler using protojson.
+func (x *DBValue[T]) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, &x.msg)
+}
+
+// MarshalText implements encoding.TextMarshaler using prototext. A nil
+// wrapper or message encodes as empty text.
+func (x *DBValue[T]) MarshalText() ([]byte, error) {
+	if x == nil {
+		return []byte{}, nil
+	}
+	return marshalText(x.msg)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using prototext. Empty
+// text decodes as an empty message.
+func (x *DBValue[T]) UnmarshalText(data []byte) error {
+	return unmarshalText(data, &x.msg)
+}
+
+// JSONValue stores a protobuf message as protojson.
+type JSONValue[T proto.Message] struct {
+	msg T
+}
+
+// NewJSON returns a JSONValue for msg. A nil msg is replaced by an empty
+// message.
+func NewJSON[T proto.Message](msg T) *JSONValue[T] {
+	return &JSONValue[T]{msg: orEmpty(msg)}
+}
+
+// Scan implements sql.Scanner.
+func (x *JSONValue[T]) Scan(src any) error {
+	return x.ScanContext(context.Background(), src)
+}
+
+// ScanContext is like Scan but returns ctx.Err() without decoding if ctx is
+// done.
+func (x *JSONValue[T]) ScanContext(ctx context.Context, src any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if scanNull(src, &x.msg) {
+		return nil
+	}
+	x.msg = orEmpty(x.msg)
+	return scanJSON(src, x.msg)
+}
+
+// ScanFields is like Scan but keeps only the top-level fields named in
+// fieldPaths, by their names in the .proto file as in a
+// google.protobuf.FieldMask, leaving the others unset. It returns an error
+// for a name that is not a top-level field of T.
+func (x *JSONValue[T]) ScanFields(src any, fieldPaths ...string) error {
+	fields, err := fieldMask[T](fieldPaths)
+	if err != nil {
+		return err
+	}
+	if scanNull(src, &x.msg) {
+		return nil
+	}
+	x.msg = orEmpty(x.msg)
+	if err := scanJSON(src, x.msg); err != nil {
+		return err
+	}
+	project(x.msg, fields)
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (x *JSONValue[T]) Value() (driver.Value, error) {
+	return x.ValueContext(context.Background())
+}
+
+// ValueContext is like Value but returns ctx.Err() without encoding if ctx
+// is done.
+func (x *JSONValue[T]) ValueContext(ctx context.Context) (driver.Value, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if !isSet(x.msg) {
+		return nil, nil
+	}
+	return jsonCodec.Encode(x.msg)
+}
+
+// ValueMasked is like Value but stores a copy of the message with only the
+// fields mask names set, for partial updates. It returns an error for a path
+// that is not a field of T. A nil or empty mask leaves no field set.
+func (x *JSONValue[T]) ValueMasked(mask *fieldmaskpb.FieldMask) (driver.Value, error) {
+	msg, err := masked(x.msg, mask)
+	if err != nil || !isSet(msg) {
+		return nil, err
+	}
+	return jsonCodec.Encode(msg)
+}
+
+// Unwrap returns the underlying protobuf message.
+func (x *JSONValue[T]) Unwrap() T {
+	return x.msg
+}
+
+// ProtoMessage returns the underlying message as a proto.Message, for code
+// that handles wrappers of different types alike. It returns a nil interface,
+// not a typed nil, for a nil wrapper or one holding no message.
+func (x *JSONValue[T]) ProtoMessage() proto.Message {
+	if x == nil || !isSet(x.msg) {
+		return nil
+	}
+	return x.msg
+}
+
+// GetOrInit returns the underlying protobuf message, first storing an empty
+// one in the wrapper if there is none, so that it never returns nil.
+func (x *JSONValue[T]) GetOrInit() T {
+	if !isSet(x.msg) {
+		x.msg = newMessage[T]()
+	}
+	return x.msg
+}
+
+// Merge merges src into the message as proto.Merge does, first storing an
+// empty message in the wrapper if there is none: populated scalar fields of
+// src overwrite those of the message, repeated fields are appended to and map
+// entries added. A nil src leaves the wrapper unchanged.
+func (x *JSONValue[T]) Merge(src T) {
+	if !isSet(src) {
+		return
+	}
+	proto.Merge(x.GetOrInit(), src)
+}
+
+// Size returns the length of the message in the protobuf wire format, or 0
+// if there is none. Value stores protojson, so Size does not measure what it
+// stores.
+func (x *JSONValue[T]) Size() int {
+	if !isSet(x.msg) {
+		return 0
+	}
+	return proto.Size(x.msg)
+}
+
+// Reset drops the message, leaving the wrapper like its zero value, so that
+// it can be reused. The message itself is not modified, since callers may
+// still hold it; use proto.Reset to clear a message in place.
+func (x *JSONValue[T]) Reset() {
+	var zero T
+	x.msg = zero
+}
+
+// Clone returns a wrapper around a deep copy of the message, or nil for a
+// nil wrapper.
+func (x *JSONValue[T]) Clone() *JSONValue[T] {
+	if x == nil || !isSet(x.msg) {
+		return nil
+	}
+	return &JSONValue[T]{msg: proto.Clone(x.msg).(T)}
+}
+
+// Equal reports whether x and other wrap equal messages. Nil wrappers are
+// equal to each other but not to a wrapper holding a message.
+func (x *JSONValue[T]) Equal(other *JSONValue[T]) bool {
+	var a, b T
+	if x != nil {
+		a = x.msg
+	}
+	if other != nil {
+		b = other.msg
+	}
+	return equal(a, b)
+}
+
+// String returns the message in prototext form, or "<nil>" if there is none
+// or x is nil.
+func (x *JSONValue[T]) String() string {
+	if x == nil {
+		return "<nil>"
+	}
+	return format(x.msg)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler with the same encoding as
+// Value. A nil message encodes as an empty one.
+func (x *JSONValue[T]) MarshalBinary() ([]byte, error) {
+	return jsonCodec.Encode(orEmpty(x.msg))
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler with the same decoding
+// as Scan.
+func (x *JSONValue[T]) UnmarshalBinary(data []byte) error {
+	x.msg = newMessage[T]()
+	return jsonCodec.Decode(data, x.msg)
+}
+
+// WriteTo implements io.WriterTo, writing the encoding of MarshalBinary to w.
+func (x *JSONValue[T]) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(w, x.MarshalBinary)
+}
+
+// ReadFrom implements io.ReaderFrom, reading r to EOF and decoding the bytes
+// like UnmarshalBinary.
+func (x *JSONValue[T]) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(r, x.UnmarshalBinary)
+}
+
+// GobEncode implements gob.GobEncoder with the same encoding as MarshalBinary.
+// A nil wrapper or message encodes as no bytes.
+func (x JSONValue[T]) GobEncode() ([]byte, error) {
+	if !isSet(x.msg) {
+		return []byte{}, nil
+	}
+	return x.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder with the same decoding as
+// UnmarshalBinary. No bytes decode as a nil message.
+func (x *JSONValue[T]) GobDecode(data []byte) error {
+	if len(data) == 0 {
+		x.Reset()
+		return nil
+	}
+	return x.UnmarshalBinary(data)
+}
+
+// MarshalJSON implements json.Marshaler using protojson. A nil message
+// encodes as null.
+func (x JSONValue[T]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(x.msg)
+}
+
+// UnmarshalJSON implements json.Unmarshaler using protojson.
+func (x *JSONValue[T]) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, &x.msg)
+}
+
+// MarshalText implements encoding.TextMarshaler using prototext. A nil
+// wrapper or message encodes as empty text.
+func (x *JSONValue[T]) MarshalText() ([]byte, error) {
+	if x == nil {
+		return []byte{}, nil
+	}
+	return marshalText(x.msg)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using prototext. Empty
+// text decodes as an empty message.
+func (x *JSONValue[T]) UnmarshalText(data []byte) error {
+	return unmarshalText(data, &x.msg)
+}
+
+// TextValue stores a protobuf message as prototext.
+type TextValue[T proto.Message] struct {
+	msg T
+}
+
+// NewText returns a TextValue for msg. A nil msg is replaced by an empty
+// message.
+func NewText[T proto.Message](msg T) *TextValue[T] {
+	return &TextValue[T]{msg: orEmpty(msg)}
+}
+
+// Scan implements sql.Scanner.
+func (x *TextValue[T]) Scan(src any) error {
+	return x.ScanContext(context.Background(), src)
+}
+
+// ScanContext is like Scan but returns ctx.Err() without decoding if ctx is
+// done.
+func (x *TextValue[T]) ScanContext(ctx context.Context, src any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if scanNull(src, &x.msg) {
+		return nil
+	}
+	x.msg = orEmpty(x.msg)
+	return scan(src, x.msg, textCodec)
+}
+
+// ScanFields is like Scan but keeps only the top-level fields named in
+// fieldPaths, by their names in the .proto file as in a
+// google.protobuf.FieldMask, leaving the others unset. It returns an error
+// for a name that is not a top-level field of T.
+func (x *TextValue[T]) ScanFields(src any, fieldPaths ...string) error {
+	fields, err := fieldMask[T](fieldPaths)
+	if err != nil {
+		return err
+	}
+	if scanNull(src, &x.msg) {
+		return nil
+	}
+	x.msg = orEmpty(x.msg)
+	if err := scan(src, x.msg, textCodec); err != nil {
+		return err
+	}
+	project(x.msg, fields)
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (x *TextValue[T]) Value() (driver.Value, error) {
+	return x.ValueContext(context.Background())
+}
+
+// ValueContext is like Value but returns ctx.Err() without encoding if ctx
+// is done.
+func (x *TextValue[T]) ValueContext(ctx context.Context) (driver.Value, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if !isSet(x.msg) {
+		return nil, nil
+	}
+	return textCodec.Encode(x.msg)
+}
+
+// ValueMasked is like Value but stores a copy of the message with only the
+// fields mask names set, for partial updates. It returns an error for a path
+// that is not a field of T. A nil or empty mask leaves no field set.
+func (x *TextValue[T]) ValueMasked(mask *fieldmaskpb.FieldMask) (driver.Value, error) {
+	msg, err := masked(x.msg, mask)
+	if err != nil || !isSet(msg) {
+		return nil, err
+	}
+	return textCodec.Encode(msg)
+}
+
+// Unwrap returns the underlying protobuf message.
+func (x *TextValue[T]) Unwrap() T {
+	return x.msg
+}
+
+// ProtoMessage returns the underlying message as a proto.Message, for code
+// that handles wrappers of different types alike. It returns a nil interface,
+// not a typed nil, for a nil wrapper or one holding no message.
+func (x *TextValue[T]) ProtoMessage() proto.Message {
+	if x == nil || !isSet(x.msg) {
+		return nil
+	}
+	return x.msg
+}
+
+// GetOrInit returns the underlying protobuf message, first storing an empty
+// one in the wrapper if there is none, so that it never returns nil.
+func (x *TextValue[T]) GetOrInit() T {
+	if !isSet(x.msg) {
+		x.msg = newMessage[T]()
+	}
+	return x.msg
+}
+
+// Merge merges src into the message as proto.Merge does, first storing an
+// empty message in the wrapper if there is none: populated scalar fields of
+// src overwrite those of the message, repeated fields are appended to and map
+// entries added. A nil src leaves the wrapper unchanged.
+func (x *TextValue[T]) Merge(src T) {
+	if !isSet(src) {
+		return
+	}
+	proto.Merge(x.GetOrInit(), src)
+}
+
+// Size returns the length of the message in the protobuf wire format, or 0
+// if there is none. Value stores prototext, so Size does not measure what it
+// stores.
+func (x *TextValue[T]) Size() int {
+	if !isSet(x.msg) {
+		return 0
+	}
+	return proto.Size(x.msg)
+}
+
+// Reset drops the message, leaving the wrapper like its zero value, so that
+// it can be reused. The message itself is not modified, since callers may
+// still hold it; use proto.Reset to clear a message in place.
+func (x *TextValue[T]) Reset() {
+	var zero T
+	x.msg = zero
+}
+
+// Clone returns a wrapper around a deep copy of the message, or nil for a
+// nil wrapper.
+func (x *TextValue[T]) Clone() *TextValue[T] {
+	if x == nil || !isSet(x.msg) {
+		return nil
+	}
+	return &TextValue[T]{msg: proto.Clone(x.msg).(T)}
+}
+
+// Equal reports whether x and other wrap equal messages. Nil wrappers are
+// equal to each other but not to a wrapper holding a message.
+func (x *TextValue[T]) Equal(other *TextValue[T]) bool {
+	var a, b T
+	if x != nil {
+		a = x.msg
+	}
+	if other != nil {
+		b = other.msg
+	}
+	return equal(a, b)
+}
+
+// String returns the message in prototext form, or "<nil>" if there is none
+// or x is nil.
+func (x *TextValue[T]) String() string {
+	if x == nil {
+		return "<nil>"
+	}
+	return format(x.msg)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler with the same encoding as
+// Value. A nil message encodes as an empty one.
+func (x *TextValue[T]) MarshalBinary() ([]byte, error) {
+	return textCodec.Encode(orEmpty(x.msg))
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler with the same decoding
+// as Scan.
+func (x *TextValue[T]) UnmarshalBinary(data []byte) error {
+	x.msg = newMessage[T]()
+	return textCodec.Decode(data, x.msg)
+}
+
+// WriteTo implements io.WriterTo, writing the encoding of MarshalBinary to w.
+func (x *TextValue[T]) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(w, x.MarshalBinary)
+}
+
+// ReadFrom implements io.ReaderFrom, reading r to EOF and decoding the bytes
+// like UnmarshalBinary.
+func (x *TextValue[T]) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(r, x.UnmarshalBinary)
+}
+
+// GobEncode implements gob.GobEncoder with the same encoding as MarshalBinary.
+// A nil wrapper or message encodes as no bytes.
+func (x TextValue[T]) GobEncode() ([]byte, error) {
+	if !isSet(x.msg) {
+		return []byte{}, nil
+	}
+	return x.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder with the same decoding as
+// UnmarshalBinary. No bytes decode as a nil message.
+func (x *TextValue[T]) GobDecode(data []byte) error {
+	if len(data) == 0 {
+		x.Reset()
+		return nil
+	}
+	return x.UnmarshalBinary(data)
+}
+
+// MarshalJSON implements json.Marshaler using protojson. A nil message
+// encodes as null.
+func (x TextValue[T]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(x.msg)
+}
+
+// UnmarshalJSON implements json.Unmarshaler using protojson.
+func (x *TextValue[T]) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, &x.msg)
+}
+
+// MarshalText implements encoding.TextMarshaler using prototext. A nil
+// wrapper or message encodes as empty text.
+func (x *TextValue[T]) MarshalText() ([]byte, error) {
+	if x == nil {
+		return []byte{}, nil
+	}
+	return marshalText(x.msg)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using prototext. Empty
+// text decodes as an empty message.
+func (x *TextValue[T]) UnmarshalText(data []byte) error {
+	return unmarshalText(data, &x.msg)
+}
+
+// EncodeBatch encodes msgs as DBValue.Value would, for bulk inserts such as
+// COPY. Nil messages give nil entries. The messages are sized first and
+// marshaled into one buffer, which the entries are slices of.
+func EncodeBatch[T proto.Message](msgs []T) ([][]byte, error) {
+	size := 0
+	redacted := make([]proto.Message, len(msgs))
+	for i, msg := range msgs {
+		if !isSet(msg) {
+			continue
+		}
+		redacted[i] = redact(msg)
+		n := proto.Size(redacted[i])
+		if err := checkSize(n); err != nil {
+			return nil, fmt.Errorf("batch element %d: %w", i, wrapError(msg, err))
+		}
+		size += n
+	}
+	// Size has cached the sizes of the messages
+	opts := proto.MarshalOptions{UseCachedSize: true}
+	buf := make([]byte, 0, size)
+	out := make([][]byte, len(msgs))
+	for i, msg := range redacted {
+		if msg == nil {
+			continue
+		}
+		start := len(buf)
+		var err error
+		if buf, err = opts.MarshalAppend(buf, msg); err != nil {
+			return nil, fmt.Errorf("batch element %d: %w", i, wrapError(msg, err))
+		}
+		out[i] = buf[start:len(buf):len(buf)]
+	}
+	return out, nil
+}
+
+// EncodeJSONBatch is like EncodeBatch but encodes msgs as JSONValue.Value
+// would.
+func EncodeJSONBatch[T proto.Message](msgs []T) ([][]byte, error) {
+	return encodeBatch(msgs, jsonCodec)
+}
+
+// EncodeTextBatch is like EncodeBatch but encodes msgs as TextValue.Value
+// would.
+func EncodeTextBatch[T proto.Message](msgs []T) ([][]byte, error) {
+	return encodeBatch(msgs, textCodec)
+}
+
+// encodeBatch encodes each message of msgs with codec, leaving nil entries for
+// nil messages.
+func encodeBatch[T proto.Message](msgs []T, codec Codec) ([][]byte, error) {
+	out := make([][]byte, len(msgs))
+	for i, msg := range msgs {
+		if !isSet(msg) {
+			continue
+		}
+		data, err := codec.Encode(msg)
+		if err != nil {
+			return nil, fmt.Errorf("batch element %d: %w", i, err)
+		}
+		out[i] = data
+	}
+	return out, nil
+}
+
+// Values returns what DBValue.Value returns for each message of msgs, for
+// passing many messages as arguments to ExecContext and the like. Nil
+// messages give nil entries, which are stored as NULL.
+func Values[T proto.Message](msgs []T) ([]driver.Value, error) {
+	return values(msgs, func(msg T) driver.Valuer { return New(msg) })
+}
+
+// JSONValues is like Values but returns what JSONValue.Value returns.
+func JSONValues[T proto.Message](msgs []T) ([]driver.Value, error) {
+	return values(msgs, func(msg T) driver.Valuer { return NewJSON(msg) })
+}
+
+// TextValues is like Values but returns what TextValue.Value returns.
+func TextValues[T proto.Message](msgs []T) ([]driver.Value, error) {
+	return values(msgs, func(msg T) driver.Valuer { return NewText(msg) })
+}
+
+// values returns the value of the wrapper wrap gives each message of msgs,
+// leaving nil entries for nil messages.
+func values[T proto.Message](msgs []T, wrap func(T) driver.Valuer) ([]driver.Value, error) {
+	out := make([]driver.Value, len(msgs))
+	for i, msg := range msgs {
+		if !isSet(msg) {
+			continue
+		}
+		v, err := wrap(msg).Value()
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+// isSet reports whether msg is a non-nil message. Generated message types
+// are pointers, so a zero T is a typed nil rather than a nil interface.
+func isSet[T proto.Message](msg T) bool {
+	return any(msg) != nil && msg.ProtoReflect().IsValid()
+}
+
+// newMessage returns a new, empty T.
+func newMessage[T proto.Message]() T {
+	var zero T
+	return zero.ProtoReflect().Type().New().Interface().(T)
+}
+
+func orEmpty[T proto.Message](msg T) T {
+	if isSet(msg) {
+		return msg
+	}
+	return newMessage[T]()
+}
+
+// scanSource returns the value a *[]byte or *string source points to, or nil,
+// meaning NULL, for a nil pointer. Other sources are returned as is.
+func scanSource(src any) any {
+	switch v := src.(type) {
+	case *[]byte:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *string:
+		if v == nil {
+			return nil
+		}
+		return *v
+	}
+	return src
+}
+
+// scanNull reports whether src is NULL, and if so drops the message *msg,
+// so that a wrapper scanned into before doesn't keep that of the previous
+// row.
+func scanNull[T proto.Message](src any, msg *T) bool {
+	if scanSource(src) != nil {
+		return false
+	}
+	var zero T
+	*msg = zero
+	return true
+}
+
+// scan decodes src, which is not NULL, into msg using codec.
+func scan(src any, msg proto.Message, codec Codec) error {
+	src = scanSource(src)
+	var data []byte
+	switch v := src.(type) {
+	case []byte:
+		// Drivers may reuse the buffer once Scan returns, so copy it.
+		data = append([]byte(nil), v...)
+	case sql.RawBytes:
+		// RawBytes is only valid until the next call to Next, Scan or Close.
+		data = append([]byte(nil), v...)
+	case string:
+		data = []byte(v)
+	case io.Reader:
+		var err error
+		if data, err = io.ReadAll(v); err != nil {
+			return wrapError(msg, fmt.Errorf("read scan source: %w", err))
+		}
+	default:
+		return &ScanTypeError{Message: msg.ProtoReflect().Descriptor().FullName(), SourceType: reflect.TypeOf(src)}
+	}
+
+	// Decode into the existing message, so that scanning row after row into
+	// one wrapper allocates no new message.
+	proto.Reset(msg)
+	return codec.Decode(data, msg)
+}
+
+// scanJSON is scan for protojson. It also accepts the json.RawMessage and
+// map[string]any values some drivers and ORMs return for JSON columns.
+func scanJSON(src any, msg proto.Message) error {
+	switch v := scanSource(src).(type) {
+	case json.RawMessage:
+		src = []byte(v)
+	case map[string]any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return wrapError(msg, fmt.Errorf("marshal scan source: %w", err))
+		}
+		src = data
+	}
+	return scan(src, msg, jsonCodec)
+}
+
+// fieldMask returns the numbers of the top-level fields of T named in paths,
+// as in a google.protobuf.FieldMask.
+func fieldMask[T proto.Message](paths []string) (map[protoreflect.FieldNumber]bool, error) {
+	var zero T
+	md := zero.ProtoReflect().Descriptor()
+	fields := make(map[protoreflect.FieldNumber]bool, len(paths))
+	for _, path := range paths {
+		fd := md.Fields().ByName(protoreflect.Name(path))
+		if fd == nil {
+			return nil, fmt.Errorf("dbtypes: %s: no top-level field %q", md.Name(), path)
+		}
+		fields[fd.Number()] = true
+	}
+	return fields, nil
+}
+
+// filterFields returns the wire-format data with only the fields numbered in
+// fields, so that the others are skipped without being decoded. Malformed
+// data is returned as is for the codec to report.
+func filterFields(data []byte, fields map[protoreflect.FieldNumber]bool) []byte {
+	out := make([]byte, 0, len(data))
+	for b := data; len(b) > 0; {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return data
+		}
+		m := protowire.ConsumeFieldValue(num, typ, b[n:])
+		if m < 0 {
+			return data
+		}
+		if fields[num] {
+			out = append(out, b[:n+m]...)
+		}
+		b = b[n+m:]
+	}
+	return out
+}
+
+// fieldsCodec is a wire-format Codec decoding only the fields numbered in
+// fields, for DBValue.ScanFields.
+type fieldsCodec struct {
+	Codec
+	fields map[protoreflect.FieldNumber]bool
+}
+
+// Decode implements Codec.
+func (c fieldsCodec) Decode(data []byte, msg proto.Message) error {
+	return c.Codec.Decode(filterFields(data, c.fields), msg)
+}
+
+// project clears the fields of msg not numbered in fields, and its unknown
+// fields, for the formats that can only be decoded whole.
+func project(msg proto.Message, fields map[protoreflect.FieldNumber]bool) {
+	m := msg.ProtoReflect()
+	var drop []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if !fields[fd.Number()] {
+			drop = append(drop, fd)
+		}
+		return true
+	})
+	for _, fd := range drop {
+		m.Clear(fd)
+	}
+	if len(m.GetUnknown()) > 0 {
+		m.SetUnknown(nil)
+	}
+}
+
+// masked returns a copy of msg with only the fields mask names set, or msg
+// itself if it is not set. The mask is checked either way.
+func masked[T proto.Message](msg T, mask *fieldmaskpb.FieldMask) (T, error) {
+	if !isSet(msg) {
+		return msg, applyMask(newMessage[T]().ProtoReflect(), mask.GetPaths())
+	}
+	msg = proto.Clone(msg).(T)
+	return msg, applyMask(msg.ProtoReflect(), mask.GetPaths())
+}
+
+// applyMask clears the fields of m that paths, the paths of a
+// google.protobuf.FieldMask, don't cover, and its unknown fields. A path may
+// name a field of a singular message field, as in "spec.name".
+func applyMask(m protoreflect.Message, paths []string) error {
+	md := m.Descriptor()
+	// keep maps each field named to the paths below it, or to nil if the
+	// whole field is kept
+	keep := make(map[protoreflect.FieldNumber][]string, len(paths))
+	for _, path := range paths {
+		name, rest, nested := strings.Cut(path, ".")
+		fd := md.Fields().ByName(protoreflect.Name(name))
+		if fd == nil || nested && (fd.Message() == nil || fd.IsList() || fd.IsMap()) {
+			return fmt.Errorf("dbtypes: %s: invalid field mask path %q", md.Name(), path)
+		}
+		sub, seen := keep[fd.Number()]
+		switch {
+		case !nested:
+			keep[fd.Number()] = nil
+		case !seen || sub != nil:
+			keep[fd.Number()] = append(sub, rest)
+		}
+	}
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		sub, ok := keep[fd.Number()]
+		switch {
+		case !ok:
+			m.Clear(fd)
+		case sub != nil:
+			// Check the paths below an unset field too
+			child := m.NewField(fd).Message()
+			if m.Has(fd) {
+				child = m.Mutable(fd).Message()
+			}
+			if err := applyMask(child, sub); err != nil {
+				return err
+			}
+		}
+	}
+	if len(m.GetUnknown()) > 0 {
+		m.SetUnknown(nil)
+	}
+	return nil
+}
+
+// writeTo writes the bytes from marshal to w, for the WriteTo methods.
+func writeTo(w io.Writer, marshal func() ([]byte, error)) (int64, error) {
+	data, err := marshal()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// readFrom reads r to EOF and hands the bytes to unmarshal, for the ReadFrom
+// methods.
+func readFrom(r io.Reader, unmarshal func([]byte) error) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return int64(len(data)), err
+	}
+	return int64(len(data)), unmarshal(data)
+}
+
+// Codec encodes messages into the bytes a wrapper stores and decodes them
+// back, so that a storage format can be reused outside database/sql, for
+// example by a message queue serializer. Errors are those of Value and Scan.
+type Codec interface {
+	Encode(msg proto.Message) ([]byte, error)
+	Decode(data []byte, msg proto.Message) error
+}
+
+// The codecs of the storage formats, which the wrappers call through.
+var (
+	binaryCodec = &funcCodec{marshal: proto.Marshal, unmarshal: proto.Unmarshal}
+	jsonCodec   = &funcCodec{marshal: marshalProtoJSON, unmarshal: unmarshalProtoJSON}
+	textCodec   = &funcCodec{marshal: prototext.Marshal, unmarshal: prototext.Unmarshal}
+)
+
+// BinaryCodec returns the Codec of DBValue, the protobuf wire format.
+func BinaryCodec() Codec { return binaryCodec }
+
+// JSONCodec returns the Codec of JSONValue, protojson.
+func JSONCodec() Codec { return jsonCodec }
+
+// TextCodec returns the Codec of TextValue, prototext.
+func TextCodec() Codec { return textCodec }
+
+// funcCodec is a Codec made of a marshal and an unmarshal function.
+type funcCodec struct {
+	marshal   func(proto.Message) ([]byte, error)
+	unmarshal func([]byte, proto.Message) error
+}
+
+// Encode marshals msg.
+func (c *funcCodec) Encode(msg proto.Message) ([]byte, error) {
+	data, err := c.marshal(redact(msg))
+	if err == nil {
+		err = checkSize(len(data))
+	}
+	if err != nil {
+		return nil, wrapError(msg, err)
+	}
+	return data, nil
+}
+
+// Decode unmarshals data into msg.
+func (c *funcCodec) Decode(data []byte, msg proto.Message) error {
+	if err := c.unmarshal(data, msg); err != nil {
+		return &DecodeError{Message: msg.ProtoReflect().Descriptor().FullName(), Err: err}
+	}
+	skip(msg)
+	return nil
+}
+
+// omittedFields are the fields of a message type that are not stored.
+type omittedFields struct {
+	// redacted are the fields marked (dbtypes.redact) or (dbtypes.skip),
+	// which are cleared before encoding.
+	redacted []protoreflect.FieldDescriptor
+	// skipped are the fields marked (dbtypes.skip), which are also cleared
+	// after decoding.
+	skipped []protoreflect.FieldDescriptor
+}
+
+// omittedFieldsCache caches the omitted fields of each message type, keyed
+// by descriptor.
+var omittedFieldsCache sync.Map // protoreflect.MessageDescriptor -> *omittedFields
+
+// omitted returns the omitted fields of the message type desc.
+func omitted(desc protoreflect.MessageDescriptor) *omittedFields {
+	if v, ok := omittedFieldsCache.Load(desc); ok {
+		return v.(*omittedFields)
+	}
+	f := &omittedFields{}
+	for i := 0; i < desc.Fields().Len(); i++ {
+		fd := desc.Fields().Get(i)
+		opts := fd.Options()
+		if opts == nil {
+			continue
+		}
+		skipped := proto.GetExtension(opts, dbtypespb.E_Skip).(bool)
+		if skipped || proto.GetExtension(opts, dbtypespb.E_Redact).(bool) {
+			f.redacted = append(f.redacted, fd)
+		}
+		if skipped {
+			f.skipped = append(f.skipped, fd)
+		}
+	}
+	v, _ := omittedFieldsCache.LoadOrStore(desc, f)
+	return v.(*omittedFields)
+}
+
+// redact returns msg, or a copy of it without the fields marked
+// (dbtypes.redact) or (dbtypes.skip) if it has any, so that the fields are
+// never stored and the caller's message is left unchanged.
+func redact(msg proto.Message) proto.Message {
+	fields := omitted(msg.ProtoReflect().Descriptor()).redacted
+	if len(fields) == 0 {
+		return msg
+	}
+	msg = proto.Clone(msg)
+	m := msg.ProtoReflect()
+	for _, fd := range fields {
+		m.Clear(fd)
+	}
+	return msg
+}
+
+// skip clears the fields of msg marked (dbtypes.skip), which rows written
+// before they were skipped may still hold.
+func skip(msg proto.Message) {
+	m := msg.ProtoReflect()
+	for _, fd := range omitted(m.Descriptor()).skipped {
+		m.Clear(fd)
+	}
+}
+
+// wrapError prefixes err with the name of the message type it concerns.
+func wrapError(msg proto.Message, err error) error {
+	return fmt.Errorf("dbtypes: %s: %w", msg.ProtoReflect().Descriptor().Name(), err)
+}
+
+// equal compares two possibly nil messages.
+func equal[T proto.Message](a, b T) bool {
+	if !isSet(a) || !isSet(b) {
+		return isSet(a) == isSet(b)
+	}
+	return proto.Equal(a, b)
+}
+
+func format[T proto.Message](msg T) string {
+	if !isSet(msg) {
+		return "<nil>"
+	}
+	return prototext.MarshalOptions{}.Format(msg)
+}
+
+func marshalJSON[T proto.Message](msg T) ([]byte, error) {
+	if !isSet(msg) {
+		return []byte("null"), nil
+	}
+	return marshalProtoJSON(msg)
+}
+
+func unmarshalJSON[T proto.Message](data []byte, msg *T) error {
+	if string(data) == "null" {
+		var zero T
+		*msg = zero
+		return nil
+	}
+	m := newMessage[T]()
+	if err := unmarshalProtoJSON(data, m); err != nil {
+		return err
+	}
+	*msg = m
+	return nil
+}
+
+func marshalText[T proto.Message](msg T) ([]byte, error) {
+	if !isSet(msg) {
+		return []byte{}, nil
+	}
+	// Appending keeps the result non-nil for an empty message
+	return prototext.MarshalOptions{}.MarshalAppend([]byte{}, msg)
+}
+
+func unmarshalText[T proto.Message](data []byte, msg *T) error {
+	m := newMessage[T]()
+	if err := prototext.Unmarshal(data, m); err != nil {
+		return err
+	}
+	*msg = m
+	return nil
+}
+
+// resolver is the resolver protojson takes, which also looks up extensions.
+type resolver interface {
+	protoregistry.MessageTypeResolver
+	protoregistry.ExtensionTypeResolver
+}
+
+// jsonResolver returns AnyResolver as a resolver, looking extensions up in
+// protoregistry.GlobalTypes unless AnyResolver can.
+func jsonResolver() resolver {
+	switch r := AnyResolver.(type) {
+	case nil:
+		return protoregistry.GlobalTypes
+	case resolver:
+		return r
+	default:
+		return struct {
+			protoregistry.MessageTypeResolver
+			protoregistry.ExtensionTypeResolver
+		}{r, protoregistry.GlobalTypes}
+	}
+}
+
+// marshalProtoJSON marshals msg as protojson, resolving Any fields with
+// AnyResolver.
+func marshalProtoJSON(msg proto.Message) ([]byte, error) {
+	data, err := protojson.MarshalOptions{Resolver: jsonResolver()}.Marshal(msg)
+	if err != nil {
+		if path := invalidUTF8(msg.ProtoReflect()); path != "" {
+			err = fmt.Errorf("field %s: %w", path, err)
+		}
+	}
+	return data, err
+}
+
+// invalidUTF8 returns the path of a string in m that is not valid UTF-8,
+// such as "items[2].key", or "" if there is none. protojson refuses to write
+// such strings without saying which field holds them.
+func invalidUTF8(m protoreflect.Message) string {
+	var path string
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len() && path == ""; i++ {
+				path = invalidUTF8In(fd, list.Get(i), fmt.Sprintf("%s[%d]", fd.Name(), i))
+			}
+		case fd.IsMap():
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				elem := fmt.Sprintf("%s[%v]", fd.Name(), k.Interface())
+				if fd.MapKey().Kind() == protoreflect.StringKind {
+					elem = fmt.Sprintf("%s[%q]", fd.Name(), k.String())
+					if !utf8.ValidString(k.String()) {
+						path = elem
+						return false
+					}
+				}
+				path = invalidUTF8In(fd.MapValue(), mv, elem)
+				return path == ""
+			})
+		default:
+			path = invalidUTF8In(fd, v, string(fd.Name()))
+		}
+		return path == ""
+	})
+	return path
+}
+
+// invalidUTF8In returns path if v, a single value of fd, is a string that is
+// not valid UTF-8, or the path within it of such a string if v is a message,
+// or "".
+func invalidUTF8In(fd protoreflect.FieldDescriptor, v protoreflect.Value, path string) string {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		if !utf8.ValidString(v.String()) {
+			return path
+		}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if sub := invalidUTF8(v.Message()); sub != "" {
+			return path + "." + sub
+		}
+	}
+	return ""
+}
+
+// unmarshalProtoJSON unmarshals protojson into msg, resolving Any fields with
+// AnyResolver.
+func unmarshalProtoJSON(data []byte, msg proto.Message) error {
+	return protojson.UnmarshalOptions{Resolver: jsonResolver()}.Unmarshal(data, msg)
+}
@@ -0,0 +1,714 @@
+package dbtypes_test
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/cadenya/protoc-gen-go-dbtypes/dbtypes"
+	testv1 "github.com/cadenya/protoc-gen-go-dbtypes/gen/go/test/v1"
+)
+
+func TestDBValue_RoundTrip(t *testing.T) {
+	spec := &testv1.ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "generic", Enabled: true}
+
+	dbVal, err := dbtypes.New(spec).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+
+	var got dbtypes.DBValue[*testv1.ToolSetSpec]
+	if err := got.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(spec, got.Unwrap()) {
+		t.Errorf("round-trip failed:\ngot:  %v\nwant: %v", got.Unwrap(), spec)
+	}
+}
+
+func TestDBValue_ScanSources(t *testing.T) {
+	spec := &testv1.ToolSetSpec{Name: "sources"}
+	data, err := proto.Marshal(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	str := string(data)
+
+	for name, src := range map[string]any{
+		"sql.RawBytes": sql.RawBytes(data),
+		"io.Reader":    bytes.NewReader(data),
+		"*[]byte":      &data,
+		"*string":      &str,
+	} {
+		var got dbtypes.DBValue[*testv1.ToolSetSpec]
+		if err := got.Scan(src); err != nil {
+			t.Errorf("Scan(%s) error: %v", name, err)
+			continue
+		}
+		if !proto.Equal(spec, got.Unwrap()) {
+			t.Errorf("Scan(%s) = %v, want %v", name, got.Unwrap(), spec)
+		}
+	}
+}
+
+func TestDBValue_ScanNilPointers(t *testing.T) {
+	for _, src := range []any{(*[]byte)(nil), (*string)(nil)} {
+		var got dbtypes.JSONValue[*testv1.ToolSetSpec]
+		if err := got.Scan(src); err != nil {
+			t.Errorf("Scan(%T(nil)) error: %v", src, err)
+		}
+	}
+}
+
+func TestDBValue_ScanRepeated(t *testing.T) {
+	first, err := dbtypes.New(&testv1.ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "first"}).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := dbtypes.New(&testv1.ToolSetSpec{Name: "second"}).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var x dbtypes.DBValue[*testv1.ToolSetSpec]
+	if err := x.Scan(first); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	msg := x.Unwrap()
+	if err := x.Scan(second); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if x.Unwrap() != msg {
+		t.Error("Scan() allocated a new message instead of reusing the wrapper's")
+	}
+	if want := (&testv1.ToolSetSpec{Name: "second"}); !proto.Equal(want, x.Unwrap()) {
+		t.Errorf("second Scan() = %v, want %v", x.Unwrap(), want)
+	}
+
+	if err := x.Scan(nil); err != nil {
+		t.Fatalf("Scan(nil) error: %v", err)
+	}
+	if x.Unwrap() != nil {
+		t.Errorf("Scan(nil) = %v, want no message", x.Unwrap())
+	}
+}
+
+func TestDBValue_ScanErrors(t *testing.T) {
+	var x dbtypes.DBValue[*testv1.ToolSetSpec]
+
+	err := x.Scan(123)
+	if !errors.Is(err, dbtypes.ErrInvalidScanType) {
+		t.Errorf("Scan(int) error = %v, want ErrInvalidScanType", err)
+	}
+	if want := "dbtypes: ToolSetSpec: unsupported scan type: int"; err == nil || err.Error() != want {
+		t.Errorf("Scan(int) error = %v, want %q", err, want)
+	}
+
+	var typeErr *dbtypes.ScanTypeError
+	if !errors.As(err, &typeErr) || typeErr.Message != "test.v1.ToolSetSpec" || typeErr.SourceType != reflect.TypeOf(123) {
+		t.Errorf("Scan(int) error = %#v, want a *ScanTypeError naming the message and int", err)
+	}
+
+	err = x.Scan([]byte{0xff})
+	if err == nil || !strings.HasPrefix(err.Error(), "dbtypes: ToolSetSpec: ") {
+		t.Errorf("Scan(corrupt) error = %v, want the dbtypes prefix and message name", err)
+	}
+	var decodeErr *dbtypes.DecodeError
+	if !errors.As(err, &decodeErr) || decodeErr.Message != "test.v1.ToolSetSpec" {
+		t.Errorf("Scan(corrupt) error = %#v, want a *DecodeError naming the message", err)
+	}
+	if errors.As(err, &typeErr) {
+		t.Errorf("Scan(corrupt) error = %v should not be a *ScanTypeError", err)
+	}
+}
+
+func TestString_NilPointer(t *testing.T) {
+	for name, got := range map[string]string{
+		"DBValue":   (*dbtypes.DBValue[*testv1.ToolSetSpec])(nil).String(),
+		"JSONValue": (*dbtypes.JSONValue[*testv1.JSONDocument])(nil).String(),
+		"TextValue": (*dbtypes.TextValue[*testv1.ToolSetSpec])(nil).String(),
+	} {
+		if got != "<nil>" {
+			t.Errorf("%s: String() of a nil pointer = %q, want <nil>", name, got)
+		}
+	}
+}
+
+func TestDBValue_Zero(t *testing.T) {
+	var x dbtypes.DBValue[*testv1.ToolSetSpec]
+	if v, err := x.Value(); err != nil || v != nil {
+		t.Errorf("Value() = %v, %v; want nil, nil", v, err)
+	}
+	if x.Unwrap() != nil {
+		t.Errorf("Unwrap() = %v, want nil", x.Unwrap())
+	}
+
+	// Scanning NULL leaves no message.
+	if err := x.Scan(nil); err != nil {
+		t.Fatalf("Scan(nil) error: %v", err)
+	}
+	if x.Unwrap() != nil {
+		t.Errorf("Unwrap() = %v after Scan(nil), want nil", x.Unwrap())
+	}
+}
+
+func TestJSONValue_StoredAsJSON(t *testing.T) {
+	doc := &testv1.JSONDocument{Id: "doc-1", Labels: map[string]string{"env": "prod"}}
+
+	dbVal, err := dbtypes.NewJSON(doc).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if !json.Valid(dbVal.([]byte)) {
+		t.Fatalf("Value() = %s, want JSON", dbVal)
+	}
+
+	var got dbtypes.JSONValue[*testv1.JSONDocument]
+	if err := got.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(doc, got.Unwrap()) {
+		t.Errorf("round-trip failed:\ngot:  %v\nwant: %v", got.Unwrap(), doc)
+	}
+}
+
+func TestJSONValue_ScanSources(t *testing.T) {
+	doc := &testv1.JSONDocument{Id: "doc-1", Labels: map[string]string{"env": "prod"}}
+	data := `{"id": "doc-1", "labels": {"env": "prod"}}`
+
+	for name, src := range map[string]any{
+		"[]byte":          []byte(data),
+		"string":          data,
+		"json.RawMessage": json.RawMessage(data),
+		"map[string]any":  map[string]any{"id": "doc-1", "labels": map[string]any{"env": "prod"}},
+	} {
+		var got dbtypes.JSONValue[*testv1.JSONDocument]
+		if err := got.Scan(src); err != nil {
+			t.Errorf("Scan(%s) error: %v", name, err)
+			continue
+		}
+		if !proto.Equal(doc, got.Unwrap()) {
+			t.Errorf("Scan(%s) = %v, want %v", name, got.Unwrap(), doc)
+		}
+	}
+
+	var got dbtypes.JSONValue[*testv1.JSONDocument]
+	if err := got.Scan([]any{"doc-1"}); !errors.Is(err, dbtypes.ErrInvalidScanType) {
+		t.Errorf("Scan([]any) error = %v, want ErrInvalidScanType", err)
+	}
+	if err := got.Scan(map[string]any{"id": func() {}}); err == nil || !strings.Contains(err.Error(), "marshal scan source") {
+		t.Errorf("Scan(map with a func) error = %v, want a marshal error", err)
+	}
+}
+
+func TestJSONValue_AnyResolver(t *testing.T) {
+	t.Cleanup(func() { dbtypes.AnyResolver = protoregistry.GlobalTypes })
+
+	payload, err := anypb.New(&testv1.ToolSetSpec{Name: "embedded"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := &testv1.Envelope{Id: "env-1", Payload: payload}
+
+	dbVal, err := dbtypes.NewJSON(env).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	var got dbtypes.JSONValue[*testv1.Envelope]
+	if err := got.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(env, got.Unwrap()) {
+		t.Errorf("round-trip failed:\ngot:  %v\nwant: %v", got.Unwrap(), env)
+	}
+
+	dbtypes.AnyResolver = new(protoregistry.Types)
+	if _, err := dbtypes.NewJSON(env).Value(); err == nil {
+		t.Error("Value() succeeded with a resolver that cannot find the embedded type")
+	}
+	if err := got.Scan(dbVal); err == nil {
+		t.Error("Scan() succeeded with a resolver that cannot find the embedded type")
+	}
+}
+
+func TestDBValue_MarshalJSONNil(t *testing.T) {
+	var x dbtypes.DBValue[*testv1.ToolSetSpec]
+	data, err := json.Marshal(x)
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+	if string(data) != "null" {
+		t.Errorf("json.Marshal() = %s, want null", data)
+	}
+}
+
+func TestDBValue_Reset(t *testing.T) {
+	x := dbtypes.New(&testv1.ToolSetSpec{Name: "pooled"})
+	x.Reset()
+	if x.Unwrap() != nil {
+		t.Errorf("Unwrap() = %v after Reset, want nil", x.Unwrap())
+	}
+	if v, err := x.Value(); v != nil || err != nil {
+		t.Errorf("Value() after Reset = %v, %v; want nil", v, err)
+	}
+}
+
+func TestDBValue_Size(t *testing.T) {
+	spec := &testv1.ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "generic", Enabled: true}
+	x := dbtypes.New(spec)
+	dbVal, err := x.Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if got, want := x.Size(), len(dbVal.([]byte)); got != want {
+		t.Errorf("Size() = %d, len(Value()) = %d", got, want)
+	}
+	if got := new(dbtypes.JSONValue[*testv1.ToolSetSpec]).Size(); got != 0 {
+		t.Errorf("Size() of a nil JSONValue = %d, want 0", got)
+	}
+}
+
+func TestProtoMessage(t *testing.T) {
+	spec := &testv1.ToolSetSpec{Name: "generic"}
+	wrappers := []interface{ ProtoMessage() proto.Message }{
+		dbtypes.New(spec),
+		dbtypes.NewJSON(spec),
+		dbtypes.NewText(spec),
+	}
+	for _, w := range wrappers {
+		if got := w.ProtoMessage(); got != spec {
+			t.Errorf("%T.ProtoMessage() = %v, want the wrapped message", w, got)
+		}
+	}
+
+	for _, w := range []interface{ ProtoMessage() proto.Message }{
+		(*dbtypes.DBValue[*testv1.ToolSetSpec])(nil),
+		new(dbtypes.JSONValue[*testv1.ToolSetSpec]),
+		new(dbtypes.TextValue[*testv1.ToolSetSpec]),
+	} {
+		if got := w.ProtoMessage(); got != nil {
+			t.Errorf("%T.ProtoMessage() = %#v, want a nil interface", w, got)
+		}
+	}
+}
+
+func TestEncodeBatch(t *testing.T) {
+	msgs := []*testv1.ToolSetSpec{{Name: "first", Enabled: true}, nil, {}}
+	for _, tt := range []struct {
+		name  string
+		batch func([]*testv1.ToolSetSpec) ([][]byte, error)
+		value func(*testv1.ToolSetSpec) (driver.Value, error)
+	}{
+		{"binary", dbtypes.EncodeBatch[*testv1.ToolSetSpec], func(m *testv1.ToolSetSpec) (driver.Value, error) { return dbtypes.New(m).Value() }},
+		{"json", dbtypes.EncodeJSONBatch[*testv1.ToolSetSpec], func(m *testv1.ToolSetSpec) (driver.Value, error) { return dbtypes.NewJSON(m).Value() }},
+		{"text", dbtypes.EncodeTextBatch[*testv1.ToolSetSpec], func(m *testv1.ToolSetSpec) (driver.Value, error) { return dbtypes.NewText(m).Value() }},
+	} {
+		batch, err := tt.batch(msgs)
+		if err != nil {
+			t.Fatalf("%s: batch error: %v", tt.name, err)
+		}
+		for i, msg := range msgs {
+			if msg == nil {
+				if batch[i] != nil {
+					t.Errorf("%s: entry %d = %q, want nil for a nil message", tt.name, i, batch[i])
+				}
+				continue
+			}
+			want, err := tt.value(msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(batch[i], want.([]byte)) {
+				t.Errorf("%s: entry %d = %q, want %q as returned by Value", tt.name, i, batch[i], want)
+			}
+		}
+	}
+
+	_, err := dbtypes.EncodeBatch([]*testv1.LegacyRecord{{}})
+	if err == nil || !strings.Contains(err.Error(), "batch element 0") {
+		t.Errorf("EncodeBatch() error = %v, want one naming the element", err)
+	}
+}
+
+func TestDBValue_MessageTooLarge(t *testing.T) {
+	defer dbtypes.SetMaxMessageSize(4)()
+	spec := &testv1.ToolSetSpec{Name: "oversized"}
+
+	if _, err := dbtypes.New(spec).Value(); !errors.Is(err, dbtypes.ErrMessageTooLarge) {
+		t.Errorf("Value() error = %v, want ErrMessageTooLarge", err)
+	} else if !strings.Contains(err.Error(), "ToolSetSpec") || !strings.Contains(err.Error(), "2 GiB") {
+		t.Errorf("Value() error = %q, want the message type and the wire limit", err)
+	}
+	if _, err := dbtypes.NewJSON(spec).Value(); !errors.Is(err, dbtypes.ErrMessageTooLarge) {
+		t.Errorf("JSONValue.Value() error = %v, want ErrMessageTooLarge", err)
+	}
+	if _, err := dbtypes.EncodeBatch([]*testv1.ToolSetSpec{{}, spec}); !errors.Is(err, dbtypes.ErrMessageTooLarge) || !strings.Contains(err.Error(), "batch element 1") {
+		t.Errorf("EncodeBatch() error = %v, want ErrMessageTooLarge naming the element", err)
+	}
+}
+
+func TestDBValue_Redact(t *testing.T) {
+	acct := &testv1.ServiceAccount{Name: "ci", ApiToken: "secret-api-token", RecoveryCodes: []string{"recovery-1"}}
+	want := proto.Clone(acct)
+
+	for name, value := range map[string]func() (driver.Value, error){
+		"binary": dbtypes.New(acct).Value,
+		"json":   dbtypes.NewJSON(acct).Value,
+		"text":   dbtypes.NewText(acct).Value,
+	} {
+		val, err := value()
+		if err != nil {
+			t.Fatalf("%s: Value() error: %v", name, err)
+		}
+		if data := fmt.Sprint(val); strings.Contains(data, "secret-api-token") || strings.Contains(data, "recovery-1") {
+			t.Errorf("%s: Value() = %q, want the redacted fields left out", name, data)
+		}
+	}
+	batch, err := dbtypes.EncodeBatch([]*testv1.ServiceAccount{acct})
+	if err != nil {
+		t.Fatalf("EncodeBatch() error: %v", err)
+	}
+	decoded := &testv1.ServiceAccount{}
+	if err := proto.Unmarshal(batch[0], decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(decoded, &testv1.ServiceAccount{Name: "ci"}) {
+		t.Errorf("EncodeBatch() stored %v, want the redacted fields left out", decoded)
+	}
+	if !proto.Equal(acct, want) {
+		t.Errorf("encoding changed the caller's message to %v, want %v", acct, want)
+	}
+}
+
+func TestDBValue_Skip(t *testing.T) {
+	cached := &testv1.ProfileCache{UserId: "u1", DisplayName: "Ada", Biography: "long biography", Tags: []string{"admin"}}
+	want := &testv1.ProfileCache{UserId: "u1", DisplayName: "Ada", Tags: []string{"admin"}}
+
+	val, err := dbtypes.New(cached).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if strings.Contains(fmt.Sprint(val), "long biography") {
+		t.Errorf("Value() = %q, want the skipped field left out", val)
+	}
+	if cached.Biography == "" {
+		t.Error("Value() cleared the skipped field of the caller's message")
+	}
+
+	// Rows written before the field was skipped still hold it
+	old, err := proto.Marshal(cached)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got dbtypes.DBValue[*testv1.ProfileCache]
+	if err := got.Scan(old); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(got.Unwrap(), want) {
+		t.Errorf("Scan() = %v, want %v", got.Unwrap(), want)
+	}
+}
+
+func TestValues(t *testing.T) {
+	spec := &testv1.ToolSetSpec{Name: "first"}
+	for name, test := range map[string]struct {
+		values func([]*testv1.ToolSetSpec) ([]driver.Value, error)
+		value  func() (driver.Value, error)
+	}{
+		"binary": {dbtypes.Values[*testv1.ToolSetSpec], dbtypes.New(spec).Value},
+		"json":   {dbtypes.JSONValues[*testv1.ToolSetSpec], dbtypes.NewJSON(spec).Value},
+		"text":   {dbtypes.TextValues[*testv1.ToolSetSpec], dbtypes.NewText(spec).Value},
+	} {
+		vals, err := test.values([]*testv1.ToolSetSpec{spec, nil})
+		if err != nil {
+			t.Fatalf("%s: error: %v", name, err)
+		}
+		want, err := test.value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(vals) != 2 || !reflect.DeepEqual(vals[0], want) || vals[1] != nil {
+			t.Errorf("%s: values = %v, want [%v <nil>]", name, vals, want)
+		}
+	}
+}
+
+func TestScanFields(t *testing.T) {
+	container := &testv1.Container{Id: "c", Spec: &testv1.ToolSetSpec{Name: "spec"}}
+	for name, test := range map[string]struct {
+		value      func() (driver.Value, error)
+		scanFields func(src any, fieldPaths ...string) (proto.Message, error)
+	}{
+		"binary": {dbtypes.New(container).Value, func(src any, paths ...string) (proto.Message, error) {
+			var v dbtypes.DBValue[*testv1.Container]
+			err := v.ScanFields(src, paths...)
+			return v.Unwrap(), err
+		}},
+		"json": {dbtypes.NewJSON(container).Value, func(src any, paths ...string) (proto.Message, error) {
+			var v dbtypes.JSONValue[*testv1.Container]
+			err := v.ScanFields(src, paths...)
+			return v.Unwrap(), err
+		}},
+		"text": {dbtypes.NewText(container).Value, func(src any, paths ...string) (proto.Message, error) {
+			var v dbtypes.TextValue[*testv1.Container]
+			err := v.ScanFields(src, paths...)
+			return v.Unwrap(), err
+		}},
+	} {
+		val, err := test.value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := test.scanFields(val, "id")
+		if err != nil {
+			t.Fatalf("%s: ScanFields() error: %v", name, err)
+		}
+		if want := (&testv1.Container{Id: "c"}); !proto.Equal(got, want) {
+			t.Errorf("%s: ScanFields(id) = %v, want %v", name, got, want)
+		}
+		if _, err := test.scanFields(val, "missing"); err == nil {
+			t.Errorf("%s: ScanFields() of an unknown field succeeded, want an error", name)
+		}
+	}
+}
+
+func TestValueMasked(t *testing.T) {
+	spec := &testv1.ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "masked", Enabled: true}
+	mask := &fieldmaskpb.FieldMask{Paths: []string{"name"}}
+	want := &testv1.ToolSetSpec{Name: "masked"}
+	for name, test := range map[string]struct {
+		valueMasked func(*fieldmaskpb.FieldMask) (driver.Value, error)
+		value       func() (driver.Value, error)
+	}{
+		"binary": {dbtypes.New(spec).ValueMasked, dbtypes.New(want).Value},
+		"json":   {dbtypes.NewJSON(spec).ValueMasked, dbtypes.NewJSON(want).Value},
+		"text":   {dbtypes.NewText(spec).ValueMasked, dbtypes.NewText(want).Value},
+	} {
+		got, err := test.valueMasked(mask)
+		if err != nil {
+			t.Fatalf("%s: ValueMasked() error: %v", name, err)
+		}
+		expected, err := test.value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("%s: ValueMasked(name) = %v, want %v", name, got, expected)
+		}
+		if _, err := test.valueMasked(&fieldmaskpb.FieldMask{Paths: []string{"name.first"}}); err == nil {
+			t.Errorf("%s: ValueMasked() of a path below a string succeeded, want an error", name)
+		}
+	}
+	if len(spec.ToolIds) != 1 || !spec.Enabled {
+		t.Errorf("ValueMasked() changed the wrapped message: %v", spec)
+	}
+}
+
+func TestJSONValue_InvalidUTF8(t *testing.T) {
+	_, err := dbtypes.NewJSON(&testv1.Container{Items: []*testv1.Container_Item{{Key: "k"}, {Key: "\xff"}}}).Value()
+	if want := "dbtypes: Container: field items[1].key: "; err == nil || !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("Value() error = %v, want it to start with %q", err, want)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	var prefs dbtypes.JSONValue[*testv1.UserPreferences]
+	prefs.Merge(&testv1.UserPreferences{Settings: map[string]string{"tz": "UTC"}})
+	prefs.Merge(&testv1.UserPreferences{Theme: "dark", Settings: map[string]string{"unit": "metric"}})
+	prefs.Merge(nil)
+
+	want := &testv1.UserPreferences{Theme: "dark", Settings: map[string]string{"tz": "UTC", "unit": "metric"}}
+	if !proto.Equal(prefs.Unwrap(), want) {
+		t.Errorf("Merge() = %v, want %v", prefs.Unwrap(), want)
+	}
+}
+
+func TestCodecs(t *testing.T) {
+	spec := &testv1.ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "codec", Enabled: true}
+	for _, tt := range []struct {
+		name  string
+		codec dbtypes.Codec
+		value func(*testv1.ToolSetSpec) (driver.Value, error)
+	}{
+		{"binary", dbtypes.BinaryCodec(), func(m *testv1.ToolSetSpec) (driver.Value, error) { return dbtypes.New(m).Value() }},
+		{"json", dbtypes.JSONCodec(), func(m *testv1.ToolSetSpec) (driver.Value, error) { return dbtypes.NewJSON(m).Value() }},
+		{"text", dbtypes.TextCodec(), func(m *testv1.ToolSetSpec) (driver.Value, error) { return dbtypes.NewText(m).Value() }},
+	} {
+		data, err := tt.codec.Encode(spec)
+		if err != nil {
+			t.Fatalf("%s: Encode() error: %v", tt.name, err)
+		}
+		want, err := tt.value(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, want.([]byte)) {
+			t.Errorf("%s: Encode() = %q, want %q as returned by Value", tt.name, data, want)
+		}
+
+		got := &testv1.ToolSetSpec{}
+		if err := tt.codec.Decode(data, got); err != nil {
+			t.Fatalf("%s: Decode() error: %v", tt.name, err)
+		}
+		if !proto.Equal(got, spec) {
+			t.Errorf("%s: Decode() = %v, want %v", tt.name, got, spec)
+		}
+
+		var decodeErr *dbtypes.DecodeError
+		if err := tt.codec.Decode([]byte{0xff, 0xff, 0xff}, &testv1.ToolSetSpec{}); !errors.As(err, &decodeErr) {
+			t.Errorf("%s: Decode() of corrupt data error = %v, want a *DecodeError", tt.name, err)
+		}
+	}
+}
+
+func TestDBValue_WriteToReadFrom(t *testing.T) {
+	spec := &testv1.ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "stream"}
+	doc := &testv1.JSONDocument{Id: "doc-1"}
+	text := &testv1.TextDocument{Id: "doc-1", Tags: []string{"t"}}
+
+	for name, tt := range map[string]struct {
+		src  io.WriterTo
+		read func(io.Reader) (proto.Message, error)
+		want proto.Message
+	}{
+		"DBValue": {dbtypes.New(spec), func(r io.Reader) (proto.Message, error) {
+			var x dbtypes.DBValue[*testv1.ToolSetSpec]
+			_, err := x.ReadFrom(r)
+			return x.Unwrap(), err
+		}, spec},
+		"JSONValue": {dbtypes.NewJSON(doc), func(r io.Reader) (proto.Message, error) {
+			var x dbtypes.JSONValue[*testv1.JSONDocument]
+			_, err := x.ReadFrom(r)
+			return x.Unwrap(), err
+		}, doc},
+		"TextValue": {dbtypes.NewText(text), func(r io.Reader) (proto.Message, error) {
+			var x dbtypes.TextValue[*testv1.TextDocument]
+			_, err := x.ReadFrom(r)
+			return x.Unwrap(), err
+		}, text},
+	} {
+		var buf bytes.Buffer
+		n, err := tt.src.WriteTo(&buf)
+		if err != nil || n != int64(buf.Len()) {
+			t.Errorf("%s: WriteTo() = %d, %v; wrote %d bytes", name, n, err, buf.Len())
+			continue
+		}
+		got, err := tt.read(&buf)
+		if err != nil {
+			t.Errorf("%s: ReadFrom() error: %v", name, err)
+			continue
+		}
+		if !proto.Equal(got, tt.want) {
+			t.Errorf("%s: stream round-trip = %v, want %v", name, got, tt.want)
+		}
+	}
+}
+
+func TestDBValue_Gob(t *testing.T) {
+	type record struct {
+		Spec  *dbtypes.DBValue[*testv1.ToolSetSpec]
+		Doc   dbtypes.JSONValue[*testv1.JSONDocument]
+		Empty dbtypes.TextValue[*testv1.TextDocument]
+	}
+	in := record{
+		Spec: dbtypes.New(&testv1.ToolSetSpec{Name: "gob", ToolIds: []string{"tool-1"}}),
+		Doc:  *dbtypes.NewJSON(&testv1.JSONDocument{Id: "doc-1"}),
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatalf("gob Encode() error: %v", err)
+	}
+	var out record
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatalf("gob Decode() error: %v", err)
+	}
+	if out.Spec == nil || !proto.Equal(out.Spec.Unwrap(), in.Spec.Unwrap()) {
+		t.Errorf("Spec = %v, want %v", out.Spec, in.Spec.Unwrap())
+	}
+	if !proto.Equal(out.Doc.Unwrap(), in.Doc.Unwrap()) {
+		t.Errorf("Doc = %v, want %v", out.Doc.Unwrap(), in.Doc.Unwrap())
+	}
+	if out.Empty.Unwrap() != nil {
+		t.Errorf("Empty = %v, want a nil message", out.Empty.Unwrap())
+	}
+}
+
+func TestDBValue_GetOrInit(t *testing.T) {
+	var x dbtypes.DBValue[*testv1.ToolSetSpec]
+	if x.Unwrap() != nil {
+		t.Fatalf("Unwrap() = %v, want nil", x.Unwrap())
+	}
+	msg := x.GetOrInit()
+	if msg == nil {
+		t.Fatal("GetOrInit() = nil, want an empty message")
+	}
+	msg.Name = "initialized"
+	if x.Unwrap() != msg {
+		t.Error("GetOrInit() did not store the message it returned")
+	}
+
+	var text dbtypes.TextValue[*testv1.TextDocument]
+	if text.GetOrInit() == nil || text.Unwrap() == nil {
+		t.Error("TextValue.GetOrInit() should store an empty message")
+	}
+}
+
+func TestDBValue_MarshalText(t *testing.T) {
+	spec := &testv1.ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "generic"}
+
+	var _ encoding.TextMarshaler = dbtypes.New(spec)
+	data, err := dbtypes.New(spec).MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText() error: %v", err)
+	}
+	var got dbtypes.DBValue[*testv1.ToolSetSpec]
+	if err := got.UnmarshalText(data); err != nil {
+		t.Fatalf("UnmarshalText() error: %v", err)
+	}
+	if !proto.Equal(spec, got.Unwrap()) {
+		t.Errorf("text round-trip failed:\ngot:  %v\nwant: %v", got.Unwrap(), spec)
+	}
+
+	var nilValue *dbtypes.JSONValue[*testv1.ToolSetSpec]
+	for _, m := range []encoding.TextMarshaler{nilValue, &dbtypes.TextValue[*testv1.ToolSetSpec]{}} {
+		if data, err := m.MarshalText(); err != nil || data == nil || len(data) != 0 {
+			t.Errorf("MarshalText() of %T = %#v, %v; want an empty slice", m, data, err)
+		}
+	}
+	if err := got.UnmarshalText(nil); err != nil || !proto.Equal(&testv1.ToolSetSpec{}, got.Unwrap()) {
+		t.Errorf("UnmarshalText(nil) = %v, %v; want an empty message", got.Unwrap(), err)
+	}
+}
+
+func TestTextValue_RoundTrip(t *testing.T) {
+	doc := &testv1.TextDocument{Id: "doc-1", Tags: []string{"a", "b"}, Labels: map[string]string{"env": "prod"}}
+
+	dbVal, err := dbtypes.NewText(doc).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+
+	var got dbtypes.TextValue[*testv1.TextDocument]
+	if err := got.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(doc, got.Unwrap()) {
+		t.Errorf("round-trip failed:\ngot:  %v\nwant: %v", got.Unwrap(), doc)
+	}
+}
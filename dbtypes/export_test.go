@@ -0,0 +1,9 @@
+package dbtypes
+
+// SetMaxMessageSize lowers the limit checkSize enforces so that tests can
+// exceed it without allocating 2 GiB, and returns a func restoring it.
+func SetMaxMessageSize(n int) (restore func()) {
+	old := maxMessageSize
+	maxMessageSize = n
+	return func() { maxMessageSize = old }
+}
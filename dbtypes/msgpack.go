@@ -0,0 +1,313 @@
+package dbtypes
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// MarshalMsgpack encodes msg as MessagePack, walking its fields with
+// protoreflect. It has the signature of proto.Marshal, so that code generated
+// with format=msgpack can use it in Value.
+//
+// The data model is the one MarshalCBOR uses: a message becomes a map keyed
+// by proto field name, repeated fields arrays, map fields maps and enums
+// their numbers. Integers use the smallest encoding that holds them. Equal
+// messages produce equal bytes. Unknown fields and extensions are dropped;
+// google.protobuf.Any and groups fail with ErrUnsupportedField.
+func MarshalMsgpack(msg proto.Message) ([]byte, error) {
+	return MarshalOptions{}.MarshalMsgpack(msg)
+}
+
+// MarshalMsgpack is like the package-level MarshalMsgpack, but follows o.
+func (o MarshalOptions) MarshalMsgpack(msg proto.Message) ([]byte, error) {
+	e := &msgpackEncoder{}
+	if err := o.encodeTree(e, msg); err != nil {
+		return nil, fmt.Errorf("msgpack: %w", err)
+	}
+	return e.buf, nil
+}
+
+// UnmarshalMsgpack decodes MessagePack produced by MarshalMsgpack, or by
+// another encoder using the same data model, into msg. It has the signature
+// of proto.Unmarshal and likewise resets msg first.
+//
+// Extension types, unknown field names and values that don't fit a field's
+// type are errors.
+func UnmarshalMsgpack(data []byte, msg proto.Message) error {
+	return UnmarshalOptions{}.UnmarshalMsgpack(data, msg)
+}
+
+// UnmarshalMsgpack is like the package-level UnmarshalMsgpack, but follows o.
+func (o UnmarshalOptions) UnmarshalMsgpack(data []byte, msg proto.Message) error {
+	proto.Reset(msg)
+	d := &msgpackDecoder{data: data}
+	tree, err := d.value(0)
+	if err == nil && d.pos < len(d.data) {
+		err = fmt.Errorf("%d trailing bytes", len(d.data)-d.pos)
+	}
+	if err == nil {
+		err = o.decodeTree(tree, msg)
+	}
+	if err != nil {
+		return fmt.Errorf("msgpack: %w", err)
+	}
+	return nil
+}
+
+type msgpackEncoder struct {
+	buf []byte
+}
+
+func (e *msgpackEncoder) mapHeader(n int) {
+	switch {
+	case n <= 15:
+		e.buf = append(e.buf, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		e.buf = binary.BigEndian.AppendUint16(append(e.buf, 0xde), uint16(n))
+	default:
+		e.buf = binary.BigEndian.AppendUint32(append(e.buf, 0xdf), uint32(n))
+	}
+}
+
+func (e *msgpackEncoder) arrayHeader(n int) {
+	switch {
+	case n <= 15:
+		e.buf = append(e.buf, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		e.buf = binary.BigEndian.AppendUint16(append(e.buf, 0xdc), uint16(n))
+	default:
+		e.buf = binary.BigEndian.AppendUint32(append(e.buf, 0xdd), uint32(n))
+	}
+}
+
+func (e *msgpackEncoder) boolean(v bool) {
+	if v {
+		e.buf = append(e.buf, 0xc3)
+	} else {
+		e.buf = append(e.buf, 0xc2)
+	}
+}
+
+func (e *msgpackEncoder) integer(v int64) {
+	switch {
+	case v >= 0:
+		e.unsigned(uint64(v))
+	case v >= -32:
+		// negative fixint
+		e.buf = append(e.buf, byte(v))
+	case v >= math.MinInt8:
+		e.buf = append(e.buf, 0xd0, byte(v))
+	case v >= math.MinInt16:
+		e.buf = binary.BigEndian.AppendUint16(append(e.buf, 0xd1), uint16(v))
+	case v >= math.MinInt32:
+		e.buf = binary.BigEndian.AppendUint32(append(e.buf, 0xd2), uint32(v))
+	default:
+		e.buf = binary.BigEndian.AppendUint64(append(e.buf, 0xd3), uint64(v))
+	}
+}
+
+func (e *msgpackEncoder) unsigned(v uint64) {
+	switch {
+	case v <= 0x7f:
+		// positive fixint
+		e.buf = append(e.buf, byte(v))
+	case v <= math.MaxUint8:
+		e.buf = append(e.buf, 0xcc, byte(v))
+	case v <= math.MaxUint16:
+		e.buf = binary.BigEndian.AppendUint16(append(e.buf, 0xcd), uint16(v))
+	case v <= math.MaxUint32:
+		e.buf = binary.BigEndian.AppendUint32(append(e.buf, 0xce), uint32(v))
+	default:
+		e.buf = binary.BigEndian.AppendUint64(append(e.buf, 0xcf), v)
+	}
+}
+
+func (e *msgpackEncoder) float32(v float32) {
+	e.buf = binary.BigEndian.AppendUint32(append(e.buf, 0xca), math.Float32bits(v))
+}
+
+func (e *msgpackEncoder) float64(v float64) {
+	e.buf = binary.BigEndian.AppendUint64(append(e.buf, 0xcb), math.Float64bits(v))
+}
+
+func (e *msgpackEncoder) text(s string) {
+	switch n := len(s); {
+	case n <= 31:
+		e.buf = append(e.buf, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = binary.BigEndian.AppendUint16(append(e.buf, 0xda), uint16(n))
+	default:
+		e.buf = binary.BigEndian.AppendUint32(append(e.buf, 0xdb), uint32(n))
+	}
+	e.buf = append(e.buf, s...)
+}
+
+func (e *msgpackEncoder) bytes(b []byte) {
+	switch n := len(b); {
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = binary.BigEndian.AppendUint16(append(e.buf, 0xc5), uint16(n))
+	default:
+		e.buf = binary.BigEndian.AppendUint32(append(e.buf, 0xc6), uint32(n))
+	}
+	e.buf = append(e.buf, b...)
+}
+
+var errMsgpackTruncated = errors.New("unexpected end of data")
+
+type msgpackDecoder struct {
+	data []byte
+	pos  int
+}
+
+// value decodes the object at the current position.
+func (d *msgpackDecoder) value(depth int) (any, error) {
+	if depth > maxDepth {
+		return nil, errors.New("nesting too deep")
+	}
+	if d.pos >= len(d.data) {
+		return nil, errMsgpackTruncated
+	}
+	b := d.data[d.pos]
+	d.pos++
+
+	switch {
+	case b <= 0x7f:
+		return uint64(b), nil
+	case b >= 0xe0:
+		return int64(int8(b)), nil
+	case b&0xf0 == 0x80:
+		return d.mapEntries(int(b&0x0f), depth)
+	case b&0xf0 == 0x90:
+		return d.array(int(b&0x0f), depth)
+	case b&0xe0 == 0xa0:
+		return d.str(int(b & 0x1f))
+	}
+
+	switch b {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.uint(1 << (b - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		return d.raw(n)
+	case 0xca:
+		n, err := d.uint(4)
+		return float64(math.Float32frombits(uint32(n))), err
+	case 0xcb:
+		n, err := d.uint(8)
+		return math.Float64frombits(n), err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		return d.uint(1 << (b - 0xcc))
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (b - 0xd0)
+		n, err := d.uint(size)
+		if err != nil {
+			return nil, err
+		}
+		// Sign-extend, then keep non-negative integers unsigned
+		shift := 64 - 8*size
+		v := int64(n<<shift) >> shift
+		if v >= 0 {
+			return uint64(v), nil
+		}
+		return v, nil
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.uint(1 << (b - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.str(int(n))
+	case 0xdc, 0xdd:
+		n, err := d.uint(2 << (b - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.array(int(n), depth)
+	case 0xde, 0xdf:
+		n, err := d.uint(2 << (b - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.mapEntries(int(n), depth)
+	case 0xc7, 0xc8, 0xc9, 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return nil, errors.New("unsupported extension type")
+	}
+	return nil, fmt.Errorf("malformed object 0x%02x", b)
+}
+
+// uint reads a big-endian unsigned integer of size bytes.
+func (d *msgpackDecoder) uint(size int) (uint64, error) {
+	if len(d.data)-d.pos < size {
+		return 0, errMsgpackTruncated
+	}
+	var n uint64
+	for _, b := range d.data[d.pos : d.pos+size] {
+		n = n<<8 | uint64(b)
+	}
+	d.pos += size
+	return n, nil
+}
+
+// raw reads n bytes into a new slice.
+func (d *msgpackDecoder) raw(n uint64) ([]byte, error) {
+	if uint64(len(d.data)-d.pos) < n {
+		return nil, errMsgpackTruncated
+	}
+	b := append([]byte{}, d.data[d.pos:d.pos+int(n)]...)
+	d.pos += int(n)
+	return b, nil
+}
+
+func (d *msgpackDecoder) str(n int) (any, error) {
+	b, err := d.raw(uint64(n))
+	if err != nil {
+		return nil, err
+	}
+	if !utf8.Valid(b) {
+		return nil, errors.New("str is not valid UTF-8")
+	}
+	return string(b), nil
+}
+
+func (d *msgpackDecoder) array(n int, depth int) (any, error) {
+	items := []any{}
+	for range n {
+		item, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (d *msgpackDecoder) mapEntries(n int, depth int) (any, error) {
+	entries := []mapEntry{}
+	for range n {
+		key, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		val, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, mapEntry{key, val})
+	}
+	return entries, nil
+}
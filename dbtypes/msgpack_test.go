@@ -0,0 +1,243 @@
+package dbtypes_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/typepb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/cadenya/protoc-gen-go-dbtypes/dbtypes"
+	testv1 "github.com/cadenya/protoc-gen-go-dbtypes/gen/go/test/v1"
+)
+
+func TestMsgpack_RoundTrip(t *testing.T) {
+	for _, msg := range []proto.Message{
+		&testv1.ToolSetSpec{ToolIds: []string{"a", "b"}, Name: "tools", Enabled: true},
+		&testv1.ToolSetSpec{ToolIds: make([]string, 20), Name: strings.Repeat("n", 300)},
+		&testv1.UserPreferences{Theme: "dark", Language: "en", Settings: map[string]string{"tz": "UTC", "font": "mono"}},
+		&testv1.Container{
+			Id:    "c1",
+			Spec:  &testv1.ToolSetSpec{Name: "nested"},
+			Items: []*testv1.Container_Item{{Key: "k1", Value: "v1"}, {Key: "k2"}},
+		},
+		&testv1.Container{},
+		&testv1.Payload{Id: "p1", Content: &testv1.Payload_Text{}},
+		&testv1.Payload{Content: &testv1.Payload_Spec{Spec: &testv1.ToolSetSpec{}}},
+		&testv1.LegacyRecord{Id: proto.String("r"), Priority: proto.Int32(5), Archived: proto.Bool(false), State: testv1.LegacyState_LEGACY_STATE_ACTIVE.Enum()},
+		&durationpb.Duration{Seconds: math.MinInt64, Nanos: -1},
+		&durationpb.Duration{Seconds: -200, Nanos: -40000},
+		wrapperspb.Int32(math.MinInt32),
+		wrapperspb.UInt64(1 << 63),
+		wrapperspb.UInt32(70000),
+		wrapperspb.Float(1.5),
+		wrapperspb.Double(-0.25),
+		wrapperspb.Bytes(make([]byte, 300)),
+		&typepb.Field{Kind: typepb.Field_TYPE_STRING, Number: 7, Packed: true},
+		&structpb.Struct{Fields: map[string]*structpb.Value{"n": structpb.NewNumberValue(3), "s": structpb.NewStringValue("x")}},
+	} {
+		data, err := dbtypes.MarshalMsgpack(msg)
+		if err != nil {
+			t.Fatalf("MarshalMsgpack(%T) error: %v", msg, err)
+		}
+		got := msg.ProtoReflect().New().Interface()
+		if err := dbtypes.UnmarshalMsgpack(data, got); err != nil {
+			t.Fatalf("UnmarshalMsgpack(%T) error: %v", msg, err)
+		}
+		if !proto.Equal(got, msg) {
+			t.Errorf("round-trip failed:\ngot:  %v\nwant: %v", got, msg)
+		}
+	}
+}
+
+func TestMsgpack_Encoding(t *testing.T) {
+	data, err := dbtypes.MarshalMsgpack(&testv1.ToolSetSpec{ToolIds: []string{"a"}, Enabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// {"tool_ids": ["a"], "enabled": true}
+	if want := "82a8746f6f6c5f69647391a161" + "a7656e61626c6564c3"; hex.EncodeToString(data) != want {
+		t.Errorf("MarshalMsgpack() = %x, want %s", data, want)
+	}
+
+	// Map entries are sorted, so equal messages encode equally
+	settings := map[string]string{}
+	for _, k := range []string{"e", "d", "c", "b", "a"} {
+		settings[k] = k
+	}
+	first, _ := dbtypes.MarshalMsgpack(&testv1.UserPreferences{Settings: settings})
+	for range 10 {
+		again, _ := dbtypes.MarshalMsgpack(&testv1.UserPreferences{Settings: settings})
+		if !bytes.Equal(first, again) {
+			t.Fatalf("MarshalMsgpack() not deterministic: %x != %x", first, again)
+		}
+	}
+}
+
+func TestMsgpack_Any(t *testing.T) {
+	value, err := anypb.New(wrapperspb.String("x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = dbtypes.MarshalMsgpack(&typepb.Option{Name: "opt", Value: value})
+	if !errors.Is(err, dbtypes.ErrUnsupportedField) {
+		t.Errorf("MarshalMsgpack(Any field) error = %v, want ErrUnsupportedField", err)
+	}
+
+	// {"value": {}}
+	err = dbtypes.UnmarshalMsgpack([]byte{0x81, 0xa5, 'v', 'a', 'l', 'u', 'e', 0x80}, &typepb.Option{})
+	if !errors.Is(err, dbtypes.ErrUnsupportedField) {
+		t.Errorf("UnmarshalMsgpack(Any field) error = %v, want ErrUnsupportedField", err)
+	}
+}
+
+func TestMsgpack_Decode(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		hex  string
+		want *testv1.ToolSetSpec
+	}{
+		// {"name": "x", "tool_ids": ["a"]} with map16, str8 and array16
+		{"wide headers", "de0002a46e616d65d90178a8" + "746f6f6c5f696473dc0001a161", &testv1.ToolSetSpec{Name: "x", ToolIds: []string{"a"}}},
+		// {"toolIds": ["a"]}, JSON name
+		{"json name", "81a7746f6f6c49647391a161", &testv1.ToolSetSpec{ToolIds: []string{"a"}}},
+		// {"name": nil}
+		{"nil", "81a46e616d65c0", &testv1.ToolSetSpec{}},
+	} {
+		data, err := hex.DecodeString(tt.hex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := &testv1.ToolSetSpec{Name: "stale"}
+		if err := dbtypes.UnmarshalMsgpack(data, got); err != nil {
+			t.Errorf("%s: UnmarshalMsgpack() error: %v", tt.name, err)
+			continue
+		}
+		if !proto.Equal(got, tt.want) {
+			t.Errorf("%s: UnmarshalMsgpack() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Signed encodings of non-negative integers fit unsigned fields: {"value": int8 5}
+	got := &wrapperspb.UInt32Value{}
+	if err := dbtypes.UnmarshalMsgpack([]byte{0x81, 0xa5, 'v', 'a', 'l', 'u', 'e', 0xd0, 0x05}, got); err != nil || got.GetValue() != 5 {
+		t.Errorf("UnmarshalMsgpack(int8) = %v, %v; want 5", got, err)
+	}
+}
+
+func TestMsgpack_DecodeErrors(t *testing.T) {
+	for name, hexData := range map[string]string{
+		"empty":          "",
+		"truncated":      "81a46e616d",
+		"trailing bytes": "8000",
+		"not a map":      "90",
+		"unknown field":  "81a3666f6f01",
+		"wrong type":     "81a46e616d6501",
+		"nil element":    "81a8746f6f6c5f69647391c0",
+		"extension":      "81a46e616d65d40100",
+		"never used":     "c1",
+		"invalid utf-8":  "81a46e616d65a1ff",
+		"not a boolean":  "81a7656e61626c656401",
+		"short length":   "81a46e616d65da00",
+		"deep nesting":   repeatHex("91", 200),
+	} {
+		data, err := hex.DecodeString(hexData)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := dbtypes.UnmarshalMsgpack(data, &testv1.ToolSetSpec{}); err == nil {
+			t.Errorf("%s: UnmarshalMsgpack() succeeded, want an error", name)
+		}
+	}
+
+	// Integers must fit the field
+	if err := dbtypes.UnmarshalMsgpack([]byte{0x81, 0xa5, 'n', 'a', 'n', 'o', 's', 0xcf, 1, 0, 0, 0, 0, 0, 0, 0}, &durationpb.Duration{}); err == nil {
+		t.Error("UnmarshalMsgpack() accepted an int32 overflow")
+	}
+	if err := dbtypes.UnmarshalMsgpack([]byte{0x81, 0xa5, 'v', 'a', 'l', 'u', 'e', 0xff}, &wrapperspb.UInt32Value{}); err == nil {
+		t.Error("UnmarshalMsgpack() accepted a negative uint32")
+	}
+}
+
+func TestMsgpack_OneofConflict(t *testing.T) {
+	// {"text": "a", "count": 1} selects two cases of the same oneof
+	data, err := hex.DecodeString("82a474657874a161a5636f756e7401")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dbtypes.UnmarshalMsgpack(data, &testv1.Payload{}); err == nil || !strings.Contains(err.Error(), "oneof content") {
+		t.Errorf("UnmarshalMsgpack() error = %v, want an error naming the oneof", err)
+	}
+}
+
+func TestMsgpack_DiscardUnknown(t *testing.T) {
+	// {"name": "x", "foo": {"bar": [1]}}
+	data, err := hex.DecodeString("82a46e616d65a178a3666f6f81a3626172" + "9101")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dbtypes.UnmarshalMsgpack(data, &testv1.ToolSetSpec{}); err == nil {
+		t.Error("UnmarshalMsgpack() accepted an unknown field")
+	}
+	got := &testv1.ToolSetSpec{}
+	if err := (dbtypes.UnmarshalOptions{DiscardUnknown: true}).UnmarshalMsgpack(data, got); err != nil {
+		t.Fatalf("UnmarshalMsgpack(DiscardUnknown) error: %v", err)
+	}
+	if want := (&testv1.ToolSetSpec{Name: "x"}); !proto.Equal(got, want) {
+		t.Errorf("UnmarshalMsgpack(DiscardUnknown) = %v, want %v", got, want)
+	}
+}
+
+func TestMsgpack_Proto2(t *testing.T) {
+	// Required fields must be set on both sides
+	if _, err := dbtypes.MarshalMsgpack(&testv1.LegacyRecord{Name: proto.String("no id")}); err == nil {
+		t.Error("MarshalMsgpack() accepted a message without its required field")
+	}
+	empty, err := dbtypes.MarshalMsgpack(&testv1.ToolSetSpec{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dbtypes.UnmarshalMsgpack(empty, &testv1.LegacyRecord{}); err == nil {
+		t.Error("UnmarshalMsgpack() accepted a document without the required field")
+	}
+
+	// Unless partial messages are allowed
+	draft := &testv1.LegacyRecord{Name: proto.String("draft")}
+	data, err := dbtypes.MarshalOptions{AllowPartial: true}.MarshalMsgpack(draft)
+	if err != nil {
+		t.Fatalf("MarshalMsgpack(AllowPartial) error: %v", err)
+	}
+	got := &testv1.LegacyRecord{}
+	if err := (dbtypes.UnmarshalOptions{AllowPartial: true}).UnmarshalMsgpack(data, got); err != nil {
+		t.Fatalf("UnmarshalMsgpack(AllowPartial) error: %v", err)
+	}
+	if !proto.Equal(got, draft) {
+		t.Errorf("UnmarshalMsgpack(AllowPartial) = %v, want %v", got, draft)
+	}
+
+	// A closed enum only holds its declared numbers: {"id": "r", "state": 7}
+	data, err = hex.DecodeString("82a26964a172a5737461746507")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dbtypes.UnmarshalMsgpack(data, &testv1.LegacyRecord{}); err == nil || !strings.Contains(err.Error(), "closed enum") {
+		t.Errorf("UnmarshalMsgpack() error = %v, want an error naming the closed enum", err)
+	}
+	if _, err := dbtypes.MarshalMsgpack(&testv1.LegacyRecord{Id: proto.String("r"), State: testv1.LegacyState(7).Enum()}); err == nil {
+		t.Error("MarshalMsgpack() accepted an undeclared number of a closed enum")
+	}
+}
+
+func TestMsgpack_InvalidUTF8(t *testing.T) {
+	if _, err := dbtypes.MarshalMsgpack(&testv1.ToolSetSpec{Name: "\xff"}); err == nil {
+		t.Error("MarshalMsgpack() accepted a string that is not valid UTF-8")
+	}
+}
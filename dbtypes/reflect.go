@@ -0,0 +1,424 @@
+package dbtypes
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// This file maps messages to the data model of the self-describing formats:
+// a message is a map from field name to value, repeated fields are arrays,
+// map fields are maps keyed by their native key type, enums are integers,
+// and the remaining scalars keep their type. Only populated fields are
+// written, in field declaration order, and map entries are sorted by key, so
+// equal messages encode to equal bytes. A oneof is written as its selected
+// member, which counts as populated even when set to its zero value.
+//
+// Unknown fields and extensions are not stored. google.protobuf.Any and
+// group fields cannot be represented and fail with ErrUnsupportedField.
+//
+// proto2 messages follow the binary format: fields with explicit presence
+// are written when set, even to their default, required fields must be set
+// on both sides unless the options set AllowPartial, and a closed enum field
+// only accepts its declared numbers.
+
+// ErrUnsupportedField is wrapped by the error the CBOR and MessagePack
+// functions return for a field the format cannot represent, such as a
+// google.protobuf.Any.
+var ErrUnsupportedField = errors.New("unsupported field")
+
+const anyFullName = protoreflect.FullName("google.protobuf.Any")
+
+// maxDepth bounds the nesting of decoded values, so that hostile input
+// cannot exhaust the stack.
+const maxDepth = 100
+
+// treeEncoder writes values of the data model in a concrete format.
+type treeEncoder interface {
+	mapHeader(n int)
+	arrayHeader(n int)
+	boolean(v bool)
+	integer(v int64)
+	unsigned(v uint64)
+	float32(v float32)
+	float64(v float64)
+	text(s string)
+	bytes(b []byte)
+}
+
+// mapEntry is a key-value pair of a decoded map, in encoded order.
+type mapEntry struct {
+	key, value any
+}
+
+// Decoded values are nil, bool, int64, uint64, float64, string, []byte,
+// []any or []mapEntry. Non-negative integers decode as uint64.
+
+// MarshalOptions configures MarshalCBOR and MarshalMsgpack, like
+// proto.MarshalOptions does proto.Marshal.
+type MarshalOptions struct {
+	// AllowPartial encodes messages with missing required fields instead of
+	// failing.
+	AllowPartial bool
+}
+
+// encodeTree writes msg, which must have its required fields set unless
+// AllowPartial is, to e.
+func (o MarshalOptions) encodeTree(e treeEncoder, msg proto.Message) error {
+	if !o.AllowPartial {
+		if err := proto.CheckInitialized(msg); err != nil {
+			return err
+		}
+	}
+	return encodeMessage(e, msg.ProtoReflect())
+}
+
+// UnmarshalOptions configures UnmarshalCBOR and UnmarshalMsgpack, like
+// proto.UnmarshalOptions does proto.Unmarshal.
+type UnmarshalOptions struct {
+	// DiscardUnknown drops fields with names the message doesn't declare,
+	// such as those written by a newer version of the schema, instead of
+	// failing.
+	DiscardUnknown bool
+	// AllowPartial accepts documents that leave required fields unset.
+	AllowPartial bool
+}
+
+// decodeTree sets the fields of msg from a decoded value, then checks that
+// its required fields are set unless AllowPartial is.
+func (o UnmarshalOptions) decodeTree(tree any, msg proto.Message) error {
+	if err := o.decodeMessage(tree, msg.ProtoReflect()); err != nil {
+		return err
+	}
+	if o.AllowPartial {
+		return nil
+	}
+	return proto.CheckInitialized(msg)
+}
+
+func encodeMessage(e treeEncoder, m protoreflect.Message) error {
+	desc := m.Descriptor()
+	if desc.FullName() == anyFullName {
+		return fmt.Errorf("%w: %s", ErrUnsupportedField, anyFullName)
+	}
+	fields := desc.Fields()
+	var set []protoreflect.FieldDescriptor
+	for i := 0; i < fields.Len(); i++ {
+		if fd := fields.Get(i); m.Has(fd) {
+			set = append(set, fd)
+		}
+	}
+	e.mapHeader(len(set))
+	for _, fd := range set {
+		e.text(string(fd.Name()))
+		if err := encodeField(e, fd, m.Get(fd)); err != nil {
+			return fmt.Errorf("field %s: %w", fd.Name(), err)
+		}
+	}
+	return nil
+}
+
+func encodeField(e treeEncoder, fd protoreflect.FieldDescriptor, v protoreflect.Value) error {
+	switch {
+	case fd.IsList():
+		list := v.List()
+		e.arrayHeader(list.Len())
+		for i := 0; i < list.Len(); i++ {
+			if err := encodeSingular(e, fd, list.Get(i)); err != nil {
+				return err
+			}
+		}
+	case fd.IsMap():
+		m := v.Map()
+		keys := make([]protoreflect.MapKey, 0, m.Len())
+		m.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+			keys = append(keys, k)
+			return true
+		})
+		keyKind := fd.MapKey().Kind()
+		slices.SortFunc(keys, func(a, b protoreflect.MapKey) int {
+			return compareMapKeys(keyKind, a, b)
+		})
+		e.mapHeader(len(keys))
+		for _, k := range keys {
+			if err := encodeSingular(e, fd.MapKey(), k.Value()); err != nil {
+				return err
+			}
+			if err := encodeSingular(e, fd.MapValue(), m.Get(k)); err != nil {
+				return err
+			}
+		}
+	default:
+		return encodeSingular(e, fd, v)
+	}
+	return nil
+}
+
+func encodeSingular(e treeEncoder, fd protoreflect.FieldDescriptor, v protoreflect.Value) error {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		e.boolean(v.Bool())
+	case protoreflect.EnumKind:
+		// A number the decoder would reject is not written either
+		if fd.Enum().IsClosed() && fd.Enum().Values().ByNumber(v.Enum()) == nil {
+			return fmt.Errorf("%d is not a value of closed enum %s", v.Enum(), fd.Enum().FullName())
+		}
+		e.integer(int64(v.Enum()))
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		e.integer(v.Int())
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		e.unsigned(v.Uint())
+	case protoreflect.FloatKind:
+		e.float32(float32(v.Float()))
+	case protoreflect.DoubleKind:
+		e.float64(v.Float())
+	case protoreflect.StringKind:
+		// Text in the self-describing formats is UTF-8 whatever the syntax
+		if !utf8.ValidString(v.String()) {
+			return errors.New("string is not valid UTF-8")
+		}
+		e.text(v.String())
+	case protoreflect.BytesKind:
+		e.bytes(v.Bytes())
+	case protoreflect.MessageKind:
+		return encodeMessage(e, v.Message())
+	default:
+		return fmt.Errorf("%w: %s kind", ErrUnsupportedField, fd.Kind())
+	}
+	return nil
+}
+
+func compareMapKeys(kind protoreflect.Kind, a, b protoreflect.MapKey) int {
+	switch kind {
+	case protoreflect.StringKind:
+		return strings.Compare(a.String(), b.String())
+	case protoreflect.BoolKind:
+		if a.Bool() == b.Bool() {
+			return 0
+		}
+		if b.Bool() {
+			return -1
+		}
+		return 1
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return cmp.Compare(a.Uint(), b.Uint())
+	}
+	return cmp.Compare(a.Int(), b.Int())
+}
+
+// decodeMessage sets the fields of m from a decoded map. Keys are matched
+// against the proto field names and, as protojson does, the JSON names.
+// Null values leave a field unset. At most one member of a oneof may be
+// given.
+func (o UnmarshalOptions) decodeMessage(tree any, m protoreflect.Message) error {
+	desc := m.Descriptor()
+	if desc.FullName() == anyFullName {
+		return fmt.Errorf("%w: %s", ErrUnsupportedField, anyFullName)
+	}
+	entries, ok := tree.([]mapEntry)
+	if !ok {
+		return fmt.Errorf("got %s, want a map for %s", describe(tree), desc.FullName())
+	}
+	fields := desc.Fields()
+	for _, entry := range entries {
+		name, ok := entry.key.(string)
+		if !ok {
+			return fmt.Errorf("got %s field key in %s, want text", describe(entry.key), desc.FullName())
+		}
+		fd := fields.ByName(protoreflect.Name(name))
+		if fd == nil {
+			fd = fields.ByJSONName(name)
+		}
+		if fd == nil && o.DiscardUnknown {
+			continue
+		}
+		if fd == nil {
+			return fmt.Errorf("unknown field %q in %s", name, desc.FullName())
+		}
+		if entry.value == nil {
+			continue
+		}
+		// Keeping only the last member would silently drop data, so a
+		// document must select at most one case, as protojson requires
+		if od := fd.ContainingOneof(); od != nil {
+			if set := m.WhichOneof(od); set != nil && set != fd {
+				return fmt.Errorf("fields %s and %s of oneof %s are both set", set.Name(), fd.Name(), od.Name())
+			}
+		}
+		if err := o.decodeField(m, fd, entry.value); err != nil {
+			return fmt.Errorf("field %s: %w", fd.Name(), err)
+		}
+	}
+	return nil
+}
+
+func (o UnmarshalOptions) decodeField(m protoreflect.Message, fd protoreflect.FieldDescriptor, v any) error {
+	switch {
+	case fd.IsList():
+		items, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("got %s, want an array", describe(v))
+		}
+		list := m.Mutable(fd).List()
+		for _, item := range items {
+			elem, err := o.decodeElement(fd, item, list.NewElement)
+			if err != nil {
+				return err
+			}
+			list.Append(elem)
+		}
+	case fd.IsMap():
+		entries, ok := v.([]mapEntry)
+		if !ok {
+			return fmt.Errorf("got %s, want a map", describe(v))
+		}
+		mp := m.Mutable(fd).Map()
+		for _, entry := range entries {
+			key, err := decodeScalar(fd.MapKey(), entry.key)
+			if err != nil {
+				return fmt.Errorf("map key: %w", err)
+			}
+			val, err := o.decodeElement(fd.MapValue(), entry.value, mp.NewValue)
+			if err != nil {
+				return err
+			}
+			mp.Set(key.MapKey(), val)
+		}
+	case fd.Kind() == protoreflect.MessageKind:
+		return o.decodeMessage(v, m.Mutable(fd).Message())
+	default:
+		val, err := decodeScalar(fd, v)
+		if err != nil {
+			return err
+		}
+		m.Set(fd, val)
+	}
+	return nil
+}
+
+// decodeElement decodes an element of a list or map, using newMessage to
+// allocate message elements.
+func (o UnmarshalOptions) decodeElement(fd protoreflect.FieldDescriptor, v any, newMessage func() protoreflect.Value) (protoreflect.Value, error) {
+	if v == nil {
+		return protoreflect.Value{}, errors.New("null element")
+	}
+	if fd.Kind() != protoreflect.MessageKind {
+		return decodeScalar(fd, v)
+	}
+	elem := newMessage()
+	if err := o.decodeMessage(v, elem.Message()); err != nil {
+		return protoreflect.Value{}, err
+	}
+	return elem, nil
+}
+
+func decodeScalar(fd protoreflect.FieldDescriptor, v any) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		if b, ok := v.(bool); ok {
+			return protoreflect.ValueOfBool(b), nil
+		}
+	case protoreflect.EnumKind:
+		n, err := toInt(v, math.MinInt32, math.MaxInt32)
+		if err == nil && fd.Enum().IsClosed() && fd.Enum().Values().ByNumber(protoreflect.EnumNumber(n)) == nil {
+			// The binary decoder would move the number to the unknown
+			// fields, which aren't stored
+			err = fmt.Errorf("%d is not a value of closed enum %s", n, fd.Enum().FullName())
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		n, err := toInt(v, math.MinInt32, math.MaxInt32)
+		return protoreflect.ValueOfInt32(int32(n)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		n, err := toInt(v, math.MinInt64, math.MaxInt64)
+		return protoreflect.ValueOfInt64(n), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		n, err := toUint(v, math.MaxUint32)
+		return protoreflect.ValueOfUint32(uint32(n)), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n, err := toUint(v, math.MaxUint64)
+		return protoreflect.ValueOfUint64(n), err
+	case protoreflect.FloatKind:
+		if f, ok := v.(float64); ok {
+			return protoreflect.ValueOfFloat32(float32(f)), nil
+		}
+	case protoreflect.DoubleKind:
+		if f, ok := v.(float64); ok {
+			return protoreflect.ValueOfFloat64(f), nil
+		}
+	case protoreflect.StringKind:
+		if s, ok := v.(string); ok {
+			return protoreflect.ValueOfString(s), nil
+		}
+	case protoreflect.BytesKind:
+		if b, ok := v.([]byte); ok {
+			return protoreflect.ValueOfBytes(b), nil
+		}
+	default:
+		return protoreflect.Value{}, fmt.Errorf("%w: %s kind", ErrUnsupportedField, fd.Kind())
+	}
+	return protoreflect.Value{}, fmt.Errorf("got %s, want %s", describe(v), fd.Kind())
+}
+
+func toInt(v any, lo, hi int64) (int64, error) {
+	switch n := v.(type) {
+	case int64:
+		if n >= lo && n <= hi {
+			return n, nil
+		}
+	case uint64:
+		if n <= uint64(hi) {
+			return int64(n), nil
+		}
+	default:
+		return 0, fmt.Errorf("got %s, want an integer", describe(v))
+	}
+	return 0, fmt.Errorf("integer %v out of range", v)
+}
+
+func toUint(v any, hi uint64) (uint64, error) {
+	switch n := v.(type) {
+	case uint64:
+		if n <= hi {
+			return n, nil
+		}
+	case int64:
+		// Only negative integers decode as int64
+	default:
+		return 0, fmt.Errorf("got %s, want an unsigned integer", describe(v))
+	}
+	return 0, fmt.Errorf("integer %v out of range", v)
+}
+
+// describe names the type of a decoded value for error messages.
+func describe(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case int64, uint64:
+		return "integer"
+	case float64:
+		return "float"
+	case string:
+		return "text"
+	case []byte:
+		return "bytes"
+	case []any:
+		return "array"
+	case []mapEntry:
+		return "map"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,53 @@
+package dbtypes
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Wrapper is the interface of the wrappers NewWrapper returns: the generated
+// XxxValue types and the generic types of this package.
+type Wrapper interface {
+	driver.Valuer
+	sql.Scanner
+	// ProtoMessage returns the wrapped message, or nil.
+	ProtoMessage() proto.Message
+}
+
+var (
+	wrappersMu sync.RWMutex
+	wrappers   = make(map[string]func() Wrapper)
+)
+
+// RegisterWrapper records newWrapper as the function NewWrapper calls for
+// the message type with the full name name. Packages generated with
+// global-registry=true call it from init for each of their wrappers. If
+// name is already registered, for example by another package wrapping the
+// same message in a different format, the first function is kept and
+// RegisterWrapper reports false.
+func RegisterWrapper(name string, newWrapper func() Wrapper) bool {
+	wrappersMu.Lock()
+	defer wrappersMu.Unlock()
+	if _, ok := wrappers[name]; ok {
+		return false
+	}
+	wrappers[name] = newWrapper
+	return true
+}
+
+// NewWrapper returns a new wrapper around an empty message of the type with
+// the full name name, for code that marshals messages it only knows by name,
+// such as from a type column. It reports false if no package linked into
+// the program registered a wrapper for the type.
+func NewWrapper(name string) (Wrapper, bool) {
+	wrappersMu.RLock()
+	newWrapper, ok := wrappers[name]
+	wrappersMu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return newWrapper(), true
+}
@@ -0,0 +1,45 @@
+package dbtypes_test
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/cadenya/protoc-gen-go-dbtypes/dbtypes"
+	testv1 "github.com/cadenya/protoc-gen-go-dbtypes/gen/go/test/v1"
+)
+
+func TestRegisterWrapper(t *testing.T) {
+	const name = "test.v1.UserPreferences"
+	newJSON := func() dbtypes.Wrapper { return dbtypes.NewJSON(&testv1.UserPreferences{}) }
+	if !dbtypes.RegisterWrapper(name, newJSON) {
+		t.Fatalf("RegisterWrapper(%s) = false for a new name", name)
+	}
+	// The first registration is kept
+	if dbtypes.RegisterWrapper(name, func() dbtypes.Wrapper { return dbtypes.New(&testv1.UserPreferences{}) }) {
+		t.Errorf("RegisterWrapper(%s) = true for a registered name", name)
+	}
+
+	prefs := &testv1.UserPreferences{Theme: "dark", Settings: map[string]string{"tz": "UTC"}}
+	val, err := dbtypes.NewJSON(prefs).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	w, ok := dbtypes.NewWrapper(name)
+	if !ok {
+		t.Fatalf("NewWrapper(%s) found no wrapper", name)
+	}
+	if _, isJSON := w.(*dbtypes.JSONValue[*testv1.UserPreferences]); !isJSON {
+		t.Errorf("NewWrapper(%s) = %T, want the first registered wrapper", name, w)
+	}
+	if err := w.Scan(val); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(w.ProtoMessage(), prefs) {
+		t.Errorf("ProtoMessage() = %v, want %v", w.ProtoMessage(), prefs)
+	}
+
+	if w, ok := dbtypes.NewWrapper("test.v1.Unregistered"); ok || w != nil {
+		t.Errorf("NewWrapper(unregistered) = %v, %v; want nil, false", w, ok)
+	}
+}
@@ -0,0 +1,228 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: dbtypes/options.proto
+
+package dbtypespb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Format selects how a message is serialized for database storage.
+type Format int32
+
+const (
+	// FORMAT_UNSPECIFIED uses the format chosen by the plugin's format option.
+	Format_FORMAT_UNSPECIFIED Format = 0
+	// BINARY stores the message in the protobuf wire format.
+	Format_BINARY Format = 1
+	// JSON stores the message as protojson.
+	Format_JSON Format = 2
+	// TEXT stores the message as prototext.
+	Format_TEXT Format = 3
+	// CBOR stores the message as a CBOR map, encoded by the dbtypes runtime
+	// package.
+	Format_CBOR Format = 4
+	// MSGPACK stores the message as a MessagePack map, encoded by the dbtypes
+	// runtime package.
+	Format_MSGPACK Format = 5
+)
+
+// Enum value maps for Format.
+var (
+	Format_name = map[int32]string{
+		0: "FORMAT_UNSPECIFIED",
+		1: "BINARY",
+		2: "JSON",
+		3: "TEXT",
+		4: "CBOR",
+		5: "MSGPACK",
+	}
+	Format_value = map[string]int32{
+		"FORMAT_UNSPECIFIED": 0,
+		"BINARY":             1,
+		"JSON":               2,
+		"TEXT":               3,
+		"CBOR":               4,
+		"MSGPACK":            5,
+	}
+)
+
+func (x Format) Enum() *Format {
+	p := new(Format)
+	*p = x
+	return p
+}
+
+func (x Format) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Format) Descriptor() protoreflect.EnumDescriptor {
+	return file_dbtypes_options_proto_enumTypes[0].Descriptor()
+}
+
+func (Format) Type() protoreflect.EnumType {
+	return &file_dbtypes_options_proto_enumTypes[0]
+}
+
+func (x Format) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Format.Descriptor instead.
+func (Format) EnumDescriptor() ([]byte, []int) {
+	return file_dbtypes_options_proto_rawDescGZIP(), []int{0}
+}
+
+var file_dbtypes_options_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.MessageOptions)(nil),
+		ExtensionType: (*Format)(nil),
+		Field:         51801,
+		Name:          "dbtypes.format",
+		Tag:           "varint,51801,opt,name=format,enum=dbtypes.Format",
+		Filename:      "dbtypes/options.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MessageOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         51802,
+		Name:          "dbtypes.generate",
+		Tag:           "varint,51802,opt,name=generate",
+		Filename:      "dbtypes/options.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         51803,
+		Name:          "dbtypes.redact",
+		Tag:           "varint,51803,opt,name=redact",
+		Filename:      "dbtypes/options.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         51804,
+		Name:          "dbtypes.skip",
+		Tag:           "varint,51804,opt,name=skip",
+		Filename:      "dbtypes/options.proto",
+	},
+}
+
+// Extension fields to descriptorpb.MessageOptions.
+var (
+	// format overrides the plugin's format option for this message.
+	//
+	// optional dbtypes.Format format = 51801;
+	E_Format = &file_dbtypes_options_proto_extTypes[0]
+	// generate marks the message for wrapper generation when the plugin runs
+	// with require-opt-in=true.
+	//
+	// optional bool generate = 51802;
+	E_Generate = &file_dbtypes_options_proto_extTypes[1]
+)
+
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// redact marks a field that Value clears before storing the message, such
+	// as a credential that must not be persisted. The caller's message is left
+	// unchanged.
+	//
+	// optional bool redact = 51803;
+	E_Redact = &file_dbtypes_options_proto_extTypes[2]
+	// skip marks a field left out of the stored message, so that the column
+	// holds a projection of it: Value clears the field on a copy and Scan
+	// leaves it unset.
+	//
+	// optional bool skip = 51804;
+	E_Skip = &file_dbtypes_options_proto_extTypes[3]
+)
+
+var File_dbtypes_options_proto protoreflect.FileDescriptor
+
+const file_dbtypes_options_proto_rawDesc = "" +
+	"\n" +
+	"\x15dbtypes/options.proto\x12\adbtypes\x1a google/protobuf/descriptor.proto*W\n" +
+	"\x06Format\x12\x16\n" +
+	"\x12FORMAT_UNSPECIFIED\x10\x00\x12\n" +
+	"\n" +
+	"\x06BINARY\x10\x01\x12\b\n" +
+	"\x04JSON\x10\x02\x12\b\n" +
+	"\x04TEXT\x10\x03\x12\b\n" +
+	"\x04CBOR\x10\x04\x12\v\n" +
+	"\aMSGPACK\x10\x05:J\n" +
+	"\x06format\x12\x1f.google.protobuf.MessageOptions\x18ٔ\x03 \x01(\x0e2\x0f.dbtypes.FormatR\x06format:=\n" +
+	"\bgenerate\x12\x1f.google.protobuf.MessageOptions\x18ڔ\x03 \x01(\bR\bgenerate:7\n" +
+	"\x06redact\x12\x1d.google.protobuf.FieldOptions\x18۔\x03 \x01(\bR\x06redact:3\n" +
+	"\x04skip\x12\x1d.google.protobuf.FieldOptions\x18ܔ\x03 \x01(\bR\x04skipBCZAgithub.com/cadenya/protoc-gen-go-dbtypes/gen/go/dbtypes;dbtypespbb\x06proto3"
+
+var (
+	file_dbtypes_options_proto_rawDescOnce sync.Once
+	file_dbtypes_options_proto_rawDescData []byte
+)
+
+func file_dbtypes_options_proto_rawDescGZIP() []byte {
+	file_dbtypes_options_proto_rawDescOnce.Do(func() {
+		file_dbtypes_options_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_dbtypes_options_proto_rawDesc), len(file_dbtypes_options_proto_rawDesc)))
+	})
+	return file_dbtypes_options_proto_rawDescData
+}
+
+var file_dbtypes_options_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_dbtypes_options_proto_goTypes = []any{
+	(Format)(0),                         // 0: dbtypes.Format
+	(*descriptorpb.MessageOptions)(nil), // 1: google.protobuf.MessageOptions
+	(*descriptorpb.FieldOptions)(nil),   // 2: google.protobuf.FieldOptions
+}
+var file_dbtypes_options_proto_depIdxs = []int32{
+	1, // 0: dbtypes.format:extendee -> google.protobuf.MessageOptions
+	1, // 1: dbtypes.generate:extendee -> google.protobuf.MessageOptions
+	2, // 2: dbtypes.redact:extendee -> google.protobuf.FieldOptions
+	2, // 3: dbtypes.skip:extendee -> google.protobuf.FieldOptions
+	0, // 4: dbtypes.format:type_name -> dbtypes.Format
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	4, // [4:5] is the sub-list for extension type_name
+	0, // [0:4] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_dbtypes_options_proto_init() }
+func file_dbtypes_options_proto_init() {
+	if File_dbtypes_options_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_dbtypes_options_proto_rawDesc), len(file_dbtypes_options_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   0,
+			NumExtensions: 4,
+			NumServices:   0,
+		},
+		GoTypes:           file_dbtypes_options_proto_goTypes,
+		DependencyIndexes: file_dbtypes_options_proto_depIdxs,
+		EnumInfos:         file_dbtypes_options_proto_enumTypes,
+		ExtensionInfos:    file_dbtypes_options_proto_extTypes,
+	}.Build()
+	File_dbtypes_options_proto = out.File
+	file_dbtypes_options_proto_goTypes = nil
+	file_dbtypes_options_proto_depIdxs = nil
+}
@@ -0,0 +1,327 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: test/v1/format.proto
+
+package testv1
+
+import (
+	_ "github.com/cadenya/protoc-gen-go-dbtypes/gen/go/dbtypes"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	anypb "google.golang.org/protobuf/types/known/anypb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// JSONDocument is always stored as protojson, whatever the plugin's format.
+type JSONDocument struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Labels        map[string]string      `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JSONDocument) Reset() {
+	*x = JSONDocument{}
+	mi := &file_test_v1_format_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JSONDocument) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JSONDocument) ProtoMessage() {}
+
+func (x *JSONDocument) ProtoReflect() protoreflect.Message {
+	mi := &file_test_v1_format_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JSONDocument.ProtoReflect.Descriptor instead.
+func (*JSONDocument) Descriptor() ([]byte, []int) {
+	return file_test_v1_format_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *JSONDocument) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *JSONDocument) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+// BinaryDocument has no format option and uses the plugin's format.
+type BinaryDocument struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Payload       []byte                 `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BinaryDocument) Reset() {
+	*x = BinaryDocument{}
+	mi := &file_test_v1_format_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BinaryDocument) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BinaryDocument) ProtoMessage() {}
+
+func (x *BinaryDocument) ProtoReflect() protoreflect.Message {
+	mi := &file_test_v1_format_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BinaryDocument.ProtoReflect.Descriptor instead.
+func (*BinaryDocument) Descriptor() ([]byte, []int) {
+	return file_test_v1_format_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BinaryDocument) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BinaryDocument) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+// TextDocument is always stored as prototext, whatever the plugin's format.
+type TextDocument struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Tags          []string               `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+	Labels        map[string]string      `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TextDocument) Reset() {
+	*x = TextDocument{}
+	mi := &file_test_v1_format_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TextDocument) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TextDocument) ProtoMessage() {}
+
+func (x *TextDocument) ProtoReflect() protoreflect.Message {
+	mi := &file_test_v1_format_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TextDocument.ProtoReflect.Descriptor instead.
+func (*TextDocument) Descriptor() ([]byte, []int) {
+	return file_test_v1_format_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TextDocument) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TextDocument) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *TextDocument) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+// Envelope carries an Any and is stored as protojson, which must resolve the
+// embedded type to write and read it.
+type Envelope struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Payload       *anypb.Any             `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Envelope) Reset() {
+	*x = Envelope{}
+	mi := &file_test_v1_format_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Envelope) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Envelope) ProtoMessage() {}
+
+func (x *Envelope) ProtoReflect() protoreflect.Message {
+	mi := &file_test_v1_format_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Envelope.ProtoReflect.Descriptor instead.
+func (*Envelope) Descriptor() ([]byte, []int) {
+	return file_test_v1_format_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Envelope) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Envelope) GetPayload() *anypb.Any {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+var File_test_v1_format_proto protoreflect.FileDescriptor
+
+const file_test_v1_format_proto_rawDesc = "" +
+	"\n" +
+	"\x14test/v1/format.proto\x12\atest.v1\x1a\x15dbtypes/options.proto\x1a\x19google/protobuf/any.proto\"\x9a\x01\n" +
+	"\fJSONDocument\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x129\n" +
+	"\x06labels\x18\x02 \x03(\v2!.test.v1.JSONDocument.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01:\x04ȥ\x19\x02\":\n" +
+	"\x0eBinaryDocument\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\apayload\x18\x02 \x01(\fR\apayload\"\xae\x01\n" +
+	"\fTextDocument\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04tags\x18\x02 \x03(\tR\x04tags\x129\n" +
+	"\x06labels\x18\x03 \x03(\v2!.test.v1.TextDocument.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01:\x04ȥ\x19\x03\"P\n" +
+	"\bEnvelope\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12.\n" +
+	"\apayload\x18\x02 \x01(\v2\x14.google.protobuf.AnyR\apayload:\x04ȥ\x19\x02BGZEgithub.com/cadenya-agents/protoc-gen-go-dbtypes/gen/go/test/v1;testv1b\x06proto3"
+
+var (
+	file_test_v1_format_proto_rawDescOnce sync.Once
+	file_test_v1_format_proto_rawDescData []byte
+)
+
+func file_test_v1_format_proto_rawDescGZIP() []byte {
+	file_test_v1_format_proto_rawDescOnce.Do(func() {
+		file_test_v1_format_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_test_v1_format_proto_rawDesc), len(file_test_v1_format_proto_rawDesc)))
+	})
+	return file_test_v1_format_proto_rawDescData
+}
+
+var file_test_v1_format_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_test_v1_format_proto_goTypes = []any{
+	(*JSONDocument)(nil),   // 0: test.v1.JSONDocument
+	(*BinaryDocument)(nil), // 1: test.v1.BinaryDocument
+	(*TextDocument)(nil),   // 2: test.v1.TextDocument
+	(*Envelope)(nil),       // 3: test.v1.Envelope
+	nil,                    // 4: test.v1.JSONDocument.LabelsEntry
+	nil,                    // 5: test.v1.TextDocument.LabelsEntry
+	(*anypb.Any)(nil),      // 6: google.protobuf.Any
+}
+var file_test_v1_format_proto_depIdxs = []int32{
+	4, // 0: test.v1.JSONDocument.labels:type_name -> test.v1.JSONDocument.LabelsEntry
+	5, // 1: test.v1.TextDocument.labels:type_name -> test.v1.TextDocument.LabelsEntry
+	6, // 2: test.v1.Envelope.payload:type_name -> google.protobuf.Any
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_test_v1_format_proto_init() }
+func file_test_v1_format_proto_init() {
+	if File_test_v1_format_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_test_v1_format_proto_rawDesc), len(file_test_v1_format_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_test_v1_format_proto_goTypes,
+		DependencyIndexes: file_test_v1_format_proto_depIdxs,
+		MessageInfos:      file_test_v1_format_proto_msgTypes,
+	}.Build()
+	File_test_v1_format_proto = out.File
+	file_test_v1_format_proto_goTypes = nil
+	file_test_v1_format_proto_depIdxs = nil
+}
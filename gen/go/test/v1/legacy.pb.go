@@ -0,0 +1,336 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: test/v1/legacy.proto
+
+package testv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// LegacyState is a closed enum, which cannot hold undeclared numbers.
+type LegacyState int32
+
+const (
+	LegacyState_LEGACY_STATE_UNKNOWN LegacyState = 0
+	LegacyState_LEGACY_STATE_ACTIVE  LegacyState = 1
+	LegacyState_LEGACY_STATE_RETIRED LegacyState = 2
+)
+
+// Enum value maps for LegacyState.
+var (
+	LegacyState_name = map[int32]string{
+		0: "LEGACY_STATE_UNKNOWN",
+		1: "LEGACY_STATE_ACTIVE",
+		2: "LEGACY_STATE_RETIRED",
+	}
+	LegacyState_value = map[string]int32{
+		"LEGACY_STATE_UNKNOWN": 0,
+		"LEGACY_STATE_ACTIVE":  1,
+		"LEGACY_STATE_RETIRED": 2,
+	}
+)
+
+func (x LegacyState) Enum() *LegacyState {
+	p := new(LegacyState)
+	*p = x
+	return p
+}
+
+func (x LegacyState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LegacyState) Descriptor() protoreflect.EnumDescriptor {
+	return file_test_v1_legacy_proto_enumTypes[0].Descriptor()
+}
+
+func (LegacyState) Type() protoreflect.EnumType {
+	return &file_test_v1_legacy_proto_enumTypes[0]
+}
+
+func (x LegacyState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *LegacyState) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = LegacyState(num)
+	return nil
+}
+
+// Deprecated: Use LegacyState.Descriptor instead.
+func (LegacyState) EnumDescriptor() ([]byte, []int) {
+	return file_test_v1_legacy_proto_rawDescGZIP(), []int{0}
+}
+
+// LegacyRecord is a proto2 message, so that every storage format is checked
+// to keep explicit presence, defaults and required fields.
+type LegacyRecord struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              *string                `protobuf:"bytes,1,req,name=id" json:"id,omitempty"`
+	Name            *string                `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	Priority        *int32                 `protobuf:"varint,3,opt,name=priority,def=5" json:"priority,omitempty"`
+	Archived        *bool                  `protobuf:"varint,4,opt,name=archived" json:"archived,omitempty"`
+	Scores          []int32                `protobuf:"varint,5,rep,packed,name=scores" json:"scores,omitempty"`
+	State           *LegacyState           `protobuf:"varint,6,opt,name=state,enum=test.v1.LegacyState,def=1" json:"state,omitempty"`
+	Labels          map[string]string      `protobuf:"bytes,7,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Detail          *LegacyRecord_Detail   `protobuf:"bytes,8,opt,name=detail" json:"detail,omitempty"`
+	extensionFields protoimpl.ExtensionFields
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+// Default values for LegacyRecord fields.
+const (
+	Default_LegacyRecord_Priority = int32(5)
+	Default_LegacyRecord_State    = LegacyState_LEGACY_STATE_ACTIVE
+)
+
+func (x *LegacyRecord) Reset() {
+	*x = LegacyRecord{}
+	mi := &file_test_v1_legacy_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LegacyRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LegacyRecord) ProtoMessage() {}
+
+func (x *LegacyRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_test_v1_legacy_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LegacyRecord.ProtoReflect.Descriptor instead.
+func (*LegacyRecord) Descriptor() ([]byte, []int) {
+	return file_test_v1_legacy_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LegacyRecord) GetId() string {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return ""
+}
+
+func (x *LegacyRecord) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *LegacyRecord) GetPriority() int32 {
+	if x != nil && x.Priority != nil {
+		return *x.Priority
+	}
+	return Default_LegacyRecord_Priority
+}
+
+func (x *LegacyRecord) GetArchived() bool {
+	if x != nil && x.Archived != nil {
+		return *x.Archived
+	}
+	return false
+}
+
+func (x *LegacyRecord) GetScores() []int32 {
+	if x != nil {
+		return x.Scores
+	}
+	return nil
+}
+
+func (x *LegacyRecord) GetState() LegacyState {
+	if x != nil && x.State != nil {
+		return *x.State
+	}
+	return Default_LegacyRecord_State
+}
+
+func (x *LegacyRecord) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *LegacyRecord) GetDetail() *LegacyRecord_Detail {
+	if x != nil {
+		return x.Detail
+	}
+	return nil
+}
+
+type LegacyRecord_Detail struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Note          *string                `protobuf:"bytes,1,opt,name=note" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LegacyRecord_Detail) Reset() {
+	*x = LegacyRecord_Detail{}
+	mi := &file_test_v1_legacy_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LegacyRecord_Detail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LegacyRecord_Detail) ProtoMessage() {}
+
+func (x *LegacyRecord_Detail) ProtoReflect() protoreflect.Message {
+	mi := &file_test_v1_legacy_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LegacyRecord_Detail.ProtoReflect.Descriptor instead.
+func (*LegacyRecord_Detail) Descriptor() ([]byte, []int) {
+	return file_test_v1_legacy_proto_rawDescGZIP(), []int{0, 1}
+}
+
+func (x *LegacyRecord_Detail) GetNote() string {
+	if x != nil && x.Note != nil {
+		return *x.Note
+	}
+	return ""
+}
+
+var file_test_v1_legacy_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*LegacyRecord)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         100,
+		Name:          "test.v1.legacy_tag",
+		Tag:           "bytes,100,opt,name=legacy_tag",
+		Filename:      "test/v1/legacy.proto",
+	},
+}
+
+// Extension fields to LegacyRecord.
+var (
+	// optional string legacy_tag = 100;
+	E_LegacyTag = &file_test_v1_legacy_proto_extTypes[0]
+)
+
+var File_test_v1_legacy_proto protoreflect.FileDescriptor
+
+const file_test_v1_legacy_proto_rawDesc = "" +
+	"\n" +
+	"\x14test/v1/legacy.proto\x12\atest.v1\"\x9b\x03\n" +
+	"\fLegacyRecord\x12\x0e\n" +
+	"\x02id\x18\x01 \x02(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\bpriority\x18\x03 \x01(\x05:\x015R\bpriority\x12\x1a\n" +
+	"\barchived\x18\x04 \x01(\bR\barchived\x12\x1a\n" +
+	"\x06scores\x18\x05 \x03(\x05B\x02\x10\x01R\x06scores\x12?\n" +
+	"\x05state\x18\x06 \x01(\x0e2\x14.test.v1.LegacyState:\x13LEGACY_STATE_ACTIVER\x05state\x129\n" +
+	"\x06labels\x18\a \x03(\v2!.test.v1.LegacyRecord.LabelsEntryR\x06labels\x124\n" +
+	"\x06detail\x18\b \x01(\v2\x1c.test.v1.LegacyRecord.DetailR\x06detail\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a\x1c\n" +
+	"\x06Detail\x12\x12\n" +
+	"\x04note\x18\x01 \x01(\tR\x04note*\x05\bd\x10\xc8\x01*Z\n" +
+	"\vLegacyState\x12\x18\n" +
+	"\x14LEGACY_STATE_UNKNOWN\x10\x00\x12\x17\n" +
+	"\x13LEGACY_STATE_ACTIVE\x10\x01\x12\x18\n" +
+	"\x14LEGACY_STATE_RETIRED\x10\x02:4\n" +
+	"\n" +
+	"legacy_tag\x12\x15.test.v1.LegacyRecord\x18d \x01(\tR\tlegacyTagBGZEgithub.com/cadenya-agents/protoc-gen-go-dbtypes/gen/go/test/v1;testv1"
+
+var (
+	file_test_v1_legacy_proto_rawDescOnce sync.Once
+	file_test_v1_legacy_proto_rawDescData []byte
+)
+
+func file_test_v1_legacy_proto_rawDescGZIP() []byte {
+	file_test_v1_legacy_proto_rawDescOnce.Do(func() {
+		file_test_v1_legacy_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_test_v1_legacy_proto_rawDesc), len(file_test_v1_legacy_proto_rawDesc)))
+	})
+	return file_test_v1_legacy_proto_rawDescData
+}
+
+var file_test_v1_legacy_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_test_v1_legacy_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_test_v1_legacy_proto_goTypes = []any{
+	(LegacyState)(0),            // 0: test.v1.LegacyState
+	(*LegacyRecord)(nil),        // 1: test.v1.LegacyRecord
+	nil,                         // 2: test.v1.LegacyRecord.LabelsEntry
+	(*LegacyRecord_Detail)(nil), // 3: test.v1.LegacyRecord.Detail
+}
+var file_test_v1_legacy_proto_depIdxs = []int32{
+	0, // 0: test.v1.LegacyRecord.state:type_name -> test.v1.LegacyState
+	2, // 1: test.v1.LegacyRecord.labels:type_name -> test.v1.LegacyRecord.LabelsEntry
+	3, // 2: test.v1.LegacyRecord.detail:type_name -> test.v1.LegacyRecord.Detail
+	1, // 3: test.v1.legacy_tag:extendee -> test.v1.LegacyRecord
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	3, // [3:4] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_test_v1_legacy_proto_init() }
+func file_test_v1_legacy_proto_init() {
+	if File_test_v1_legacy_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_test_v1_legacy_proto_rawDesc), len(file_test_v1_legacy_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   3,
+			NumExtensions: 1,
+			NumServices:   0,
+		},
+		GoTypes:           file_test_v1_legacy_proto_goTypes,
+		DependencyIndexes: file_test_v1_legacy_proto_depIdxs,
+		EnumInfos:         file_test_v1_legacy_proto_enumTypes,
+		MessageInfos:      file_test_v1_legacy_proto_msgTypes,
+		ExtensionInfos:    file_test_v1_legacy_proto_extTypes,
+	}.Build()
+	File_test_v1_legacy_proto = out.File
+	file_test_v1_legacy_proto_goTypes = nil
+	file_test_v1_legacy_proto_depIdxs = nil
+}
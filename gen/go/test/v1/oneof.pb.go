@@ -0,0 +1,234 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: test/v1/oneof.proto
+
+package testv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Payload has a oneof, so that every storage format is checked to keep
+// the selected case, including a case set to its zero value.
+type Payload struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Types that are valid to be assigned to Content:
+	//
+	//	*Payload_Text
+	//	*Payload_Blob
+	//	*Payload_Count
+	//	*Payload_Flag
+	//	*Payload_Spec
+	Content       isPayload_Content `protobuf_oneof:"content"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Payload) Reset() {
+	*x = Payload{}
+	mi := &file_test_v1_oneof_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Payload) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Payload) ProtoMessage() {}
+
+func (x *Payload) ProtoReflect() protoreflect.Message {
+	mi := &file_test_v1_oneof_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Payload.ProtoReflect.Descriptor instead.
+func (*Payload) Descriptor() ([]byte, []int) {
+	return file_test_v1_oneof_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Payload) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Payload) GetContent() isPayload_Content {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *Payload) GetText() string {
+	if x != nil {
+		if x, ok := x.Content.(*Payload_Text); ok {
+			return x.Text
+		}
+	}
+	return ""
+}
+
+func (x *Payload) GetBlob() []byte {
+	if x != nil {
+		if x, ok := x.Content.(*Payload_Blob); ok {
+			return x.Blob
+		}
+	}
+	return nil
+}
+
+func (x *Payload) GetCount() int64 {
+	if x != nil {
+		if x, ok := x.Content.(*Payload_Count); ok {
+			return x.Count
+		}
+	}
+	return 0
+}
+
+func (x *Payload) GetFlag() bool {
+	if x != nil {
+		if x, ok := x.Content.(*Payload_Flag); ok {
+			return x.Flag
+		}
+	}
+	return false
+}
+
+func (x *Payload) GetSpec() *ToolSetSpec {
+	if x != nil {
+		if x, ok := x.Content.(*Payload_Spec); ok {
+			return x.Spec
+		}
+	}
+	return nil
+}
+
+type isPayload_Content interface {
+	isPayload_Content()
+}
+
+type Payload_Text struct {
+	Text string `protobuf:"bytes,2,opt,name=text,proto3,oneof"`
+}
+
+type Payload_Blob struct {
+	Blob []byte `protobuf:"bytes,3,opt,name=blob,proto3,oneof"`
+}
+
+type Payload_Count struct {
+	Count int64 `protobuf:"varint,4,opt,name=count,proto3,oneof"`
+}
+
+type Payload_Flag struct {
+	Flag bool `protobuf:"varint,5,opt,name=flag,proto3,oneof"`
+}
+
+type Payload_Spec struct {
+	Spec *ToolSetSpec `protobuf:"bytes,6,opt,name=spec,proto3,oneof"`
+}
+
+func (*Payload_Text) isPayload_Content() {}
+
+func (*Payload_Blob) isPayload_Content() {}
+
+func (*Payload_Count) isPayload_Content() {}
+
+func (*Payload_Flag) isPayload_Content() {}
+
+func (*Payload_Spec) isPayload_Content() {}
+
+var File_test_v1_oneof_proto protoreflect.FileDescriptor
+
+const file_test_v1_oneof_proto_rawDesc = "" +
+	"\n" +
+	"\x13test/v1/oneof.proto\x12\atest.v1\x1a\x12test/v1/test.proto\"\xaa\x01\n" +
+	"\aPayload\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x04text\x18\x02 \x01(\tH\x00R\x04text\x12\x14\n" +
+	"\x04blob\x18\x03 \x01(\fH\x00R\x04blob\x12\x16\n" +
+	"\x05count\x18\x04 \x01(\x03H\x00R\x05count\x12\x14\n" +
+	"\x04flag\x18\x05 \x01(\bH\x00R\x04flag\x12*\n" +
+	"\x04spec\x18\x06 \x01(\v2\x14.test.v1.ToolSetSpecH\x00R\x04specB\t\n" +
+	"\acontentBGZEgithub.com/cadenya-agents/protoc-gen-go-dbtypes/gen/go/test/v1;testv1b\x06proto3"
+
+var (
+	file_test_v1_oneof_proto_rawDescOnce sync.Once
+	file_test_v1_oneof_proto_rawDescData []byte
+)
+
+func file_test_v1_oneof_proto_rawDescGZIP() []byte {
+	file_test_v1_oneof_proto_rawDescOnce.Do(func() {
+		file_test_v1_oneof_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_test_v1_oneof_proto_rawDesc), len(file_test_v1_oneof_proto_rawDesc)))
+	})
+	return file_test_v1_oneof_proto_rawDescData
+}
+
+var file_test_v1_oneof_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_test_v1_oneof_proto_goTypes = []any{
+	(*Payload)(nil),     // 0: test.v1.Payload
+	(*ToolSetSpec)(nil), // 1: test.v1.ToolSetSpec
+}
+var file_test_v1_oneof_proto_depIdxs = []int32{
+	1, // 0: test.v1.Payload.spec:type_name -> test.v1.ToolSetSpec
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_test_v1_oneof_proto_init() }
+func file_test_v1_oneof_proto_init() {
+	if File_test_v1_oneof_proto != nil {
+		return
+	}
+	file_test_v1_test_proto_init()
+	file_test_v1_oneof_proto_msgTypes[0].OneofWrappers = []any{
+		(*Payload_Text)(nil),
+		(*Payload_Blob)(nil),
+		(*Payload_Count)(nil),
+		(*Payload_Flag)(nil),
+		(*Payload_Spec)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_test_v1_oneof_proto_rawDesc), len(file_test_v1_oneof_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_test_v1_oneof_proto_goTypes,
+		DependencyIndexes: file_test_v1_oneof_proto_depIdxs,
+		MessageInfos:      file_test_v1_oneof_proto_msgTypes,
+	}.Build()
+	File_test_v1_oneof_proto = out.File
+	file_test_v1_oneof_proto_goTypes = nil
+	file_test_v1_oneof_proto_depIdxs = nil
+}
@@ -0,0 +1,173 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: test/v1/optin.proto
+
+package testv1
+
+import (
+	_ "github.com/cadenya/protoc-gen-go-dbtypes/gen/go/dbtypes"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// OptInRecord is marked for storage and is generated even with
+// require-opt-in=true.
+type OptInRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OptInRecord) Reset() {
+	*x = OptInRecord{}
+	mi := &file_test_v1_optin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OptInRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OptInRecord) ProtoMessage() {}
+
+func (x *OptInRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_test_v1_optin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OptInRecord.ProtoReflect.Descriptor instead.
+func (*OptInRecord) Descriptor() ([]byte, []int) {
+	return file_test_v1_optin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *OptInRecord) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// PlainRecord has no generate option and is skipped with require-opt-in=true.
+type PlainRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlainRecord) Reset() {
+	*x = PlainRecord{}
+	mi := &file_test_v1_optin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlainRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlainRecord) ProtoMessage() {}
+
+func (x *PlainRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_test_v1_optin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlainRecord.ProtoReflect.Descriptor instead.
+func (*PlainRecord) Descriptor() ([]byte, []int) {
+	return file_test_v1_optin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PlainRecord) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+var File_test_v1_optin_proto protoreflect.FileDescriptor
+
+const file_test_v1_optin_proto_rawDesc = "" +
+	"\n" +
+	"\x13test/v1/optin.proto\x12\atest.v1\x1a\x15dbtypes/options.proto\"#\n" +
+	"\vOptInRecord\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id:\x04Х\x19\x01\"\x1d\n" +
+	"\vPlainRecord\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02idBGZEgithub.com/cadenya-agents/protoc-gen-go-dbtypes/gen/go/test/v1;testv1b\x06proto3"
+
+var (
+	file_test_v1_optin_proto_rawDescOnce sync.Once
+	file_test_v1_optin_proto_rawDescData []byte
+)
+
+func file_test_v1_optin_proto_rawDescGZIP() []byte {
+	file_test_v1_optin_proto_rawDescOnce.Do(func() {
+		file_test_v1_optin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_test_v1_optin_proto_rawDesc), len(file_test_v1_optin_proto_rawDesc)))
+	})
+	return file_test_v1_optin_proto_rawDescData
+}
+
+var file_test_v1_optin_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_test_v1_optin_proto_goTypes = []any{
+	(*OptInRecord)(nil), // 0: test.v1.OptInRecord
+	(*PlainRecord)(nil), // 1: test.v1.PlainRecord
+}
+var file_test_v1_optin_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_test_v1_optin_proto_init() }
+func file_test_v1_optin_proto_init() {
+	if File_test_v1_optin_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_test_v1_optin_proto_rawDesc), len(file_test_v1_optin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_test_v1_optin_proto_goTypes,
+		DependencyIndexes: file_test_v1_optin_proto_depIdxs,
+		MessageInfos:      file_test_v1_optin_proto_msgTypes,
+	}.Build()
+	File_test_v1_optin_proto = out.File
+	file_test_v1_optin_proto_goTypes = nil
+	file_test_v1_optin_proto_depIdxs = nil
+}
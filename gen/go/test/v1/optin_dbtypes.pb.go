@@ -0,0 +1,756 @@
+// Code generated by protoc-gen-go-dbtypes. DO NOT EDIT.
+// source: test/v1/optin.proto
+
+package testv1
+
+import (
+	context "context"
+	sql "database/sql"
+	driver "database/sql/driver"
+	encoding "encoding"
+	gob "encoding/gob"
+	prototext "google.golang.org/protobuf/encoding/prototext"
+	proto "google.golang.org/protobuf/proto"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	io "io"
+)
+
+// OptInRecordValue is a database-serializable wrapper around test.v1.OptInRecord.
+// Value stores it as protobuf binary.
+type OptInRecordValue struct {
+	*ProtoValue[*OptInRecord]
+}
+
+// NewOptInRecordValue creates a new OptInRecordValue wrapper. A nil msg
+// gives an empty wrapper, which is stored as NULL like a nil one.
+func NewOptInRecordValue(msg *OptInRecord) *OptInRecordValue {
+	if msg == nil {
+		return &OptInRecordValue{}
+	}
+	return &OptInRecordValue{
+		ProtoValue: &ProtoValue[*OptInRecord]{Message: msg},
+	}
+}
+
+// OptInRecordCodec returns the codec of OptInRecordValue.
+func OptInRecordCodec() Codec { return dbtypesBinaryCodec }
+
+// Scan implements sql.Scanner.
+func (x *OptInRecordValue) Scan(src any) error {
+	return x.ScanContext(context.Background(), src)
+}
+
+// ScanContext is like Scan but returns ctx.Err() without decoding if ctx is
+// done.
+func (x *OptInRecordValue) ScanContext(ctx context.Context, src any) error {
+	if x.ProtoValue == nil {
+		x.ProtoValue = &ProtoValue[*OptInRecord]{Message: &OptInRecord{}}
+	}
+	if x.ProtoValue.Message == nil {
+		x.ProtoValue.Message = &OptInRecord{}
+	}
+	return x.ProtoValue.scan(ctx, src, OptInRecordCodec())
+}
+
+// ScanFields is like Scan but keeps only the top-level fields named in
+// fieldPaths, by their names in the .proto file as in a
+// google.protobuf.FieldMask, leaving the others unset. It returns an error
+// for a name that is not a top-level field of test.v1.OptInRecord.
+// The other fields are skipped in the stored bytes without being decoded.
+func (x *OptInRecordValue) ScanFields(src any, fieldPaths ...string) error {
+	fields, err := dbtypesFieldMask((*OptInRecord)(nil).ProtoReflect().Descriptor(), fieldPaths)
+	if err != nil {
+		return err
+	}
+	if x.ProtoValue == nil {
+		x.ProtoValue = &ProtoValue[*OptInRecord]{Message: &OptInRecord{}}
+	}
+	if x.ProtoValue.Message == nil {
+		x.ProtoValue.Message = &OptInRecord{}
+	}
+	err = x.ProtoValue.scan(context.Background(), src, dbtypesFieldsCodec{Codec: OptInRecordCodec(), fields: fields})
+	if err != nil {
+		return err
+	}
+	// Clear the other fields of the formats that are decoded whole
+	dbtypesProject(x.ProtoValue.Message, fields)
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (x *OptInRecordValue) Value() (driver.Value, error) {
+	return x.ValueContext(context.Background())
+}
+
+// ValueContext is like Value but returns ctx.Err() without encoding if ctx
+// is done.
+func (x *OptInRecordValue) ValueContext(ctx context.Context) (driver.Value, error) {
+	if x.ProtoValue == nil {
+		return nil, nil
+	}
+	return x.ProtoValue.value(ctx, OptInRecordCodec())
+}
+
+// ValueMasked is like Value but stores a copy of the message with only the
+// fields mask names set, for partial updates. It returns an error for a path
+// that is not a field of test.v1.OptInRecord. A nil or empty mask leaves no
+// field set.
+func (x *OptInRecordValue) ValueMasked(mask *fieldmaskpb.FieldMask) (driver.Value, error) {
+	msg := &OptInRecord{}
+	if x.ProtoValue != nil && x.ProtoValue.Message != nil {
+		msg = proto.Clone(x.ProtoValue.Message).(*OptInRecord)
+	}
+	if err := dbtypesApplyMask(msg.ProtoReflect(), mask.GetPaths()); err != nil {
+		return nil, err
+	}
+	if x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return x.ValueContext(context.Background())
+	}
+	masked := *x.ProtoValue
+	masked.Message = msg
+	return (&OptInRecordValue{ProtoValue: &masked}).ValueContext(context.Background())
+}
+
+var (
+	_ driver.Valuer = (*OptInRecordValue)(nil)
+	_ sql.Scanner   = (*OptInRecordValue)(nil)
+)
+
+// Unwrap returns the underlying protobuf message.
+func (x *OptInRecordValue) Unwrap() *OptInRecord {
+	if x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return nil
+	}
+	return x.ProtoValue.Message
+}
+
+// ProtoMessage returns the underlying message as a proto.Message, for code
+// that handles wrappers of different types alike. It returns a nil interface,
+// not a typed nil, for a nil wrapper or one holding no message.
+func (x *OptInRecordValue) ProtoMessage() proto.Message {
+	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return nil
+	}
+	return x.ProtoValue.Message
+}
+
+// GetOrInit returns the underlying protobuf message, first storing an empty
+// one in the wrapper if there is none, so that it never returns nil.
+func (x *OptInRecordValue) GetOrInit() *OptInRecord {
+	if x.ProtoValue == nil {
+		x.ProtoValue = &ProtoValue[*OptInRecord]{}
+	}
+	if x.ProtoValue.Message == nil {
+		x.ProtoValue.Message = &OptInRecord{}
+	}
+	return x.ProtoValue.Message
+}
+
+// Merge merges src into the message as proto.Merge does, first storing an
+// empty message in the wrapper if there is none: populated scalar fields of
+// src overwrite those of the message, repeated fields are appended to and map
+// entries added. A nil src leaves the wrapper unchanged.
+func (x *OptInRecordValue) Merge(src *OptInRecord) {
+	if src == nil {
+		return
+	}
+	proto.Merge(x.GetOrInit(), src)
+}
+
+// Reset drops the message, leaving the wrapper like its zero value, so that
+// it can be reused. The message itself is not modified, since callers may
+// still hold it; use proto.Reset to clear a message in place.
+func (x *OptInRecordValue) Reset() {
+	x.ProtoValue = nil
+}
+
+// Size returns the length of the message in the protobuf wire format, or 0
+// if there is none. Value stores that many bytes unless it compresses,
+// encrypts or stores the message as NULL.
+func (x *OptInRecordValue) Size() int {
+	return proto.Size(x.Unwrap())
+}
+
+// Clone returns a wrapper around a deep copy of the message, or nil for a
+// nil wrapper.
+func (x *OptInRecordValue) Clone() *OptInRecordValue {
+	if x == nil || x.ProtoValue == nil {
+		return nil
+	}
+	msg, _ := proto.Clone(x.ProtoValue.Message).(*OptInRecord)
+	return &OptInRecordValue{ProtoValue: &ProtoValue[*OptInRecord]{Message: msg}}
+}
+
+// Equal reports whether x and other wrap equal messages. Nil wrappers are
+// equal to each other but not to a wrapper holding a message.
+func (x *OptInRecordValue) Equal(other *OptInRecordValue) bool {
+	var a, b *OptInRecord
+	if x != nil {
+		a = x.Unwrap()
+	}
+	if other != nil {
+		b = other.Unwrap()
+	}
+	return proto.Equal(a, b)
+}
+
+// String returns the message in prototext form, or "<nil>" if there is none
+// or the wrapper is nil.
+func (x *OptInRecordValue) String() string {
+	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return "<nil>"
+	}
+	return x.ProtoValue.Message.String()
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler with the same encoding as
+// Value. A nil message encodes as an empty one.
+func (x *OptInRecordValue) MarshalBinary() ([]byte, error) {
+	msg := x.Unwrap()
+	if msg == nil {
+		msg = &OptInRecord{}
+	}
+	return OptInRecordCodec().Encode(msg)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler with the same decoding
+// as Scan.
+func (x *OptInRecordValue) UnmarshalBinary(data []byte) error {
+	x.ProtoValue = &ProtoValue[*OptInRecord]{Message: &OptInRecord{}}
+	return OptInRecordCodec().Decode(data, x.ProtoValue.Message)
+}
+
+// WriteTo implements io.WriterTo, writing the encoding of MarshalBinary to w.
+func (x *OptInRecordValue) WriteTo(w io.Writer) (int64, error) {
+	data, err := x.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// ReadFrom implements io.ReaderFrom, reading r to EOF and decoding the bytes
+// like UnmarshalBinary.
+func (x *OptInRecordValue) ReadFrom(r io.Reader) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return int64(len(data)), err
+	}
+	return int64(len(data)), x.UnmarshalBinary(data)
+}
+
+var (
+	_ encoding.BinaryMarshaler   = (*OptInRecordValue)(nil)
+	_ encoding.BinaryUnmarshaler = (*OptInRecordValue)(nil)
+	_ io.WriterTo                = (*OptInRecordValue)(nil)
+	_ io.ReaderFrom              = (*OptInRecordValue)(nil)
+)
+
+// GobEncode implements gob.GobEncoder with the same encoding as MarshalBinary.
+// A nil wrapper or message encodes as no bytes.
+func (x OptInRecordValue) GobEncode() ([]byte, error) {
+	if x.Unwrap() == nil {
+		return []byte{}, nil
+	}
+	return x.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder with the same decoding as
+// UnmarshalBinary. No bytes decode as a nil message.
+func (x *OptInRecordValue) GobDecode(data []byte) error {
+	if len(data) == 0 {
+		x.ProtoValue = nil
+		return nil
+	}
+	return x.UnmarshalBinary(data)
+}
+
+var (
+	_ gob.GobEncoder = (*OptInRecordValue)(nil)
+	_ gob.GobDecoder = (*OptInRecordValue)(nil)
+)
+
+// MarshalJSON implements json.Marshaler using protojson. A nil message
+// encodes as null.
+func (x OptInRecordValue) MarshalJSON() ([]byte, error) {
+	if x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return []byte("null"), nil
+	}
+	return dbtypesMarshalJSON(x.ProtoValue.Message)
+}
+
+// UnmarshalJSON implements json.Unmarshaler using protojson.
+func (x *OptInRecordValue) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		x.ProtoValue = nil
+		return nil
+	}
+	msg := &OptInRecord{}
+	if err := dbtypesUnmarshalJSON(data, msg); err != nil {
+		return err
+	}
+	x.ProtoValue = &ProtoValue[*OptInRecord]{Message: msg}
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler using prototext. A nil
+// wrapper or message encodes as empty text.
+func (x *OptInRecordValue) MarshalText() ([]byte, error) {
+	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return []byte{}, nil
+	}
+	// Appending keeps the result non-nil for an empty message
+	return prototext.MarshalOptions{}.MarshalAppend([]byte{}, x.ProtoValue.Message)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using prototext. Empty
+// text decodes as an empty message.
+func (x *OptInRecordValue) UnmarshalText(data []byte) error {
+	msg := &OptInRecord{}
+	if err := prototext.Unmarshal(data, msg); err != nil {
+		return err
+	}
+	x.ProtoValue = &ProtoValue[*OptInRecord]{Message: msg}
+	return nil
+}
+
+var (
+	_ encoding.TextMarshaler   = (*OptInRecordValue)(nil)
+	_ encoding.TextUnmarshaler = (*OptInRecordValue)(nil)
+)
+
+// EncodeOptInRecordBatch encodes msgs as OptInRecordValue.Value would, for
+// bulk inserts such as COPY. Nil messages give nil entries.
+func EncodeOptInRecordBatch(msgs []*OptInRecord) ([][]byte, error) {
+	return dbtypesMarshalBatch(msgs, func(msg *OptInRecord) (driver.Value, error) {
+		return NewOptInRecordValue(msg).Value()
+	})
+}
+
+// OptInRecordValues returns what OptInRecordValue.Value returns for each message
+// of msgs, for passing many messages as arguments to ExecContext and the
+// like. Nil messages give nil entries, which are stored as NULL.
+func OptInRecordValues(msgs []*OptInRecord) ([]driver.Value, error) {
+	return dbtypesValues(msgs, func(msg *OptInRecord) (driver.Value, error) {
+		return NewOptInRecordValue(msg).Value()
+	})
+}
+
+// DatabaseValue returns a database-compatible wrapper for this message.
+func (x *OptInRecord) DatabaseValue() *OptInRecordValue {
+	return NewOptInRecordValue(x)
+}
+
+// NullOptInRecordValue represents a *OptInRecord that may be NULL.
+type NullOptInRecordValue struct {
+	OptInRecordValue OptInRecordValue
+	Valid            bool // Valid is true if OptInRecordValue is not NULL
+}
+
+// NewNullOptInRecordValue creates a new NullOptInRecordValue that is valid if msg is non-nil.
+func NewNullOptInRecordValue(msg *OptInRecord) NullOptInRecordValue {
+	if msg == nil {
+		return NullOptInRecordValue{}
+	}
+	return NullOptInRecordValue{OptInRecordValue: *NewOptInRecordValue(msg), Valid: true}
+}
+
+// Scan implements sql.Scanner.
+func (n *NullOptInRecordValue) Scan(src any) error {
+	src = dbtypesScanSource(src)
+	if src == nil {
+		n.OptInRecordValue, n.Valid = OptInRecordValue{}, false
+		return nil
+	}
+	err := n.OptInRecordValue.Scan(src)
+	n.Valid = err == nil
+	return err
+}
+
+// Value implements driver.Valuer.
+func (n NullOptInRecordValue) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	msg := n.OptInRecordValue.Unwrap()
+	if msg == nil {
+		msg = &OptInRecord{}
+	}
+	return NewOptInRecordValue(msg).Value()
+}
+
+var (
+	_ driver.Valuer = (*NullOptInRecordValue)(nil)
+	_ sql.Scanner   = (*NullOptInRecordValue)(nil)
+)
+
+// PlainRecordValue is a database-serializable wrapper around test.v1.PlainRecord.
+// Value stores it as protobuf binary.
+type PlainRecordValue struct {
+	*ProtoValue[*PlainRecord]
+}
+
+// NewPlainRecordValue creates a new PlainRecordValue wrapper. A nil msg
+// gives an empty wrapper, which is stored as NULL like a nil one.
+func NewPlainRecordValue(msg *PlainRecord) *PlainRecordValue {
+	if msg == nil {
+		return &PlainRecordValue{}
+	}
+	return &PlainRecordValue{
+		ProtoValue: &ProtoValue[*PlainRecord]{Message: msg},
+	}
+}
+
+// PlainRecordCodec returns the codec of PlainRecordValue.
+func PlainRecordCodec() Codec { return dbtypesBinaryCodec }
+
+// Scan implements sql.Scanner.
+func (x *PlainRecordValue) Scan(src any) error {
+	return x.ScanContext(context.Background(), src)
+}
+
+// ScanContext is like Scan but returns ctx.Err() without decoding if ctx is
+// done.
+func (x *PlainRecordValue) ScanContext(ctx context.Context, src any) error {
+	if x.ProtoValue == nil {
+		x.ProtoValue = &ProtoValue[*PlainRecord]{Message: &PlainRecord{}}
+	}
+	if x.ProtoValue.Message == nil {
+		x.ProtoValue.Message = &PlainRecord{}
+	}
+	return x.ProtoValue.scan(ctx, src, PlainRecordCodec())
+}
+
+// ScanFields is like Scan but keeps only the top-level fields named in
+// fieldPaths, by their names in the .proto file as in a
+// google.protobuf.FieldMask, leaving the others unset. It returns an error
+// for a name that is not a top-level field of test.v1.PlainRecord.
+// The other fields are skipped in the stored bytes without being decoded.
+func (x *PlainRecordValue) ScanFields(src any, fieldPaths ...string) error {
+	fields, err := dbtypesFieldMask((*PlainRecord)(nil).ProtoReflect().Descriptor(), fieldPaths)
+	if err != nil {
+		return err
+	}
+	if x.ProtoValue == nil {
+		x.ProtoValue = &ProtoValue[*PlainRecord]{Message: &PlainRecord{}}
+	}
+	if x.ProtoValue.Message == nil {
+		x.ProtoValue.Message = &PlainRecord{}
+	}
+	err = x.ProtoValue.scan(context.Background(), src, dbtypesFieldsCodec{Codec: PlainRecordCodec(), fields: fields})
+	if err != nil {
+		return err
+	}
+	// Clear the other fields of the formats that are decoded whole
+	dbtypesProject(x.ProtoValue.Message, fields)
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (x *PlainRecordValue) Value() (driver.Value, error) {
+	return x.ValueContext(context.Background())
+}
+
+// ValueContext is like Value but returns ctx.Err() without encoding if ctx
+// is done.
+func (x *PlainRecordValue) ValueContext(ctx context.Context) (driver.Value, error) {
+	if x.ProtoValue == nil {
+		return nil, nil
+	}
+	return x.ProtoValue.value(ctx, PlainRecordCodec())
+}
+
+// ValueMasked is like Value but stores a copy of the message with only the
+// fields mask names set, for partial updates. It returns an error for a path
+// that is not a field of test.v1.PlainRecord. A nil or empty mask leaves no
+// field set.
+func (x *PlainRecordValue) ValueMasked(mask *fieldmaskpb.FieldMask) (driver.Value, error) {
+	msg := &PlainRecord{}
+	if x.ProtoValue != nil && x.ProtoValue.Message != nil {
+		msg = proto.Clone(x.ProtoValue.Message).(*PlainRecord)
+	}
+	if err := dbtypesApplyMask(msg.ProtoReflect(), mask.GetPaths()); err != nil {
+		return nil, err
+	}
+	if x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return x.ValueContext(context.Background())
+	}
+	masked := *x.ProtoValue
+	masked.Message = msg
+	return (&PlainRecordValue{ProtoValue: &masked}).ValueContext(context.Background())
+}
+
+var (
+	_ driver.Valuer = (*PlainRecordValue)(nil)
+	_ sql.Scanner   = (*PlainRecordValue)(nil)
+)
+
+// Unwrap returns the underlying protobuf message.
+func (x *PlainRecordValue) Unwrap() *PlainRecord {
+	if x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return nil
+	}
+	return x.ProtoValue.Message
+}
+
+// ProtoMessage returns the underlying message as a proto.Message, for code
+// that handles wrappers of different types alike. It returns a nil interface,
+// not a typed nil, for a nil wrapper or one holding no message.
+func (x *PlainRecordValue) ProtoMessage() proto.Message {
+	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return nil
+	}
+	return x.ProtoValue.Message
+}
+
+// GetOrInit returns the underlying protobuf message, first storing an empty
+// one in the wrapper if there is none, so that it never returns nil.
+func (x *PlainRecordValue) GetOrInit() *PlainRecord {
+	if x.ProtoValue == nil {
+		x.ProtoValue = &ProtoValue[*PlainRecord]{}
+	}
+	if x.ProtoValue.Message == nil {
+		x.ProtoValue.Message = &PlainRecord{}
+	}
+	return x.ProtoValue.Message
+}
+
+// Merge merges src into the message as proto.Merge does, first storing an
+// empty message in the wrapper if there is none: populated scalar fields of
+// src overwrite those of the message, repeated fields are appended to and map
+// entries added. A nil src leaves the wrapper unchanged.
+func (x *PlainRecordValue) Merge(src *PlainRecord) {
+	if src == nil {
+		return
+	}
+	proto.Merge(x.GetOrInit(), src)
+}
+
+// Reset drops the message, leaving the wrapper like its zero value, so that
+// it can be reused. The message itself is not modified, since callers may
+// still hold it; use proto.Reset to clear a message in place.
+func (x *PlainRecordValue) Reset() {
+	x.ProtoValue = nil
+}
+
+// Size returns the length of the message in the protobuf wire format, or 0
+// if there is none. Value stores that many bytes unless it compresses,
+// encrypts or stores the message as NULL.
+func (x *PlainRecordValue) Size() int {
+	return proto.Size(x.Unwrap())
+}
+
+// Clone returns a wrapper around a deep copy of the message, or nil for a
+// nil wrapper.
+func (x *PlainRecordValue) Clone() *PlainRecordValue {
+	if x == nil || x.ProtoValue == nil {
+		return nil
+	}
+	msg, _ := proto.Clone(x.ProtoValue.Message).(*PlainRecord)
+	return &PlainRecordValue{ProtoValue: &ProtoValue[*PlainRecord]{Message: msg}}
+}
+
+// Equal reports whether x and other wrap equal messages. Nil wrappers are
+// equal to each other but not to a wrapper holding a message.
+func (x *PlainRecordValue) Equal(other *PlainRecordValue) bool {
+	var a, b *PlainRecord
+	if x != nil {
+		a = x.Unwrap()
+	}
+	if other != nil {
+		b = other.Unwrap()
+	}
+	return proto.Equal(a, b)
+}
+
+// String returns the message in prototext form, or "<nil>" if there is none
+// or the wrapper is nil.
+func (x *PlainRecordValue) String() string {
+	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return "<nil>"
+	}
+	return x.ProtoValue.Message.String()
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler with the same encoding as
+// Value. A nil message encodes as an empty one.
+func (x *PlainRecordValue) MarshalBinary() ([]byte, error) {
+	msg := x.Unwrap()
+	if msg == nil {
+		msg = &PlainRecord{}
+	}
+	return PlainRecordCodec().Encode(msg)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler with the same decoding
+// as Scan.
+func (x *PlainRecordValue) UnmarshalBinary(data []byte) error {
+	x.ProtoValue = &ProtoValue[*PlainRecord]{Message: &PlainRecord{}}
+	return PlainRecordCodec().Decode(data, x.ProtoValue.Message)
+}
+
+// WriteTo implements io.WriterTo, writing the encoding of MarshalBinary to w.
+func (x *PlainRecordValue) WriteTo(w io.Writer) (int64, error) {
+	data, err := x.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// ReadFrom implements io.ReaderFrom, reading r to EOF and decoding the bytes
+// like UnmarshalBinary.
+func (x *PlainRecordValue) ReadFrom(r io.Reader) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return int64(len(data)), err
+	}
+	return int64(len(data)), x.UnmarshalBinary(data)
+}
+
+var (
+	_ encoding.BinaryMarshaler   = (*PlainRecordValue)(nil)
+	_ encoding.BinaryUnmarshaler = (*PlainRecordValue)(nil)
+	_ io.WriterTo                = (*PlainRecordValue)(nil)
+	_ io.ReaderFrom              = (*PlainRecordValue)(nil)
+)
+
+// GobEncode implements gob.GobEncoder with the same encoding as MarshalBinary.
+// A nil wrapper or message encodes as no bytes.
+func (x PlainRecordValue) GobEncode() ([]byte, error) {
+	if x.Unwrap() == nil {
+		return []byte{}, nil
+	}
+	return x.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder with the same decoding as
+// UnmarshalBinary. No bytes decode as a nil message.
+func (x *PlainRecordValue) GobDecode(data []byte) error {
+	if len(data) == 0 {
+		x.ProtoValue = nil
+		return nil
+	}
+	return x.UnmarshalBinary(data)
+}
+
+var (
+	_ gob.GobEncoder = (*PlainRecordValue)(nil)
+	_ gob.GobDecoder = (*PlainRecordValue)(nil)
+)
+
+// MarshalJSON implements json.Marshaler using protojson. A nil message
+// encodes as null.
+func (x PlainRecordValue) MarshalJSON() ([]byte, error) {
+	if x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return []byte("null"), nil
+	}
+	return dbtypesMarshalJSON(x.ProtoValue.Message)
+}
+
+// UnmarshalJSON implements json.Unmarshaler using protojson.
+func (x *PlainRecordValue) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		x.ProtoValue = nil
+		return nil
+	}
+	msg := &PlainRecord{}
+	if err := dbtypesUnmarshalJSON(data, msg); err != nil {
+		return err
+	}
+	x.ProtoValue = &ProtoValue[*PlainRecord]{Message: msg}
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler using prototext. A nil
+// wrapper or message encodes as empty text.
+func (x *PlainRecordValue) MarshalText() ([]byte, error) {
+	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return []byte{}, nil
+	}
+	// Appending keeps the result non-nil for an empty message
+	return prototext.MarshalOptions{}.MarshalAppend([]byte{}, x.ProtoValue.Message)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using prototext. Empty
+// text decodes as an empty message.
+func (x *PlainRecordValue) UnmarshalText(data []byte) error {
+	msg := &PlainRecord{}
+	if err := prototext.Unmarshal(data, msg); err != nil {
+		return err
+	}
+	x.ProtoValue = &ProtoValue[*PlainRecord]{Message: msg}
+	return nil
+}
+
+var (
+	_ encoding.TextMarshaler   = (*PlainRecordValue)(nil)
+	_ encoding.TextUnmarshaler = (*PlainRecordValue)(nil)
+)
+
+// EncodePlainRecordBatch encodes msgs as PlainRecordValue.Value would, for
+// bulk inserts such as COPY. Nil messages give nil entries.
+func EncodePlainRecordBatch(msgs []*PlainRecord) ([][]byte, error) {
+	return dbtypesMarshalBatch(msgs, func(msg *PlainRecord) (driver.Value, error) {
+		return NewPlainRecordValue(msg).Value()
+	})
+}
+
+// PlainRecordValues returns what PlainRecordValue.Value returns for each message
+// of msgs, for passing many messages as arguments to ExecContext and the
+// like. Nil messages give nil entries, which are stored as NULL.
+func PlainRecordValues(msgs []*PlainRecord) ([]driver.Value, error) {
+	return dbtypesValues(msgs, func(msg *PlainRecord) (driver.Value, error) {
+		return NewPlainRecordValue(msg).Value()
+	})
+}
+
+// DatabaseValue returns a database-compatible wrapper for this message.
+func (x *PlainRecord) DatabaseValue() *PlainRecordValue {
+	return NewPlainRecordValue(x)
+}
+
+// NullPlainRecordValue represents a *PlainRecord that may be NULL.
+type NullPlainRecordValue struct {
+	PlainRecordValue PlainRecordValue
+	Valid            bool // Valid is true if PlainRecordValue is not NULL
+}
+
+// NewNullPlainRecordValue creates a new NullPlainRecordValue that is valid if msg is non-nil.
+func NewNullPlainRecordValue(msg *PlainRecord) NullPlainRecordValue {
+	if msg == nil {
+		return NullPlainRecordValue{}
+	}
+	return NullPlainRecordValue{PlainRecordValue: *NewPlainRecordValue(msg), Valid: true}
+}
+
+// Scan implements sql.Scanner.
+func (n *NullPlainRecordValue) Scan(src any) error {
+	src = dbtypesScanSource(src)
+	if src == nil {
+		n.PlainRecordValue, n.Valid = PlainRecordValue{}, false
+		return nil
+	}
+	err := n.PlainRecordValue.Scan(src)
+	n.Valid = err == nil
+	return err
+}
+
+// Value implements driver.Valuer.
+func (n NullPlainRecordValue) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	msg := n.PlainRecordValue.Unwrap()
+	if msg == nil {
+		msg = &PlainRecord{}
+	}
+	return NewPlainRecordValue(msg).Value()
+}
+
+var (
+	_ driver.Valuer = (*NullPlainRecordValue)(nil)
+	_ sql.Scanner   = (*NullPlainRecordValue)(nil)
+)
@@ -4,77 +4,118 @@
 package testv1
 
 import (
+	context "context"
+	sql "database/sql"
 	driver "database/sql/driver"
-	fmt "fmt"
+	encoding "encoding"
+	gob "encoding/gob"
+	prototext "google.golang.org/protobuf/encoding/prototext"
 	proto "google.golang.org/protobuf/proto"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	io "io"
 )
 
-// ProtoValue wraps a protobuf message for database scanning/valuing.
-type ProtoValue[T proto.Message] struct {
-	Message T
-}
-
-// Scan implements sql.Scanner.
-func (p *ProtoValue[T]) Scan(src any) error {
-	if src == nil {
-		return nil
-	}
-
-	var data []byte
-	switch v := src.(type) {
-	case []byte:
-		data = v
-	case string:
-		data = []byte(v)
-	default:
-		return fmt.Errorf("dbtypes: unsupported scan type: %T", src)
-	}
-
-	return proto.Unmarshal(data, p.Message)
-}
-
-// Value implements driver.Valuer.
-func (p *ProtoValue[T]) Value() (driver.Value, error) {
-	if any(p.Message) == nil {
-		return nil, nil
-	}
-	return proto.Marshal(p.Message)
-}
-
-// AnotherMessageValue wraps *AnotherMessage for database operations.
+// AnotherMessageValue is a database-serializable wrapper around test.v1.AnotherMessage.
+// Value stores it as protobuf binary.
 type AnotherMessageValue struct {
 	*ProtoValue[*AnotherMessage]
 }
 
-// NewAnotherMessageValue creates a new AnotherMessageValue wrapper.
+// NewAnotherMessageValue creates a new AnotherMessageValue wrapper. A nil msg
+// gives an empty wrapper, which is stored as NULL like a nil one.
 func NewAnotherMessageValue(msg *AnotherMessage) *AnotherMessageValue {
 	if msg == nil {
-		msg = &AnotherMessage{}
+		return &AnotherMessageValue{}
 	}
 	return &AnotherMessageValue{
 		ProtoValue: &ProtoValue[*AnotherMessage]{Message: msg},
 	}
 }
 
+// AnotherMessageCodec returns the codec of AnotherMessageValue.
+func AnotherMessageCodec() Codec { return dbtypesBinaryCodec }
+
 // Scan implements sql.Scanner.
 func (x *AnotherMessageValue) Scan(src any) error {
+	return x.ScanContext(context.Background(), src)
+}
+
+// ScanContext is like Scan but returns ctx.Err() without decoding if ctx is
+// done.
+func (x *AnotherMessageValue) ScanContext(ctx context.Context, src any) error {
+	if x.ProtoValue == nil {
+		x.ProtoValue = &ProtoValue[*AnotherMessage]{Message: &AnotherMessage{}}
+	}
+	if x.ProtoValue.Message == nil {
+		x.ProtoValue.Message = &AnotherMessage{}
+	}
+	return x.ProtoValue.scan(ctx, src, AnotherMessageCodec())
+}
+
+// ScanFields is like Scan but keeps only the top-level fields named in
+// fieldPaths, by their names in the .proto file as in a
+// google.protobuf.FieldMask, leaving the others unset. It returns an error
+// for a name that is not a top-level field of test.v1.AnotherMessage.
+// The other fields are skipped in the stored bytes without being decoded.
+func (x *AnotherMessageValue) ScanFields(src any, fieldPaths ...string) error {
+	fields, err := dbtypesFieldMask((*AnotherMessage)(nil).ProtoReflect().Descriptor(), fieldPaths)
+	if err != nil {
+		return err
+	}
 	if x.ProtoValue == nil {
 		x.ProtoValue = &ProtoValue[*AnotherMessage]{Message: &AnotherMessage{}}
 	}
 	if x.ProtoValue.Message == nil {
 		x.ProtoValue.Message = &AnotherMessage{}
 	}
-	return x.ProtoValue.Scan(src)
+	err = x.ProtoValue.scan(context.Background(), src, dbtypesFieldsCodec{Codec: AnotherMessageCodec(), fields: fields})
+	if err != nil {
+		return err
+	}
+	// Clear the other fields of the formats that are decoded whole
+	dbtypesProject(x.ProtoValue.Message, fields)
+	return nil
 }
 
 // Value implements driver.Valuer.
 func (x *AnotherMessageValue) Value() (driver.Value, error) {
+	return x.ValueContext(context.Background())
+}
+
+// ValueContext is like Value but returns ctx.Err() without encoding if ctx
+// is done.
+func (x *AnotherMessageValue) ValueContext(ctx context.Context) (driver.Value, error) {
 	if x.ProtoValue == nil {
 		return nil, nil
 	}
-	return x.ProtoValue.Value()
+	return x.ProtoValue.value(ctx, AnotherMessageCodec())
+}
+
+// ValueMasked is like Value but stores a copy of the message with only the
+// fields mask names set, for partial updates. It returns an error for a path
+// that is not a field of test.v1.AnotherMessage. A nil or empty mask leaves no
+// field set.
+func (x *AnotherMessageValue) ValueMasked(mask *fieldmaskpb.FieldMask) (driver.Value, error) {
+	msg := &AnotherMessage{}
+	if x.ProtoValue != nil && x.ProtoValue.Message != nil {
+		msg = proto.Clone(x.ProtoValue.Message).(*AnotherMessage)
+	}
+	if err := dbtypesApplyMask(msg.ProtoReflect(), mask.GetPaths()); err != nil {
+		return nil, err
+	}
+	if x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return x.ValueContext(context.Background())
+	}
+	masked := *x.ProtoValue
+	masked.Message = msg
+	return (&AnotherMessageValue{ProtoValue: &masked}).ValueContext(context.Background())
 }
 
+var (
+	_ driver.Valuer = (*AnotherMessageValue)(nil)
+	_ sql.Scanner   = (*AnotherMessageValue)(nil)
+)
+
 // Unwrap returns the underlying protobuf message.
 func (x *AnotherMessageValue) Unwrap() *AnotherMessage {
 	if x.ProtoValue == nil || x.ProtoValue.Message == nil {
@@ -83,45 +124,368 @@ func (x *AnotherMessageValue) Unwrap() *AnotherMessage {
 	return x.ProtoValue.Message
 }
 
+// ProtoMessage returns the underlying message as a proto.Message, for code
+// that handles wrappers of different types alike. It returns a nil interface,
+// not a typed nil, for a nil wrapper or one holding no message.
+func (x *AnotherMessageValue) ProtoMessage() proto.Message {
+	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return nil
+	}
+	return x.ProtoValue.Message
+}
+
+// GetOrInit returns the underlying protobuf message, first storing an empty
+// one in the wrapper if there is none, so that it never returns nil.
+func (x *AnotherMessageValue) GetOrInit() *AnotherMessage {
+	if x.ProtoValue == nil {
+		x.ProtoValue = &ProtoValue[*AnotherMessage]{}
+	}
+	if x.ProtoValue.Message == nil {
+		x.ProtoValue.Message = &AnotherMessage{}
+	}
+	return x.ProtoValue.Message
+}
+
+// Merge merges src into the message as proto.Merge does, first storing an
+// empty message in the wrapper if there is none: populated scalar fields of
+// src overwrite those of the message, repeated fields are appended to and map
+// entries added. A nil src leaves the wrapper unchanged.
+func (x *AnotherMessageValue) Merge(src *AnotherMessage) {
+	if src == nil {
+		return
+	}
+	proto.Merge(x.GetOrInit(), src)
+}
+
+// Reset drops the message, leaving the wrapper like its zero value, so that
+// it can be reused. The message itself is not modified, since callers may
+// still hold it; use proto.Reset to clear a message in place.
+func (x *AnotherMessageValue) Reset() {
+	x.ProtoValue = nil
+}
+
+// Size returns the length of the message in the protobuf wire format, or 0
+// if there is none. Value stores that many bytes unless it compresses,
+// encrypts or stores the message as NULL.
+func (x *AnotherMessageValue) Size() int {
+	return proto.Size(x.Unwrap())
+}
+
+// Clone returns a wrapper around a deep copy of the message, or nil for a
+// nil wrapper.
+func (x *AnotherMessageValue) Clone() *AnotherMessageValue {
+	if x == nil || x.ProtoValue == nil {
+		return nil
+	}
+	msg, _ := proto.Clone(x.ProtoValue.Message).(*AnotherMessage)
+	return &AnotherMessageValue{ProtoValue: &ProtoValue[*AnotherMessage]{Message: msg}}
+}
+
+// Equal reports whether x and other wrap equal messages. Nil wrappers are
+// equal to each other but not to a wrapper holding a message.
+func (x *AnotherMessageValue) Equal(other *AnotherMessageValue) bool {
+	var a, b *AnotherMessage
+	if x != nil {
+		a = x.Unwrap()
+	}
+	if other != nil {
+		b = other.Unwrap()
+	}
+	return proto.Equal(a, b)
+}
+
+// String returns the message in prototext form, or "<nil>" if there is none
+// or the wrapper is nil.
+func (x *AnotherMessageValue) String() string {
+	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return "<nil>"
+	}
+	return x.ProtoValue.Message.String()
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler with the same encoding as
+// Value. A nil message encodes as an empty one.
+func (x *AnotherMessageValue) MarshalBinary() ([]byte, error) {
+	msg := x.Unwrap()
+	if msg == nil {
+		msg = &AnotherMessage{}
+	}
+	return AnotherMessageCodec().Encode(msg)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler with the same decoding
+// as Scan.
+func (x *AnotherMessageValue) UnmarshalBinary(data []byte) error {
+	x.ProtoValue = &ProtoValue[*AnotherMessage]{Message: &AnotherMessage{}}
+	return AnotherMessageCodec().Decode(data, x.ProtoValue.Message)
+}
+
+// WriteTo implements io.WriterTo, writing the encoding of MarshalBinary to w.
+func (x *AnotherMessageValue) WriteTo(w io.Writer) (int64, error) {
+	data, err := x.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// ReadFrom implements io.ReaderFrom, reading r to EOF and decoding the bytes
+// like UnmarshalBinary.
+func (x *AnotherMessageValue) ReadFrom(r io.Reader) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return int64(len(data)), err
+	}
+	return int64(len(data)), x.UnmarshalBinary(data)
+}
+
+var (
+	_ encoding.BinaryMarshaler   = (*AnotherMessageValue)(nil)
+	_ encoding.BinaryUnmarshaler = (*AnotherMessageValue)(nil)
+	_ io.WriterTo                = (*AnotherMessageValue)(nil)
+	_ io.ReaderFrom              = (*AnotherMessageValue)(nil)
+)
+
+// GobEncode implements gob.GobEncoder with the same encoding as MarshalBinary.
+// A nil wrapper or message encodes as no bytes.
+func (x AnotherMessageValue) GobEncode() ([]byte, error) {
+	if x.Unwrap() == nil {
+		return []byte{}, nil
+	}
+	return x.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder with the same decoding as
+// UnmarshalBinary. No bytes decode as a nil message.
+func (x *AnotherMessageValue) GobDecode(data []byte) error {
+	if len(data) == 0 {
+		x.ProtoValue = nil
+		return nil
+	}
+	return x.UnmarshalBinary(data)
+}
+
+var (
+	_ gob.GobEncoder = (*AnotherMessageValue)(nil)
+	_ gob.GobDecoder = (*AnotherMessageValue)(nil)
+)
+
+// MarshalJSON implements json.Marshaler using protojson. A nil message
+// encodes as null.
+func (x AnotherMessageValue) MarshalJSON() ([]byte, error) {
+	if x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return []byte("null"), nil
+	}
+	return dbtypesMarshalJSON(x.ProtoValue.Message)
+}
+
+// UnmarshalJSON implements json.Unmarshaler using protojson.
+func (x *AnotherMessageValue) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		x.ProtoValue = nil
+		return nil
+	}
+	msg := &AnotherMessage{}
+	if err := dbtypesUnmarshalJSON(data, msg); err != nil {
+		return err
+	}
+	x.ProtoValue = &ProtoValue[*AnotherMessage]{Message: msg}
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler using prototext. A nil
+// wrapper or message encodes as empty text.
+func (x *AnotherMessageValue) MarshalText() ([]byte, error) {
+	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return []byte{}, nil
+	}
+	// Appending keeps the result non-nil for an empty message
+	return prototext.MarshalOptions{}.MarshalAppend([]byte{}, x.ProtoValue.Message)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using prototext. Empty
+// text decodes as an empty message.
+func (x *AnotherMessageValue) UnmarshalText(data []byte) error {
+	msg := &AnotherMessage{}
+	if err := prototext.Unmarshal(data, msg); err != nil {
+		return err
+	}
+	x.ProtoValue = &ProtoValue[*AnotherMessage]{Message: msg}
+	return nil
+}
+
+var (
+	_ encoding.TextMarshaler   = (*AnotherMessageValue)(nil)
+	_ encoding.TextUnmarshaler = (*AnotherMessageValue)(nil)
+)
+
+// EncodeAnotherMessageBatch encodes msgs as AnotherMessageValue.Value would, for
+// bulk inserts such as COPY. Nil messages give nil entries.
+func EncodeAnotherMessageBatch(msgs []*AnotherMessage) ([][]byte, error) {
+	return dbtypesMarshalBatch(msgs, func(msg *AnotherMessage) (driver.Value, error) {
+		return NewAnotherMessageValue(msg).Value()
+	})
+}
+
+// AnotherMessageValues returns what AnotherMessageValue.Value returns for each message
+// of msgs, for passing many messages as arguments to ExecContext and the
+// like. Nil messages give nil entries, which are stored as NULL.
+func AnotherMessageValues(msgs []*AnotherMessage) ([]driver.Value, error) {
+	return dbtypesValues(msgs, func(msg *AnotherMessage) (driver.Value, error) {
+		return NewAnotherMessageValue(msg).Value()
+	})
+}
+
 // DatabaseValue returns a database-compatible wrapper for this message.
 func (x *AnotherMessage) DatabaseValue() *AnotherMessageValue {
 	return NewAnotherMessageValue(x)
 }
 
-// SecondMessageValue wraps *SecondMessage for database operations.
+// NullAnotherMessageValue represents a *AnotherMessage that may be NULL.
+type NullAnotherMessageValue struct {
+	AnotherMessageValue AnotherMessageValue
+	Valid               bool // Valid is true if AnotherMessageValue is not NULL
+}
+
+// NewNullAnotherMessageValue creates a new NullAnotherMessageValue that is valid if msg is non-nil.
+func NewNullAnotherMessageValue(msg *AnotherMessage) NullAnotherMessageValue {
+	if msg == nil {
+		return NullAnotherMessageValue{}
+	}
+	return NullAnotherMessageValue{AnotherMessageValue: *NewAnotherMessageValue(msg), Valid: true}
+}
+
+// Scan implements sql.Scanner.
+func (n *NullAnotherMessageValue) Scan(src any) error {
+	src = dbtypesScanSource(src)
+	if src == nil {
+		n.AnotherMessageValue, n.Valid = AnotherMessageValue{}, false
+		return nil
+	}
+	err := n.AnotherMessageValue.Scan(src)
+	n.Valid = err == nil
+	return err
+}
+
+// Value implements driver.Valuer.
+func (n NullAnotherMessageValue) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	msg := n.AnotherMessageValue.Unwrap()
+	if msg == nil {
+		msg = &AnotherMessage{}
+	}
+	return NewAnotherMessageValue(msg).Value()
+}
+
+var (
+	_ driver.Valuer = (*NullAnotherMessageValue)(nil)
+	_ sql.Scanner   = (*NullAnotherMessageValue)(nil)
+)
+
+// SecondMessageValue is a database-serializable wrapper around test.v1.SecondMessage.
+// Value stores it as protobuf binary.
 type SecondMessageValue struct {
 	*ProtoValue[*SecondMessage]
 }
 
-// NewSecondMessageValue creates a new SecondMessageValue wrapper.
+// NewSecondMessageValue creates a new SecondMessageValue wrapper. A nil msg
+// gives an empty wrapper, which is stored as NULL like a nil one.
 func NewSecondMessageValue(msg *SecondMessage) *SecondMessageValue {
 	if msg == nil {
-		msg = &SecondMessage{}
+		return &SecondMessageValue{}
 	}
 	return &SecondMessageValue{
 		ProtoValue: &ProtoValue[*SecondMessage]{Message: msg},
 	}
 }
 
+// SecondMessageCodec returns the codec of SecondMessageValue.
+func SecondMessageCodec() Codec { return dbtypesBinaryCodec }
+
 // Scan implements sql.Scanner.
 func (x *SecondMessageValue) Scan(src any) error {
+	return x.ScanContext(context.Background(), src)
+}
+
+// ScanContext is like Scan but returns ctx.Err() without decoding if ctx is
+// done.
+func (x *SecondMessageValue) ScanContext(ctx context.Context, src any) error {
 	if x.ProtoValue == nil {
 		x.ProtoValue = &ProtoValue[*SecondMessage]{Message: &SecondMessage{}}
 	}
 	if x.ProtoValue.Message == nil {
 		x.ProtoValue.Message = &SecondMessage{}
 	}
-	return x.ProtoValue.Scan(src)
+	return x.ProtoValue.scan(ctx, src, SecondMessageCodec())
+}
+
+// ScanFields is like Scan but keeps only the top-level fields named in
+// fieldPaths, by their names in the .proto file as in a
+// google.protobuf.FieldMask, leaving the others unset. It returns an error
+// for a name that is not a top-level field of test.v1.SecondMessage.
+// The other fields are skipped in the stored bytes without being decoded.
+func (x *SecondMessageValue) ScanFields(src any, fieldPaths ...string) error {
+	fields, err := dbtypesFieldMask((*SecondMessage)(nil).ProtoReflect().Descriptor(), fieldPaths)
+	if err != nil {
+		return err
+	}
+	if x.ProtoValue == nil {
+		x.ProtoValue = &ProtoValue[*SecondMessage]{Message: &SecondMessage{}}
+	}
+	if x.ProtoValue.Message == nil {
+		x.ProtoValue.Message = &SecondMessage{}
+	}
+	err = x.ProtoValue.scan(context.Background(), src, dbtypesFieldsCodec{Codec: SecondMessageCodec(), fields: fields})
+	if err != nil {
+		return err
+	}
+	// Clear the other fields of the formats that are decoded whole
+	dbtypesProject(x.ProtoValue.Message, fields)
+	return nil
 }
 
 // Value implements driver.Valuer.
 func (x *SecondMessageValue) Value() (driver.Value, error) {
+	return x.ValueContext(context.Background())
+}
+
+// ValueContext is like Value but returns ctx.Err() without encoding if ctx
+// is done.
+func (x *SecondMessageValue) ValueContext(ctx context.Context) (driver.Value, error) {
 	if x.ProtoValue == nil {
 		return nil, nil
 	}
-	return x.ProtoValue.Value()
+	return x.ProtoValue.value(ctx, SecondMessageCodec())
+}
+
+// ValueMasked is like Value but stores a copy of the message with only the
+// fields mask names set, for partial updates. It returns an error for a path
+// that is not a field of test.v1.SecondMessage. A nil or empty mask leaves no
+// field set.
+func (x *SecondMessageValue) ValueMasked(mask *fieldmaskpb.FieldMask) (driver.Value, error) {
+	msg := &SecondMessage{}
+	if x.ProtoValue != nil && x.ProtoValue.Message != nil {
+		msg = proto.Clone(x.ProtoValue.Message).(*SecondMessage)
+	}
+	if err := dbtypesApplyMask(msg.ProtoReflect(), mask.GetPaths()); err != nil {
+		return nil, err
+	}
+	if x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return x.ValueContext(context.Background())
+	}
+	masked := *x.ProtoValue
+	masked.Message = msg
+	return (&SecondMessageValue{ProtoValue: &masked}).ValueContext(context.Background())
 }
 
+var (
+	_ driver.Valuer = (*SecondMessageValue)(nil)
+	_ sql.Scanner   = (*SecondMessageValue)(nil)
+)
+
 // Unwrap returns the underlying protobuf message.
 func (x *SecondMessageValue) Unwrap() *SecondMessage {
 	if x.ProtoValue == nil || x.ProtoValue.Message == nil {
@@ -130,7 +494,263 @@ func (x *SecondMessageValue) Unwrap() *SecondMessage {
 	return x.ProtoValue.Message
 }
 
+// ProtoMessage returns the underlying message as a proto.Message, for code
+// that handles wrappers of different types alike. It returns a nil interface,
+// not a typed nil, for a nil wrapper or one holding no message.
+func (x *SecondMessageValue) ProtoMessage() proto.Message {
+	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return nil
+	}
+	return x.ProtoValue.Message
+}
+
+// GetOrInit returns the underlying protobuf message, first storing an empty
+// one in the wrapper if there is none, so that it never returns nil.
+func (x *SecondMessageValue) GetOrInit() *SecondMessage {
+	if x.ProtoValue == nil {
+		x.ProtoValue = &ProtoValue[*SecondMessage]{}
+	}
+	if x.ProtoValue.Message == nil {
+		x.ProtoValue.Message = &SecondMessage{}
+	}
+	return x.ProtoValue.Message
+}
+
+// Merge merges src into the message as proto.Merge does, first storing an
+// empty message in the wrapper if there is none: populated scalar fields of
+// src overwrite those of the message, repeated fields are appended to and map
+// entries added. A nil src leaves the wrapper unchanged.
+func (x *SecondMessageValue) Merge(src *SecondMessage) {
+	if src == nil {
+		return
+	}
+	proto.Merge(x.GetOrInit(), src)
+}
+
+// Reset drops the message, leaving the wrapper like its zero value, so that
+// it can be reused. The message itself is not modified, since callers may
+// still hold it; use proto.Reset to clear a message in place.
+func (x *SecondMessageValue) Reset() {
+	x.ProtoValue = nil
+}
+
+// Size returns the length of the message in the protobuf wire format, or 0
+// if there is none. Value stores that many bytes unless it compresses,
+// encrypts or stores the message as NULL.
+func (x *SecondMessageValue) Size() int {
+	return proto.Size(x.Unwrap())
+}
+
+// Clone returns a wrapper around a deep copy of the message, or nil for a
+// nil wrapper.
+func (x *SecondMessageValue) Clone() *SecondMessageValue {
+	if x == nil || x.ProtoValue == nil {
+		return nil
+	}
+	msg, _ := proto.Clone(x.ProtoValue.Message).(*SecondMessage)
+	return &SecondMessageValue{ProtoValue: &ProtoValue[*SecondMessage]{Message: msg}}
+}
+
+// Equal reports whether x and other wrap equal messages. Nil wrappers are
+// equal to each other but not to a wrapper holding a message.
+func (x *SecondMessageValue) Equal(other *SecondMessageValue) bool {
+	var a, b *SecondMessage
+	if x != nil {
+		a = x.Unwrap()
+	}
+	if other != nil {
+		b = other.Unwrap()
+	}
+	return proto.Equal(a, b)
+}
+
+// String returns the message in prototext form, or "<nil>" if there is none
+// or the wrapper is nil.
+func (x *SecondMessageValue) String() string {
+	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return "<nil>"
+	}
+	return x.ProtoValue.Message.String()
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler with the same encoding as
+// Value. A nil message encodes as an empty one.
+func (x *SecondMessageValue) MarshalBinary() ([]byte, error) {
+	msg := x.Unwrap()
+	if msg == nil {
+		msg = &SecondMessage{}
+	}
+	return SecondMessageCodec().Encode(msg)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler with the same decoding
+// as Scan.
+func (x *SecondMessageValue) UnmarshalBinary(data []byte) error {
+	x.ProtoValue = &ProtoValue[*SecondMessage]{Message: &SecondMessage{}}
+	return SecondMessageCodec().Decode(data, x.ProtoValue.Message)
+}
+
+// WriteTo implements io.WriterTo, writing the encoding of MarshalBinary to w.
+func (x *SecondMessageValue) WriteTo(w io.Writer) (int64, error) {
+	data, err := x.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// ReadFrom implements io.ReaderFrom, reading r to EOF and decoding the bytes
+// like UnmarshalBinary.
+func (x *SecondMessageValue) ReadFrom(r io.Reader) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return int64(len(data)), err
+	}
+	return int64(len(data)), x.UnmarshalBinary(data)
+}
+
+var (
+	_ encoding.BinaryMarshaler   = (*SecondMessageValue)(nil)
+	_ encoding.BinaryUnmarshaler = (*SecondMessageValue)(nil)
+	_ io.WriterTo                = (*SecondMessageValue)(nil)
+	_ io.ReaderFrom              = (*SecondMessageValue)(nil)
+)
+
+// GobEncode implements gob.GobEncoder with the same encoding as MarshalBinary.
+// A nil wrapper or message encodes as no bytes.
+func (x SecondMessageValue) GobEncode() ([]byte, error) {
+	if x.Unwrap() == nil {
+		return []byte{}, nil
+	}
+	return x.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder with the same decoding as
+// UnmarshalBinary. No bytes decode as a nil message.
+func (x *SecondMessageValue) GobDecode(data []byte) error {
+	if len(data) == 0 {
+		x.ProtoValue = nil
+		return nil
+	}
+	return x.UnmarshalBinary(data)
+}
+
+var (
+	_ gob.GobEncoder = (*SecondMessageValue)(nil)
+	_ gob.GobDecoder = (*SecondMessageValue)(nil)
+)
+
+// MarshalJSON implements json.Marshaler using protojson. A nil message
+// encodes as null.
+func (x SecondMessageValue) MarshalJSON() ([]byte, error) {
+	if x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return []byte("null"), nil
+	}
+	return dbtypesMarshalJSON(x.ProtoValue.Message)
+}
+
+// UnmarshalJSON implements json.Unmarshaler using protojson.
+func (x *SecondMessageValue) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		x.ProtoValue = nil
+		return nil
+	}
+	msg := &SecondMessage{}
+	if err := dbtypesUnmarshalJSON(data, msg); err != nil {
+		return err
+	}
+	x.ProtoValue = &ProtoValue[*SecondMessage]{Message: msg}
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler using prototext. A nil
+// wrapper or message encodes as empty text.
+func (x *SecondMessageValue) MarshalText() ([]byte, error) {
+	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return []byte{}, nil
+	}
+	// Appending keeps the result non-nil for an empty message
+	return prototext.MarshalOptions{}.MarshalAppend([]byte{}, x.ProtoValue.Message)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using prototext. Empty
+// text decodes as an empty message.
+func (x *SecondMessageValue) UnmarshalText(data []byte) error {
+	msg := &SecondMessage{}
+	if err := prototext.Unmarshal(data, msg); err != nil {
+		return err
+	}
+	x.ProtoValue = &ProtoValue[*SecondMessage]{Message: msg}
+	return nil
+}
+
+var (
+	_ encoding.TextMarshaler   = (*SecondMessageValue)(nil)
+	_ encoding.TextUnmarshaler = (*SecondMessageValue)(nil)
+)
+
+// EncodeSecondMessageBatch encodes msgs as SecondMessageValue.Value would, for
+// bulk inserts such as COPY. Nil messages give nil entries.
+func EncodeSecondMessageBatch(msgs []*SecondMessage) ([][]byte, error) {
+	return dbtypesMarshalBatch(msgs, func(msg *SecondMessage) (driver.Value, error) {
+		return NewSecondMessageValue(msg).Value()
+	})
+}
+
+// SecondMessageValues returns what SecondMessageValue.Value returns for each message
+// of msgs, for passing many messages as arguments to ExecContext and the
+// like. Nil messages give nil entries, which are stored as NULL.
+func SecondMessageValues(msgs []*SecondMessage) ([]driver.Value, error) {
+	return dbtypesValues(msgs, func(msg *SecondMessage) (driver.Value, error) {
+		return NewSecondMessageValue(msg).Value()
+	})
+}
+
 // DatabaseValue returns a database-compatible wrapper for this message.
 func (x *SecondMessage) DatabaseValue() *SecondMessageValue {
 	return NewSecondMessageValue(x)
 }
+
+// NullSecondMessageValue represents a *SecondMessage that may be NULL.
+type NullSecondMessageValue struct {
+	SecondMessageValue SecondMessageValue
+	Valid              bool // Valid is true if SecondMessageValue is not NULL
+}
+
+// NewNullSecondMessageValue creates a new NullSecondMessageValue that is valid if msg is non-nil.
+func NewNullSecondMessageValue(msg *SecondMessage) NullSecondMessageValue {
+	if msg == nil {
+		return NullSecondMessageValue{}
+	}
+	return NullSecondMessageValue{SecondMessageValue: *NewSecondMessageValue(msg), Valid: true}
+}
+
+// Scan implements sql.Scanner.
+func (n *NullSecondMessageValue) Scan(src any) error {
+	src = dbtypesScanSource(src)
+	if src == nil {
+		n.SecondMessageValue, n.Valid = SecondMessageValue{}, false
+		return nil
+	}
+	err := n.SecondMessageValue.Scan(src)
+	n.Valid = err == nil
+	return err
+}
+
+// Value implements driver.Valuer.
+func (n NullSecondMessageValue) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	msg := n.SecondMessageValue.Unwrap()
+	if msg == nil {
+		msg = &SecondMessage{}
+	}
+	return NewSecondMessageValue(msg).Value()
+}
+
+var (
+	_ driver.Valuer = (*NullSecondMessageValue)(nil)
+	_ sql.Scanner   = (*NullSecondMessageValue)(nil)
+)
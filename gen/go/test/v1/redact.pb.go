@@ -0,0 +1,142 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: test/v1/redact.proto
+
+package testv1
+
+import (
+	_ "github.com/cadenya/protoc-gen-go-dbtypes/gen/go/dbtypes"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ServiceAccount holds credentials that are redacted before storage.
+type ServiceAccount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ApiToken      string                 `protobuf:"bytes,2,opt,name=api_token,json=apiToken,proto3" json:"api_token,omitempty"`
+	RecoveryCodes []string               `protobuf:"bytes,3,rep,name=recovery_codes,json=recoveryCodes,proto3" json:"recovery_codes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServiceAccount) Reset() {
+	*x = ServiceAccount{}
+	mi := &file_test_v1_redact_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServiceAccount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceAccount) ProtoMessage() {}
+
+func (x *ServiceAccount) ProtoReflect() protoreflect.Message {
+	mi := &file_test_v1_redact_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceAccount.ProtoReflect.Descriptor instead.
+func (*ServiceAccount) Descriptor() ([]byte, []int) {
+	return file_test_v1_redact_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ServiceAccount) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ServiceAccount) GetApiToken() string {
+	if x != nil {
+		return x.ApiToken
+	}
+	return ""
+}
+
+func (x *ServiceAccount) GetRecoveryCodes() []string {
+	if x != nil {
+		return x.RecoveryCodes
+	}
+	return nil
+}
+
+var File_test_v1_redact_proto protoreflect.FileDescriptor
+
+const file_test_v1_redact_proto_rawDesc = "" +
+	"\n" +
+	"\x14test/v1/redact.proto\x12\atest.v1\x1a\x15dbtypes/options.proto\"t\n" +
+	"\x0eServiceAccount\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12!\n" +
+	"\tapi_token\x18\x02 \x01(\tB\x04إ\x19\x01R\bapiToken\x12+\n" +
+	"\x0erecovery_codes\x18\x03 \x03(\tB\x04إ\x19\x01R\rrecoveryCodesBGZEgithub.com/cadenya-agents/protoc-gen-go-dbtypes/gen/go/test/v1;testv1b\x06proto3"
+
+var (
+	file_test_v1_redact_proto_rawDescOnce sync.Once
+	file_test_v1_redact_proto_rawDescData []byte
+)
+
+func file_test_v1_redact_proto_rawDescGZIP() []byte {
+	file_test_v1_redact_proto_rawDescOnce.Do(func() {
+		file_test_v1_redact_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_test_v1_redact_proto_rawDesc), len(file_test_v1_redact_proto_rawDesc)))
+	})
+	return file_test_v1_redact_proto_rawDescData
+}
+
+var file_test_v1_redact_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_test_v1_redact_proto_goTypes = []any{
+	(*ServiceAccount)(nil), // 0: test.v1.ServiceAccount
+}
+var file_test_v1_redact_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_test_v1_redact_proto_init() }
+func file_test_v1_redact_proto_init() {
+	if File_test_v1_redact_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_test_v1_redact_proto_rawDesc), len(file_test_v1_redact_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_test_v1_redact_proto_goTypes,
+		DependencyIndexes: file_test_v1_redact_proto_depIdxs,
+		MessageInfos:      file_test_v1_redact_proto_msgTypes,
+	}.Build()
+	File_test_v1_redact_proto = out.File
+	file_test_v1_redact_proto_goTypes = nil
+	file_test_v1_redact_proto_depIdxs = nil
+}
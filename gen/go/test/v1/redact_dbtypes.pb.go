@@ -0,0 +1,428 @@
+// Code generated by protoc-gen-go-dbtypes. DO NOT EDIT.
+// source: test/v1/redact.proto
+
+package testv1
+
+import (
+	context "context"
+	sql "database/sql"
+	driver "database/sql/driver"
+	encoding "encoding"
+	gob "encoding/gob"
+	prototext "google.golang.org/protobuf/encoding/prototext"
+	proto "google.golang.org/protobuf/proto"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	io "io"
+)
+
+// dbtypesRedactCodec is the codec of messages with fields marked
+// (dbtypes.redact) or (dbtypes.skip). It encodes a copy of each message with
+// redact applied, so that the fields are never stored and the caller's
+// message is left unchanged, and applies skip, if set, to decoded messages.
+type dbtypesRedactCodec struct {
+	Codec
+	redact func(proto.Message)
+	skip   func(proto.Message)
+}
+
+// Encode implements Codec.
+func (c dbtypesRedactCodec) Encode(msg proto.Message) ([]byte, error) {
+	msg = proto.Clone(msg)
+	c.redact(msg)
+	return c.Codec.Encode(msg)
+}
+
+// Decode implements Codec.
+func (c dbtypesRedactCodec) Decode(data []byte, msg proto.Message) error {
+	if err := c.Codec.Decode(data, msg); err != nil {
+		return err
+	}
+	if c.skip != nil {
+		// Rows written before the fields were skipped may still hold them
+		c.skip(msg)
+	}
+	return nil
+}
+
+// ServiceAccountValue is a database-serializable wrapper around test.v1.ServiceAccount.
+// Value stores it as protobuf binary.
+type ServiceAccountValue struct {
+	*ProtoValue[*ServiceAccount]
+}
+
+// NewServiceAccountValue creates a new ServiceAccountValue wrapper. A nil msg
+// gives an empty wrapper, which is stored as NULL like a nil one.
+func NewServiceAccountValue(msg *ServiceAccount) *ServiceAccountValue {
+	if msg == nil {
+		return &ServiceAccountValue{}
+	}
+	return &ServiceAccountValue{
+		ProtoValue: &ProtoValue[*ServiceAccount]{Message: msg},
+	}
+}
+
+// ServiceAccountCodec returns the codec of ServiceAccountValue, which
+// encodes the message without the fields marked (dbtypes.redact) or
+// (dbtypes.skip).
+func ServiceAccountCodec() Codec { return dbtypesServiceAccountCodec }
+
+var dbtypesServiceAccountCodec Codec = dbtypesRedactCodec{Codec: dbtypesBinaryCodec, redact: dbtypesRedactServiceAccount}
+
+// dbtypesRedactServiceAccount clears the fields of a ServiceAccount
+// marked (dbtypes.redact) or (dbtypes.skip).
+func dbtypesRedactServiceAccount(m proto.Message) {
+	msg := m.ProtoReflect()
+	fields := msg.Descriptor().Fields()
+	msg.Clear(fields.ByNumber(2)) // api_token
+	msg.Clear(fields.ByNumber(3)) // recovery_codes
+}
+
+// Scan implements sql.Scanner.
+func (x *ServiceAccountValue) Scan(src any) error {
+	return x.ScanContext(context.Background(), src)
+}
+
+// ScanContext is like Scan but returns ctx.Err() without decoding if ctx is
+// done.
+func (x *ServiceAccountValue) ScanContext(ctx context.Context, src any) error {
+	if x.ProtoValue == nil {
+		x.ProtoValue = &ProtoValue[*ServiceAccount]{Message: &ServiceAccount{}}
+	}
+	if x.ProtoValue.Message == nil {
+		x.ProtoValue.Message = &ServiceAccount{}
+	}
+	return x.ProtoValue.scan(ctx, src, ServiceAccountCodec())
+}
+
+// ScanFields is like Scan but keeps only the top-level fields named in
+// fieldPaths, by their names in the .proto file as in a
+// google.protobuf.FieldMask, leaving the others unset. It returns an error
+// for a name that is not a top-level field of test.v1.ServiceAccount.
+// The other fields are skipped in the stored bytes without being decoded.
+func (x *ServiceAccountValue) ScanFields(src any, fieldPaths ...string) error {
+	fields, err := dbtypesFieldMask((*ServiceAccount)(nil).ProtoReflect().Descriptor(), fieldPaths)
+	if err != nil {
+		return err
+	}
+	if x.ProtoValue == nil {
+		x.ProtoValue = &ProtoValue[*ServiceAccount]{Message: &ServiceAccount{}}
+	}
+	if x.ProtoValue.Message == nil {
+		x.ProtoValue.Message = &ServiceAccount{}
+	}
+	err = x.ProtoValue.scan(context.Background(), src, dbtypesFieldsCodec{Codec: ServiceAccountCodec(), fields: fields})
+	if err != nil {
+		return err
+	}
+	// Clear the other fields of the formats that are decoded whole
+	dbtypesProject(x.ProtoValue.Message, fields)
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (x *ServiceAccountValue) Value() (driver.Value, error) {
+	return x.ValueContext(context.Background())
+}
+
+// ValueContext is like Value but returns ctx.Err() without encoding if ctx
+// is done.
+func (x *ServiceAccountValue) ValueContext(ctx context.Context) (driver.Value, error) {
+	if x.ProtoValue == nil {
+		return nil, nil
+	}
+	return x.ProtoValue.value(ctx, ServiceAccountCodec())
+}
+
+// ValueMasked is like Value but stores a copy of the message with only the
+// fields mask names set, for partial updates. It returns an error for a path
+// that is not a field of test.v1.ServiceAccount. A nil or empty mask leaves no
+// field set.
+func (x *ServiceAccountValue) ValueMasked(mask *fieldmaskpb.FieldMask) (driver.Value, error) {
+	msg := &ServiceAccount{}
+	if x.ProtoValue != nil && x.ProtoValue.Message != nil {
+		msg = proto.Clone(x.ProtoValue.Message).(*ServiceAccount)
+	}
+	if err := dbtypesApplyMask(msg.ProtoReflect(), mask.GetPaths()); err != nil {
+		return nil, err
+	}
+	if x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return x.ValueContext(context.Background())
+	}
+	masked := *x.ProtoValue
+	masked.Message = msg
+	return (&ServiceAccountValue{ProtoValue: &masked}).ValueContext(context.Background())
+}
+
+var (
+	_ driver.Valuer = (*ServiceAccountValue)(nil)
+	_ sql.Scanner   = (*ServiceAccountValue)(nil)
+)
+
+// Unwrap returns the underlying protobuf message.
+func (x *ServiceAccountValue) Unwrap() *ServiceAccount {
+	if x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return nil
+	}
+	return x.ProtoValue.Message
+}
+
+// ProtoMessage returns the underlying message as a proto.Message, for code
+// that handles wrappers of different types alike. It returns a nil interface,
+// not a typed nil, for a nil wrapper or one holding no message.
+func (x *ServiceAccountValue) ProtoMessage() proto.Message {
+	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return nil
+	}
+	return x.ProtoValue.Message
+}
+
+// GetOrInit returns the underlying protobuf message, first storing an empty
+// one in the wrapper if there is none, so that it never returns nil.
+func (x *ServiceAccountValue) GetOrInit() *ServiceAccount {
+	if x.ProtoValue == nil {
+		x.ProtoValue = &ProtoValue[*ServiceAccount]{}
+	}
+	if x.ProtoValue.Message == nil {
+		x.ProtoValue.Message = &ServiceAccount{}
+	}
+	return x.ProtoValue.Message
+}
+
+// Merge merges src into the message as proto.Merge does, first storing an
+// empty message in the wrapper if there is none: populated scalar fields of
+// src overwrite those of the message, repeated fields are appended to and map
+// entries added. A nil src leaves the wrapper unchanged.
+func (x *ServiceAccountValue) Merge(src *ServiceAccount) {
+	if src == nil {
+		return
+	}
+	proto.Merge(x.GetOrInit(), src)
+}
+
+// Reset drops the message, leaving the wrapper like its zero value, so that
+// it can be reused. The message itself is not modified, since callers may
+// still hold it; use proto.Reset to clear a message in place.
+func (x *ServiceAccountValue) Reset() {
+	x.ProtoValue = nil
+}
+
+// Size returns the length of the message in the protobuf wire format, or 0
+// if there is none. Value stores that many bytes unless it compresses,
+// encrypts or stores the message as NULL.
+func (x *ServiceAccountValue) Size() int {
+	return proto.Size(x.Unwrap())
+}
+
+// Clone returns a wrapper around a deep copy of the message, or nil for a
+// nil wrapper.
+func (x *ServiceAccountValue) Clone() *ServiceAccountValue {
+	if x == nil || x.ProtoValue == nil {
+		return nil
+	}
+	msg, _ := proto.Clone(x.ProtoValue.Message).(*ServiceAccount)
+	return &ServiceAccountValue{ProtoValue: &ProtoValue[*ServiceAccount]{Message: msg}}
+}
+
+// Equal reports whether x and other wrap equal messages. Nil wrappers are
+// equal to each other but not to a wrapper holding a message.
+func (x *ServiceAccountValue) Equal(other *ServiceAccountValue) bool {
+	var a, b *ServiceAccount
+	if x != nil {
+		a = x.Unwrap()
+	}
+	if other != nil {
+		b = other.Unwrap()
+	}
+	return proto.Equal(a, b)
+}
+
+// String returns the message in prototext form, or "<nil>" if there is none
+// or the wrapper is nil.
+func (x *ServiceAccountValue) String() string {
+	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return "<nil>"
+	}
+	return x.ProtoValue.Message.String()
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler with the same encoding as
+// Value. A nil message encodes as an empty one.
+func (x *ServiceAccountValue) MarshalBinary() ([]byte, error) {
+	msg := x.Unwrap()
+	if msg == nil {
+		msg = &ServiceAccount{}
+	}
+	return ServiceAccountCodec().Encode(msg)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler with the same decoding
+// as Scan.
+func (x *ServiceAccountValue) UnmarshalBinary(data []byte) error {
+	x.ProtoValue = &ProtoValue[*ServiceAccount]{Message: &ServiceAccount{}}
+	return ServiceAccountCodec().Decode(data, x.ProtoValue.Message)
+}
+
+// WriteTo implements io.WriterTo, writing the encoding of MarshalBinary to w.
+func (x *ServiceAccountValue) WriteTo(w io.Writer) (int64, error) {
+	data, err := x.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// ReadFrom implements io.ReaderFrom, reading r to EOF and decoding the bytes
+// like UnmarshalBinary.
+func (x *ServiceAccountValue) ReadFrom(r io.Reader) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return int64(len(data)), err
+	}
+	return int64(len(data)), x.UnmarshalBinary(data)
+}
+
+var (
+	_ encoding.BinaryMarshaler   = (*ServiceAccountValue)(nil)
+	_ encoding.BinaryUnmarshaler = (*ServiceAccountValue)(nil)
+	_ io.WriterTo                = (*ServiceAccountValue)(nil)
+	_ io.ReaderFrom              = (*ServiceAccountValue)(nil)
+)
+
+// GobEncode implements gob.GobEncoder with the same encoding as MarshalBinary.
+// A nil wrapper or message encodes as no bytes.
+func (x ServiceAccountValue) GobEncode() ([]byte, error) {
+	if x.Unwrap() == nil {
+		return []byte{}, nil
+	}
+	return x.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder with the same decoding as
+// UnmarshalBinary. No bytes decode as a nil message.
+func (x *ServiceAccountValue) GobDecode(data []byte) error {
+	if len(data) == 0 {
+		x.ProtoValue = nil
+		return nil
+	}
+	return x.UnmarshalBinary(data)
+}
+
+var (
+	_ gob.GobEncoder = (*ServiceAccountValue)(nil)
+	_ gob.GobDecoder = (*ServiceAccountValue)(nil)
+)
+
+// MarshalJSON implements json.Marshaler using protojson. A nil message
+// encodes as null.
+func (x ServiceAccountValue) MarshalJSON() ([]byte, error) {
+	if x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return []byte("null"), nil
+	}
+	return dbtypesMarshalJSON(x.ProtoValue.Message)
+}
+
+// UnmarshalJSON implements json.Unmarshaler using protojson.
+func (x *ServiceAccountValue) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		x.ProtoValue = nil
+		return nil
+	}
+	msg := &ServiceAccount{}
+	if err := dbtypesUnmarshalJSON(data, msg); err != nil {
+		return err
+	}
+	x.ProtoValue = &ProtoValue[*ServiceAccount]{Message: msg}
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler using prototext. A nil
+// wrapper or message encodes as empty text.
+func (x *ServiceAccountValue) MarshalText() ([]byte, error) {
+	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return []byte{}, nil
+	}
+	// Appending keeps the result non-nil for an empty message
+	return prototext.MarshalOptions{}.MarshalAppend([]byte{}, x.ProtoValue.Message)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using prototext. Empty
+// text decodes as an empty message.
+func (x *ServiceAccountValue) UnmarshalText(data []byte) error {
+	msg := &ServiceAccount{}
+	if err := prototext.Unmarshal(data, msg); err != nil {
+		return err
+	}
+	x.ProtoValue = &ProtoValue[*ServiceAccount]{Message: msg}
+	return nil
+}
+
+var (
+	_ encoding.TextMarshaler   = (*ServiceAccountValue)(nil)
+	_ encoding.TextUnmarshaler = (*ServiceAccountValue)(nil)
+)
+
+// EncodeServiceAccountBatch encodes msgs as ServiceAccountValue.Value would, for
+// bulk inserts such as COPY. Nil messages give nil entries.
+func EncodeServiceAccountBatch(msgs []*ServiceAccount) ([][]byte, error) {
+	return dbtypesEncodeBatch(msgs, func(msg *ServiceAccount) (driver.Value, error) {
+		return NewServiceAccountValue(msg).Value()
+	})
+}
+
+// ServiceAccountValues returns what ServiceAccountValue.Value returns for each message
+// of msgs, for passing many messages as arguments to ExecContext and the
+// like. Nil messages give nil entries, which are stored as NULL.
+func ServiceAccountValues(msgs []*ServiceAccount) ([]driver.Value, error) {
+	return dbtypesValues(msgs, func(msg *ServiceAccount) (driver.Value, error) {
+		return NewServiceAccountValue(msg).Value()
+	})
+}
+
+// DatabaseValue returns a database-compatible wrapper for this message.
+func (x *ServiceAccount) DatabaseValue() *ServiceAccountValue {
+	return NewServiceAccountValue(x)
+}
+
+// NullServiceAccountValue represents a *ServiceAccount that may be NULL.
+type NullServiceAccountValue struct {
+	ServiceAccountValue ServiceAccountValue
+	Valid               bool // Valid is true if ServiceAccountValue is not NULL
+}
+
+// NewNullServiceAccountValue creates a new NullServiceAccountValue that is valid if msg is non-nil.
+func NewNullServiceAccountValue(msg *ServiceAccount) NullServiceAccountValue {
+	if msg == nil {
+		return NullServiceAccountValue{}
+	}
+	return NullServiceAccountValue{ServiceAccountValue: *NewServiceAccountValue(msg), Valid: true}
+}
+
+// Scan implements sql.Scanner.
+func (n *NullServiceAccountValue) Scan(src any) error {
+	src = dbtypesScanSource(src)
+	if src == nil {
+		n.ServiceAccountValue, n.Valid = ServiceAccountValue{}, false
+		return nil
+	}
+	err := n.ServiceAccountValue.Scan(src)
+	n.Valid = err == nil
+	return err
+}
+
+// Value implements driver.Valuer.
+func (n NullServiceAccountValue) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	msg := n.ServiceAccountValue.Unwrap()
+	if msg == nil {
+		msg = &ServiceAccount{}
+	}
+	return NewServiceAccountValue(msg).Value()
+}
+
+var (
+	_ driver.Valuer = (*NullServiceAccountValue)(nil)
+	_ sql.Scanner   = (*NullServiceAccountValue)(nil)
+)
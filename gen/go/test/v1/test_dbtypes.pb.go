@@ -4,43 +4,118 @@
 package testv1
 
 import (
+	context "context"
+	sql "database/sql"
 	driver "database/sql/driver"
+	encoding "encoding"
+	gob "encoding/gob"
+	prototext "google.golang.org/protobuf/encoding/prototext"
+	proto "google.golang.org/protobuf/proto"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	io "io"
 )
 
-// ToolSetSpecValue wraps *ToolSetSpec for database operations.
+// ToolSetSpecValue is a database-serializable wrapper around test.v1.ToolSetSpec.
+// Value stores it as protobuf binary.
 type ToolSetSpecValue struct {
 	*ProtoValue[*ToolSetSpec]
 }
 
-// NewToolSetSpecValue creates a new ToolSetSpecValue wrapper.
+// NewToolSetSpecValue creates a new ToolSetSpecValue wrapper. A nil msg
+// gives an empty wrapper, which is stored as NULL like a nil one.
 func NewToolSetSpecValue(msg *ToolSetSpec) *ToolSetSpecValue {
 	if msg == nil {
-		msg = &ToolSetSpec{}
+		return &ToolSetSpecValue{}
 	}
 	return &ToolSetSpecValue{
 		ProtoValue: &ProtoValue[*ToolSetSpec]{Message: msg},
 	}
 }
 
+// ToolSetSpecCodec returns the codec of ToolSetSpecValue.
+func ToolSetSpecCodec() Codec { return dbtypesBinaryCodec }
+
 // Scan implements sql.Scanner.
 func (x *ToolSetSpecValue) Scan(src any) error {
+	return x.ScanContext(context.Background(), src)
+}
+
+// ScanContext is like Scan but returns ctx.Err() without decoding if ctx is
+// done.
+func (x *ToolSetSpecValue) ScanContext(ctx context.Context, src any) error {
 	if x.ProtoValue == nil {
 		x.ProtoValue = &ProtoValue[*ToolSetSpec]{Message: &ToolSetSpec{}}
 	}
 	if x.ProtoValue.Message == nil {
 		x.ProtoValue.Message = &ToolSetSpec{}
 	}
-	return x.ProtoValue.Scan(src)
+	return x.ProtoValue.scan(ctx, src, ToolSetSpecCodec())
+}
+
+// ScanFields is like Scan but keeps only the top-level fields named in
+// fieldPaths, by their names in the .proto file as in a
+// google.protobuf.FieldMask, leaving the others unset. It returns an error
+// for a name that is not a top-level field of test.v1.ToolSetSpec.
+// The other fields are skipped in the stored bytes without being decoded.
+func (x *ToolSetSpecValue) ScanFields(src any, fieldPaths ...string) error {
+	fields, err := dbtypesFieldMask((*ToolSetSpec)(nil).ProtoReflect().Descriptor(), fieldPaths)
+	if err != nil {
+		return err
+	}
+	if x.ProtoValue == nil {
+		x.ProtoValue = &ProtoValue[*ToolSetSpec]{Message: &ToolSetSpec{}}
+	}
+	if x.ProtoValue.Message == nil {
+		x.ProtoValue.Message = &ToolSetSpec{}
+	}
+	err = x.ProtoValue.scan(context.Background(), src, dbtypesFieldsCodec{Codec: ToolSetSpecCodec(), fields: fields})
+	if err != nil {
+		return err
+	}
+	// Clear the other fields of the formats that are decoded whole
+	dbtypesProject(x.ProtoValue.Message, fields)
+	return nil
 }
 
 // Value implements driver.Valuer.
 func (x *ToolSetSpecValue) Value() (driver.Value, error) {
+	return x.ValueContext(context.Background())
+}
+
+// ValueContext is like Value but returns ctx.Err() without encoding if ctx
+// is done.
+func (x *ToolSetSpecValue) ValueContext(ctx context.Context) (driver.Value, error) {
 	if x.ProtoValue == nil {
 		return nil, nil
 	}
-	return x.ProtoValue.Value()
+	return x.ProtoValue.value(ctx, ToolSetSpecCodec())
 }
 
+// ValueMasked is like Value but stores a copy of the message with only the
+// fields mask names set, for partial updates. It returns an error for a path
+// that is not a field of test.v1.ToolSetSpec. A nil or empty mask leaves no
+// field set.
+func (x *ToolSetSpecValue) ValueMasked(mask *fieldmaskpb.FieldMask) (driver.Value, error) {
+	msg := &ToolSetSpec{}
+	if x.ProtoValue != nil && x.ProtoValue.Message != nil {
+		msg = proto.Clone(x.ProtoValue.Message).(*ToolSetSpec)
+	}
+	if err := dbtypesApplyMask(msg.ProtoReflect(), mask.GetPaths()); err != nil {
+		return nil, err
+	}
+	if x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return x.ValueContext(context.Background())
+	}
+	masked := *x.ProtoValue
+	masked.Message = msg
+	return (&ToolSetSpecValue{ProtoValue: &masked}).ValueContext(context.Background())
+}
+
+var (
+	_ driver.Valuer = (*ToolSetSpecValue)(nil)
+	_ sql.Scanner   = (*ToolSetSpecValue)(nil)
+)
+
 // Unwrap returns the underlying protobuf message.
 func (x *ToolSetSpecValue) Unwrap() *ToolSetSpec {
 	if x.ProtoValue == nil || x.ProtoValue.Message == nil {
@@ -49,45 +124,368 @@ func (x *ToolSetSpecValue) Unwrap() *ToolSetSpec {
 	return x.ProtoValue.Message
 }
 
+// ProtoMessage returns the underlying message as a proto.Message, for code
+// that handles wrappers of different types alike. It returns a nil interface,
+// not a typed nil, for a nil wrapper or one holding no message.
+func (x *ToolSetSpecValue) ProtoMessage() proto.Message {
+	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return nil
+	}
+	return x.ProtoValue.Message
+}
+
+// GetOrInit returns the underlying protobuf message, first storing an empty
+// one in the wrapper if there is none, so that it never returns nil.
+func (x *ToolSetSpecValue) GetOrInit() *ToolSetSpec {
+	if x.ProtoValue == nil {
+		x.ProtoValue = &ProtoValue[*ToolSetSpec]{}
+	}
+	if x.ProtoValue.Message == nil {
+		x.ProtoValue.Message = &ToolSetSpec{}
+	}
+	return x.ProtoValue.Message
+}
+
+// Merge merges src into the message as proto.Merge does, first storing an
+// empty message in the wrapper if there is none: populated scalar fields of
+// src overwrite those of the message, repeated fields are appended to and map
+// entries added. A nil src leaves the wrapper unchanged.
+func (x *ToolSetSpecValue) Merge(src *ToolSetSpec) {
+	if src == nil {
+		return
+	}
+	proto.Merge(x.GetOrInit(), src)
+}
+
+// Reset drops the message, leaving the wrapper like its zero value, so that
+// it can be reused. The message itself is not modified, since callers may
+// still hold it; use proto.Reset to clear a message in place.
+func (x *ToolSetSpecValue) Reset() {
+	x.ProtoValue = nil
+}
+
+// Size returns the length of the message in the protobuf wire format, or 0
+// if there is none. Value stores that many bytes unless it compresses,
+// encrypts or stores the message as NULL.
+func (x *ToolSetSpecValue) Size() int {
+	return proto.Size(x.Unwrap())
+}
+
+// Clone returns a wrapper around a deep copy of the message, or nil for a
+// nil wrapper.
+func (x *ToolSetSpecValue) Clone() *ToolSetSpecValue {
+	if x == nil || x.ProtoValue == nil {
+		return nil
+	}
+	msg, _ := proto.Clone(x.ProtoValue.Message).(*ToolSetSpec)
+	return &ToolSetSpecValue{ProtoValue: &ProtoValue[*ToolSetSpec]{Message: msg}}
+}
+
+// Equal reports whether x and other wrap equal messages. Nil wrappers are
+// equal to each other but not to a wrapper holding a message.
+func (x *ToolSetSpecValue) Equal(other *ToolSetSpecValue) bool {
+	var a, b *ToolSetSpec
+	if x != nil {
+		a = x.Unwrap()
+	}
+	if other != nil {
+		b = other.Unwrap()
+	}
+	return proto.Equal(a, b)
+}
+
+// String returns the message in prototext form, or "<nil>" if there is none
+// or the wrapper is nil.
+func (x *ToolSetSpecValue) String() string {
+	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return "<nil>"
+	}
+	return x.ProtoValue.Message.String()
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler with the same encoding as
+// Value. A nil message encodes as an empty one.
+func (x *ToolSetSpecValue) MarshalBinary() ([]byte, error) {
+	msg := x.Unwrap()
+	if msg == nil {
+		msg = &ToolSetSpec{}
+	}
+	return ToolSetSpecCodec().Encode(msg)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler with the same decoding
+// as Scan.
+func (x *ToolSetSpecValue) UnmarshalBinary(data []byte) error {
+	x.ProtoValue = &ProtoValue[*ToolSetSpec]{Message: &ToolSetSpec{}}
+	return ToolSetSpecCodec().Decode(data, x.ProtoValue.Message)
+}
+
+// WriteTo implements io.WriterTo, writing the encoding of MarshalBinary to w.
+func (x *ToolSetSpecValue) WriteTo(w io.Writer) (int64, error) {
+	data, err := x.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// ReadFrom implements io.ReaderFrom, reading r to EOF and decoding the bytes
+// like UnmarshalBinary.
+func (x *ToolSetSpecValue) ReadFrom(r io.Reader) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return int64(len(data)), err
+	}
+	return int64(len(data)), x.UnmarshalBinary(data)
+}
+
+var (
+	_ encoding.BinaryMarshaler   = (*ToolSetSpecValue)(nil)
+	_ encoding.BinaryUnmarshaler = (*ToolSetSpecValue)(nil)
+	_ io.WriterTo                = (*ToolSetSpecValue)(nil)
+	_ io.ReaderFrom              = (*ToolSetSpecValue)(nil)
+)
+
+// GobEncode implements gob.GobEncoder with the same encoding as MarshalBinary.
+// A nil wrapper or message encodes as no bytes.
+func (x ToolSetSpecValue) GobEncode() ([]byte, error) {
+	if x.Unwrap() == nil {
+		return []byte{}, nil
+	}
+	return x.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder with the same decoding as
+// UnmarshalBinary. No bytes decode as a nil message.
+func (x *ToolSetSpecValue) GobDecode(data []byte) error {
+	if len(data) == 0 {
+		x.ProtoValue = nil
+		return nil
+	}
+	return x.UnmarshalBinary(data)
+}
+
+var (
+	_ gob.GobEncoder = (*ToolSetSpecValue)(nil)
+	_ gob.GobDecoder = (*ToolSetSpecValue)(nil)
+)
+
+// MarshalJSON implements json.Marshaler using protojson. A nil message
+// encodes as null.
+func (x ToolSetSpecValue) MarshalJSON() ([]byte, error) {
+	if x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return []byte("null"), nil
+	}
+	return dbtypesMarshalJSON(x.ProtoValue.Message)
+}
+
+// UnmarshalJSON implements json.Unmarshaler using protojson.
+func (x *ToolSetSpecValue) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		x.ProtoValue = nil
+		return nil
+	}
+	msg := &ToolSetSpec{}
+	if err := dbtypesUnmarshalJSON(data, msg); err != nil {
+		return err
+	}
+	x.ProtoValue = &ProtoValue[*ToolSetSpec]{Message: msg}
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler using prototext. A nil
+// wrapper or message encodes as empty text.
+func (x *ToolSetSpecValue) MarshalText() ([]byte, error) {
+	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return []byte{}, nil
+	}
+	// Appending keeps the result non-nil for an empty message
+	return prototext.MarshalOptions{}.MarshalAppend([]byte{}, x.ProtoValue.Message)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using prototext. Empty
+// text decodes as an empty message.
+func (x *ToolSetSpecValue) UnmarshalText(data []byte) error {
+	msg := &ToolSetSpec{}
+	if err := prototext.Unmarshal(data, msg); err != nil {
+		return err
+	}
+	x.ProtoValue = &ProtoValue[*ToolSetSpec]{Message: msg}
+	return nil
+}
+
+var (
+	_ encoding.TextMarshaler   = (*ToolSetSpecValue)(nil)
+	_ encoding.TextUnmarshaler = (*ToolSetSpecValue)(nil)
+)
+
+// EncodeToolSetSpecBatch encodes msgs as ToolSetSpecValue.Value would, for
+// bulk inserts such as COPY. Nil messages give nil entries.
+func EncodeToolSetSpecBatch(msgs []*ToolSetSpec) ([][]byte, error) {
+	return dbtypesMarshalBatch(msgs, func(msg *ToolSetSpec) (driver.Value, error) {
+		return NewToolSetSpecValue(msg).Value()
+	})
+}
+
+// ToolSetSpecValues returns what ToolSetSpecValue.Value returns for each message
+// of msgs, for passing many messages as arguments to ExecContext and the
+// like. Nil messages give nil entries, which are stored as NULL.
+func ToolSetSpecValues(msgs []*ToolSetSpec) ([]driver.Value, error) {
+	return dbtypesValues(msgs, func(msg *ToolSetSpec) (driver.Value, error) {
+		return NewToolSetSpecValue(msg).Value()
+	})
+}
+
 // DatabaseValue returns a database-compatible wrapper for this message.
 func (x *ToolSetSpec) DatabaseValue() *ToolSetSpecValue {
 	return NewToolSetSpecValue(x)
 }
 
-// UserPreferencesValue wraps *UserPreferences for database operations.
+// NullToolSetSpecValue represents a *ToolSetSpec that may be NULL.
+type NullToolSetSpecValue struct {
+	ToolSetSpecValue ToolSetSpecValue
+	Valid            bool // Valid is true if ToolSetSpecValue is not NULL
+}
+
+// NewNullToolSetSpecValue creates a new NullToolSetSpecValue that is valid if msg is non-nil.
+func NewNullToolSetSpecValue(msg *ToolSetSpec) NullToolSetSpecValue {
+	if msg == nil {
+		return NullToolSetSpecValue{}
+	}
+	return NullToolSetSpecValue{ToolSetSpecValue: *NewToolSetSpecValue(msg), Valid: true}
+}
+
+// Scan implements sql.Scanner.
+func (n *NullToolSetSpecValue) Scan(src any) error {
+	src = dbtypesScanSource(src)
+	if src == nil {
+		n.ToolSetSpecValue, n.Valid = ToolSetSpecValue{}, false
+		return nil
+	}
+	err := n.ToolSetSpecValue.Scan(src)
+	n.Valid = err == nil
+	return err
+}
+
+// Value implements driver.Valuer.
+func (n NullToolSetSpecValue) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	msg := n.ToolSetSpecValue.Unwrap()
+	if msg == nil {
+		msg = &ToolSetSpec{}
+	}
+	return NewToolSetSpecValue(msg).Value()
+}
+
+var (
+	_ driver.Valuer = (*NullToolSetSpecValue)(nil)
+	_ sql.Scanner   = (*NullToolSetSpecValue)(nil)
+)
+
+// UserPreferencesValue is a database-serializable wrapper around test.v1.UserPreferences.
+// Value stores it as protobuf binary.
 type UserPreferencesValue struct {
 	*ProtoValue[*UserPreferences]
 }
 
-// NewUserPreferencesValue creates a new UserPreferencesValue wrapper.
+// NewUserPreferencesValue creates a new UserPreferencesValue wrapper. A nil msg
+// gives an empty wrapper, which is stored as NULL like a nil one.
 func NewUserPreferencesValue(msg *UserPreferences) *UserPreferencesValue {
 	if msg == nil {
-		msg = &UserPreferences{}
+		return &UserPreferencesValue{}
 	}
 	return &UserPreferencesValue{
 		ProtoValue: &ProtoValue[*UserPreferences]{Message: msg},
 	}
 }
 
+// UserPreferencesCodec returns the codec of UserPreferencesValue.
+func UserPreferencesCodec() Codec { return dbtypesBinaryCodec }
+
 // Scan implements sql.Scanner.
 func (x *UserPreferencesValue) Scan(src any) error {
+	return x.ScanContext(context.Background(), src)
+}
+
+// ScanContext is like Scan but returns ctx.Err() without decoding if ctx is
+// done.
+func (x *UserPreferencesValue) ScanContext(ctx context.Context, src any) error {
 	if x.ProtoValue == nil {
 		x.ProtoValue = &ProtoValue[*UserPreferences]{Message: &UserPreferences{}}
 	}
 	if x.ProtoValue.Message == nil {
 		x.ProtoValue.Message = &UserPreferences{}
 	}
-	return x.ProtoValue.Scan(src)
+	return x.ProtoValue.scan(ctx, src, UserPreferencesCodec())
+}
+
+// ScanFields is like Scan but keeps only the top-level fields named in
+// fieldPaths, by their names in the .proto file as in a
+// google.protobuf.FieldMask, leaving the others unset. It returns an error
+// for a name that is not a top-level field of test.v1.UserPreferences.
+// The other fields are skipped in the stored bytes without being decoded.
+func (x *UserPreferencesValue) ScanFields(src any, fieldPaths ...string) error {
+	fields, err := dbtypesFieldMask((*UserPreferences)(nil).ProtoReflect().Descriptor(), fieldPaths)
+	if err != nil {
+		return err
+	}
+	if x.ProtoValue == nil {
+		x.ProtoValue = &ProtoValue[*UserPreferences]{Message: &UserPreferences{}}
+	}
+	if x.ProtoValue.Message == nil {
+		x.ProtoValue.Message = &UserPreferences{}
+	}
+	err = x.ProtoValue.scan(context.Background(), src, dbtypesFieldsCodec{Codec: UserPreferencesCodec(), fields: fields})
+	if err != nil {
+		return err
+	}
+	// Clear the other fields of the formats that are decoded whole
+	dbtypesProject(x.ProtoValue.Message, fields)
+	return nil
 }
 
 // Value implements driver.Valuer.
 func (x *UserPreferencesValue) Value() (driver.Value, error) {
+	return x.ValueContext(context.Background())
+}
+
+// ValueContext is like Value but returns ctx.Err() without encoding if ctx
+// is done.
+func (x *UserPreferencesValue) ValueContext(ctx context.Context) (driver.Value, error) {
 	if x.ProtoValue == nil {
 		return nil, nil
 	}
-	return x.ProtoValue.Value()
+	return x.ProtoValue.value(ctx, UserPreferencesCodec())
 }
 
+// ValueMasked is like Value but stores a copy of the message with only the
+// fields mask names set, for partial updates. It returns an error for a path
+// that is not a field of test.v1.UserPreferences. A nil or empty mask leaves no
+// field set.
+func (x *UserPreferencesValue) ValueMasked(mask *fieldmaskpb.FieldMask) (driver.Value, error) {
+	msg := &UserPreferences{}
+	if x.ProtoValue != nil && x.ProtoValue.Message != nil {
+		msg = proto.Clone(x.ProtoValue.Message).(*UserPreferences)
+	}
+	if err := dbtypesApplyMask(msg.ProtoReflect(), mask.GetPaths()); err != nil {
+		return nil, err
+	}
+	if x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return x.ValueContext(context.Background())
+	}
+	masked := *x.ProtoValue
+	masked.Message = msg
+	return (&UserPreferencesValue{ProtoValue: &masked}).ValueContext(context.Background())
+}
+
+var (
+	_ driver.Valuer = (*UserPreferencesValue)(nil)
+	_ sql.Scanner   = (*UserPreferencesValue)(nil)
+)
+
 // Unwrap returns the underlying protobuf message.
 func (x *UserPreferencesValue) Unwrap() *UserPreferences {
 	if x.ProtoValue == nil || x.ProtoValue.Message == nil {
@@ -96,45 +494,368 @@ func (x *UserPreferencesValue) Unwrap() *UserPreferences {
 	return x.ProtoValue.Message
 }
 
+// ProtoMessage returns the underlying message as a proto.Message, for code
+// that handles wrappers of different types alike. It returns a nil interface,
+// not a typed nil, for a nil wrapper or one holding no message.
+func (x *UserPreferencesValue) ProtoMessage() proto.Message {
+	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return nil
+	}
+	return x.ProtoValue.Message
+}
+
+// GetOrInit returns the underlying protobuf message, first storing an empty
+// one in the wrapper if there is none, so that it never returns nil.
+func (x *UserPreferencesValue) GetOrInit() *UserPreferences {
+	if x.ProtoValue == nil {
+		x.ProtoValue = &ProtoValue[*UserPreferences]{}
+	}
+	if x.ProtoValue.Message == nil {
+		x.ProtoValue.Message = &UserPreferences{}
+	}
+	return x.ProtoValue.Message
+}
+
+// Merge merges src into the message as proto.Merge does, first storing an
+// empty message in the wrapper if there is none: populated scalar fields of
+// src overwrite those of the message, repeated fields are appended to and map
+// entries added. A nil src leaves the wrapper unchanged.
+func (x *UserPreferencesValue) Merge(src *UserPreferences) {
+	if src == nil {
+		return
+	}
+	proto.Merge(x.GetOrInit(), src)
+}
+
+// Reset drops the message, leaving the wrapper like its zero value, so that
+// it can be reused. The message itself is not modified, since callers may
+// still hold it; use proto.Reset to clear a message in place.
+func (x *UserPreferencesValue) Reset() {
+	x.ProtoValue = nil
+}
+
+// Size returns the length of the message in the protobuf wire format, or 0
+// if there is none. Value stores that many bytes unless it compresses,
+// encrypts or stores the message as NULL.
+func (x *UserPreferencesValue) Size() int {
+	return proto.Size(x.Unwrap())
+}
+
+// Clone returns a wrapper around a deep copy of the message, or nil for a
+// nil wrapper.
+func (x *UserPreferencesValue) Clone() *UserPreferencesValue {
+	if x == nil || x.ProtoValue == nil {
+		return nil
+	}
+	msg, _ := proto.Clone(x.ProtoValue.Message).(*UserPreferences)
+	return &UserPreferencesValue{ProtoValue: &ProtoValue[*UserPreferences]{Message: msg}}
+}
+
+// Equal reports whether x and other wrap equal messages. Nil wrappers are
+// equal to each other but not to a wrapper holding a message.
+func (x *UserPreferencesValue) Equal(other *UserPreferencesValue) bool {
+	var a, b *UserPreferences
+	if x != nil {
+		a = x.Unwrap()
+	}
+	if other != nil {
+		b = other.Unwrap()
+	}
+	return proto.Equal(a, b)
+}
+
+// String returns the message in prototext form, or "<nil>" if there is none
+// or the wrapper is nil.
+func (x *UserPreferencesValue) String() string {
+	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return "<nil>"
+	}
+	return x.ProtoValue.Message.String()
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler with the same encoding as
+// Value. A nil message encodes as an empty one.
+func (x *UserPreferencesValue) MarshalBinary() ([]byte, error) {
+	msg := x.Unwrap()
+	if msg == nil {
+		msg = &UserPreferences{}
+	}
+	return UserPreferencesCodec().Encode(msg)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler with the same decoding
+// as Scan.
+func (x *UserPreferencesValue) UnmarshalBinary(data []byte) error {
+	x.ProtoValue = &ProtoValue[*UserPreferences]{Message: &UserPreferences{}}
+	return UserPreferencesCodec().Decode(data, x.ProtoValue.Message)
+}
+
+// WriteTo implements io.WriterTo, writing the encoding of MarshalBinary to w.
+func (x *UserPreferencesValue) WriteTo(w io.Writer) (int64, error) {
+	data, err := x.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// ReadFrom implements io.ReaderFrom, reading r to EOF and decoding the bytes
+// like UnmarshalBinary.
+func (x *UserPreferencesValue) ReadFrom(r io.Reader) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return int64(len(data)), err
+	}
+	return int64(len(data)), x.UnmarshalBinary(data)
+}
+
+var (
+	_ encoding.BinaryMarshaler   = (*UserPreferencesValue)(nil)
+	_ encoding.BinaryUnmarshaler = (*UserPreferencesValue)(nil)
+	_ io.WriterTo                = (*UserPreferencesValue)(nil)
+	_ io.ReaderFrom              = (*UserPreferencesValue)(nil)
+)
+
+// GobEncode implements gob.GobEncoder with the same encoding as MarshalBinary.
+// A nil wrapper or message encodes as no bytes.
+func (x UserPreferencesValue) GobEncode() ([]byte, error) {
+	if x.Unwrap() == nil {
+		return []byte{}, nil
+	}
+	return x.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder with the same decoding as
+// UnmarshalBinary. No bytes decode as a nil message.
+func (x *UserPreferencesValue) GobDecode(data []byte) error {
+	if len(data) == 0 {
+		x.ProtoValue = nil
+		return nil
+	}
+	return x.UnmarshalBinary(data)
+}
+
+var (
+	_ gob.GobEncoder = (*UserPreferencesValue)(nil)
+	_ gob.GobDecoder = (*UserPreferencesValue)(nil)
+)
+
+// MarshalJSON implements json.Marshaler using protojson. A nil message
+// encodes as null.
+func (x UserPreferencesValue) MarshalJSON() ([]byte, error) {
+	if x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return []byte("null"), nil
+	}
+	return dbtypesMarshalJSON(x.ProtoValue.Message)
+}
+
+// UnmarshalJSON implements json.Unmarshaler using protojson.
+func (x *UserPreferencesValue) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		x.ProtoValue = nil
+		return nil
+	}
+	msg := &UserPreferences{}
+	if err := dbtypesUnmarshalJSON(data, msg); err != nil {
+		return err
+	}
+	x.ProtoValue = &ProtoValue[*UserPreferences]{Message: msg}
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler using prototext. A nil
+// wrapper or message encodes as empty text.
+func (x *UserPreferencesValue) MarshalText() ([]byte, error) {
+	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return []byte{}, nil
+	}
+	// Appending keeps the result non-nil for an empty message
+	return prototext.MarshalOptions{}.MarshalAppend([]byte{}, x.ProtoValue.Message)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using prototext. Empty
+// text decodes as an empty message.
+func (x *UserPreferencesValue) UnmarshalText(data []byte) error {
+	msg := &UserPreferences{}
+	if err := prototext.Unmarshal(data, msg); err != nil {
+		return err
+	}
+	x.ProtoValue = &ProtoValue[*UserPreferences]{Message: msg}
+	return nil
+}
+
+var (
+	_ encoding.TextMarshaler   = (*UserPreferencesValue)(nil)
+	_ encoding.TextUnmarshaler = (*UserPreferencesValue)(nil)
+)
+
+// EncodeUserPreferencesBatch encodes msgs as UserPreferencesValue.Value would, for
+// bulk inserts such as COPY. Nil messages give nil entries.
+func EncodeUserPreferencesBatch(msgs []*UserPreferences) ([][]byte, error) {
+	return dbtypesMarshalBatch(msgs, func(msg *UserPreferences) (driver.Value, error) {
+		return NewUserPreferencesValue(msg).Value()
+	})
+}
+
+// UserPreferencesValues returns what UserPreferencesValue.Value returns for each message
+// of msgs, for passing many messages as arguments to ExecContext and the
+// like. Nil messages give nil entries, which are stored as NULL.
+func UserPreferencesValues(msgs []*UserPreferences) ([]driver.Value, error) {
+	return dbtypesValues(msgs, func(msg *UserPreferences) (driver.Value, error) {
+		return NewUserPreferencesValue(msg).Value()
+	})
+}
+
 // DatabaseValue returns a database-compatible wrapper for this message.
 func (x *UserPreferences) DatabaseValue() *UserPreferencesValue {
 	return NewUserPreferencesValue(x)
 }
 
-// ContainerValue wraps *Container for database operations.
+// NullUserPreferencesValue represents a *UserPreferences that may be NULL.
+type NullUserPreferencesValue struct {
+	UserPreferencesValue UserPreferencesValue
+	Valid                bool // Valid is true if UserPreferencesValue is not NULL
+}
+
+// NewNullUserPreferencesValue creates a new NullUserPreferencesValue that is valid if msg is non-nil.
+func NewNullUserPreferencesValue(msg *UserPreferences) NullUserPreferencesValue {
+	if msg == nil {
+		return NullUserPreferencesValue{}
+	}
+	return NullUserPreferencesValue{UserPreferencesValue: *NewUserPreferencesValue(msg), Valid: true}
+}
+
+// Scan implements sql.Scanner.
+func (n *NullUserPreferencesValue) Scan(src any) error {
+	src = dbtypesScanSource(src)
+	if src == nil {
+		n.UserPreferencesValue, n.Valid = UserPreferencesValue{}, false
+		return nil
+	}
+	err := n.UserPreferencesValue.Scan(src)
+	n.Valid = err == nil
+	return err
+}
+
+// Value implements driver.Valuer.
+func (n NullUserPreferencesValue) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	msg := n.UserPreferencesValue.Unwrap()
+	if msg == nil {
+		msg = &UserPreferences{}
+	}
+	return NewUserPreferencesValue(msg).Value()
+}
+
+var (
+	_ driver.Valuer = (*NullUserPreferencesValue)(nil)
+	_ sql.Scanner   = (*NullUserPreferencesValue)(nil)
+)
+
+// ContainerValue is a database-serializable wrapper around test.v1.Container.
+// Value stores it as protobuf binary.
 type ContainerValue struct {
 	*ProtoValue[*Container]
 }
 
-// NewContainerValue creates a new ContainerValue wrapper.
+// NewContainerValue creates a new ContainerValue wrapper. A nil msg
+// gives an empty wrapper, which is stored as NULL like a nil one.
 func NewContainerValue(msg *Container) *ContainerValue {
 	if msg == nil {
-		msg = &Container{}
+		return &ContainerValue{}
 	}
 	return &ContainerValue{
 		ProtoValue: &ProtoValue[*Container]{Message: msg},
 	}
 }
 
+// ContainerCodec returns the codec of ContainerValue.
+func ContainerCodec() Codec { return dbtypesBinaryCodec }
+
 // Scan implements sql.Scanner.
 func (x *ContainerValue) Scan(src any) error {
+	return x.ScanContext(context.Background(), src)
+}
+
+// ScanContext is like Scan but returns ctx.Err() without decoding if ctx is
+// done.
+func (x *ContainerValue) ScanContext(ctx context.Context, src any) error {
 	if x.ProtoValue == nil {
 		x.ProtoValue = &ProtoValue[*Container]{Message: &Container{}}
 	}
 	if x.ProtoValue.Message == nil {
 		x.ProtoValue.Message = &Container{}
 	}
-	return x.ProtoValue.Scan(src)
+	return x.ProtoValue.scan(ctx, src, ContainerCodec())
+}
+
+// ScanFields is like Scan but keeps only the top-level fields named in
+// fieldPaths, by their names in the .proto file as in a
+// google.protobuf.FieldMask, leaving the others unset. It returns an error
+// for a name that is not a top-level field of test.v1.Container.
+// The other fields are skipped in the stored bytes without being decoded.
+func (x *ContainerValue) ScanFields(src any, fieldPaths ...string) error {
+	fields, err := dbtypesFieldMask((*Container)(nil).ProtoReflect().Descriptor(), fieldPaths)
+	if err != nil {
+		return err
+	}
+	if x.ProtoValue == nil {
+		x.ProtoValue = &ProtoValue[*Container]{Message: &Container{}}
+	}
+	if x.ProtoValue.Message == nil {
+		x.ProtoValue.Message = &Container{}
+	}
+	err = x.ProtoValue.scan(context.Background(), src, dbtypesFieldsCodec{Codec: ContainerCodec(), fields: fields})
+	if err != nil {
+		return err
+	}
+	// Clear the other fields of the formats that are decoded whole
+	dbtypesProject(x.ProtoValue.Message, fields)
+	return nil
 }
 
 // Value implements driver.Valuer.
 func (x *ContainerValue) Value() (driver.Value, error) {
+	return x.ValueContext(context.Background())
+}
+
+// ValueContext is like Value but returns ctx.Err() without encoding if ctx
+// is done.
+func (x *ContainerValue) ValueContext(ctx context.Context) (driver.Value, error) {
 	if x.ProtoValue == nil {
 		return nil, nil
 	}
-	return x.ProtoValue.Value()
+	return x.ProtoValue.value(ctx, ContainerCodec())
+}
+
+// ValueMasked is like Value but stores a copy of the message with only the
+// fields mask names set, for partial updates. It returns an error for a path
+// that is not a field of test.v1.Container. A nil or empty mask leaves no
+// field set.
+func (x *ContainerValue) ValueMasked(mask *fieldmaskpb.FieldMask) (driver.Value, error) {
+	msg := &Container{}
+	if x.ProtoValue != nil && x.ProtoValue.Message != nil {
+		msg = proto.Clone(x.ProtoValue.Message).(*Container)
+	}
+	if err := dbtypesApplyMask(msg.ProtoReflect(), mask.GetPaths()); err != nil {
+		return nil, err
+	}
+	if x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return x.ValueContext(context.Background())
+	}
+	masked := *x.ProtoValue
+	masked.Message = msg
+	return (&ContainerValue{ProtoValue: &masked}).ValueContext(context.Background())
 }
 
+var (
+	_ driver.Valuer = (*ContainerValue)(nil)
+	_ sql.Scanner   = (*ContainerValue)(nil)
+)
+
 // Unwrap returns the underlying protobuf message.
 func (x *ContainerValue) Unwrap() *Container {
 	if x.ProtoValue == nil || x.ProtoValue.Message == nil {
@@ -143,7 +864,263 @@ func (x *ContainerValue) Unwrap() *Container {
 	return x.ProtoValue.Message
 }
 
+// ProtoMessage returns the underlying message as a proto.Message, for code
+// that handles wrappers of different types alike. It returns a nil interface,
+// not a typed nil, for a nil wrapper or one holding no message.
+func (x *ContainerValue) ProtoMessage() proto.Message {
+	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return nil
+	}
+	return x.ProtoValue.Message
+}
+
+// GetOrInit returns the underlying protobuf message, first storing an empty
+// one in the wrapper if there is none, so that it never returns nil.
+func (x *ContainerValue) GetOrInit() *Container {
+	if x.ProtoValue == nil {
+		x.ProtoValue = &ProtoValue[*Container]{}
+	}
+	if x.ProtoValue.Message == nil {
+		x.ProtoValue.Message = &Container{}
+	}
+	return x.ProtoValue.Message
+}
+
+// Merge merges src into the message as proto.Merge does, first storing an
+// empty message in the wrapper if there is none: populated scalar fields of
+// src overwrite those of the message, repeated fields are appended to and map
+// entries added. A nil src leaves the wrapper unchanged.
+func (x *ContainerValue) Merge(src *Container) {
+	if src == nil {
+		return
+	}
+	proto.Merge(x.GetOrInit(), src)
+}
+
+// Reset drops the message, leaving the wrapper like its zero value, so that
+// it can be reused. The message itself is not modified, since callers may
+// still hold it; use proto.Reset to clear a message in place.
+func (x *ContainerValue) Reset() {
+	x.ProtoValue = nil
+}
+
+// Size returns the length of the message in the protobuf wire format, or 0
+// if there is none. Value stores that many bytes unless it compresses,
+// encrypts or stores the message as NULL.
+func (x *ContainerValue) Size() int {
+	return proto.Size(x.Unwrap())
+}
+
+// Clone returns a wrapper around a deep copy of the message, or nil for a
+// nil wrapper.
+func (x *ContainerValue) Clone() *ContainerValue {
+	if x == nil || x.ProtoValue == nil {
+		return nil
+	}
+	msg, _ := proto.Clone(x.ProtoValue.Message).(*Container)
+	return &ContainerValue{ProtoValue: &ProtoValue[*Container]{Message: msg}}
+}
+
+// Equal reports whether x and other wrap equal messages. Nil wrappers are
+// equal to each other but not to a wrapper holding a message.
+func (x *ContainerValue) Equal(other *ContainerValue) bool {
+	var a, b *Container
+	if x != nil {
+		a = x.Unwrap()
+	}
+	if other != nil {
+		b = other.Unwrap()
+	}
+	return proto.Equal(a, b)
+}
+
+// String returns the message in prototext form, or "<nil>" if there is none
+// or the wrapper is nil.
+func (x *ContainerValue) String() string {
+	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return "<nil>"
+	}
+	return x.ProtoValue.Message.String()
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler with the same encoding as
+// Value. A nil message encodes as an empty one.
+func (x *ContainerValue) MarshalBinary() ([]byte, error) {
+	msg := x.Unwrap()
+	if msg == nil {
+		msg = &Container{}
+	}
+	return ContainerCodec().Encode(msg)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler with the same decoding
+// as Scan.
+func (x *ContainerValue) UnmarshalBinary(data []byte) error {
+	x.ProtoValue = &ProtoValue[*Container]{Message: &Container{}}
+	return ContainerCodec().Decode(data, x.ProtoValue.Message)
+}
+
+// WriteTo implements io.WriterTo, writing the encoding of MarshalBinary to w.
+func (x *ContainerValue) WriteTo(w io.Writer) (int64, error) {
+	data, err := x.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// ReadFrom implements io.ReaderFrom, reading r to EOF and decoding the bytes
+// like UnmarshalBinary.
+func (x *ContainerValue) ReadFrom(r io.Reader) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return int64(len(data)), err
+	}
+	return int64(len(data)), x.UnmarshalBinary(data)
+}
+
+var (
+	_ encoding.BinaryMarshaler   = (*ContainerValue)(nil)
+	_ encoding.BinaryUnmarshaler = (*ContainerValue)(nil)
+	_ io.WriterTo                = (*ContainerValue)(nil)
+	_ io.ReaderFrom              = (*ContainerValue)(nil)
+)
+
+// GobEncode implements gob.GobEncoder with the same encoding as MarshalBinary.
+// A nil wrapper or message encodes as no bytes.
+func (x ContainerValue) GobEncode() ([]byte, error) {
+	if x.Unwrap() == nil {
+		return []byte{}, nil
+	}
+	return x.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder with the same decoding as
+// UnmarshalBinary. No bytes decode as a nil message.
+func (x *ContainerValue) GobDecode(data []byte) error {
+	if len(data) == 0 {
+		x.ProtoValue = nil
+		return nil
+	}
+	return x.UnmarshalBinary(data)
+}
+
+var (
+	_ gob.GobEncoder = (*ContainerValue)(nil)
+	_ gob.GobDecoder = (*ContainerValue)(nil)
+)
+
+// MarshalJSON implements json.Marshaler using protojson. A nil message
+// encodes as null.
+func (x ContainerValue) MarshalJSON() ([]byte, error) {
+	if x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return []byte("null"), nil
+	}
+	return dbtypesMarshalJSON(x.ProtoValue.Message)
+}
+
+// UnmarshalJSON implements json.Unmarshaler using protojson.
+func (x *ContainerValue) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		x.ProtoValue = nil
+		return nil
+	}
+	msg := &Container{}
+	if err := dbtypesUnmarshalJSON(data, msg); err != nil {
+		return err
+	}
+	x.ProtoValue = &ProtoValue[*Container]{Message: msg}
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler using prototext. A nil
+// wrapper or message encodes as empty text.
+func (x *ContainerValue) MarshalText() ([]byte, error) {
+	if x == nil || x.ProtoValue == nil || x.ProtoValue.Message == nil {
+		return []byte{}, nil
+	}
+	// Appending keeps the result non-nil for an empty message
+	return prototext.MarshalOptions{}.MarshalAppend([]byte{}, x.ProtoValue.Message)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using prototext. Empty
+// text decodes as an empty message.
+func (x *ContainerValue) UnmarshalText(data []byte) error {
+	msg := &Container{}
+	if err := prototext.Unmarshal(data, msg); err != nil {
+		return err
+	}
+	x.ProtoValue = &ProtoValue[*Container]{Message: msg}
+	return nil
+}
+
+var (
+	_ encoding.TextMarshaler   = (*ContainerValue)(nil)
+	_ encoding.TextUnmarshaler = (*ContainerValue)(nil)
+)
+
+// EncodeContainerBatch encodes msgs as ContainerValue.Value would, for
+// bulk inserts such as COPY. Nil messages give nil entries.
+func EncodeContainerBatch(msgs []*Container) ([][]byte, error) {
+	return dbtypesMarshalBatch(msgs, func(msg *Container) (driver.Value, error) {
+		return NewContainerValue(msg).Value()
+	})
+}
+
+// ContainerValues returns what ContainerValue.Value returns for each message
+// of msgs, for passing many messages as arguments to ExecContext and the
+// like. Nil messages give nil entries, which are stored as NULL.
+func ContainerValues(msgs []*Container) ([]driver.Value, error) {
+	return dbtypesValues(msgs, func(msg *Container) (driver.Value, error) {
+		return NewContainerValue(msg).Value()
+	})
+}
+
 // DatabaseValue returns a database-compatible wrapper for this message.
 func (x *Container) DatabaseValue() *ContainerValue {
 	return NewContainerValue(x)
 }
+
+// NullContainerValue represents a *Container that may be NULL.
+type NullContainerValue struct {
+	ContainerValue ContainerValue
+	Valid          bool // Valid is true if ContainerValue is not NULL
+}
+
+// NewNullContainerValue creates a new NullContainerValue that is valid if msg is non-nil.
+func NewNullContainerValue(msg *Container) NullContainerValue {
+	if msg == nil {
+		return NullContainerValue{}
+	}
+	return NullContainerValue{ContainerValue: *NewContainerValue(msg), Valid: true}
+}
+
+// Scan implements sql.Scanner.
+func (n *NullContainerValue) Scan(src any) error {
+	src = dbtypesScanSource(src)
+	if src == nil {
+		n.ContainerValue, n.Valid = ContainerValue{}, false
+		return nil
+	}
+	err := n.ContainerValue.Scan(src)
+	n.Valid = err == nil
+	return err
+}
+
+// Value implements driver.Valuer.
+func (n NullContainerValue) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	msg := n.ContainerValue.Unwrap()
+	if msg == nil {
+		msg = &Container{}
+	}
+	return NewContainerValue(msg).Value()
+}
+
+var (
+	_ driver.Valuer = (*NullContainerValue)(nil)
+	_ sql.Scanner   = (*NullContainerValue)(nil)
+)
@@ -1,9 +1,26 @@
 package testv1
 
 import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
+	"testing/iotest"
 
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 func TestToolSetSpecValue_RoundTrip(t *testing.T) {
@@ -36,14 +53,35 @@ func TestToolSetSpecValue_RoundTrip(t *testing.T) {
 }
 
 func TestToolSetSpecValue_NilMessage(t *testing.T) {
-	// Test with nil message
-	wrapper := NewToolSetSpecValue(nil)
+	// A typed nil is stored as NULL, like a nil wrapper
+	wrapper := NewToolSetSpecValue((*ToolSetSpec)(nil))
 
-	// Should still be able to get value
 	val, err := wrapper.Value()
 	if err != nil {
 		t.Fatalf("Value() error: %v", err)
 	}
+	if val != nil {
+		t.Errorf("Value() = %v for a nil message, want NULL", val)
+	}
+
+	// The wrapper can still be scanned into
+	src, err := NewToolSetSpecValue(&ToolSetSpec{Name: "scanned"}).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if err := wrapper.Scan(src); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if got := wrapper.Unwrap().GetName(); got != "scanned" {
+		t.Errorf("Unwrap().Name = %q, want %q", got, "scanned")
+	}
+}
+
+func TestToolSetSpecValue_EmptyMessage(t *testing.T) {
+	val, err := NewToolSetSpecValue(&ToolSetSpec{}).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
 
 	// Empty proto should produce some bytes
 	if val == nil {
@@ -74,6 +112,73 @@ func TestToolSetSpecValue_ScanNil(t *testing.T) {
 	}
 }
 
+func TestToolSetSpecValue_ScanRepeated(t *testing.T) {
+	var rows []driver.Value
+	for _, spec := range []*ToolSetSpec{
+		{ToolIds: []string{"tool-1", "tool-2"}, Name: "first", Enabled: true},
+		{Name: "second"},
+	} {
+		val, err := NewToolSetSpecValue(spec).Value()
+		if err != nil {
+			t.Fatalf("Value() error: %v", err)
+		}
+		rows = append(rows, val)
+	}
+
+	var wrapper ToolSetSpecValue
+	if err := wrapper.Scan(rows[0]); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	msg := wrapper.Unwrap()
+
+	// The second row reuses the message and leaves no field of the first
+	if err := wrapper.Scan(rows[1]); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if wrapper.Unwrap() != msg {
+		t.Error("Scan() allocated a new message instead of reusing the wrapper's")
+	}
+	if want := (&ToolSetSpec{Name: "second"}); !proto.Equal(want, wrapper.Unwrap()) {
+		t.Errorf("second Scan() = %v, want %v", wrapper.Unwrap(), want)
+	}
+
+	// NULL drops what the previous row left
+	if err := wrapper.Scan(nil); err != nil {
+		t.Fatalf("Scan(nil) error: %v", err)
+	}
+	if wrapper.Unwrap() != nil {
+		t.Errorf("Scan(nil) = %v, want no message", wrapper.Unwrap())
+	}
+	if msg.GetName() != "second" {
+		t.Errorf("Scan(nil) changed the message of the previous row to %v", msg)
+	}
+
+	// and the next row gets a message of its own
+	if err := wrapper.Scan(rows[0]); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if wrapper.Unwrap() == msg || wrapper.Unwrap().GetName() != "first" {
+		t.Errorf("Scan() after NULL = %v, want a new message holding the first row", wrapper.Unwrap())
+	}
+}
+
+func TestToolSetSpecValue_ScanNullClearsMessage(t *testing.T) {
+	val, err := NewToolSetSpecValue(&ToolSetSpec{Name: "stale"}).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	var wrapper ToolSetSpecValue
+	if err := wrapper.Scan(val); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if err := wrapper.Scan(nil); err != nil {
+		t.Fatalf("Scan(nil) error: %v", err)
+	}
+	if got := wrapper.Unwrap(); got != nil {
+		t.Errorf("Unwrap() = %v after scanning NULL, want nil", got)
+	}
+}
+
 func TestToolSetSpecValue_ScanString(t *testing.T) {
 	spec := &ToolSetSpec{
 		ToolIds: []string{"tool-1"},
@@ -81,15 +186,15 @@ func TestToolSetSpecValue_ScanString(t *testing.T) {
 		Enabled: true,
 	}
 
-	// Marshal to bytes
-	data, err := proto.Marshal(spec)
+	// Serialize in the generated storage format
+	data, err := NewToolSetSpecValue(spec).Value()
 	if err != nil {
-		t.Fatalf("proto.Marshal error: %v", err)
+		t.Fatalf("Value() error: %v", err)
 	}
 
 	// Scan from string (some databases return strings)
 	wrapper := &ToolSetSpecValue{}
-	if err := wrapper.Scan(string(data)); err != nil {
+	if err := wrapper.Scan(string(data.([]byte))); err != nil {
 		t.Fatalf("Scan(string) error: %v", err)
 	}
 
@@ -98,12 +203,182 @@ func TestToolSetSpecValue_ScanString(t *testing.T) {
 	}
 }
 
+func TestToolSetSpecValue_ScanSources(t *testing.T) {
+	spec := &ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "sources"}
+
+	dbVal, err := NewToolSetSpecValue(spec).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	data := dbVal.([]byte)
+	str := string(data)
+
+	for name, src := range map[string]any{
+		"[]byte":       append([]byte(nil), data...),
+		"sql.RawBytes": sql.RawBytes(append([]byte(nil), data...)),
+		"string":       string(data),
+		"io.Reader":    bytes.NewReader(data),
+		"*[]byte":      &data,
+		"*string":      &str,
+	} {
+		var got ToolSetSpecValue
+		if err := got.Scan(src); err != nil {
+			t.Errorf("Scan(%s) error: %v", name, err)
+			continue
+		}
+		if !proto.Equal(spec, got.Unwrap()) {
+			t.Errorf("Scan(%s) = %v, want %v", name, got.Unwrap(), spec)
+		}
+	}
+}
+
+func TestToolSetSpecValue_ScanNilPointers(t *testing.T) {
+	for name, src := range map[string]any{
+		"*[]byte": (*[]byte)(nil),
+		"*string": (*string)(nil),
+	} {
+		got := NewToolSetSpecValue(&ToolSetSpec{})
+		if err := got.Scan(src); err != nil || got.Unwrap() != nil {
+			t.Errorf("Scan(nil %s) = %v, %v; want no message", name, got.Unwrap(), err)
+		}
+
+		var null NullToolSetSpecValue
+		if err := null.Scan(src); err != nil {
+			t.Errorf("NullToolSetSpecValue.Scan(nil %s) error: %v", name, err)
+		}
+		if null.Valid {
+			t.Errorf("NullToolSetSpecValue.Scan(nil %s) is valid, want NULL", name)
+		}
+	}
+
+	// Pointers to other types are still rejected
+	n := 42
+	var got ToolSetSpecValue
+	if err := got.Scan(&n); !errors.Is(err, ErrInvalidScanType) {
+		t.Errorf("Scan(*int) error = %v, want ErrInvalidScanType", err)
+	}
+}
+
+func TestToolSetSpecValue_ScanRawBytesCopied(t *testing.T) {
+	spec := &ToolSetSpec{Name: "raw"}
+
+	dbVal, err := NewToolSetSpecValue(spec).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	raw := sql.RawBytes(dbVal.([]byte))
+
+	var got ToolSetSpecValue
+	if err := got.Scan(raw); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	for i := range raw {
+		raw[i] = 'x'
+	}
+	if !proto.Equal(spec, got.Unwrap()) {
+		t.Errorf("scanned message changed when the RawBytes were reused:\ngot:  %v\nwant: %v", got.Unwrap(), spec)
+	}
+}
+
+func TestToolSetSpecValue_ScanReaderError(t *testing.T) {
+	errRead := errors.New("connection reset")
+
+	err := (&ToolSetSpecValue{}).Scan(iotest.ErrReader(errRead))
+	if !errors.Is(err, errRead) {
+		t.Errorf("Scan(failing reader) error = %v, want %v", err, errRead)
+	}
+}
+
+func TestToolSetSpecValue_ContextCancelled(t *testing.T) {
+	spec := &ToolSetSpec{Name: "cancelled"}
+	data, err := NewToolSetSpecValue(spec).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if v, err := NewToolSetSpecValue(spec).ValueContext(ctx); !errors.Is(err, context.Canceled) || v != nil {
+		t.Errorf("ValueContext(cancelled) = %v, %v; want nil, %v", v, err, context.Canceled)
+	}
+	var got ToolSetSpecValue
+	if err := got.ScanContext(ctx, data); !errors.Is(err, context.Canceled) {
+		t.Errorf("ScanContext(cancelled) error = %v, want %v", err, context.Canceled)
+	}
+	if got.Unwrap().GetName() != "" {
+		t.Errorf("ScanContext(cancelled) decoded %v", got.Unwrap())
+	}
+
+	if err := got.ScanContext(context.Background(), data); err != nil {
+		t.Fatalf("ScanContext() error: %v", err)
+	}
+	if !proto.Equal(spec, got.Unwrap()) {
+		t.Errorf("ScanContext() = %v, want %v", got.Unwrap(), spec)
+	}
+}
+
 func TestToolSetSpecValue_ScanInvalidType(t *testing.T) {
 	wrapper := &ToolSetSpecValue{}
 
 	err := wrapper.Scan(123) // invalid type
+	if !errors.Is(err, ErrInvalidScanType) {
+		t.Errorf("Scan(int) error = %v, want ErrInvalidScanType", err)
+	}
+	if want := "dbtypes: ToolSetSpec: unsupported scan type: int"; err == nil || err.Error() != want {
+		t.Errorf("Scan(int) error = %v, want %q", err, want)
+	}
+}
+
+func TestToolSetSpecValue_ScanErrorTypes(t *testing.T) {
+	err := (&ToolSetSpecValue{}).Scan(123)
+	var typeErr *ScanTypeError
+	if !errors.As(err, &typeErr) {
+		t.Fatalf("Scan(int) error = %v, want a *ScanTypeError", err)
+	}
+	if typeErr.Message != "test.v1.ToolSetSpec" || typeErr.SourceType != reflect.TypeOf(123) {
+		t.Errorf("ScanTypeError = %+v, want message test.v1.ToolSetSpec and source type int", typeErr)
+	}
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) {
+		t.Errorf("Scan(int) error = %v should not be a *DecodeError", err)
+	}
+
+	err = (&ToolSetSpecValue{}).Scan([]byte{0xff})
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("Scan(corrupt) error = %v, want a *DecodeError", err)
+	}
+	if decodeErr.Message != "test.v1.ToolSetSpec" || decodeErr.Err == nil {
+		t.Errorf("DecodeError = %+v, want message test.v1.ToolSetSpec and the decoder's error", decodeErr)
+	}
+	if errors.As(err, &typeErr) {
+		t.Errorf("Scan(corrupt) error = %v should not be a *ScanTypeError", err)
+	}
+
+	// UnmarshalBinary shares the decoding
+	if err := (&ToolSetSpecValue{}).UnmarshalBinary([]byte{0xff}); !errors.As(err, &decodeErr) {
+		t.Errorf("UnmarshalBinary(corrupt) error = %v, want a *DecodeError", err)
+	}
+}
+
+func TestToolSetSpecValue_ValueErrorNamesType(t *testing.T) {
+	// proto3 strings must be valid UTF-8, so no format can encode this.
+	_, err := NewToolSetSpecValue(&ToolSetSpec{Name: "\xff"}).Value()
+	if err == nil || !strings.HasPrefix(err.Error(), "dbtypes: ToolSetSpec: ") {
+		t.Errorf("Value() error = %v, want the dbtypes prefix and message name", err)
+	}
+}
+
+func TestToolSetSpecValue_ScanCorruptNamesType(t *testing.T) {
+	err := (&ToolSetSpecValue{}).Scan([]byte{0xff})
 	if err == nil {
-		t.Error("Scan(int) should return error")
+		t.Fatal("Scan(corrupt) should return error")
+	}
+	if !strings.HasPrefix(err.Error(), "dbtypes: ToolSetSpec: ") {
+		t.Errorf("Scan(corrupt) error = %q, want the dbtypes prefix and message name", err)
+	}
+	if errors.Is(err, ErrInvalidScanType) {
+		t.Errorf("Scan(corrupt) error = %v should not be ErrInvalidScanType", err)
 	}
 }
 
@@ -129,6 +404,41 @@ func TestToolSetSpecValue_UnwrapNil(t *testing.T) {
 	}
 }
 
+func TestProtoMessage(t *testing.T) {
+	spec := &ToolSetSpec{Name: "test"}
+	prefs := &UserPreferences{Theme: "dark"}
+
+	wrappers := []interface {
+		driver.Valuer
+		ProtoMessage() proto.Message
+	}{
+		NewToolSetSpecValue(spec),
+		NewUserPreferencesValue(prefs),
+		NewJSONDocumentValue(&JSONDocument{Id: "doc"}),
+	}
+	var names []protoreflect.FullName
+	for _, w := range wrappers {
+		names = append(names, w.ProtoMessage().ProtoReflect().Descriptor().FullName())
+	}
+	if want := []protoreflect.FullName{"test.v1.ToolSetSpec", "test.v1.UserPreferences", "test.v1.JSONDocument"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("ProtoMessage() types = %v, want %v", names, want)
+	}
+	if wrappers[0].ProtoMessage() != spec {
+		t.Error("ProtoMessage() should return the wrapped message")
+	}
+}
+
+func TestProtoMessage_Nil(t *testing.T) {
+	for name, w := range map[string]interface{ ProtoMessage() proto.Message }{
+		"nil wrapper":  (*ToolSetSpecValue)(nil),
+		"zero wrapper": &ToolSetSpecValue{},
+	} {
+		if msg := w.ProtoMessage(); msg != nil {
+			t.Errorf("%s: ProtoMessage() = %#v, want a nil interface", name, msg)
+		}
+	}
+}
+
 func TestUserPreferencesValue_RoundTrip(t *testing.T) {
 	prefs := &UserPreferences{
 		Theme:    "dark",
@@ -265,3 +575,1172 @@ func TestDatabaseValue(t *testing.T) {
 		t.Error("DatabaseValue().Unwrap() should return the original message")
 	}
 }
+
+// Tests for types from format.proto to verify the (dbtypes.format) option
+
+func TestJSONDocumentValue_StoredAsJSON(t *testing.T) {
+	doc := &JSONDocument{
+		Id:     "doc-1",
+		Labels: map[string]string{"env": "prod"},
+	}
+
+	dbVal, err := NewJSONDocumentValue(doc).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+
+	// The format option forces protojson regardless of the plugin flag
+	data, ok := dbVal.([]byte)
+	if !ok || !json.Valid(data) {
+		t.Fatalf("Value() = %q, want protojson", dbVal)
+	}
+
+	wrapper := &JSONDocumentValue{}
+	if err := wrapper.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+
+	if !proto.Equal(doc, wrapper.Unwrap()) {
+		t.Errorf("round-trip failed:\ngot:  %v\nwant: %v", wrapper.Unwrap(), doc)
+	}
+}
+
+func TestJSONDocumentValue_ScanSources(t *testing.T) {
+	doc := &JSONDocument{Id: "doc-1", Labels: map[string]string{"env": "prod"}}
+	data := `{"id": "doc-1", "labels": {"env": "prod"}}`
+
+	// ORMs may hand JSON columns back already decoded
+	for name, src := range map[string]any{
+		"[]byte":          []byte(data),
+		"string":          data,
+		"json.RawMessage": json.RawMessage(data),
+		"map[string]any":  map[string]any{"id": "doc-1", "labels": map[string]any{"env": "prod"}},
+	} {
+		wrapper := &JSONDocumentValue{}
+		if err := wrapper.Scan(src); err != nil {
+			t.Errorf("Scan(%s) error: %v", name, err)
+			continue
+		}
+		if !proto.Equal(doc, wrapper.Unwrap()) {
+			t.Errorf("Scan(%s) = %v, want %v", name, wrapper.Unwrap(), doc)
+		}
+	}
+
+	err := (&JSONDocumentValue{}).Scan([]any{"doc-1"})
+	if !errors.Is(err, ErrInvalidScanType) {
+		t.Errorf("Scan([]any) error = %v, want ErrInvalidScanType", err)
+	}
+	if want := "dbtypes: JSONDocument: unsupported scan type: []interface {}"; err == nil || err.Error() != want {
+		t.Errorf("Scan([]any) error = %v, want %q", err, want)
+	}
+}
+
+func TestTextDocumentValue_StoredAsText(t *testing.T) {
+	doc := &TextDocument{
+		Id:     "doc-1",
+		Tags:   []string{"config", "readable"},
+		Labels: map[string]string{"env": "prod", "team": "dba"},
+	}
+
+	dbVal, err := NewTextDocumentValue(doc).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+
+	// The format option forces prototext regardless of the plugin flag
+	data, ok := dbVal.([]byte)
+	if !ok || !bytes.Contains(data, []byte(`id:`)) {
+		t.Fatalf("Value() = %q, want prototext", dbVal)
+	}
+
+	wrapper := &TextDocumentValue{}
+	if err := wrapper.Scan(string(data)); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+
+	if !proto.Equal(doc, wrapper.Unwrap()) {
+		t.Errorf("round-trip failed:\ngot:  %v\nwant: %v", wrapper.Unwrap(), doc)
+	}
+}
+
+func TestBinaryDocumentValue_RoundTrip(t *testing.T) {
+	doc := &BinaryDocument{
+		Id:      "doc-2",
+		Payload: []byte{0x00, 0x01, 0xff},
+	}
+
+	dbVal, err := NewBinaryDocumentValue(doc).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+
+	wrapper := &BinaryDocumentValue{}
+	if err := wrapper.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+
+	if !proto.Equal(doc, wrapper.Unwrap()) {
+		t.Errorf("round-trip failed:\ngot:  %v\nwant: %v", wrapper.Unwrap(), doc)
+	}
+}
+
+func TestEnvelopeValue_AnyRoundTrip(t *testing.T) {
+	payload, err := anypb.New(&ToolSetSpec{Name: "embedded", ToolIds: []string{"a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := &Envelope{Id: "env-1", Payload: payload}
+
+	dbVal, err := NewEnvelopeValue(env).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	var stored struct {
+		Payload struct {
+			Type string `json:"@type"`
+		} `json:"payload"`
+	}
+	data, _ := dbVal.([]byte)
+	if err := json.Unmarshal(data, &stored); err != nil {
+		t.Fatalf("Value() = %q, want protojson: %v", dbVal, err)
+	}
+	if stored.Payload.Type != payload.GetTypeUrl() {
+		t.Errorf("stored @type = %q, want %q", stored.Payload.Type, payload.GetTypeUrl())
+	}
+
+	wrapper := &EnvelopeValue{}
+	if err := wrapper.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	got := wrapper.Unwrap().GetPayload()
+	if got.GetTypeUrl() != payload.GetTypeUrl() {
+		t.Errorf("type URL = %q, want %q", got.GetTypeUrl(), payload.GetTypeUrl())
+	}
+	spec := &ToolSetSpec{}
+	if err := got.UnmarshalTo(spec); err != nil {
+		t.Fatalf("UnmarshalTo() error: %v", err)
+	}
+	if spec.GetName() != "embedded" || len(spec.GetToolIds()) != 1 {
+		t.Errorf("payload = %v, want the embedded ToolSetSpec", spec)
+	}
+}
+
+// Tests for oneof.proto to verify that the storage format keeps the selected
+// oneof case
+
+func TestPayloadValue_OneofRoundTrip(t *testing.T) {
+	for _, content := range []isPayload_Content{
+		&Payload_Text{Text: "hello"},
+		&Payload_Text{},
+		&Payload_Blob{Blob: []byte{0x00, 0xff}},
+		&Payload_Blob{Blob: []byte{}},
+		&Payload_Count{Count: -42},
+		&Payload_Count{},
+		&Payload_Flag{Flag: true},
+		&Payload_Flag{},
+		&Payload_Spec{Spec: &ToolSetSpec{Name: "nested"}},
+		&Payload_Spec{Spec: &ToolSetSpec{}},
+	} {
+		payload := &Payload{Id: "p1", Content: content}
+
+		dbVal, err := NewPayloadValue(payload).Value()
+		if err != nil {
+			t.Fatalf("Value(%T) error: %v", content, err)
+		}
+		wrapper := &PayloadValue{}
+		if err := wrapper.Scan(dbVal); err != nil {
+			t.Fatalf("Scan(%T) error: %v", content, err)
+		}
+
+		got := wrapper.Unwrap().GetContent()
+		if fmt.Sprintf("%T", got) != fmt.Sprintf("%T", content) {
+			t.Errorf("oneof case = %T, want %T", got, content)
+		}
+		if !proto.Equal(payload, wrapper.Unwrap()) {
+			t.Errorf("round-trip failed:\ngot:  %v\nwant: %v", wrapper.Unwrap(), payload)
+		}
+	}
+}
+
+// Tests for legacy.proto
+
+func TestLegacyRecordValue_Proto2RoundTrip(t *testing.T) {
+	for name, record := range map[string]*LegacyRecord{
+		"required only": {Id: proto.String("r1")},
+		// Fields set to their zero value or default stay set
+		"explicit defaults": {
+			Id:       proto.String("r2"),
+			Name:     proto.String(""),
+			Priority: proto.Int32(5),
+			Archived: proto.Bool(false),
+			State:    LegacyState_LEGACY_STATE_ACTIVE.Enum(),
+		},
+		"populated": {
+			Id:       proto.String("r3"),
+			Name:     proto.String("legacy"),
+			Priority: proto.Int32(-1),
+			Archived: proto.Bool(true),
+			Scores:   []int32{3, 1, 2},
+			State:    LegacyState_LEGACY_STATE_RETIRED.Enum(),
+			Labels:   map[string]string{"env": "prod"},
+			Detail:   &LegacyRecord_Detail{Note: proto.String("")},
+		},
+	} {
+		dbVal, err := NewLegacyRecordValue(record).Value()
+		if err != nil {
+			t.Fatalf("%s: Value() error: %v", name, err)
+		}
+		var got LegacyRecordValue
+		if err := got.Scan(dbVal); err != nil {
+			t.Fatalf("%s: Scan() error: %v", name, err)
+		}
+		if !proto.Equal(record, got.Unwrap()) {
+			t.Errorf("%s: round-trip failed:\ngot:  %v\nwant: %v", name, got.Unwrap(), record)
+		}
+	}
+
+	// Unset fields still read as their defaults
+	var got LegacyRecordValue
+	dbVal, err := NewLegacyRecordValue(&LegacyRecord{Id: proto.String("r4")}).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := got.Scan(dbVal); err != nil {
+		t.Fatal(err)
+	}
+	if msg := got.Unwrap(); msg.Priority != nil || msg.GetPriority() != 5 || msg.GetState() != LegacyState_LEGACY_STATE_ACTIVE {
+		t.Errorf("Scan() = %v, want unset fields with their defaults", msg)
+	}
+}
+
+func TestLegacyRecordValue_RequiredField(t *testing.T) {
+	if _, err := NewLegacyRecordValue(&LegacyRecord{Name: proto.String("no id")}).Value(); err == nil {
+		t.Error("Value() accepted a message without its required field")
+	}
+}
+
+func TestNullUserPreferencesValue_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  *UserPreferences
+	}{
+		{"null", nil},
+		{"empty", &UserPreferences{}},
+		{"populated", &UserPreferences{Theme: "dark", Settings: map[string]string{"autoSave": "true"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbVal, err := NewNullUserPreferencesValue(tt.msg).Value()
+			if err != nil {
+				t.Fatalf("Value() error: %v", err)
+			}
+			if (dbVal == nil) != (tt.msg == nil) {
+				t.Fatalf("Value() = %v, want NULL only for a nil message", dbVal)
+			}
+
+			var p NullUserPreferencesValue
+			if err := p.Scan(dbVal); err != nil {
+				t.Fatalf("Scan() error: %v", err)
+			}
+			if p.Valid != (tt.msg != nil) {
+				t.Errorf("Valid = %v, want %v", p.Valid, tt.msg != nil)
+			}
+			if tt.msg != nil && !proto.Equal(tt.msg, p.UserPreferencesValue.Unwrap()) {
+				t.Errorf("round-trip failed:\ngot:  %v\nwant: %v", p.UserPreferencesValue.Unwrap(), tt.msg)
+			}
+		})
+	}
+}
+
+func TestNullUserPreferencesValue_ScanNilResets(t *testing.T) {
+	p := NewNullUserPreferencesValue(&UserPreferences{Theme: "dark"})
+
+	if err := p.Scan(nil); err != nil {
+		t.Fatalf("Scan(nil) error: %v", err)
+	}
+	if p.Valid {
+		t.Error("Valid = true after scanning NULL")
+	}
+	if p.UserPreferencesValue.Unwrap() != nil {
+		t.Errorf("Unwrap() = %v after scanning NULL, want nil", p.UserPreferencesValue.Unwrap())
+	}
+}
+
+func TestNullUserPreferencesValue_ScanInvalidType(t *testing.T) {
+	var p NullUserPreferencesValue
+	if err := p.Scan(42); err == nil {
+		t.Error("Scan(42) should fail")
+	}
+	if p.Valid {
+		t.Error("Valid = true after a failed scan")
+	}
+}
+
+func TestValue_ReturnsBytes(t *testing.T) {
+	// Every format stores []byte unless value-as-string is set
+	for name, value := range map[string]driver.Valuer{
+		"binary": NewBinaryDocumentValue(&BinaryDocument{Id: "doc"}),
+		"json":   NewJSONDocumentValue(&JSONDocument{Id: "doc"}),
+		"text":   NewTextDocumentValue(&TextDocument{Id: "doc"}),
+	} {
+		v, err := value.Value()
+		if err != nil {
+			t.Fatalf("%s: Value() error: %v", name, err)
+		}
+		if _, ok := v.([]byte); !ok {
+			t.Errorf("%s: Value() returned %T, want []byte", name, v)
+		}
+	}
+}
+
+func TestToolSetSpecValue_GetOrInit(t *testing.T) {
+	var x ToolSetSpecValue
+	msg := x.GetOrInit()
+	if msg == nil {
+		t.Fatal("GetOrInit() = nil, want an empty message")
+	}
+	if !proto.Equal(msg, &ToolSetSpec{}) {
+		t.Errorf("GetOrInit() = %v, want an empty message", msg)
+	}
+
+	// The message is stored in the wrapper
+	msg.Name = "initialized"
+	if x.Unwrap() != msg || x.GetOrInit() != msg {
+		t.Error("GetOrInit() did not store the message it returned")
+	}
+
+	// An existing message is returned as-is
+	spec := &ToolSetSpec{Name: "existing"}
+	if got := NewToolSetSpecValue(spec).GetOrInit(); got != spec {
+		t.Errorf("GetOrInit() = %v, want the wrapped message", got)
+	}
+
+	// Unwrap keeps reporting a missing message
+	var null NullToolSetSpecValue
+	if null.ToolSetSpecValue.Unwrap() != nil {
+		t.Error("Unwrap() of an invalid NullToolSetSpecValue should be nil")
+	}
+}
+
+func TestToolSetSpecValue_Reset(t *testing.T) {
+	spec := &ToolSetSpec{Name: "pooled", ToolIds: []string{"tool-1"}}
+	x := NewToolSetSpecValue(spec)
+	x.Reset()
+	if x.Unwrap() != nil {
+		t.Errorf("Unwrap() = %v after Reset, want nil", x.Unwrap())
+	}
+	if spec.GetName() != "pooled" {
+		t.Errorf("Reset() modified the message: %v", spec)
+	}
+
+	// A reset wrapper behaves like a fresh one
+	if v, err := x.Value(); v != nil || err != nil {
+		t.Errorf("Value() after Reset = %v, %v; want nil", v, err)
+	}
+	dbVal, err := NewToolSetSpecValue(&ToolSetSpec{Name: "next"}).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := x.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() after Reset error: %v", err)
+	}
+	if x.Unwrap() == spec || x.Unwrap().GetName() != "next" {
+		t.Errorf("Scan() after Reset = %v, want a new message", x.Unwrap())
+	}
+}
+
+func TestToolSetSpecValue_Size(t *testing.T) {
+	spec := &ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "my-toolset", Enabled: true}
+	if got, want := NewToolSetSpecValue(spec).Size(), proto.Size(spec); got != want {
+		t.Errorf("Size() = %d, want proto.Size() = %d", got, want)
+	}
+	if got := new(ToolSetSpecValue).Size(); got != 0 {
+		t.Errorf("Size() of a nil wrapper = %d, want 0", got)
+	}
+}
+
+func TestUserPreferencesValue_MarshalJSON(t *testing.T) {
+	prefs := &UserPreferences{
+		Theme:    "dark",
+		Language: "en",
+		Settings: map[string]string{"autoSave": "true"},
+	}
+
+	got, err := json.Marshal(NewUserPreferencesValue(prefs))
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+	want, err := protojson.Marshal(prefs)
+	if err != nil {
+		t.Fatalf("protojson.Marshal() error: %v", err)
+	}
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, want); err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != compacted.String() {
+		t.Errorf("json.Marshal() = %s, want %s", got, compacted.String())
+	}
+
+	var decoded UserPreferencesValue
+	if err := json.Unmarshal(got, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error: %v", err)
+	}
+	if !proto.Equal(prefs, decoded.Unwrap()) {
+		t.Errorf("JSON round-trip failed:\ngot:  %v\nwant: %v", decoded.Unwrap(), prefs)
+	}
+}
+
+func TestUserPreferencesValue_MarshalJSONNil(t *testing.T) {
+	resp := struct {
+		Prefs *UserPreferencesValue `json:"prefs"`
+		Empty UserPreferencesValue  `json:"empty"`
+	}{Prefs: &UserPreferencesValue{}}
+
+	got, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+	if want := `{"prefs":null,"empty":null}`; string(got) != want {
+		t.Errorf("json.Marshal() = %s, want %s", got, want)
+	}
+
+	decoded := NewUserPreferencesValue(&UserPreferences{Theme: "dark"})
+	if err := json.Unmarshal([]byte("null"), decoded); err != nil {
+		t.Fatalf("json.Unmarshal(null) error: %v", err)
+	}
+	if decoded.Unwrap() != nil {
+		t.Errorf("Unwrap() = %v after decoding null, want nil", decoded.Unwrap())
+	}
+}
+
+func TestUserPreferencesValue_UnmarshalJSONInvalid(t *testing.T) {
+	var decoded UserPreferencesValue
+	if err := json.Unmarshal([]byte(`{"theme": 42}`), &decoded); err == nil {
+		t.Error("json.Unmarshal() should reject a mistyped field")
+	}
+}
+
+func TestToolSetSpecValue_MarshalBinary(t *testing.T) {
+	spec := &ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "my-toolset"}
+
+	data, err := NewToolSetSpecValue(spec).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error: %v", err)
+	}
+
+	// MarshalBinary and Value produce the same bytes.
+	dbVal, err := NewToolSetSpecValue(spec).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if !bytes.Equal(data, dbVal.([]byte)) {
+		t.Errorf("MarshalBinary() = %x, Value() = %x", data, dbVal)
+	}
+
+	var decoded ToolSetSpecValue
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary() error: %v", err)
+	}
+	if !proto.Equal(spec, decoded.Unwrap()) {
+		t.Errorf("binary round-trip failed:\ngot:  %v\nwant: %v", decoded.Unwrap(), spec)
+	}
+}
+
+func TestToolSetSpecValue_MarshalBinaryEmpty(t *testing.T) {
+	for name, wrapper := range map[string]*ToolSetSpecValue{
+		"nil wrapper":   {},
+		"empty message": NewToolSetSpecValue(&ToolSetSpec{}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			data, err := wrapper.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary() error: %v", err)
+			}
+
+			var decoded ToolSetSpecValue
+			if err := decoded.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary() error: %v", err)
+			}
+			if !proto.Equal(&ToolSetSpec{}, decoded.Unwrap()) {
+				t.Errorf("UnmarshalBinary() = %v, want an empty message", decoded.Unwrap())
+			}
+		})
+	}
+}
+
+func TestContainerValue_WriteToReadFrom(t *testing.T) {
+	container := &Container{Id: "big", Spec: &ToolSetSpec{Name: "nested"}}
+	for i := range 20 {
+		container.Items = append(container.Items, &Container_Item{Key: fmt.Sprintf("key-%d", i), Value: "value"})
+	}
+
+	var buf bytes.Buffer
+	n, err := NewContainerValue(container).WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo() error: %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo() = %d, but wrote %d bytes", n, buf.Len())
+	}
+
+	// The stream holds the same encoding as MarshalBinary
+	want, err := NewContainerValue(container).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("WriteTo() wrote %x, MarshalBinary() = %x", buf.Bytes(), want)
+	}
+
+	var decoded ContainerValue
+	n, err = decoded.ReadFrom(&buf)
+	if err != nil {
+		t.Fatalf("ReadFrom() error: %v", err)
+	}
+	if n != int64(len(want)) {
+		t.Errorf("ReadFrom() = %d, want %d", n, len(want))
+	}
+	if !proto.Equal(container, decoded.Unwrap()) {
+		t.Errorf("stream round-trip failed:\ngot:  %v\nwant: %v", decoded.Unwrap(), container)
+	}
+
+	errRead := errors.New("connection reset")
+	if _, err := decoded.ReadFrom(iotest.ErrReader(errRead)); !errors.Is(err, errRead) {
+		t.Errorf("ReadFrom(failing reader) error = %v, want %v", err, errRead)
+	}
+}
+
+func TestUserPreferencesValue_MarshalText(t *testing.T) {
+	prefs := &UserPreferences{
+		Theme:    "dark",
+		Settings: map[string]string{"autoSave": "true"},
+	}
+
+	var _ encoding.TextMarshaler = NewUserPreferencesValue(prefs)
+	data, err := NewUserPreferencesValue(prefs).MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText() error: %v", err)
+	}
+	if !strings.Contains(string(data), `theme:`) || !strings.Contains(string(data), `"dark"`) {
+		t.Errorf("MarshalText() = %s, want prototext", data)
+	}
+
+	var decoded UserPreferencesValue
+	if err := decoded.UnmarshalText(data); err != nil {
+		t.Fatalf("UnmarshalText() error: %v", err)
+	}
+	if !proto.Equal(prefs, decoded.Unwrap()) {
+		t.Errorf("text round-trip failed:\ngot:  %v\nwant: %v", decoded.Unwrap(), prefs)
+	}
+
+	if err := decoded.UnmarshalText([]byte(`theme: 42`)); err == nil {
+		t.Error("UnmarshalText() should reject a mistyped field")
+	}
+}
+
+func TestUserPreferencesValue_MarshalTextEmpty(t *testing.T) {
+	for name, wrapper := range map[string]*UserPreferencesValue{
+		"nil pointer":   nil,
+		"nil wrapper":   {},
+		"empty message": NewUserPreferencesValue(&UserPreferences{}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			data, err := wrapper.MarshalText()
+			if err != nil {
+				t.Fatalf("MarshalText() error: %v", err)
+			}
+			if data == nil || len(data) != 0 {
+				t.Errorf("MarshalText() = %#v, want an empty slice", data)
+			}
+
+			decoded := NewUserPreferencesValue(&UserPreferences{Theme: "stale"})
+			if err := decoded.UnmarshalText(data); err != nil {
+				t.Fatalf("UnmarshalText() error: %v", err)
+			}
+			if !proto.Equal(&UserPreferences{}, decoded.Unwrap()) {
+				t.Errorf("UnmarshalText() = %v, want an empty message", decoded.Unwrap())
+			}
+		})
+	}
+}
+
+func TestToolSetSpecValue_Gob(t *testing.T) {
+	type record struct {
+		ID    string
+		Spec  ToolSetSpecValue
+		Prefs *UserPreferencesValue
+		Empty ToolSetSpecValue
+		Doc   *JSONDocumentValue
+	}
+	in := record{
+		ID:    "rec-1",
+		Spec:  *NewToolSetSpecValue(&ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "gob", Enabled: true}),
+		Prefs: NewUserPreferencesValue(&UserPreferences{Theme: "dark", Settings: map[string]string{"tz": "UTC"}}),
+		Doc:   NewJSONDocumentValue(&JSONDocument{Id: "doc-1"}),
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatalf("gob Encode() error: %v", err)
+	}
+	var out record
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatalf("gob Decode() error: %v", err)
+	}
+
+	if out.ID != in.ID {
+		t.Errorf("ID = %q, want %q", out.ID, in.ID)
+	}
+	if !proto.Equal(out.Spec.Unwrap(), in.Spec.Unwrap()) {
+		t.Errorf("Spec = %v, want %v", out.Spec.Unwrap(), in.Spec.Unwrap())
+	}
+	if out.Prefs == nil || !proto.Equal(out.Prefs.Unwrap(), in.Prefs.Unwrap()) {
+		t.Errorf("Prefs = %v, want %v", out.Prefs, in.Prefs.Unwrap())
+	}
+	if out.Doc == nil || !proto.Equal(out.Doc.Unwrap(), in.Doc.Unwrap()) {
+		t.Errorf("Doc = %v, want %v", out.Doc, in.Doc.Unwrap())
+	}
+	if out.Empty.Unwrap() != nil {
+		t.Errorf("Empty = %v, want a nil message", out.Empty.Unwrap())
+	}
+}
+
+func TestToolSetSpecValue_GobNil(t *testing.T) {
+	data, err := ToolSetSpecValue{}.GobEncode()
+	if err != nil || data == nil || len(data) != 0 {
+		t.Errorf("GobEncode() of a nil wrapper = %#v, %v; want an empty slice", data, err)
+	}
+
+	decoded := NewToolSetSpecValue(&ToolSetSpec{Name: "stale"})
+	if err := decoded.GobDecode(nil); err != nil {
+		t.Fatalf("GobDecode(nil) error: %v", err)
+	}
+	if decoded.Unwrap() != nil {
+		t.Errorf("GobDecode(nil) = %v, want a nil message", decoded.Unwrap())
+	}
+}
+
+func TestBinaryDocumentValue_ScanReusedBuffer(t *testing.T) {
+	doc := &BinaryDocument{Id: "doc-1", Payload: []byte("first payload")}
+
+	dbVal, err := NewBinaryDocumentValue(doc).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	buf := dbVal.([]byte)
+
+	var got BinaryDocumentValue
+	if err := got.Scan(buf); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+
+	// Simulate a driver that reuses the buffer for the next row.
+	for i := range buf {
+		buf[i] = 'x'
+	}
+
+	if !proto.Equal(doc, got.Unwrap()) {
+		t.Errorf("scanned message changed when the driver buffer was reused:\ngot:  %v\nwant: %v", got.Unwrap(), doc)
+	}
+}
+
+func BenchmarkValue(b *testing.B) {
+	wrapper := NewToolSetSpecValue(&ToolSetSpec{
+		ToolIds: []string{"tool-1", "tool-2", "tool-3"},
+		Name:    "my-toolset",
+		Enabled: true,
+	})
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := wrapper.Value(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkScan(b *testing.B) {
+	val, err := NewToolSetSpecValue(&ToolSetSpec{
+		ToolIds: []string{"tool-1", "tool-2", "tool-3"},
+		Name:    "my-toolset",
+		Enabled: true,
+	}).Value()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	// Scanning into one wrapper, as a rows loop would, reuses its message
+	var wrapper ToolSetSpecValue
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := wrapper.Scan(val); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestEncodeToolSetSpecBatch(t *testing.T) {
+	msgs := []*ToolSetSpec{
+		{ToolIds: []string{"tool-1", "tool-2"}, Name: "my-toolset", Enabled: true},
+		nil,
+		{},
+		{Name: "last"},
+	}
+	batch, err := EncodeToolSetSpecBatch(msgs)
+	if err != nil {
+		t.Fatalf("EncodeToolSetSpecBatch() error: %v", err)
+	}
+	if len(batch) != len(msgs) {
+		t.Fatalf("EncodeToolSetSpecBatch() returned %d entries, want %d", len(batch), len(msgs))
+	}
+	for i, msg := range msgs {
+		if msg == nil {
+			if batch[i] != nil {
+				t.Errorf("entry %d = %x, want nil for a nil message", i, batch[i])
+			}
+			continue
+		}
+		dbVal, err := NewToolSetSpecValue(msg).Value()
+		if err != nil {
+			t.Fatalf("Value() error: %v", err)
+		}
+		if !bytes.Equal(batch[i], dbVal.([]byte)) {
+			t.Errorf("entry %d = %x, want %x as returned by Value", i, batch[i], dbVal)
+		}
+	}
+
+	// Entries don't share capacity, so appending to one leaves the next intact
+	want := append([]byte(nil), batch[3]...)
+	_ = append(batch[2], 0xff)
+	if !bytes.Equal(batch[3], want) {
+		t.Errorf("appending to entry 2 changed entry 3 to %x", batch[3])
+	}
+
+	if batch, err := EncodeToolSetSpecBatch(nil); err != nil || len(batch) != 0 {
+		t.Errorf("EncodeToolSetSpecBatch(nil) = %v, %v; want no entries", batch, err)
+	}
+}
+
+func TestToolSetSpecValues(t *testing.T) {
+	msgs := []*ToolSetSpec{
+		{ToolIds: []string{"tool-1"}, Name: "first", Enabled: true},
+		nil,
+		{},
+	}
+	vals, err := ToolSetSpecValues(msgs)
+	if err != nil {
+		t.Fatalf("ToolSetSpecValues() error: %v", err)
+	}
+	if len(vals) != len(msgs) {
+		t.Fatalf("ToolSetSpecValues() returned %d values, want %d", len(vals), len(msgs))
+	}
+	for i, msg := range msgs {
+		want, err := NewToolSetSpecValue(msg).Value()
+		if err != nil {
+			t.Fatalf("Value() error: %v", err)
+		}
+		if !reflect.DeepEqual(vals[i], want) {
+			t.Errorf("value %d = %v, want %v as returned by Value", i, vals[i], want)
+		}
+	}
+	if vals[1] != nil {
+		t.Errorf("value 1 = %v, want nil for a nil message", vals[1])
+	}
+}
+
+func TestEncodeLegacyRecordBatch_ErrorNamesElement(t *testing.T) {
+	_, err := EncodeLegacyRecordBatch([]*LegacyRecord{{Id: proto.String("r")}, {}})
+	if err == nil || !strings.Contains(err.Error(), "batch element 1") || !strings.Contains(err.Error(), "LegacyRecord") {
+		t.Errorf("EncodeLegacyRecordBatch() error = %v, want one naming element 1 and the type", err)
+	}
+}
+
+func BenchmarkEncodeToolSetSpecBatch(b *testing.B) {
+	msgs := make([]*ToolSetSpec, 1000)
+	for i := range msgs {
+		msgs[i] = &ToolSetSpec{
+			ToolIds: []string{"tool-1", "tool-2", "tool-3"},
+			Name:    fmt.Sprintf("toolset-%d", i),
+			Enabled: true,
+		}
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := EncodeToolSetSpecBatch(msgs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestToolSetSpecValue_String(t *testing.T) {
+	spec := &ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "my-toolset", Enabled: true}
+
+	got := fmt.Sprintf("%v", NewToolSetSpecValue(spec))
+	for _, want := range []string{"tool-1", "my-toolset", "enabled"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("%%v = %q, want it to contain %q", got, want)
+		}
+	}
+	if got, want := NewToolSetSpecValue(spec).String(), spec.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestToolSetSpecValue_StringNil(t *testing.T) {
+	var nilPtr *ToolSetSpecValue
+	for name, got := range map[string]string{
+		"nil wrapper": (&ToolSetSpecValue{}).String(),
+		"nil pointer": fmt.Sprintf("%s", nilPtr),
+		"nil String":  nilPtr.String(),
+	} {
+		if got != "<nil>" {
+			t.Errorf("%s: got %q, want <nil>", name, got)
+		}
+	}
+}
+
+func TestToolSetSpecValue_Clone(t *testing.T) {
+	original := NewToolSetSpecValue(&ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "original"})
+
+	clone := original.Clone()
+	clone.Unwrap().Name = "mutated"
+	clone.Unwrap().ToolIds[0] = "tool-2"
+
+	want := &ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "original"}
+	if !proto.Equal(want, original.Unwrap()) {
+		t.Errorf("mutating the clone changed the original: %v", original.Unwrap())
+	}
+}
+
+func TestToolSetSpecValue_CloneNil(t *testing.T) {
+	var nilPtr *ToolSetSpecValue
+	if got := nilPtr.Clone(); got != nil {
+		t.Errorf("Clone() of a nil pointer = %v, want nil", got)
+	}
+	if got := (&ToolSetSpecValue{}).Clone(); got != nil {
+		t.Errorf("Clone() of a nil wrapper = %v, want nil", got)
+	}
+}
+
+func TestToolSetSpecValue_Equal(t *testing.T) {
+	var nilPtr *ToolSetSpecValue
+	spec := &ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "my-toolset"}
+
+	tests := []struct {
+		name string
+		a, b *ToolSetSpecValue
+		want bool
+	}{
+		{"nil/nil", nilPtr, nilPtr, true},
+		{"nil wrapper/nil pointer", &ToolSetSpecValue{}, nilPtr, true},
+		{"nil/non-nil", nilPtr, NewToolSetSpecValue(spec), false},
+		{"non-nil/nil", NewToolSetSpecValue(spec), &ToolSetSpecValue{}, false},
+		{"equal", NewToolSetSpecValue(spec), NewToolSetSpecValue(proto.Clone(spec).(*ToolSetSpec)), true},
+		{"unequal", NewToolSetSpecValue(spec), NewToolSetSpecValue(&ToolSetSpec{Name: "other"}), false},
+		{"empty/nil", NewToolSetSpecValue(&ToolSetSpec{}), nilPtr, false},
+		{"typed nil/nil", NewToolSetSpecValue((*ToolSetSpec)(nil)), nilPtr, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Equal(tt.b); got != tt.want {
+				t.Errorf("Equal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServiceAccountValue_Redact(t *testing.T) {
+	const token = "secret-api-token"
+	acct := &ServiceAccount{Name: "ci", ApiToken: token, RecoveryCodes: []string{"recovery-1"}}
+	want := proto.Clone(acct)
+	stored := &ServiceAccount{Name: "ci"}
+
+	dbVal, err := NewServiceAccountValue(acct).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	var data []byte
+	switch v := dbVal.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		t.Fatalf("Value() = %T, want []byte or string", dbVal)
+	}
+	if bytes.Contains(data, []byte(token)) || bytes.Contains(data, []byte("recovery-1")) {
+		t.Errorf("Value() = %q, want the redacted fields left out", data)
+	}
+	if !proto.Equal(acct, want) {
+		t.Errorf("Value() changed the caller's message to %v, want %v", acct, want)
+	}
+
+	var scanned ServiceAccountValue
+	if err := scanned.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(scanned.Unwrap(), stored) {
+		t.Errorf("Scan() = %v, want %v", scanned.Unwrap(), stored)
+	}
+
+	// The codec and batch encoding redact alike
+	data, err = ServiceAccountCodec().Encode(acct)
+	if err != nil {
+		t.Fatalf("Encode() error: %v", err)
+	}
+	if bytes.Contains(data, []byte(token)) {
+		t.Errorf("Encode() = %q, want the redacted fields left out", data)
+	}
+	batch, err := EncodeServiceAccountBatch([]*ServiceAccount{acct})
+	if err != nil {
+		t.Fatalf("EncodeServiceAccountBatch() error: %v", err)
+	}
+	if bytes.Contains(batch[0], []byte(token)) {
+		t.Errorf("EncodeServiceAccountBatch() = %q, want the redacted fields left out", batch[0])
+	}
+	if !proto.Equal(acct, want) {
+		t.Errorf("encoding changed the caller's message to %v, want %v", acct, want)
+	}
+}
+
+func TestProfileCacheValue_Skip(t *testing.T) {
+	cached := &ProfileCache{UserId: "u1", DisplayName: "Ada", Biography: "long biography", Tags: []string{"admin"}}
+	want := &ProfileCache{UserId: "u1", DisplayName: "Ada", Tags: []string{"admin"}}
+
+	dbVal, err := NewProfileCacheValue(cached).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if strings.Contains(fmt.Sprint(dbVal), "long biography") {
+		t.Errorf("Value() = %q, want the skipped field left out", dbVal)
+	}
+	if cached.Biography == "" {
+		t.Error("Value() cleared the skipped field of the caller's message")
+	}
+
+	// The column holds a projection, so the round trip drops the field
+	var scanned ProfileCacheValue
+	if err := scanned.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(scanned.Unwrap(), want) {
+		t.Errorf("Scan() = %v, want %v", scanned.Unwrap(), want)
+	}
+}
+
+func TestEditionRecordValue_Presence(t *testing.T) {
+	// An edition 2023 message: id has explicit presence, so that setting it
+	// to "" must survive the round trip, while note has implicit presence.
+	original := &EditionRecord{
+		Id:       proto.String(""),
+		Priority: proto.Int32(0),
+		Scores:   []int32{3, 1},
+		State:    EditionState_EDITION_STATE_OPEN.Enum(),
+		Detail:   &EditionRecord_Detail{Text: proto.String("nested")},
+	}
+
+	dbVal, err := NewEditionRecordValue(original).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	var scanned EditionRecordValue
+	if err := scanned.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	got := scanned.Unwrap()
+	if !proto.Equal(got, original) {
+		t.Errorf("round trip = %v, want %v", got, original)
+	}
+	if got.Id == nil || got.Priority == nil {
+		t.Errorf("round trip lost explicitly set zero values: %v", got)
+	}
+	if got.ProtoReflect().Has(got.ProtoReflect().Descriptor().Fields().ByName("note")) {
+		t.Error("implicit-presence note reported as set")
+	}
+}
+
+func TestDBTypeRegistry_RoundTrip(t *testing.T) {
+	newWrapper, ok := DBTypeRegistry["test.v1.ToolSetSpec"]
+	if !ok {
+		t.Fatal("DBTypeRegistry has no entry for test.v1.ToolSetSpec")
+	}
+
+	spec := &ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "registered"}
+	in := newWrapper()
+	proto.Merge(in.(*ToolSetSpecValue).Unwrap(), spec)
+	dbVal, err := in.Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+
+	out := newWrapper()
+	if err := out.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if got := out.(*ToolSetSpecValue).Unwrap(); !proto.Equal(got, spec) {
+		t.Errorf("Scan() = %v, want %v", got, spec)
+	}
+
+	if in == out {
+		t.Error("DBTypeRegistry should return a new wrapper on every call")
+	}
+	for name := range DBTypeRegistry {
+		if _, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(name)); err != nil {
+			t.Errorf("DBTypeRegistry key %q is not a message: %v", name, err)
+		}
+	}
+}
+
+func TestToolSetSpecCodec(t *testing.T) {
+	spec := &ToolSetSpec{ToolIds: []string{"tool-1", "tool-2"}, Name: "codec", Enabled: true}
+	codec := ToolSetSpecCodec()
+
+	// Scan reads what the codec encodes
+	data, err := codec.Encode(spec)
+	if err != nil {
+		t.Fatalf("Encode() error: %v", err)
+	}
+	var scanned ToolSetSpecValue
+	if err := scanned.Scan(data); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if !proto.Equal(scanned.Unwrap(), spec) {
+		t.Errorf("Scan() = %v, want %v", scanned.Unwrap(), spec)
+	}
+
+	// and the codec decodes what Value stores
+	dbVal, err := NewToolSetSpecValue(spec).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	var stored []byte
+	switch v := dbVal.(type) {
+	case []byte:
+		stored = v
+	case string:
+		stored = []byte(v)
+	default:
+		t.Fatalf("Value() = %T, want []byte or string", dbVal)
+	}
+	decoded := &ToolSetSpec{}
+	if err := codec.Decode(stored, decoded); err != nil {
+		t.Fatalf("Decode() error: %v", err)
+	}
+	if !proto.Equal(decoded, spec) {
+		t.Errorf("Decode() = %v, want %v", decoded, spec)
+	}
+
+	var decodeErr *DecodeError
+	if err := codec.Decode([]byte{0xff, 0xff, 0xff}, &ToolSetSpec{}); !errors.As(err, &decodeErr) {
+		t.Errorf("Decode() of corrupt data error = %v, want a *DecodeError", err)
+	}
+}
+
+func TestContainerValue_ScanFields(t *testing.T) {
+	container := &Container{
+		Id:    "container-1",
+		Spec:  &ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "spec"},
+		Items: []*Container_Item{{Key: "k", Value: "v"}},
+	}
+	dbVal, err := NewContainerValue(container).Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+
+	var scanned ContainerValue
+	if err := scanned.ScanFields(dbVal, "id"); err != nil {
+		t.Fatalf("ScanFields() error: %v", err)
+	}
+	if want := (&Container{Id: "container-1"}); !proto.Equal(scanned.Unwrap(), want) {
+		t.Errorf("ScanFields(id) = %v, want %v", scanned.Unwrap(), want)
+	}
+
+	// Scanning the same wrapper again drops the fields of the previous row
+	if err := scanned.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if err := scanned.ScanFields(dbVal, "spec", "items"); err != nil {
+		t.Fatalf("ScanFields() error: %v", err)
+	}
+	if got := scanned.Unwrap(); got.GetId() != "" || !proto.Equal(got.GetSpec(), container.Spec) || len(got.GetItems()) != 1 {
+		t.Errorf("ScanFields(spec, items) = %v, want the spec and items only", got)
+	}
+
+	if err := scanned.ScanFields(dbVal, "spec.name"); err == nil {
+		t.Error("ScanFields() of a nested path succeeded, want an error")
+	}
+	if err := scanned.ScanFields(nil, "id"); err != nil || scanned.Unwrap() != nil {
+		t.Errorf("ScanFields(nil) = %v, %v, want no message", scanned.Unwrap(), err)
+	}
+}
+
+func TestToolSetSpecValue_ValueMasked(t *testing.T) {
+	spec := &ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "renamed", Enabled: true}
+	wrapper := NewToolSetSpecValue(spec)
+	dbVal, err := wrapper.ValueMasked(&fieldmaskpb.FieldMask{Paths: []string{"name"}})
+	if err != nil {
+		t.Fatalf("ValueMasked() error: %v", err)
+	}
+	var scanned ToolSetSpecValue
+	if err := scanned.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if want := (&ToolSetSpec{Name: "renamed"}); !proto.Equal(scanned.Unwrap(), want) {
+		t.Errorf("ValueMasked(name) stored %v, want %v", scanned.Unwrap(), want)
+	}
+	if len(spec.ToolIds) != 1 || !spec.Enabled {
+		t.Errorf("ValueMasked() changed the wrapped message: %v", spec)
+	}
+
+	if _, err := wrapper.ValueMasked(&fieldmaskpb.FieldMask{Paths: []string{"nickname"}}); err == nil {
+		t.Error("ValueMasked() of an unknown path succeeded, want an error")
+	}
+	if dbVal, err := (&ToolSetSpecValue{}).ValueMasked(&fieldmaskpb.FieldMask{Paths: []string{"name"}}); err != nil || dbVal != nil {
+		t.Errorf("ValueMasked() of an empty wrapper = %v, %v, want NULL", dbVal, err)
+	}
+}
+
+func TestContainerValue_ValueMaskedNested(t *testing.T) {
+	container := &Container{Id: "c", Spec: &ToolSetSpec{ToolIds: []string{"tool-1"}, Name: "spec"}}
+	dbVal, err := NewContainerValue(container).ValueMasked(&fieldmaskpb.FieldMask{Paths: []string{"id", "spec.name"}})
+	if err != nil {
+		t.Fatalf("ValueMasked() error: %v", err)
+	}
+	var scanned ContainerValue
+	if err := scanned.Scan(dbVal); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if want := (&Container{Id: "c", Spec: &ToolSetSpec{Name: "spec"}}); !proto.Equal(scanned.Unwrap(), want) {
+		t.Errorf("ValueMasked(id, spec.name) stored %v, want %v", scanned.Unwrap(), want)
+	}
+
+	// Paths below an unset field are checked too
+	for _, path := range []string{"items.key", "id.value", "spec.nickname"} {
+		if _, err := NewContainerValue(&Container{}).ValueMasked(&fieldmaskpb.FieldMask{Paths: []string{path}}); err == nil {
+			t.Errorf("ValueMasked(%s) succeeded, want an error", path)
+		}
+	}
+}
+
+func TestUserPreferencesValue_Merge(t *testing.T) {
+	stored := NewUserPreferencesValue(&UserPreferences{Theme: "dark", Settings: map[string]string{"tz": "UTC"}})
+	stored.Merge(&UserPreferences{Language: "de", Settings: map[string]string{"tz": "CET", "unit": "metric"}})
+
+	want := &UserPreferences{Theme: "dark", Language: "de", Settings: map[string]string{"tz": "CET", "unit": "metric"}}
+	if !proto.Equal(stored.Unwrap(), want) {
+		t.Errorf("Merge() = %v, want %v", stored.Unwrap(), want)
+	}
+
+	// Repeated fields are appended to, and an empty wrapper gets a message
+	var spec ToolSetSpecValue
+	spec.Merge(&ToolSetSpec{ToolIds: []string{"tool-1"}})
+	spec.Merge(&ToolSetSpec{ToolIds: []string{"tool-2"}})
+	spec.Merge(nil)
+	if got := spec.Unwrap().GetToolIds(); len(got) != 2 || got[0] != "tool-1" || got[1] != "tool-2" {
+		t.Errorf("Merge() tool_ids = %v, want [tool-1 tool-2]", got)
+	}
+}
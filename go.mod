@@ -2,4 +2,7 @@ module github.com/cadenya/protoc-gen-go-dbtypes
 
 go 1.25.4
 
-require google.golang.org/protobuf v1.36.11
+require (
+	google.golang.org/protobuf v1.36.11
+	gopkg.in/yaml.v3 v3.0.1
+)